@@ -0,0 +1,279 @@
+// Command abidec-notify watches wallets for token transfers and posts human readable
+// notifications to a webhook. The JSON payload carries a "text" field, so Slack and Discord
+// compatible incoming webhooks can be used as they are, next to the structured transfer.
+//
+// Usage:
+//
+//	abidec-notify -rpc https://rpc.example.org -webhook https://hooks.example.org/abc \
+//		-wallets 0xd22861049f6582BcAd6b7a33F211e6fC701DBBBB,0x594cBC09284981fF5e45F00d65d07f81f4C8B23d
+//
+// Transfers are detected from the Transfer logs decoded with the default ABIs of the package and
+// amounts are formatted with the token metadata queried from the contracts.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/rpc"
+	"github.com/w2496/go-abi-decoder/v2/store"
+	"github.com/w2496/go-abi-decoder/v2/tokens"
+)
+
+// Notification is the payload posted to the webhook for every transfer of a watched wallet.
+type Notification struct {
+	Text            string `json:"text"`              // Human readable summary.
+	Wallet          string `json:"wallet"`            // Watched wallet.
+	Direction       string `json:"direction"`         // "in" or "out".
+	Counterparty    string `json:"counterparty"`      // Sender of incoming, recipient of outgoing transfers.
+	Token           string `json:"token"`             // Token contract.
+	Symbol          string `json:"symbol,omitempty"`  // Token symbol, if known.
+	Amount          string `json:"amount"`            // Amount formatted with the token decimals.
+	TokenId         string `json:"tokenId,omitempty"` // Token id of NFT transfers.
+	TransactionHash string `json:"transactionHash"`
+	BlockNumber     uint64 `json:"blockNumber"`
+}
+
+// notifier turns decoded Transfer logs of the watched wallets into webhook notifications.
+type notifier struct {
+	webhook string
+	client  *http.Client
+	wallets map[common.Address]bool
+	tokens  func(token common.Address) *tokens.ITknInfo
+}
+
+func main() {
+	endpoint := flag.String("rpc", os.Getenv("ABIDEC_RPC"), "JSON-RPC endpoint of the node")
+	webhook := flag.String("webhook", os.Getenv("ABIDEC_WEBHOOK"), "URL notifications are posted to")
+	wallets := flag.String("wallets", os.Getenv("ABIDEC_WALLETS"), "comma separated wallet addresses to watch")
+	from := flag.Uint64("from", 0, "first block to scan, 0 starts at the next block")
+	poll := flag.Duration("poll", 15*time.Second, "interval between polls for new blocks")
+	confirmations := flag.Uint64("confirmations", 2, "blocks a transfer has to be buried under before it is notified")
+	flag.Parse()
+
+	if *endpoint == "" || *webhook == "" || *wallets == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	watched := make(map[common.Address]bool)
+	for _, wallet := range strings.Split(*wallets, ",") {
+		wallet = strings.TrimSpace(wallet)
+		if !common.IsHexAddress(wallet) {
+			log.Fatalf("invalid wallet address: %q", wallet)
+		}
+		watched[common.HexToAddress(wallet)] = true
+	}
+
+	if _, err := rpc.ConnectWithOptions(*endpoint, rpc.ConnectOptions{}); err != nil {
+		log.Fatal("error connecting to node: ", err)
+	}
+	store.Store.ParseAndAddABIs(decode.ALL_DEFAULT_ABIS...)
+
+	n := &notifier{
+		webhook: *webhook,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		wallets: watched,
+		tokens:  tokenInfo,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := n.run(ctx, *from, *poll, *confirmations); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
+
+// tokenInfo returns the cached metadata of the token, querying it on first use.
+func tokenInfo(token common.Address) *tokens.ITknInfo {
+	info, err := tokens.TknStore.Get(token)
+	if err != nil {
+		return nil
+	}
+
+	tokens.TknStore.Set(info)
+	return info
+}
+
+// run polls for new blocks and notifies the transfers of the watched wallets until the context is
+// done.
+func (n *notifier) run(ctx context.Context, from uint64, poll time.Duration, confirmations uint64) error {
+	addresses := make([]string, 0, len(n.wallets))
+	for wallet := range n.wallets {
+		addresses = append(addresses, wallet.Hex())
+	}
+
+	// transfers are fetched once by sender and once by recipient, topics can not be OR-ed
+	outgoing, err := store.Store.BuildFilter("Transfer", map[string]interface{}{"from": addresses})
+	if err != nil {
+		return err
+	}
+
+	incoming, err := store.Store.BuildFilter("Transfer", map[string]interface{}{"to": addresses})
+	if err != nil {
+		return err
+	}
+
+	next := from
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		latest, err := rpc.GetClient().BlockNumber(ctx)
+		if err != nil {
+			log.Println("error getting block number:", err)
+		} else if next == 0 {
+			next = latest + 1
+			log.Printf("watching %d wallets from block %d", len(n.wallets), next)
+		} else if latest >= confirmations && latest-confirmations >= next {
+			safe := latest - confirmations
+			if err := n.scan(ctx, next, safe, outgoing, incoming); err != nil {
+				log.Printf("error scanning blocks %d - %d: %v", next, safe, err)
+			} else {
+				next = safe + 1
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan notifies the transfers of the watched wallets in the inclusive block range. Transfers
+// between two watched wallets are found by both filters but notified once per wallet.
+func (n *notifier) scan(ctx context.Context, fromBlock uint64, toBlock uint64, filters ...ethereum.FilterQuery) error {
+	seen := make(map[string]bool)
+
+	for _, filter := range filters {
+		filter.FromBlock = new(big.Int).SetUint64(fromBlock)
+		filter.ToBlock = new(big.Int).SetUint64(toBlock)
+
+		err := store.Store.ScanLogs(ctx, filter, nil, func(decoded *decode.DecodedLog) error {
+			if seen[decoded.IdempotencyKey] {
+				return nil
+			}
+			seen[decoded.IdempotencyKey] = true
+
+			for _, notification := range n.notifications(decoded) {
+				if err := n.post(ctx, notification); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifications returns the notifications of the watched wallets taking part in the transfer.
+func (n *notifier) notifications(decoded *decode.DecodedLog) []Notification {
+	movement := tokens.TransferFromLog(decoded)
+	if movement == nil {
+		return nil
+	}
+
+	token := common.HexToAddress(movement.Asset)
+	symbol, amount := "", movement.Amount.String()
+	if info := n.tokens(token); info != nil {
+		symbol = info.Symbol
+		if movement.Kind == tokens.MovementERC20 {
+			amount = tokens.FormatAmount(movement.Amount, info.Decimals)
+		}
+	}
+
+	base := Notification{
+		Token:           token.Hex(),
+		Symbol:          symbol,
+		Amount:          amount,
+		TransactionHash: decoded.TransactionHash,
+		BlockNumber:     decoded.BlockNumber,
+	}
+	if movement.TokenId != nil {
+		base.TokenId = movement.TokenId.String()
+	}
+
+	asset := symbol
+	if asset == "" {
+		asset = shorten(token.Hex())
+	}
+	if movement.Kind == tokens.MovementERC721 {
+		asset = fmt.Sprintf("%s #%s", asset, base.TokenId)
+		base.Amount = "1"
+	} else {
+		asset = amount + " " + asset
+	}
+
+	var result []Notification
+	if from := common.HexToAddress(movement.From); n.wallets[from] {
+		notification := base
+		notification.Wallet, notification.Direction, notification.Counterparty = from.Hex(), "out", movement.To
+		notification.Text = fmt.Sprintf("%s sent %s to %s (tx %s)", shorten(from.Hex()), asset, shorten(movement.To), shorten(decoded.TransactionHash))
+		result = append(result, notification)
+	}
+
+	if to := common.HexToAddress(movement.To); n.wallets[to] {
+		notification := base
+		notification.Wallet, notification.Direction, notification.Counterparty = to.Hex(), "in", movement.From
+		notification.Text = fmt.Sprintf("%s received %s from %s (tx %s)", shorten(to.Hex()), asset, shorten(movement.From), shorten(decoded.TransactionHash))
+		result = append(result, notification)
+	}
+
+	return result
+}
+
+// post sends the notification to the webhook.
+func (n *notifier) post(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("webhook request failed: %s", response.Status)
+	}
+
+	return nil
+}
+
+// shorten abbreviates hashes and addresses to their first and last 4 hex digits.
+func shorten(value string) string {
+	if len(value) <= 12 {
+		return value
+	}
+
+	return value[:6] + "…" + value[len(value)-4:]
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/tokens"
+)
+
+func TestNotifications(t *testing.T) {
+	wallet := common.HexToAddress("0xd22861049f6582BcAd6b7a33F211e6fC701DBBBB")
+	other := common.HexToAddress("0x594cBC09284981fF5e45F00d65d07f81f4C8B23d")
+	token := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	var posted []Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification Notification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			t.Fatal(err)
+		}
+		posted = append(posted, notification)
+	}))
+	defer server.Close()
+
+	n := &notifier{
+		webhook: server.URL,
+		client:  server.Client(),
+		wallets: map[common.Address]bool{wallet: true},
+		tokens: func(common.Address) *tokens.ITknInfo {
+			return &tokens.ITknInfo{Address: token, Symbol: "TKN", Decimals: 6}
+		},
+	}
+
+	decoded := &decode.DecodedLog{
+		Contract:        token.Hex(),
+		Topic:           decode.TransferTopic,
+		TransactionHash: "0x10ad8530cdad3cf34c765ee728e6cd9cef6bf311bdeb2ed0c7dbe8a32d7a0aa8",
+		BlockNumber:     7,
+		Params:          decode.Params{"from": other.Hex(), "to": wallet.Hex(), "value": "1500000"},
+	}
+
+	notifications := n.notifications(decoded)
+	if len(notifications) != 1 {
+		t.Fatalf("invalid notifications: %v", notifications)
+	}
+
+	notification := notifications[0]
+	if notification.Direction != "in" || notification.Amount != "1.5" || notification.Counterparty != other.Hex() {
+		t.Fatalf("invalid notification: %+v", notification)
+	}
+
+	if !strings.Contains(notification.Text, "received 1.5 TKN from 0x594c…B23d") {
+		t.Fatalf("invalid text: %s", notification.Text)
+	}
+
+	if err := n.post(context.Background(), notification); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(posted) != 1 || posted[0].Text != notification.Text {
+		t.Fatalf("invalid posted notifications: %v", posted)
+	}
+
+	// logs of other wallets are not notified
+	decoded.Params["to"] = other.Hex()
+	if notifications := n.notifications(decoded); len(notifications) != 0 {
+		t.Fatalf("unrelated transfer notified: %v", notifications)
+	}
+}
@@ -0,0 +1,398 @@
+package decoder
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+)
+
+// ALL_DEFAULT_ABIS is decode.ALL_DEFAULT_ABIS.
+var ALL_DEFAULT_ABIS = decode.ALL_DEFAULT_ABIS
+
+// MaxAmbiguityContracts points to decode.MaxAmbiguityContracts.
+var MaxAmbiguityContracts = decode.MaxAmbiguityContracts
+
+// Ambiguity is decode.Ambiguity.
+type Ambiguity = decode.Ambiguity
+
+// Call calls decode.Call.
+func Call(ctx context.Context, contract common.Address, method string, args ...interface{}) (Params, error) {
+	return decode.Call(ctx, contract, method, args...)
+}
+
+// EventEntry is decode.EventEntry.
+type EventEntry = decode.EventEntry
+
+// MethodEntry is decode.MethodEntry.
+type MethodEntry = decode.MethodEntry
+
+// Catalog is decode.Catalog.
+type Catalog = decode.Catalog
+
+// MethodMatch is decode.MethodMatch.
+type MethodMatch = decode.MethodMatch
+
+// EventMatch is decode.EventMatch.
+type EventMatch = decode.EventMatch
+
+// ComplianceHook is decode.ComplianceHook.
+type ComplianceHook = decode.ComplianceHook
+
+// ScreeningResult is decode.ScreeningResult.
+type ScreeningResult = decode.ScreeningResult
+
+// ListScreener is decode.ListScreener.
+type ListScreener = decode.ListScreener
+
+// NewListScreener calls decode.NewListScreener.
+func NewListScreener(path string, block bool) (*ListScreener, error) {
+	return decode.NewListScreener(path, block)
+}
+
+// AbiDecoder is decode.AbiDecoder.
+type AbiDecoder = decode.AbiDecoder
+
+// MinimalProxyTarget calls decode.MinimalProxyTarget.
+func MinimalProxyTarget(code []byte) (common.Address, bool) {
+	return decode.MinimalProxyTarget(code)
+}
+
+// IsDeployment calls decode.IsDeployment.
+func IsDeployment(tx *types.Transaction) bool {
+	return decode.IsDeployment(tx)
+}
+
+// FacetResolver is decode.FacetResolver.
+type FacetResolver = decode.FacetResolver
+
+// DiamondFacet is decode.DiamondFacet.
+type DiamondFacet = decode.DiamondFacet
+
+// Diamond is decode.Diamond.
+type Diamond = decode.Diamond
+
+// Instruction is decode.Instruction.
+type Instruction = decode.Instruction
+
+// Disassemble calls decode.Disassemble.
+func Disassemble(bytecode string) ([]Instruction, error) {
+	return decode.Disassemble(bytecode)
+}
+
+// ExtractSelectors calls decode.ExtractSelectors.
+func ExtractSelectors(bytecode string) []string {
+	return decode.ExtractSelectors(bytecode)
+}
+
+// ExtractTopics calls decode.ExtractTopics.
+func ExtractTopics(bytecode string) []string {
+	return decode.ExtractTopics(bytecode)
+}
+
+// TypedDataTypes is decode.TypedDataTypes.
+var TypedDataTypes = decode.TypedDataTypes
+
+// ParseTypedData calls decode.ParseTypedData.
+func ParseTypedData(data []byte) (*apitypes.TypedData, error) {
+	return decode.ParseTypedData(data)
+}
+
+// NewTypedData calls decode.NewTypedData.
+func NewTypedData(types apitypes.Types, primaryType string, domain apitypes.TypedDataDomain, message apitypes.TypedDataMessage) apitypes.TypedData {
+	return decode.NewTypedData(types, primaryType, domain, message)
+}
+
+// DomainSeparator calls decode.DomainSeparator.
+func DomainSeparator(typed apitypes.TypedData) (common.Hash, error) {
+	return decode.DomainSeparator(typed)
+}
+
+// TypedDataDigest calls decode.TypedDataDigest.
+func TypedDataDigest(typed apitypes.TypedData) (common.Hash, error) {
+	return decode.TypedDataDigest(typed)
+}
+
+// RecoverTypedDataSigner calls decode.RecoverTypedDataSigner.
+func RecoverTypedDataSigner(typed apitypes.TypedData, signature []byte) (common.Address, error) {
+	return decode.RecoverTypedDataSigner(typed, signature)
+}
+
+// DecodeTypedStruct calls decode.DecodeTypedStruct.
+func DecodeTypedStruct(types apitypes.Types, primaryType string, data []byte) (apitypes.TypedDataMessage, error) {
+	return decode.DecodeTypedStruct(types, primaryType, data)
+}
+
+// DecodeTypedParam calls decode.DecodeTypedParam.
+func DecodeTypedParam(method *DecodedMethod, param string, primaryType string) (apitypes.TypedDataMessage, error) {
+	return decode.DecodeTypedParam(method, param, primaryType)
+}
+
+// DecodeAttempt is decode.DecodeAttempt.
+type DecodeAttempt = decode.DecodeAttempt
+
+// DecodeTrace is decode.DecodeTrace.
+type DecodeTrace = decode.DecodeTrace
+
+// FormatterFunc is decode.FormatterFunc.
+type FormatterFunc = decode.FormatterFunc
+
+// RegisterFormatter calls decode.RegisterFormatter.
+func RegisterFormatter(abiType string, fn FormatterFunc) {
+	decode.RegisterFormatter(abiType, fn)
+}
+
+// RegisterParamFormatter calls decode.RegisterParamFormatter.
+func RegisterParamFormatter(name string, fn FormatterFunc) {
+	decode.RegisterParamFormatter(name, fn)
+}
+
+// ResetFormatters calls decode.ResetFormatters.
+func ResetFormatters() {
+	decode.ResetFormatters()
+}
+
+// SetCodeTxType is decode.SetCodeTxType.
+const SetCodeTxType = decode.SetCodeTxType
+
+// TxTypeName calls decode.TxTypeName.
+func TxTypeName(txType uint8) string {
+	return decode.TxTypeName(txType)
+}
+
+// InterfaceThreshold points to decode.InterfaceThreshold.
+var InterfaceThreshold = decode.InterfaceThreshold
+
+// InterfaceMatch is decode.InterfaceMatch.
+type InterfaceMatch = decode.InterfaceMatch
+
+// InterfaceReport is decode.InterfaceReport.
+type InterfaceReport = decode.InterfaceReport
+
+// AnalyzeBytecode calls decode.AnalyzeBytecode.
+func AnalyzeBytecode(code string) InterfaceReport {
+	return decode.AnalyzeBytecode(code)
+}
+
+// KeccakFunc is decode.KeccakFunc.
+type KeccakFunc = decode.KeccakFunc
+
+// SetKeccak calls decode.SetKeccak.
+func SetKeccak(fn KeccakFunc) {
+	decode.SetKeccak(fn)
+}
+
+// ResetSHA3Cache calls decode.ResetSHA3Cache.
+func ResetSHA3Cache() {
+	decode.ResetSHA3Cache()
+}
+
+// Labels is decode.Labels.
+var Labels = decode.Labels
+
+// LabelStore is decode.LabelStore.
+type LabelStore = decode.LabelStore
+
+// NewLabelStore calls decode.NewLabelStore.
+func NewLabelStore() *LabelStore {
+	return decode.NewLabelStore()
+}
+
+// LazyLog is decode.LazyLog.
+type LazyLog = decode.LazyLog
+
+// LazyMethod is decode.LazyMethod.
+type LazyMethod = decode.LazyMethod
+
+// ContractMetadata is decode.ContractMetadata.
+type ContractMetadata = decode.ContractMetadata
+
+// ExtractMetadata calls decode.ExtractMetadata.
+func ExtractMetadata(bytecode string) (*ContractMetadata, error) {
+	return decode.ExtractMetadata(bytecode)
+}
+
+// MaxNestedDepth points to decode.MaxNestedDepth.
+var MaxNestedDepth = decode.MaxNestedDepth
+
+// ProfilerConfig is decode.ProfilerConfig.
+type ProfilerConfig = decode.ProfilerConfig
+
+// Anomaly is decode.Anomaly.
+type Anomaly = decode.Anomaly
+
+// ContractProfile is decode.ContractProfile.
+type ContractProfile = decode.ContractProfile
+
+// MethodProfile is decode.MethodProfile.
+type MethodProfile = decode.MethodProfile
+
+// ParamStats is decode.ParamStats.
+type ParamStats = decode.ParamStats
+
+// CallProfiler is decode.CallProfiler.
+type CallProfiler = decode.CallProfiler
+
+// NewCallProfiler calls decode.NewCallProfiler.
+func NewCallProfiler(config ProfilerConfig) (*CallProfiler, error) {
+	return decode.NewCallProfiler(config)
+}
+
+// RecoverABI calls decode.RecoverABI.
+func RecoverABI(bytecode string, resolver SignatureResolver) abi.ABI {
+	return decode.RecoverABI(bytecode, resolver)
+}
+
+// ResolveTimeout points to decode.ResolveTimeout.
+var ResolveTimeout = decode.ResolveTimeout
+
+// SignatureResolver is decode.SignatureResolver.
+type SignatureResolver = decode.SignatureResolver
+
+// OpenchainResolver is decode.OpenchainResolver.
+type OpenchainResolver = decode.OpenchainResolver
+
+// FourByteResolver is decode.FourByteResolver.
+type FourByteResolver = decode.FourByteResolver
+
+// ResetResolvedSignatures calls decode.ResetResolvedSignatures.
+func ResetResolvedSignatures() {
+	decode.ResetResolvedSignatures()
+}
+
+// DecodedError is decode.DecodedError.
+type DecodedError = decode.DecodedError
+
+// LookupSelector calls decode.LookupSelector.
+func LookupSelector(sigHash string) []string {
+	return decode.LookupSelector(sigHash)
+}
+
+// LookupTopic calls decode.LookupTopic.
+func LookupTopic(topic string) []string {
+	return decode.LookupTopic(topic)
+}
+
+// EmbeddedResolver is decode.EmbeddedResolver.
+type EmbeddedResolver = decode.EmbeddedResolver
+
+// DecodedCallTree is decode.DecodedCallTree.
+type DecodedCallTree = decode.DecodedCallTree
+
+// DecodedTransaction is decode.DecodedTransaction.
+type DecodedTransaction = decode.DecodedTransaction
+
+// DecodeTransactionFull calls decode.DecodeTransactionFull.
+func DecodeTransactionFull(ctx context.Context, transactionHash string) (*DecodedTransaction, error) {
+	return decode.DecodeTransactionFull(ctx, transactionHash)
+}
+
+// Params is decode.Params.
+type Params = decode.Params
+
+// AddressNames is decode.AddressNames.
+type AddressNames = decode.AddressNames
+
+// ParamInfo is decode.ParamInfo.
+type ParamInfo = decode.ParamInfo
+
+// DecodedParam is decode.DecodedParam.
+type DecodedParam = decode.DecodedParam
+
+// ScannedLogs is decode.ScannedLogs.
+type ScannedLogs = decode.ScannedLogs
+
+// DecodedLog is decode.DecodedLog.
+type DecodedLog = decode.DecodedLog
+
+// DecodedMethod is decode.DecodedMethod.
+type DecodedMethod = decode.DecodedMethod
+
+// TxInfo is decode.TxInfo.
+type TxInfo = decode.TxInfo
+
+// DecodedDeployment is decode.DecodedDeployment.
+type DecodedDeployment = decode.DecodedDeployment
+
+// DecodedReceipt is decode.DecodedReceipt.
+type DecodedReceipt = decode.DecodedReceipt
+
+const (
+	// EtherAddress is decode.EtherAddress.
+	EtherAddress = decode.EtherAddress
+
+	// Zero32Bytes is decode.Zero32Bytes.
+	Zero32Bytes = decode.Zero32Bytes
+
+	// TransferTopic is decode.TransferTopic.
+	TransferTopic = decode.TransferTopic
+)
+
+// ParseABI calls decode.ParseABI.
+func ParseABI(input string) *abi.ABI {
+	return decode.ParseABI(input)
+}
+
+// ToAscii calls decode.ToAscii.
+func ToAscii(input []byte) string {
+	return decode.ToAscii(input)
+}
+
+// MergeABIs calls decode.MergeABIs.
+func MergeABIs(jsonAbis ...string) *abi.ABI {
+	return decode.MergeABIs(jsonAbis...)
+}
+
+// IsEIP1559 calls decode.IsEIP1559.
+func IsEIP1559(client Backend, ctx_ context.Context) (*bool, error) {
+	return decode.IsEIP1559(client, ctx_)
+}
+
+// ToSHA3 calls decode.ToSHA3.
+func ToSHA3(data string) string {
+	return decode.ToSHA3(data)
+}
+
+// IsToken calls decode.IsToken.
+func IsToken(bytecode string) bool {
+	return decode.IsToken(bytecode)
+}
+
+// IsERC1155 calls decode.IsERC1155.
+func IsERC1155(bytecode string) bool {
+	return decode.IsERC1155(bytecode)
+}
+
+// IsERC721 calls decode.IsERC721.
+func IsERC721(bytecode string) bool {
+	return decode.IsERC721(bytecode)
+}
+
+// IsERC20 calls decode.IsERC20.
+func IsERC20(bytecode string) bool {
+	return decode.IsERC20(bytecode)
+}
+
+// DetectTokenStandard calls decode.DetectTokenStandard.
+func DetectTokenStandard(bytecode string) string {
+	return decode.DetectTokenStandard(bytecode)
+}
+
+// DetectBytecodes calls decode.DetectBytecodes.
+func DetectBytecodes(bytecode string, signatures []string) bool {
+	return decode.DetectBytecodes(bytecode, signatures)
+}
+
+// GetMinerAndNonce calls decode.GetMinerAndNonce.
+func GetMinerAndNonce(block *types.Block) (string, string) {
+	return decode.GetMinerAndNonce(block)
+}
+
+// FieldError is decode.FieldError.
+type FieldError = decode.FieldError
+
+// ValidationErrors is decode.ValidationErrors.
+type ValidationErrors = decode.ValidationErrors
@@ -0,0 +1,11 @@
+// Package decoder keeps the API of the flat v1 package for migration to the v2 module, where the
+// decoder is split into the packages decode, store, tokens, scan, rpc, sinks and protocols of
+// github.com/w2496/go-abi-decoder/v2. Types are aliases of the v2 types, functions call their v2
+// counterparts and the files of this package are grouped by v2 package, so both can be mixed while
+// migrating.
+//
+// The global stores and package-level settings, e.g. Store and Ctx, are pointers to the variables
+// of v2, see CHANGELOG.md.
+//
+// Deprecated: Use the packages of github.com/w2496/go-abi-decoder/v2 instead.
+package decoder
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+	"github.com/w2496/go-abi-decoder/v2/rpc"
+	"github.com/w2496/go-abi-decoder/v2/scan"
+	"github.com/w2496/go-abi-decoder/v2/store"
+)
+
+// memorySink keeps the block hashes of the written logs by block number.
+type memorySink struct {
+	mu     sync.Mutex
+	blocks map[uint64]string
+}
+
+func (s *memorySink) Write(decoded *decode.DecodedLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks[decoded.BlockNumber] = decoded.BlockHash
+	return nil
+}
+
+func (s *memorySink) Revert(fromBlock uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for block := range s.blocks {
+		if block >= fromBlock {
+			delete(s.blocks, block)
+		}
+	}
+	return nil
+}
+
+// chain simulates a node whose blocks from forkBlock on are replaced by the fork.
+type chain struct {
+	mu        sync.Mutex
+	head      uint64
+	forkBlock uint64
+	fork      string
+}
+
+func (c *chain) header(number uint64) *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	extra := "main"
+	if c.fork != "" && number >= c.forkBlock {
+		extra = c.fork
+	}
+
+	return &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0), Extra: []byte(extra)}
+}
+
+func TestIndexerReorg(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	c := &chain{head: 5}
+	server.Handle("eth_blockNumber", func(json.RawMessage) (interface{}, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return hexutil.Uint64(c.head), nil
+	})
+	server.Handle("eth_getBlockByNumber", func(params json.RawMessage) (interface{}, error) {
+		var args []interface{}
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		number, _ := hexutil.DecodeUint64(args[0].(string))
+		return c.header(number), nil
+	})
+	// every block holds a single ERC-20 transfer
+	server.Handle("eth_getLogs", func(params json.RawMessage) (interface{}, error) {
+		var query []struct {
+			FromBlock string `json:"fromBlock"`
+			ToBlock   string `json:"toBlock"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+
+		from, _ := hexutil.DecodeUint64(query[0].FromBlock)
+		to, _ := hexutil.DecodeUint64(query[0].ToBlock)
+		logs := make([]types.Log, 0)
+		for block := from; block <= to; block++ {
+			logs = append(logs, types.Log{
+				Address:     common.HexToAddress("0x1111111111111111111111111111111111111111"),
+				Topics:      []common.Hash{common.HexToHash(decode.TransferTopic), {}, {}},
+				Data:        common.LeftPadBytes([]byte{1}, 32),
+				BlockNumber: block,
+				BlockHash:   c.header(block).Hash(),
+			})
+		}
+		return logs, nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := rpc.GetClient()
+	rpc.SetClient(client)
+	defer rpc.SetClient(previous)
+
+	storage := &store.Storage{}
+	storage.ParseAndAddABIs(decode.ALL_DEFAULT_ABIS...)
+
+	sink := &memorySink{blocks: make(map[uint64]string)}
+	idx := newIndexer(storage, client, sink, 1)
+	idx.options = &scan.ScanOptions{ChunkSize: 3}
+
+	ctx := context.Background()
+	if err := idx.step(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	c.mu.Lock()
+	c.head = 10
+	c.mu.Unlock()
+	if err := idx.step(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.blocks) != 10 {
+		t.Fatalf("invalid indexed blocks: %v", sink.blocks)
+	}
+
+	// blocks 8 - 10 are replaced, the checkpoint of block 5 is still canonical
+	c.mu.Lock()
+	c.head, c.forkBlock, c.fork = 12, 8, "fork"
+	c.mu.Unlock()
+	if err := idx.step(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.blocks) != 12 {
+		t.Fatalf("invalid indexed blocks after reorg: %v", sink.blocks)
+	}
+
+	for block := uint64(1); block <= 12; block++ {
+		if sink.blocks[block] != c.header(block).Hash().Hex() {
+			t.Fatalf("block %d not reindexed: %s", block, sink.blocks[block])
+		}
+	}
+
+	if reorgs, ok := idx.metrics.Get("reorgs").(*expvar.Int); !ok || reorgs.Value() != 1 {
+		t.Fatalf("reorg not counted: %v", idx.metrics.Get("reorgs"))
+	}
+
+	if logs := idx.metrics.Get("logs").(*expvar.Int).Value(); logs != 17 {
+		t.Fatalf("invalid number of indexed logs: %d", logs)
+	}
+
+	if idx.next != 13 || idx.metrics.Get("head").String() != "12" {
+		t.Fatalf("invalid progress: next %d, head %s", idx.next, idx.metrics.Get("head"))
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/w2496/go-abi-decoder/v2/decode"
+)
+
+// postgresSink writes decoded logs to a Postgres table keyed by their idempotency key, so logs
+// written again after a failed step are not duplicated. Reverts delete the rows of the reverted
+// blocks.
+type postgresSink struct {
+	db    *sql.DB
+	table string
+}
+
+func newPostgresSink(db *sql.DB, table string) *postgresSink {
+	if table == "" {
+		table = "decoded_logs"
+	}
+
+	return &postgresSink{db: db, table: table}
+}
+
+// Migrate creates the table of the sink, if it does not exist.
+func (s *postgresSink) Migrate(ctx context.Context) error {
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, block_number BIGINT NOT NULL, block_hash TEXT NOT NULL, tx_hash TEXT NOT NULL, log_index INTEGER NOT NULL, contract TEXT NOT NULL, name TEXT NOT NULL, log JSONB NOT NULL)", s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_block_number ON %s (block_number)", s.table, s.table),
+	}
+
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *postgresSink) Write(decoded *decode.DecodedLog) error {
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (key, block_number, block_hash, tx_hash, log_index, contract, name, log) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (key) DO NOTHING", s.table)
+	_, err = s.db.Exec(insert, decoded.IdempotencyKey, int64(decoded.BlockNumber), decoded.BlockHash, decoded.TransactionHash, decoded.LogIndex, decoded.Contract, decoded.Name, string(data))
+	return err
+}
+
+func (s *postgresSink) Revert(fromBlock uint64) error {
+	_, err := s.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE block_number >= $1", s.table), int64(fromBlock))
+	return err
+}
@@ -0,0 +1,30 @@
+// Package mockrpc provides a scriptable JSON-RPC server for integration tests of code built on
+// the decoder package.
+//
+// Deprecated: Use github.com/w2496/go-abi-decoder/v2/mockrpc instead.
+package mockrpc
+
+import "github.com/w2496/go-abi-decoder/v2/mockrpc"
+
+// Error is mockrpc.Error.
+type Error = mockrpc.Error
+
+// Response is mockrpc.Response.
+type Response = mockrpc.Response
+
+// Handler is mockrpc.Handler.
+type Handler = mockrpc.Handler
+
+// CallHandler is mockrpc.CallHandler.
+type CallHandler = mockrpc.CallHandler
+
+// ErrReverted is mockrpc.ErrReverted.
+var ErrReverted = mockrpc.ErrReverted
+
+// Server is mockrpc.Server.
+type Server = mockrpc.Server
+
+// NewServer calls mockrpc.NewServer.
+func NewServer() *Server {
+	return mockrpc.NewServer()
+}
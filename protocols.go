@@ -0,0 +1,196 @@
+package decoder
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/protocols"
+)
+
+// PriceFeeds is protocols.PriceFeeds.
+var PriceFeeds = protocols.PriceFeeds
+
+// USDAmountFormat points to protocols.USDAmountFormat.
+var USDAmountFormat = protocols.USDAmountFormat
+
+// PriceRound is protocols.PriceRound.
+type PriceRound = protocols.PriceRound
+
+// PriceUpdate is protocols.PriceUpdate.
+type PriceUpdate = protocols.PriceUpdate
+
+// IsAnswerUpdatedLog calls protocols.IsAnswerUpdatedLog.
+func IsAnswerUpdatedLog(vLog *types.Log) bool {
+	return protocols.IsAnswerUpdatedLog(vLog)
+}
+
+// DecodeAnswerUpdated calls protocols.DecodeAnswerUpdated.
+func DecodeAnswerUpdated(vLog *types.Log) (*PriceUpdate, error) {
+	return protocols.DecodeAnswerUpdated(vLog)
+}
+
+// LatestRoundData calls protocols.LatestRoundData.
+func LatestRoundData(ctx context.Context, feed common.Address) (*PriceRound, error) {
+	return protocols.LatestRoundData(ctx, feed)
+}
+
+// ENSRegistry points to protocols.ENSRegistry.
+var ENSRegistry = protocols.ENSRegistry
+
+// ResolveENS calls protocols.ResolveENS.
+func ResolveENS(name string) (common.Address, error) {
+	return protocols.ResolveENS(name)
+}
+
+// LookupENS calls protocols.LookupENS.
+func LookupENS(address common.Address) (string, error) {
+	return protocols.LookupENS(address)
+}
+
+const (
+	// DepositTxType is protocols.DepositTxType.
+	DepositTxType = protocols.DepositTxType
+
+	// ArbitrumDepositTxType is protocols.ArbitrumDepositTxType.
+	ArbitrumDepositTxType = protocols.ArbitrumDepositTxType
+
+	// ArbitrumUnsignedTxType is protocols.ArbitrumUnsignedTxType.
+	ArbitrumUnsignedTxType = protocols.ArbitrumUnsignedTxType
+
+	// ArbitrumContractTxType is protocols.ArbitrumContractTxType.
+	ArbitrumContractTxType = protocols.ArbitrumContractTxType
+
+	// ArbitrumRetryTxType is protocols.ArbitrumRetryTxType.
+	ArbitrumRetryTxType = protocols.ArbitrumRetryTxType
+
+	// ArbitrumSubmitRetryableTxType is protocols.ArbitrumSubmitRetryableTxType.
+	ArbitrumSubmitRetryableTxType = protocols.ArbitrumSubmitRetryableTxType
+
+	// ArbitrumInternalTxType is protocols.ArbitrumInternalTxType.
+	ArbitrumInternalTxType = protocols.ArbitrumInternalTxType
+
+	// ArbitrumLegacyTxType is protocols.ArbitrumLegacyTxType.
+	ArbitrumLegacyTxType = protocols.ArbitrumLegacyTxType
+)
+
+var (
+	// L1AttributesDepositor is protocols.L1AttributesDepositor.
+	L1AttributesDepositor = protocols.L1AttributesDepositor
+
+	// ArbOSAddress is protocols.ArbOSAddress.
+	ArbOSAddress = protocols.ArbOSAddress
+)
+
+// IsL2TxType calls protocols.IsL2TxType.
+func IsL2TxType(txType uint8) bool {
+	return protocols.IsL2TxType(txType)
+}
+
+const (
+	// MarketplaceSeaport is protocols.MarketplaceSeaport.
+	MarketplaceSeaport = protocols.MarketplaceSeaport
+
+	// MarketplaceLooksRare is protocols.MarketplaceLooksRare.
+	MarketplaceLooksRare = protocols.MarketplaceLooksRare
+)
+
+// NFTSale is protocols.NFTSale.
+type NFTSale = protocols.NFTSale
+
+// IsNFTSaleLog calls protocols.IsNFTSaleLog.
+func IsNFTSaleLog(vLog *types.Log) bool {
+	return protocols.IsNFTSaleLog(vLog)
+}
+
+// DecodeNFTSales calls protocols.DecodeNFTSales.
+func DecodeNFTSales(vLog *types.Log) ([]NFTSale, error) {
+	return protocols.DecodeNFTSales(vLog)
+}
+
+// ExtractNFTSales calls protocols.ExtractNFTSales.
+func ExtractNFTSales(vLogs []*types.Log) []NFTSale {
+	return protocols.ExtractNFTSales(vLogs)
+}
+
+// DecodeBasicOrder calls protocols.DecodeBasicOrder.
+func DecodeBasicOrder(tx *types.Transaction) (*NFTSale, error) {
+	return protocols.DecodeBasicOrder(tx)
+}
+
+const (
+	// RouterUniversal is protocols.RouterUniversal.
+	RouterUniversal = protocols.RouterUniversal
+
+	// RouterAggregator is protocols.RouterAggregator.
+	RouterAggregator = protocols.RouterAggregator
+)
+
+// RouterCommands is protocols.RouterCommands.
+var RouterCommands = protocols.RouterCommands
+
+// RouterOperation is protocols.RouterOperation.
+type RouterOperation = protocols.RouterOperation
+
+// RouterCall is protocols.RouterCall.
+type RouterCall = protocols.RouterCall
+
+// IsRouterCall calls protocols.IsRouterCall.
+func IsRouterCall(data []byte) bool {
+	return protocols.IsRouterCall(data)
+}
+
+// DecodeRouterCall calls protocols.DecodeRouterCall.
+func DecodeRouterCall(contract string, data []byte) (*RouterCall, error) {
+	return protocols.DecodeRouterCall(contract, data)
+}
+
+const (
+	// ProtocolUniswapV2 is protocols.ProtocolUniswapV2.
+	ProtocolUniswapV2 = protocols.ProtocolUniswapV2
+
+	// ProtocolUniswapV3 is protocols.ProtocolUniswapV3.
+	ProtocolUniswapV3 = protocols.ProtocolUniswapV3
+)
+
+// SwapInfo is protocols.SwapInfo.
+type SwapInfo = protocols.SwapInfo
+
+// IsSwapLog calls protocols.IsSwapLog.
+func IsSwapLog(vLog *types.Log) bool {
+	return protocols.IsSwapLog(vLog)
+}
+
+// DecodeSwap calls protocols.DecodeSwap.
+func DecodeSwap(ctx context.Context, vLog *types.Log) (*SwapInfo, error) {
+	return protocols.DecodeSwap(ctx, vLog)
+}
+
+const (
+	// VaultDeposit is protocols.VaultDeposit.
+	VaultDeposit = protocols.VaultDeposit
+
+	// VaultWithdraw is protocols.VaultWithdraw.
+	VaultWithdraw = protocols.VaultWithdraw
+)
+
+// ERC4626InterfaceID is protocols.ERC4626InterfaceID.
+var ERC4626InterfaceID = protocols.ERC4626InterfaceID
+
+// VaultEvent is protocols.VaultEvent.
+type VaultEvent = protocols.VaultEvent
+
+// IsVaultLog calls protocols.IsVaultLog.
+func IsVaultLog(vLog *types.Log) bool {
+	return protocols.IsVaultLog(vLog)
+}
+
+// DecodeVaultEvent calls protocols.DecodeVaultEvent.
+func DecodeVaultEvent(vLog *types.Log) (*VaultEvent, error) {
+	return protocols.DecodeVaultEvent(vLog)
+}
+
+// ExtractVaultEvents calls protocols.ExtractVaultEvents.
+func ExtractVaultEvents(vLogs []*types.Log) []VaultEvent {
+	return protocols.ExtractVaultEvents(vLogs)
+}
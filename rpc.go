@@ -0,0 +1,197 @@
+package decoder
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/rpc"
+)
+
+// Backend is rpc.Backend.
+type Backend = rpc.Backend
+
+// BuildTx calls rpc.BuildTx.
+func BuildTx(ctx context.Context, from, contract common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	return rpc.BuildTx(ctx, from, contract, method, args...)
+}
+
+// ResetNonce calls rpc.ResetNonce.
+func ResetNonce(chainId *big.Int, from common.Address) {
+	rpc.ResetNonce(chainId, from)
+}
+
+// CodeBatchSize points to rpc.CodeBatchSize.
+var CodeBatchSize = rpc.CodeBatchSize
+
+// ResetCodeCache calls rpc.ResetCodeCache.
+func ResetCodeCache() {
+	rpc.ResetCodeCache()
+}
+
+// GetCodes calls rpc.GetCodes.
+func GetCodes(ctx context.Context, addresses []common.Address, block *big.Int) (map[common.Address][]byte, error) {
+	return rpc.GetCodes(ctx, addresses, block)
+}
+
+// ChainConfig is rpc.ChainConfig.
+type ChainConfig = rpc.ChainConfig
+
+// ChainRegistry is rpc.ChainRegistry.
+type ChainRegistry = rpc.ChainRegistry
+
+// NewChainRegistry calls rpc.NewChainRegistry.
+func NewChainRegistry(configs ...*ChainConfig) *ChainRegistry {
+	return rpc.NewChainRegistry(configs...)
+}
+
+// Chains is rpc.Chains.
+var Chains = rpc.Chains
+
+// Clock is rpc.Clock.
+type Clock = rpc.Clock
+
+// Ticker is rpc.Ticker.
+type Ticker = rpc.Ticker
+
+// SystemClock points to rpc.SystemClock.
+var SystemClock = rpc.SystemClock
+
+// SetClock calls rpc.SetClock.
+func SetClock(c Clock) {
+	rpc.SetClock(c)
+}
+
+// ManualClock is rpc.ManualClock.
+type ManualClock = rpc.ManualClock
+
+// NewManualClock calls rpc.NewManualClock.
+func NewManualClock(now time.Time) *ManualClock {
+	return rpc.NewManualClock(now)
+}
+
+// ConnectOptions is rpc.ConnectOptions.
+type ConnectOptions = rpc.ConnectOptions
+
+// BasicAuth is rpc.BasicAuth.
+type BasicAuth = rpc.BasicAuth
+
+// DialWithOptions calls rpc.DialWithOptions.
+func DialWithOptions(ctx context.Context, nodeUrl string, options ConnectOptions) (*ethclient.Client, error) {
+	return rpc.DialWithOptions(ctx, nodeUrl, options)
+}
+
+// ConnectWithOptions calls rpc.ConnectWithOptions.
+func ConnectWithOptions(nodeUrl string, options ConnectOptions) (*ethclient.Client, error) {
+	return rpc.ConnectWithOptions(nodeUrl, options)
+}
+
+// Ctx points to rpc.Ctx.
+var Ctx = rpc.Ctx
+
+// NewCtx is rpc.NewCtx.
+var NewCtx = rpc.NewCtx
+
+// SetClient calls rpc.SetClient.
+func SetClient(client *ethclient.Client) *ethclient.Client {
+	return rpc.SetClient(client)
+}
+
+// SetBackend calls rpc.SetBackend.
+func SetBackend(backend Backend) Backend {
+	return rpc.SetBackend(backend)
+}
+
+// GetClient calls rpc.GetClient.
+func GetClient() *ethclient.Client {
+	return rpc.GetClient()
+}
+
+// GetBackend calls rpc.GetBackend.
+func GetBackend() Backend {
+	return rpc.GetBackend()
+}
+
+// Connect calls rpc.Connect.
+func Connect(nodeUrl string) *ethclient.Client {
+	return rpc.Connect(nodeUrl)
+}
+
+// ErrNotVerified is rpc.ErrNotVerified.
+var ErrNotVerified = rpc.ErrNotVerified
+
+// ExplorerConfig is rpc.ExplorerConfig.
+type ExplorerConfig = rpc.ExplorerConfig
+
+// RPCCall is rpc.RPCCall.
+type RPCCall = rpc.RPCCall
+
+// RPCInterceptor is rpc.RPCInterceptor.
+type RPCInterceptor = rpc.RPCInterceptor
+
+// MultiClientOptions is rpc.MultiClientOptions.
+type MultiClientOptions = rpc.MultiClientOptions
+
+// EndpointStatus is rpc.EndpointStatus.
+type EndpointStatus = rpc.EndpointStatus
+
+// MultiClient is rpc.MultiClient.
+type MultiClient = rpc.MultiClient
+
+// DialMulti calls rpc.DialMulti.
+func DialMulti(ctx context.Context, urls []string, options MultiClientOptions) (*MultiClient, error) {
+	return rpc.DialMulti(ctx, urls, options)
+}
+
+// ChainFeatures is rpc.ChainFeatures.
+type ChainFeatures = rpc.ChainFeatures
+
+// ProbeChain calls rpc.ProbeChain.
+func ProbeChain(ctx context.Context) (*ChainFeatures, error) {
+	return rpc.ProbeChain(ctx)
+}
+
+// RateLimiter is rpc.RateLimiter.
+type RateLimiter = rpc.RateLimiter
+
+// NewRateLimiter calls rpc.NewRateLimiter.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return rpc.NewRateLimiter(perSecond, burst)
+}
+
+// WithRequestID calls rpc.WithRequestID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return rpc.WithRequestID(ctx, id)
+}
+
+// RequestIDFromContext calls rpc.RequestIDFromContext.
+func RequestIDFromContext(ctx context.Context) string {
+	return rpc.RequestIDFromContext(ctx)
+}
+
+// RetryPolicy is rpc.RetryPolicy.
+type RetryPolicy = rpc.RetryPolicy
+
+// DefaultRetryPolicy points to rpc.DefaultRetryPolicy.
+var DefaultRetryPolicy = rpc.DefaultRetryPolicy
+
+// SetRetryPolicy calls rpc.SetRetryPolicy.
+func SetRetryPolicy(policy *RetryPolicy) error {
+	return rpc.SetRetryPolicy(policy)
+}
+
+// GetRetryPolicy calls rpc.GetRetryPolicy.
+func GetRetryPolicy() *RetryPolicy {
+	return rpc.GetRetryPolicy()
+}
+
+// IsRetryable calls rpc.IsRetryable.
+func IsRetryable(err error) bool {
+	return rpc.IsRetryable(err)
+}
+
+// SourcifyConfig is rpc.SourcifyConfig.
+type SourcifyConfig = rpc.SourcifyConfig
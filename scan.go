@@ -0,0 +1,75 @@
+package decoder
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/scan"
+)
+
+// BatchSize points to scan.BatchSize.
+var BatchSize = scan.BatchSize
+
+// DecodedBlock is scan.DecodedBlock.
+type DecodedBlock = scan.DecodedBlock
+
+// DecodedBlockTransaction is scan.DecodedBlockTransaction.
+type DecodedBlockTransaction = scan.DecodedBlockTransaction
+
+// SubscribeBlocks calls scan.SubscribeBlocks.
+func SubscribeBlocks(ctx context.Context) (<-chan DecodedBlock, error) {
+	return scan.SubscribeBlocks(ctx)
+}
+
+// BloomMatches calls scan.BloomMatches.
+func BloomMatches(bloom types.Bloom, storage *Storage) bool {
+	return scan.BloomMatches(bloom, storage)
+}
+
+// CheckpointStore is scan.CheckpointStore.
+type CheckpointStore = scan.CheckpointStore
+
+// ScanFunc is scan.ScanFunc.
+type ScanFunc = scan.ScanFunc
+
+// ScanSession is scan.ScanSession.
+type ScanSession = scan.ScanSession
+
+// NewScanSession calls scan.NewScanSession.
+func NewScanSession(key string, checkpoint CheckpointStore, filter ethereum.FilterQuery) *ScanSession {
+	return scan.NewScanSession(key, checkpoint, filter)
+}
+
+// FileCheckpointStore is scan.FileCheckpointStore.
+type FileCheckpointStore = scan.FileCheckpointStore
+
+// SQLCheckpointStore is scan.SQLCheckpointStore.
+type SQLCheckpointStore = scan.SQLCheckpointStore
+
+// ReorgTracker is scan.ReorgTracker.
+type ReorgTracker = scan.ReorgTracker
+
+// NewReorgTracker calls scan.NewReorgTracker.
+func NewReorgTracker(depth uint64) *ReorgTracker {
+	return scan.NewReorgTracker(depth)
+}
+
+// ScanChunkSize points to scan.ScanChunkSize.
+var ScanChunkSize = scan.ScanChunkSize
+
+// ScanOptions is scan.ScanOptions.
+type ScanOptions = scan.ScanOptions
+
+// ScanEvents calls scan.ScanEvents.
+func ScanEvents(ctx context.Context, eventName string, fromBlock uint64, toBlock uint64) (*ScannedLogs, error) {
+	return scan.ScanEvents(ctx, eventName, fromBlock, toBlock)
+}
+
+// SubscribeOptions is scan.SubscribeOptions.
+type SubscribeOptions = scan.SubscribeOptions
+
+// SubscribeLogs calls scan.SubscribeLogs.
+func SubscribeLogs(ctx context.Context, filter ethereum.FilterQuery) (<-chan DecodedLog, error) {
+	return scan.SubscribeLogs(ctx, filter)
+}
@@ -0,0 +1,83 @@
+package decoder
+
+import (
+	"database/sql"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/sinks"
+)
+
+// Attestation is sinks.Attestation.
+type Attestation = sinks.Attestation
+
+// Attestor is sinks.Attestor.
+type Attestor = sinks.Attestor
+
+// NewAttestor calls sinks.NewAttestor.
+func NewAttestor(hexKey string, abiSet string) (*Attestor, error) {
+	return sinks.NewAttestor(hexKey, abiSet)
+}
+
+// VerifyMethod calls sinks.VerifyMethod.
+func VerifyMethod(decoded *DecodedMethod, trusted ...common.Address) error {
+	return sinks.VerifyMethod(decoded, trusted...)
+}
+
+// VerifyLog calls sinks.VerifyLog.
+func VerifyLog(decoded *DecodedLog, trusted ...common.Address) error {
+	return sinks.VerifyLog(decoded, trusted...)
+}
+
+// LogIdempotencyKey calls sinks.LogIdempotencyKey.
+func LogIdempotencyKey(chainId *big.Int, blockHash string, txHash string, logIndex uint) string {
+	return sinks.LogIdempotencyKey(chainId, blockHash, txHash, logIndex)
+}
+
+// MethodIdempotencyKey calls sinks.MethodIdempotencyKey.
+func MethodIdempotencyKey(txHash string) string {
+	return sinks.MethodIdempotencyKey(txHash)
+}
+
+// StorageDriver is sinks.StorageDriver.
+type StorageDriver = sinks.StorageDriver
+
+// NamedABI is sinks.NamedABI.
+type NamedABI = sinks.NamedABI
+
+// SQLStorageDriver is sinks.SQLStorageDriver.
+type SQLStorageDriver = sinks.SQLStorageDriver
+
+// NewSQLiteDriver calls sinks.NewSQLiteDriver.
+func NewSQLiteDriver(db *sql.DB) *SQLStorageDriver {
+	return sinks.NewSQLiteDriver(db)
+}
+
+// NewPostgresDriver calls sinks.NewPostgresDriver.
+func NewPostgresDriver(db *sql.DB) *SQLStorageDriver {
+	return sinks.NewPostgresDriver(db)
+}
+
+// SchemaVersion is sinks.SchemaVersion.
+const SchemaVersion = sinks.SchemaVersion
+
+// MinSchemaVersion is sinks.MinSchemaVersion.
+const MinSchemaVersion = sinks.MinSchemaVersion
+
+// BuildInfo is sinks.BuildInfo.
+type BuildInfo = sinks.BuildInfo
+
+// Version calls sinks.Version.
+func Version() string {
+	return sinks.Version()
+}
+
+// GetBuildInfo calls sinks.GetBuildInfo.
+func GetBuildInfo() BuildInfo {
+	return sinks.GetBuildInfo()
+}
+
+// CheckSchemaCompatibility calls sinks.CheckSchemaCompatibility.
+func CheckSchemaCompatibility(version int) error {
+	return sinks.CheckSchemaCompatibility(version)
+}
@@ -0,0 +1,123 @@
+package decoder
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/w2496/go-abi-decoder/v2/store"
+)
+
+// Storage is store.Storage.
+type Storage = store.Storage
+
+// Store points to store.Store.
+var Store = store.Store
+
+// Cache is store.Cache.
+type Cache = store.Cache
+
+// CacheTTL points to store.CacheTTL.
+var CacheTTL = store.CacheTTL
+
+// SetCache calls store.SetCache.
+func SetCache(cache Cache) {
+	store.SetCache(cache)
+}
+
+// GetCache calls store.GetCache.
+func GetCache() Cache {
+	return store.GetCache()
+}
+
+// MemoryCache is store.MemoryCache.
+type MemoryCache = store.MemoryCache
+
+// NewMemoryCache calls store.NewMemoryCache.
+func NewMemoryCache(size int) *MemoryCache {
+	return store.NewMemoryCache(size)
+}
+
+// RedisCache is store.RedisCache.
+type RedisCache = store.RedisCache
+
+// NewRedisCache calls store.NewRedisCache.
+func NewRedisCache(addr string) *RedisCache {
+	return store.NewRedisCache(addr)
+}
+
+// IndexedABI is store.IndexedABI.
+type IndexedABI = store.IndexedABI
+
+// BlockRange is store.BlockRange.
+type BlockRange = store.BlockRange
+
+// AbiVersion is store.AbiVersion.
+type AbiVersion = store.AbiVersion
+
+const (
+	// LookupBytecode is store.LookupBytecode.
+	LookupBytecode = store.LookupBytecode
+
+	// LookupSymbol is store.LookupSymbol.
+	LookupSymbol = store.LookupSymbol
+
+	// LookupName is store.LookupName.
+	LookupName = store.LookupName
+
+	// LookupDecimals is store.LookupDecimals.
+	LookupDecimals = store.LookupDecimals
+
+	// LookupENSName is store.LookupENSName.
+	LookupENSName = store.LookupENSName
+)
+
+// LookupCache is store.LookupCache.
+type LookupCache = store.LookupCache
+
+// NewLookupCache calls store.NewLookupCache.
+func NewLookupCache(size int, ttl time.Duration) *LookupCache {
+	return store.NewLookupCache(size, ttl)
+}
+
+// SetLookupCache calls store.SetLookupCache.
+func SetLookupCache(cache *LookupCache) {
+	store.SetLookupCache(cache)
+}
+
+// GetLookupCache calls store.GetLookupCache.
+func GetLookupCache() *LookupCache {
+	return store.GetLookupCache()
+}
+
+// MarshalABI calls store.MarshalABI.
+func MarshalABI(contractAbi abi.ABI) ([]byte, error) {
+	return store.MarshalABI(contractAbi)
+}
+
+// DefaultABIs is store.DefaultABIs.
+var DefaultABIs = store.DefaultABIs
+
+// ABIRegistry is store.ABIRegistry.
+type ABIRegistry = store.ABIRegistry
+
+// NewABIRegistry calls store.NewABIRegistry.
+func NewABIRegistry() *ABIRegistry {
+	return store.NewABIRegistry()
+}
+
+// WatchdogConfig is store.WatchdogConfig.
+type WatchdogConfig = store.WatchdogConfig
+
+// WatchdogStats is store.WatchdogStats.
+type WatchdogStats = store.WatchdogStats
+
+// WatchdogAlert is store.WatchdogAlert.
+type WatchdogAlert = store.WatchdogAlert
+
+// Watchdog is store.Watchdog.
+type Watchdog = store.Watchdog
+
+// NewWatchdog calls store.NewWatchdog.
+func NewWatchdog(config WatchdogConfig) (*Watchdog, error) {
+	return store.NewWatchdog(config)
+}
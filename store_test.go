@@ -0,0 +1,29 @@
+package decoder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/store"
+)
+
+func TestStoreSharedWithV2(t *testing.T) {
+	if Store != store.Store {
+		t.Fatal("expected the global storage of v2")
+	}
+
+	storage := &Storage{}
+	storage.ParseAndAddABIs(ALL_DEFAULT_ABIS...)
+
+	from, to := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	decoded := storage.DecodeLog(&types.Log{
+		Topics: []common.Hash{common.HexToHash(TransferTopic), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   common.LeftPadBytes(big.NewInt(5).Bytes(), 32),
+	})
+
+	if decoded == nil || decoded.Name != "Transfer" || decoded.Params["to"] != to.Hex() {
+		t.Fatalf("unexpected log: %v", decoded)
+	}
+}
@@ -1,162 +1,245 @@
 package decoder
 
 import (
-	"context"
-	"fmt"
-	"time"
+	"math/big"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/tokens"
 )
 
-// ITknInfo represents the structure of the 'token_info' table.
-type ITknInfo struct {
-	Address   common.Address
-	IsERC20   bool
-	IsERC721  bool
-	IsERC1155 bool
-	Name      string
-	Symbol    string
-	Decimals  uint8
-	Meta      string
-}
+// RoundingMode is tokens.RoundingMode.
+type RoundingMode = tokens.RoundingMode
 
-type ITknStore struct {
-	data map[common.Address]*ITknInfo
-	abis map[common.Address]*abi.ABI
-}
+const (
+	// RoundHalfEven is tokens.RoundHalfEven.
+	RoundHalfEven = tokens.RoundHalfEven
 
-var TknStore = ITknStore{
-	data: make(map[common.Address]*ITknInfo),
-}
+	// RoundHalfUp is tokens.RoundHalfUp.
+	RoundHalfUp = tokens.RoundHalfUp
 
-func (store *ITknStore) GetClient() *ethclient.Client {
-	return Ctx.eth
-}
+	// RoundTruncate is tokens.RoundTruncate.
+	RoundTruncate = tokens.RoundTruncate
+)
+
+// AmountFormat is tokens.AmountFormat.
+type AmountFormat = tokens.AmountFormat
+
+// DefaultAmountFormat points to tokens.DefaultAmountFormat.
+var DefaultAmountFormat = tokens.DefaultAmountFormat
 
-func (store *ITknStore) SetClient(client *ethclient.Client) {
-	SetClient(client)
+// FormatAmount calls tokens.FormatAmount.
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	return tokens.FormatAmount(amount, decimals)
 }
 
-func (store *ITknStore) Connect(nodeUrl string) {
-	Connect(nodeUrl)
+const (
+	// MovementNative is tokens.MovementNative.
+	MovementNative = tokens.MovementNative
+
+	// MovementFee is tokens.MovementFee.
+	MovementFee = tokens.MovementFee
+
+	// MovementERC20 is tokens.MovementERC20.
+	MovementERC20 = tokens.MovementERC20
+
+	// MovementERC721 is tokens.MovementERC721.
+	MovementERC721 = tokens.MovementERC721
+)
+
+// FeeAccount points to tokens.FeeAccount.
+var FeeAccount = tokens.FeeAccount
+
+// AssetMovement is tokens.AssetMovement.
+type AssetMovement = tokens.AssetMovement
+
+// BalanceSummary is tokens.BalanceSummary.
+type BalanceSummary = tokens.BalanceSummary
+
+// SummarizeBalanceChanges calls tokens.SummarizeBalanceChanges.
+func SummarizeBalanceChanges(tx *types.Transaction, receipt *types.Receipt, logs []*DecodedLog) (*BalanceSummary, error) {
+	return tokens.SummarizeBalanceChanges(tx, receipt, logs)
 }
 
-func (store *ITknStore) HasAbi(address common.Address) bool {
-	return store.data[address] != nil
+// TransferFromLog calls tokens.TransferFromLog.
+func TransferFromLog(decoded *DecodedLog) *AssetMovement {
+	return tokens.TransferFromLog(decoded)
 }
 
-func (store *ITknStore) SetAbi(tkn common.Address, abis abi.ABI) {
-	store.abis[tkn] = &abis
+// FeeBreakdown is tokens.FeeBreakdown.
+type FeeBreakdown = tokens.FeeBreakdown
+
+// L1Fees is tokens.L1Fees.
+type L1Fees = tokens.L1Fees
+
+// ComputeFees calls tokens.ComputeFees.
+func ComputeFees(receipt *types.Receipt, header *types.Header) (*FeeBreakdown, error) {
+	return tokens.ComputeFees(receipt, header)
 }
 
-func (store *ITknStore) GetAbi(addr common.Address) *abi.ABI {
-	var result *abi.ABI
+const (
+	// LedgerDebit is tokens.LedgerDebit.
+	LedgerDebit = tokens.LedgerDebit
 
-	if store.HasAbi(addr) {
-		result = store.abis[addr]
-	} else if tkn, err := store.Get(addr); err == nil {
-		if tkn.IsERC20 {
-			result = ParseABI(ALL_DEFAULT_ABIS[0])
-		} else if tkn.IsERC721 {
-			result = ParseABI(ALL_DEFAULT_ABIS[1])
-		}
-	}
+	// LedgerCredit is tokens.LedgerCredit.
+	LedgerCredit = tokens.LedgerCredit
+)
 
-	if result == nil {
-		result = MergeABIs(ALL_DEFAULT_ABIS[0], ALL_DEFAULT_ABIS[1])
-	}
+// LedgerLine is tokens.LedgerLine.
+type LedgerLine = tokens.LedgerLine
 
-	return result
-}
+// Ledger is tokens.Ledger.
+type Ledger = tokens.Ledger
 
-func (store *ITknStore) Has(address common.Address) bool {
-	return store.data[address] != nil
+// LedgerFromSummary calls tokens.LedgerFromSummary.
+func LedgerFromSummary(summary *BalanceSummary) Ledger {
+	return tokens.LedgerFromSummary(summary)
 }
 
-func (store *ITknStore) Set(nfo *ITknInfo) {
-	store.data[nfo.Address] = nfo
-}
+const (
+	// PermitEIP2612 is tokens.PermitEIP2612.
+	PermitEIP2612 = tokens.PermitEIP2612
 
-func (store *ITknStore) Get(address common.Address) (*ITknInfo, error) {
-	var result ITknInfo
+	// PermitPermit2 is tokens.PermitPermit2.
+	PermitPermit2 = tokens.PermitPermit2
+)
 
-	if store.Has(address) {
-		return store.data[address], nil
-	} else {
-		// Create a context with a timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		result = queryTokenInfo(ctx, address)
-	}
+// PermitInfo is tokens.PermitInfo.
+type PermitInfo = tokens.PermitInfo
 
-	return &result, nil
+// DecodePermits calls tokens.DecodePermits.
+func DecodePermits(contract string, data []byte) []PermitInfo {
+	return tokens.DecodePermits(contract, data)
 }
 
-func (store *ITknStore) BalanceOf(tkn common.Address, addr common.Address) (uint64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	return getERC20Balance(ctx, addr, tkn)
+// VerifyPermits calls tokens.VerifyPermits.
+func VerifyPermits(decoded *DecodedMethod, chainId *big.Int) {
+	tokens.VerifyPermits(decoded, chainId)
 }
 
-func (store *ITknStore) GetDecoder(contract common.Address) (*AbiDecoder, error) {
-	if !store.Has(contract) {
-		return nil, fmt.Errorf("can not create decoder, token not in store: %s", contract.Hex())
-	}
+const (
+	// RosettaOpCall is tokens.RosettaOpCall.
+	RosettaOpCall = tokens.RosettaOpCall
 
-	info, err := store.Get(contract)
-	if err != nil {
-		return nil, err
-	}
+	// RosettaOpFee is tokens.RosettaOpFee.
+	RosettaOpFee = tokens.RosettaOpFee
 
-	decoder := info.CreateDecoder()
-	return &decoder, err
-}
+	// RosettaOpERC20 is tokens.RosettaOpERC20.
+	RosettaOpERC20 = tokens.RosettaOpERC20
+
+	// RosettaOpERC721 is tokens.RosettaOpERC721.
+	RosettaOpERC721 = tokens.RosettaOpERC721
 
-func (tkn *ITknInfo) CreateDecoder() AbiDecoder {
-	var contractAddress string
+	// RosettaSuccess is tokens.RosettaSuccess.
+	RosettaSuccess = tokens.RosettaSuccess
 
-	if tkn.Address.Hex() != EtherAddress {
-		contractAddress = tkn.Address.Hex()
-	}
+	// RosettaFailure is tokens.RosettaFailure.
+	RosettaFailure = tokens.RosettaFailure
+)
 
-	abi := TknStore.GetAbi(tkn.Address)
+// RosettaNativeCurrency points to tokens.RosettaNativeCurrency.
+var RosettaNativeCurrency = tokens.RosettaNativeCurrency
 
-	fmt.Println("abi loaded", abi.Methods)
+// RosettaTransaction is tokens.RosettaTransaction.
+type RosettaTransaction = tokens.RosettaTransaction
 
-	return AbiDecoder{
-		ContractAddress: &contractAddress,
-		client:          TknStore.GetClient(),
-		Abi:             abi,
-	}
-}
+// RosettaTransactionIdentifier is tokens.RosettaTransactionIdentifier.
+type RosettaTransactionIdentifier = tokens.RosettaTransactionIdentifier
 
-func (tkn *ITknInfo) Query() (*ITknInfo, error) {
-	return TknStore.Get(tkn.Address)
-}
+// RosettaOperationIdentifier is tokens.RosettaOperationIdentifier.
+type RosettaOperationIdentifier = tokens.RosettaOperationIdentifier
 
-func (tkn *ITknInfo) GetName() *string {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	return getName(ctx, tkn.Address)
+// RosettaOperation is tokens.RosettaOperation.
+type RosettaOperation = tokens.RosettaOperation
+
+// RosettaAccount is tokens.RosettaAccount.
+type RosettaAccount = tokens.RosettaAccount
+
+// RosettaAmount is tokens.RosettaAmount.
+type RosettaAmount = tokens.RosettaAmount
+
+// RosettaCurrency is tokens.RosettaCurrency.
+type RosettaCurrency = tokens.RosettaCurrency
+
+// ToRosetta calls tokens.ToRosetta.
+func ToRosetta(tx *types.Transaction, receipt *types.Receipt, logs []*DecodedLog) (*RosettaTransaction, error) {
+	return tokens.ToRosetta(tx, receipt, logs)
 }
 
-func (tkn *ITknInfo) GetSymbol() *string {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	return getSymbol(ctx, tkn.Address)
+// ITknInfo is tokens.ITknInfo.
+type ITknInfo = tokens.ITknInfo
+
+// ITknStore is tokens.ITknStore.
+type ITknStore = tokens.ITknStore
+
+// TknStore points to tokens.TknStore.
+var TknStore = tokens.TknStore
+
+const (
+	// StandardERC20 is tokens.StandardERC20.
+	StandardERC20 = tokens.StandardERC20
+
+	// StandardERC721 is tokens.StandardERC721.
+	StandardERC721 = tokens.StandardERC721
+
+	// StandardERC1155 is tokens.StandardERC1155.
+	StandardERC1155 = tokens.StandardERC1155
+
+	// StandardWrapped is tokens.StandardWrapped.
+	StandardWrapped = tokens.StandardWrapped
+)
+
+// WrappedNativeTokens is tokens.WrappedNativeTokens.
+var WrappedNativeTokens = tokens.WrappedNativeTokens
+
+// TokenTransfer is tokens.TokenTransfer.
+type TokenTransfer = tokens.TokenTransfer
+
+// ExtractTransfers calls tokens.ExtractTransfers.
+func ExtractTransfers(logs []*DecodedLog) []TokenTransfer {
+	return tokens.ExtractTransfers(logs)
 }
 
-func (tkn *ITknInfo) GetDecimals() *uint8 {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	return getDecimals(ctx, tkn.Address)
+// ExtractReceiptTransfers calls tokens.ExtractReceiptTransfers.
+func ExtractReceiptTransfers(receipt *types.Receipt) []TokenTransfer {
+	return tokens.ExtractReceiptTransfers(receipt)
 }
 
-func (tkn *ITknInfo) BalanceOf(addr common.Address) (uint64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+const (
+	// LabelSwap is tokens.LabelSwap.
+	LabelSwap = tokens.LabelSwap
+
+	// LabelTransfer is tokens.LabelTransfer.
+	LabelTransfer = tokens.LabelTransfer
+
+	// LabelApproval is tokens.LabelApproval.
+	LabelApproval = tokens.LabelApproval
+
+	// LabelMint is tokens.LabelMint.
+	LabelMint = tokens.LabelMint
+
+	// LabelBurn is tokens.LabelBurn.
+	LabelBurn = tokens.LabelBurn
+
+	// LabelBridge is tokens.LabelBridge.
+	LabelBridge = tokens.LabelBridge
+
+	// LabelDeploy is tokens.LabelDeploy.
+	LabelDeploy = tokens.LabelDeploy
+
+	// LabelUnknown is tokens.LabelUnknown.
+	LabelUnknown = tokens.LabelUnknown
+)
+
+// BridgeMethods is tokens.BridgeMethods.
+var BridgeMethods = tokens.BridgeMethods
+
+// BridgeEvents is tokens.BridgeEvents.
+var BridgeEvents = tokens.BridgeEvents
+
+// Classification is tokens.Classification.
+type Classification = tokens.Classification
 
-	return getERC20Balance(ctx, addr, tkn.Address)
+// ClassifyTransaction calls tokens.ClassifyTransaction.
+func ClassifyTransaction(tx *types.Transaction, method *DecodedMethod, logs []*DecodedLog) *Classification {
+	return tokens.ClassifyTransaction(tx, method, logs)
 }
@@ -0,0 +1,9 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ALL_DEFAULT_ABIS lists the built-in ABIs in a fixed order, see DefaultABIs to select them by
+// name.
+var ALL_DEFAULT_ABIS = core.ALL_DEFAULT_ABIS
@@ -0,0 +1,13 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// MaxAmbiguityContracts is the number of contracts recorded per ambiguous selector or topic.
+var MaxAmbiguityContracts = &core.MaxAmbiguityContracts
+
+// Ambiguity is a struct for holding a selector or topic matching several signatures of the
+// ABIs of a store. Event candidates mark their indexed params, as e.g. the Transfer events of
+// ERC-20 and ERC-721 share their topic.
+type Ambiguity = core.Ambiguity
@@ -0,0 +1,13 @@
+package decode
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Call calls the read method of the contract with the global Store, see Storage.Call.
+func Call(ctx context.Context, contract common.Address, method string, args ...interface{}) (Params, error) {
+	return core.Call(ctx, contract, method, args...)
+}
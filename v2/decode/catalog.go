@@ -0,0 +1,20 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// EventEntry is a struct for holding the description of an event the decoder can decode.
+type EventEntry = core.EventEntry
+
+// MethodEntry is a struct for holding the description of a method the decoder can decode.
+type MethodEntry = core.MethodEntry
+
+// Catalog is a struct for holding all events and methods known to a Storage.
+type Catalog = core.Catalog
+
+// MethodMatch is a struct for holding a method of AbiList together with the ABI defining it.
+type MethodMatch = core.MethodMatch
+
+// EventMatch is a struct for holding an event of AbiList together with the ABI defining it.
+type EventMatch = core.EventMatch
@@ -0,0 +1,23 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ComplianceHook screens the addresses appearing in a decoded result (sender, contract, address
+// params, also inside tuples and arrays, and the nested calls). Integrators implement it to flag or block sanctioned addresses.
+type ComplianceHook = core.ComplianceHook
+
+// ScreeningResult is the verdict of a ComplianceHook. Flagged addresses are attached to the
+// decoded result, Block drops the decoded result entirely.
+type ScreeningResult = core.ScreeningResult
+
+// ListScreener is a ComplianceHook backed by a file of sanctioned addresses. The file is either a
+// JSON array of addresses or a plain list with one address per line (CSV rows use the first
+// column, lines starting with # are ignored). Watch reloads the list whenever the file changes.
+type ListScreener = core.ListScreener
+
+// NewListScreener creates a ListScreener and loads the address list from the given path.
+func NewListScreener(path string, block bool) (*ListScreener, error) {
+	return core.NewListScreener(path, block)
+}
@@ -0,0 +1,8 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// AbiDecoder is a struct used to decode contract ABIs.
+type AbiDecoder = core.AbiDecoder
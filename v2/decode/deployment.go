@@ -0,0 +1,18 @@
+package decode
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// MinimalProxyTarget returns the implementation address of EIP-1167 minimal proxy code. Both the
+// runtime code of a clone and the init code deploying it are recognized.
+func MinimalProxyTarget(code []byte) (common.Address, bool) {
+	return core.MinimalProxyTarget(code)
+}
+
+// IsDeployment returns true if the given transaction creates a contract.
+func IsDeployment(tx *types.Transaction) bool {
+	return core.IsDeployment(tx)
+}
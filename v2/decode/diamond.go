@@ -0,0 +1,17 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// FacetResolver returns the ABI of a diamond facet, e.g. from Store.Indexed or an explorer.
+type FacetResolver = core.FacetResolver
+
+// DiamondFacet is a struct for holding a facet of a diamond and the selectors routed to it.
+type DiamondFacet = core.DiamondFacet
+
+// Diamond keeps the merged ABI of an EIP-2535 diamond proxy up to date. The merged ABI holds the
+// methods routed to each facet, the events of all facets and the DiamondCut event, and is indexed
+// in the Storage under the diamond's address. It is flagged Verified once the ABIs of all facets
+// are resolved.
+type Diamond = core.Diamond
@@ -0,0 +1,27 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Instruction is a struct for holding a disassembled opcode and its push data.
+type Instruction = core.Instruction
+
+// Disassemble walks the opcodes of the hex encoded bytecode. Push data is attached to its
+// opcode, so bytes of constants are never read as opcodes.
+func Disassemble(bytecode string) ([]Instruction, error) {
+	return core.Disassemble(bytecode)
+}
+
+// ExtractSelectors returns the 4 byte function selectors pushed by the bytecode, hex encoded with
+// 0x prefix and sorted. Operands of PUSH4 which are masks (0xffffffff) or zero are skipped.
+func ExtractSelectors(bytecode string) []string {
+	return core.ExtractSelectors(bytecode)
+}
+
+// ExtractTopics returns the 32 byte words pushed by the bytecode, hex encoded with 0x prefix and
+// sorted. Solidity pushes event topics with PUSH32 before LOG, so these include all topics of the
+// events emitted, next to other 32 byte constants.
+func ExtractTopics(bytecode string) []string {
+	return core.ExtractTopics(bytecode)
+}
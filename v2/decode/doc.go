@@ -0,0 +1,6 @@
+// Package decode decodes contract calls and events of EVM chains from JSON ABIs, with AbiDecoder
+// for a single ABI and the result types shared by the other packages.
+//
+// MaxAmbiguityContracts, InterfaceThreshold, MaxNestedDepth and ResolveTimeout point to settings
+// shared by all packages of the module, changed through the pointers.
+package decode
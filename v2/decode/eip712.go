@@ -0,0 +1,54 @@
+package decode
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// TypedDataTypes holds the EIP-712 types of well-known signed payloads by their primary type:
+// EIP-2612 Permit, the Permit2 permits and Seaport orders. DecodeTypedParam decodes params holding
+// them.
+var TypedDataTypes = core.TypedDataTypes
+
+// ParseTypedData parses EIP-712 typed data in the JSON format of eth_signTypedData_v4.
+func ParseTypedData(data []byte) (*apitypes.TypedData, error) {
+	return core.ParseTypedData(data)
+}
+
+// NewTypedData returns the typed data of the message. The EIP712Domain type is derived from the
+// fields set in the domain, the types are not modified.
+func NewTypedData(types apitypes.Types, primaryType string, domain apitypes.TypedDataDomain, message apitypes.TypedDataMessage) apitypes.TypedData {
+	return core.NewTypedData(types, primaryType, domain, message)
+}
+
+// DomainSeparator returns the hash of the domain of the typed data, as returned by the
+// DOMAIN_SEPARATOR of the verifying contract.
+func DomainSeparator(typed apitypes.TypedData) (common.Hash, error) {
+	return core.DomainSeparator(typed)
+}
+
+// TypedDataDigest returns the EIP-712 digest of the typed data, the hash signed by its signer.
+func TypedDataDigest(typed apitypes.TypedData) (common.Hash, error) {
+	return core.TypedDataDigest(typed)
+}
+
+// RecoverTypedDataSigner returns the address that signed the typed data. The recovery id of a
+// 65 byte signature may be 0/1 or 27/28, 64 byte signatures are read as EIP-2098 compact
+// signatures.
+func RecoverTypedDataSigner(typed apitypes.TypedData, signature []byte) (common.Address, error) {
+	return core.RecoverTypedDataSigner(typed, signature)
+}
+
+// DecodeTypedStruct decodes the ABI encoded struct of the primary type, as passed to contracts
+// verifying the signature, into a message of the typed data. Addresses are checksummed, integers
+// decimal strings and bytes hex strings.
+func DecodeTypedStruct(types apitypes.Types, primaryType string, data []byte) (apitypes.TypedDataMessage, error) {
+	return core.DecodeTypedStruct(types, primaryType, data)
+}
+
+// DecodeTypedParam decodes a bytes param of the decoded method holding a struct of one of the
+// TypedDataTypes, see DecodeTypedStruct.
+func DecodeTypedParam(method *DecodedMethod, param string, primaryType string) (apitypes.TypedDataMessage, error) {
+	return core.DecodeTypedParam(method, param, primaryType)
+}
@@ -0,0 +1,12 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// DecodeAttempt is a struct for holding a single ABI candidate tried while decoding.
+type DecodeAttempt = core.DecodeAttempt
+
+// DecodeTrace is a struct for holding every decision taken while decoding a transaction or log,
+// in the order the decoder takes them.
+type DecodeTrace = core.DecodeTrace
@@ -0,0 +1,3 @@
+package decode
+
+import ()
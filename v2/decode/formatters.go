@@ -0,0 +1,28 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// FormatterFunc formats a decoded param value. It receives the param name and the value as
+// unpacked by the abi package (e.g. *big.Int, common.Address, [4]byte) and returns the value
+// stored in Params.
+type FormatterFunc = core.FormatterFunc
+
+// RegisterFormatter registers a formatter for all params of the given ABI type, e.g. "uint256"
+// or "bytes4". It replaces the default formatting of these params in all decoded results, except
+// for decoders preserving native types.
+func RegisterFormatter(abiType string, fn FormatterFunc) {
+	core.RegisterFormatter(abiType, fn)
+}
+
+// RegisterParamFormatter registers a formatter for all params with the given name. Name
+// formatters take precedence over type formatters.
+func RegisterParamFormatter(name string, fn FormatterFunc) {
+	core.RegisterParamFormatter(name, fn)
+}
+
+// ResetFormatters removes all registered formatters.
+func ResetFormatters() {
+	core.ResetFormatters()
+}
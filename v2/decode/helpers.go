@@ -0,0 +1,16 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// SetCodeTxType is the EIP-7702 set code transaction type. Transactions of this type are not
+// supported by the signer of the go-ethereum version this package is built with, and fail to
+// decode from RPC responses.
+const SetCodeTxType = core.SetCodeTxType
+
+// TxTypeName returns the name of the EIP-2718 transaction type, e.g. dynamicFee for EIP-1559
+// transactions, or the type in hex for unknown types.
+func TxTypeName(txType uint8) string {
+	return core.TxTypeName(txType)
+}
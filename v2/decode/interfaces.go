@@ -0,0 +1,25 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// InterfaceThreshold is the share of selectors and topics of an interface that has to be found in
+// the bytecode for the interface to be reported as supported.
+var InterfaceThreshold = &core.InterfaceThreshold
+
+// InterfaceMatch is a struct for holding the score of an interface in a bytecode.
+type InterfaceMatch = core.InterfaceMatch
+
+// InterfaceReport is a struct for holding the result of a bytecode analysis.
+type InterfaceReport = core.InterfaceReport
+
+// AnalyzeBytecode extracts the PUSH4 and PUSH32 operands of the runtime bytecode and scores the
+// known token interfaces against them. Walking the opcodes skips the push data, so selectors are
+// only found where the dispatcher pushes them and not inside unrelated constants. Constructors
+// and metadata are not stripped, their bytes may add spurious operands but never hide real ones.
+// Selectors starting with zero bytes, which solc pushes with PUSH1 to PUSH3, are matched too and
+// reported in Selectors if they belong to a known interface.
+func AnalyzeBytecode(code string) InterfaceReport {
+	return core.AnalyzeBytecode(code)
+}
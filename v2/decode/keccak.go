@@ -0,0 +1,20 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// KeccakFunc computes the keccak256 hash of the data.
+type KeccakFunc = core.KeccakFunc
+
+// SetKeccak replaces the keccak256 implementation used to compute selectors and topics, e.g. with
+// an assembly optimized one. A nil function restores the go-ethereum implementation. The memoized
+// hashes are dropped. Attestations always use the go-ethereum implementation.
+func SetKeccak(fn KeccakFunc) {
+	core.SetKeccak(fn)
+}
+
+// ResetSHA3Cache drops the memoized hashes of ToSHA3.
+func ResetSHA3Cache() {
+	core.ResetSHA3Cache()
+}
@@ -0,0 +1,18 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Labels is the global label store. Set it as Labels of a Storage or AbiDecoder to label their
+// decoded results.
+var Labels = core.Labels
+
+// LabelStore holds human-readable labels of addresses, e.g. "Binance 14". It is safe for
+// concurrent use.
+type LabelStore = core.LabelStore
+
+// NewLabelStore returns an empty LabelStore.
+func NewLabelStore() *LabelStore {
+	return core.NewLabelStore()
+}
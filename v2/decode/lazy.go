@@ -0,0 +1,13 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// LazyLog is a handle of a classified log which decodes its params on first use. The handle is
+// safe for concurrent use, the decoded result is memoized.
+type LazyLog = core.LazyLog
+
+// LazyMethod is a handle of a classified transaction which decodes its calldata on first use. The
+// handle is safe for concurrent use, the decoded result is memoized.
+type LazyMethod = core.LazyMethod
@@ -0,0 +1,16 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ContractMetadata is a struct for holding the CBOR metadata solc appends to the runtime bytecode.
+type ContractMetadata = core.ContractMetadata
+
+// ExtractMetadata parses the CBOR metadata at the end of the runtime bytecode. solc appends the
+// blob followed by its length as 2 byte big endian integer. The IPFS hash identifies the
+// metadata.json of the compilation, e.g. for a Sourcify lookup, the solc version fingerprints
+// the compiler. Unknown keys are ignored.
+func ExtractMetadata(bytecode string) (*ContractMetadata, error) {
+	return core.ExtractMetadata(bytecode)
+}
@@ -0,0 +1,8 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// MaxNestedDepth limits how deep nested calldata (multicalls inside multicalls) is decoded.
+var MaxNestedDepth = &core.MaxNestedDepth
@@ -0,0 +1,32 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ProfilerConfig configures a CallProfiler.
+type ProfilerConfig = core.ProfilerConfig
+
+// Anomaly is a struct for holding a statistical anomaly of a decoded call.
+type Anomaly = core.Anomaly
+
+// ContractProfile is a struct for holding the call statistics of a contract.
+type ContractProfile = core.ContractProfile
+
+// MethodProfile is a struct for holding the call statistics of a method.
+type MethodProfile = core.MethodProfile
+
+// ParamStats is a struct for holding the distribution of a param. Numeric params track their
+// range, address params the frequency of every address.
+type ParamStats = core.ParamStats
+
+// CallProfiler profiles the params of decoded calls per contract and reports anomalies: selectors
+// not called before and numeric params outside of their historical range. Anomalies are only
+// reported once a contract has been observed WarmUp times.
+type CallProfiler = core.CallProfiler
+
+// NewCallProfiler creates a profiler with the given configuration. An invalid configuration
+// fails with ValidationErrors.
+func NewCallProfiler(config ProfilerConfig) (*CallProfiler, error) {
+	return core.NewCallProfiler(config)
+}
@@ -0,0 +1,15 @@
+package decode
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// RecoverABI synthesizes a best-effort ABI for an unverified contract from the selectors pushed by
+// its bytecode, see ExtractSelectors. Every selector is resolved with the resolver, the embedded
+// selector database if nil, and the first signature hashing to it is kept. Params are named by
+// position. Events are left out, as the bytecode does not tell which of their params are indexed;
+// logs of the contract can still be decoded through the Resolver of the store.
+func RecoverABI(bytecode string, resolver SignatureResolver) abi.ABI {
+	return core.RecoverABI(bytecode, resolver)
+}
@@ -0,0 +1,26 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ResolveTimeout limits the duration of a single online signature lookup.
+var ResolveTimeout = &core.ResolveTimeout
+
+// SignatureResolver looks up the text signatures of unknown function selectors and event topics,
+// e.g. "transfer(address,uint256)". Several signatures are returned on selector collisions, the
+// most likely first.
+type SignatureResolver = core.SignatureResolver
+
+// OpenchainResolver resolves signatures with the openchain.xyz signature database.
+type OpenchainResolver = core.OpenchainResolver
+
+// FourByteResolver resolves signatures with the 4byte.directory database. Older entries are
+// preferred on collisions, as later ones are commonly spam.
+type FourByteResolver = core.FourByteResolver
+
+// ResetResolvedSignatures clears the cache of resolved signatures. Signatures shared through the
+// Cache are kept.
+func ResetResolvedSignatures() {
+	core.ResetResolvedSignatures()
+}
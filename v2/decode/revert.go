@@ -0,0 +1,9 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// DecodedError is a struct for holding decoded revert payloads. It implements error, so decode
+// functions can return it to surface the revert reason of a failed transaction.
+type DecodedError = core.DecodedError
@@ -0,0 +1,22 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// LookupSelector returns the signatures of the embedded database matching the 4 byte function
+// selector, e.g. "0xa9059cbb". It returns nil if the selector is unknown or the package was built
+// with the noselectordb tag.
+func LookupSelector(sigHash string) []string {
+	return core.LookupSelector(sigHash)
+}
+
+// LookupTopic returns the event signatures of the embedded database matching the topic hash. It
+// returns nil if the topic is unknown or the package was built with the noselectordb tag.
+func LookupTopic(topic string) []string {
+	return core.LookupTopic(topic)
+}
+
+// EmbeddedResolver is a SignatureResolver backed by the embedded selector database, identifying
+// common methods and events fully offline.
+type EmbeddedResolver = core.EmbeddedResolver
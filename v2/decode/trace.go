@@ -0,0 +1,8 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// DecodedCallTree is a struct for holding a decoded internal call and all of its sub calls.
+type DecodedCallTree = core.DecodedCallTree
@@ -0,0 +1,17 @@
+package decode
+
+import (
+	"context"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// DecodedTransaction is a struct for holding a fully decoded transaction: its decoded method and
+// logs, the token transfers of the receipt, the revert reason if it failed, and the block it was
+// included in.
+type DecodedTransaction = core.DecodedTransaction
+
+// DecodeTransactionFull is Store.DecodeTransactionFull.
+func DecodeTransactionFull(ctx context.Context, transactionHash string) (*DecodedTransaction, error) {
+	return core.DecodeTransactionFull(ctx, transactionHash)
+}
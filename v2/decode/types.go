@@ -0,0 +1,33 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+type Params = core.Params
+
+// AddressNames maps checksummed addresses to human-readable names, e.g. their ENS names.
+type AddressNames = core.AddressNames
+
+// ParamInfo is a struct for holding the ABI metadata of a decoded param.
+type ParamInfo = core.ParamInfo
+
+// DecodedParam is a struct for holding a decoded param together with its ABI metadata.
+type DecodedParam = core.DecodedParam
+
+type ScannedLogs = core.ScannedLogs
+
+// DecodedLog is a struct for holding decoded Ethereum logs.
+type DecodedLog = core.DecodedLog
+
+// DecodedMethod is a struct for holding decoded Ethereum methods.
+type DecodedMethod = core.DecodedMethod
+
+// TxInfo holds the fields of the transaction a method was decoded from.
+type TxInfo = core.TxInfo
+
+// DecodedDeployment is a struct for holding decoded contract-creation transactions.
+type DecodedDeployment = core.DecodedDeployment
+
+// DecodedReceipt is a struct for holding a decoded transaction receipt including its fees.
+type DecodedReceipt = core.DecodedReceipt
@@ -0,0 +1,91 @@
+package decode
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+	"github.com/w2496/go-abi-decoder/v2/rpc"
+)
+
+const (
+	EtherAddress  = core.EtherAddress
+	Zero32Bytes   = core.Zero32Bytes
+	TransferTopic = core.TransferTopic
+)
+
+func ParseABI(input string) *abi.ABI {
+	return core.ParseABI(input)
+}
+
+func ToAscii(input []byte) string {
+	return core.ToAscii(input)
+}
+
+func MergeABIs(jsonAbis ...string) *abi.ABI {
+	return core.MergeABIs(jsonAbis...)
+}
+
+// IsEIP1559 reports whether the latest block of the backend has a base fee.
+//
+// Deprecated: use ProbeChain, which detects further features of the chain and node and is cached.
+func IsEIP1559(client rpc.Backend, ctx_ context.Context) (*bool, error) {
+	return core.IsEIP1559(client, ctx_)
+}
+
+// ToSHA3 returns the 0x prefixed keccak256 hash of the string. Hashes are memoized, as the same
+// signatures are hashed over and over when indexing contracts, see SetKeccak.
+func ToSHA3(data string) string {
+	return core.ToSHA3(data)
+}
+
+// IsToken checks whether the bytecode implements any of the known token standards.
+func IsToken(bytecode string) bool {
+	return core.IsToken(bytecode)
+}
+
+func IsERC1155(bytecode string) bool {
+	return core.IsERC1155(bytecode)
+}
+
+func IsERC721(bytecode string) bool {
+	return core.IsERC721(bytecode)
+}
+
+func IsERC20(bytecode string) bool {
+	return core.IsERC20(bytecode)
+}
+
+// helper function to detect token standard.
+// results: ERC20, ERC721, ERC1155, ERC4626, ERC777, UNKNOWN
+func DetectTokenStandard(bytecode string) string {
+	return core.DetectTokenStandard(bytecode)
+}
+
+// DetectBytecodes checks if a given bytecode contains a set of signatures.
+//
+// 4 byte selectors are looked up in the PUSH1 to PUSH4 operands and 32 byte topics in the PUSH32
+// operands of the bytecode, see AnalyzeBytecode. Signatures of other lengths are searched in the raw
+// bytecode.
+//
+// Parameters:
+// - bytecode: The bytecode string to search for signatures.
+// - signatures: A list of hex signatures to check for within the bytecode.
+//
+// Returns:
+//   - true if all signatures are found, otherwise false.
+//
+// Example Usage:
+//
+//	bytecode := "0x63a9059cbb14"
+//	signatures := []string{"0xa9059cbb"}
+//	result := DetectBytecodes(bytecode, signatures)
+//	// result will be true as the selector is pushed by the dispatcher.
+func DetectBytecodes(bytecode string, signatures []string) bool {
+	return core.DetectBytecodes(bytecode, signatures)
+}
+
+func GetMinerAndNonce(block *types.Block) (string, string) {
+	return core.GetMinerAndNonce(block)
+}
@@ -0,0 +1,11 @@
+package decode
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// FieldError is a single invalid field of a configuration struct.
+type FieldError = core.FieldError
+
+// ValidationErrors aggregates all invalid fields found by a Validate method.
+type ValidationErrors = core.ValidationErrors
@@ -0,0 +1,567 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/exp/slices"
+)
+
+// Storage is a struct that holds all the ABIs and indexed contracts.
+type Storage struct {
+	AbiList []abi.ABI              // global abi storage that holds all abis from `contracts` folder
+	Indexed map[string]*IndexedABI // indexed contracts are basically not thought for this application.
+
+	// Anonymous enables a second pass over all ABIs that tries to match logs against anonymous
+	// events when no event signature matched. Matches are flagged with a confidence on DecodedLog.
+	Anonymous bool
+
+	// Compliance is an optional hook screening all addresses of decoded results.
+	Compliance ComplianceHook
+
+	// PreserveTypes keeps native Go types (*big.Int, common.Address, []byte) in the params of
+	// decoded results instead of converting them to strings.
+	PreserveTypes bool
+
+	// KeepUnknown returns calls whose selector is unknown to all ABIs as DecodedMethod flagged as
+	// Unknown, with the raw calldata, instead of nil.
+	KeepUnknown bool
+
+	// ResolveNames annotates the address params of decoded results with their ENS names, see
+	// LookupENS.
+	ResolveNames bool
+
+	// IncludeTx adds the type, recovered sender, value, nonce, gas, access list and blob hashes of
+	// the transaction to methods decoded from transactions, see TxInfo.
+	IncludeTx bool
+
+	// Labels optionally labels the contract and address params of decoded results, e.g. with the
+	// global Labels.
+	Labels *LabelStore
+
+	// Resolver is an optional online signature lookup, used when no ABI knows a selector or topic.
+	// Signatures are turned into minimal ABIs whose params are keyed by position.
+	Resolver SignatureResolver
+
+	// Attestor optionally signs all decoded results, see Attestation.
+	Attestor *Attestor
+
+	// BloomCheck skips the receipts of blocks, and the logs of receipts, whose logsBloom does not
+	// match any event of the store, see BloomMatches.
+	BloomCheck bool
+
+	// Retry is an optional retry policy of the RPC calls of the store, overriding the global one,
+	// see SetRetryPolicy.
+	Retry *RetryPolicy
+
+	names     map[int]string  // source names of the ABIs added with AddABI, keyed by AbiList index
+	ambiguity *ambiguityState // see Ambiguities, guarded by ambiguityMu
+
+	signatureIndex *signatureIndex // selectors and topics of AbiList, guarded by signatureIndexMu
+
+	client        Backend  // see SetClient, nil for the global backend
+	clientChainId *big.Int // chain reported by client, see keyChainID
+
+	mu               sync.RWMutex // guards the writes of AbiList and Indexed against Len
+	signatureIndexMu sync.RWMutex // guards signatureIndex, built on first use
+	ambiguityMu      sync.RWMutex // guards ambiguity, created on first use
+	partitionsMu     sync.Mutex   // guards partitions, see ForChain
+
+	chainId    uint64              // chain of a partition, see ForChain
+	tokens     *ITknStore          // token infos of a partition, see Tokens
+	partitions map[uint64]*Storage // partitions by chain id, guarded by partitionsMu
+}
+
+// Store is a global variable of type Storage, holding all the ABIs and indexed contracts.
+var Store = Storage{
+	AbiList: make([]abi.ABI, 0),
+	Indexed: make(map[string]*IndexedABI),
+}
+
+// IndexedAddresses returns a slice of all the addresses of indexed contracts in Store.
+func (store *Storage) IndexedAddresses() []string {
+	keys := make([]string, 0)
+
+	for k, _ := range store.Indexed {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// GetIndexed returns the IndexedABI struct for the given address if it exists in Store.
+func (store *Storage) GetIndexed(address string) *IndexedABI {
+	if store.Indexed[address] != nil {
+		return store.Indexed[address]
+	}
+	return nil
+}
+
+// SetIndexed adds the given abi to the indexed contract with the given address in Store.
+// With a block range, the abi is added as a version of the contract valid in that range, and
+// DecodeLog and DecodeMethodAt pick the version valid at the block of the log or transaction.
+// An open ended range also makes the abi the contract's current Abi.
+func (store *Storage) SetIndexed(address string, input abi.ABI, verified bool, isToken bool, bytecode *string, blockRange ...BlockRange) *IndexedABI {
+	if existing := store.GetIndexed(address); existing != nil && len(blockRange) > 0 {
+		for _, r := range blockRange {
+			existing.Versions = append(existing.Versions, AbiVersion{Range: r, Abi: input})
+			if r.To == 0 {
+				existing.Abi = input
+			}
+		}
+		return existing
+	}
+
+	result := IndexedABI{
+		Address:  common.HexToAddress(address),
+		Abi:      input,
+		Verified: verified,
+		IsToken:  isToken,
+		Bytecode: bytecode,
+		client:   store.client,
+	}
+
+	if bytecode == nil && store.GetBackend() != nil {
+		result.Bytecode = getBytecode(store.GetBackend(), common.HexToAddress(address))
+	}
+
+	if bytecode != nil {
+		report := AnalyzeBytecode(*bytecode)
+		result.IsToken = report.Standard != "UNKNOWN"
+		if result.IsToken {
+			isERC721 := report.Supports("ERC721")
+			result.IsERC721 = &isERC721
+		}
+	}
+
+	if result.Bytecode != nil {
+		result.Metadata, _ = ExtractMetadata(*result.Bytecode)
+	}
+
+	for _, r := range blockRange {
+		result.Versions = append(result.Versions, AbiVersion{Range: r, Abi: input})
+	}
+
+	store.putIndexed(address, &result)
+
+	return &result
+}
+
+// putIndexed stores the indexed contract under the address.
+func (store *Storage) putIndexed(address string, indexed *IndexedABI) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.Indexed == nil {
+		store.Indexed = make(map[string]*IndexedABI)
+	}
+	store.Indexed[address] = indexed
+}
+
+// RemoveIndexed removes the indexed contract with the given address from Store.
+func (store *Storage) RemoveIndexed(address string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.Indexed, address)
+}
+
+// Len returns the number of ABIs in AbiList and of indexed contracts. Unlike reading the fields,
+// it is safe to call concurrently with the methods adding and removing ABIs and contracts, e.g.
+// from the watchdog.
+func (store *Storage) Len() (abis int, indexed int) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return len(store.AbiList), len(store.Indexed)
+}
+
+// IsIndexed returns true if the given address exists in Store's indexed contracts.
+func (s *Storage) IsIndexed(address string) bool {
+	return slices.Contains(s.IndexedAddresses(), address)
+}
+
+// DecodeLogs decodes an array of Ethereum logs into an array of DecodedLogs using the DecodeLog function.
+// Any nil DecodedLogs are not included in the result array.
+func (store *Storage) DecodeLogs(vLogs []*types.Log) []*DecodedLog {
+	var decodedLogs []*DecodedLog
+
+	for _, log := range vLogs {
+		decoded := store.DecodeLog(log)
+		if decoded != nil {
+			decodedLogs = append(decodedLogs, decoded)
+		}
+	}
+
+	return decodedLogs
+}
+
+// DecodeLog decodes a single Ethereum log entry and returns a `DecodedLog` object that contains the
+// decoded values. This function checks if the log entry corresponds to a token transfer event and
+// if so, it determines whether it is an ERC20 or ERC721 transfer and picks the right ABI for
+// decoding the log data. If the log cannot be decoded or is not a token transfer event, it returns
+// nil. This function looks up the ABIs of Store.AbiList defining the topic of the log in an index
+// and attempts to decode the log data using each of them in turn. If the log can be decoded by any
+// ABI, it returns a `DecodedLog` object containing the decoded values. Otherwise, it returns nil.
+func (store *Storage) DecodeLog(vLog *types.Log) *DecodedLog {
+	decoded := store.decodeLog(vLog)
+	if !screenLog(store.Compliance, decoded) {
+		return nil
+	}
+
+	nameLog(store.ResolveNames, store.GetBackend(), decoded)
+	labelLog(store.Labels, decoded)
+	keyLog(store.keyChainID(), decoded)
+	attestLog(store.Attestor, decoded)
+	return decoded
+}
+
+func (store *Storage) decodeLog(vLog *types.Log) *DecodedLog {
+	// Indexed contracts are decoded with their own ABI first, the version valid at the block of the
+	// log for versioned ones, so contracts sharing topics with other ABIs are not mis-decoded.
+	if indexed := store.indexedContract(vLog.Address); indexed != nil {
+		contractAbi := indexed.AbiAt(vLog.BlockNumber)
+		abiDecoder := AbiDecoder{Abi: &contractAbi, Anonymous: store.Anonymous, PreserveTypes: store.PreserveTypes}
+		if decoded := abiDecoder.DecodeLog(vLog); decoded != nil {
+			return decoded
+		}
+	}
+
+	// Events pinned with ResolveAmbiguity take precedence over the order of AbiList.
+	if pinned := store.pinnedEventABI(vLog); pinned != nil {
+		abiDecoder := AbiDecoder{Abi: pinned, PreserveTypes: store.PreserveTypes}
+		if decoded := abiDecoder.DecodeLog(vLog); decoded != nil {
+			return decoded
+		}
+	}
+
+	// Check the ABIs defining the topic, in the order of AbiList.
+	if len(vLog.Topics) > 0 {
+		for _, contractAbi := range store.eventABIs(vLog.Topics[0]) {
+			abiDecoder := AbiDecoder{Abi: &contractAbi, PreserveTypes: store.PreserveTypes}
+			decoded := abiDecoder.DecodeLog(vLog)
+			if decoded != nil && decoded.Signature != "" {
+				store.observeAmbiguity(decoded.Topic, decoded.Contract)
+				return decoded
+			}
+		}
+	}
+
+	// Only fall back to anonymous events once no ABI matched the signature.
+	if store.Anonymous {
+		// candidates are counted across all ABIs, so the confidence reflects every match
+		if decoded := parseAnonymousLog(vLog, store.AbiList, nil, store.PreserveTypes); decoded != nil {
+			return decoded
+		}
+	}
+
+	// Finally look up the topic online, if a resolver is configured.
+	if store.Resolver != nil {
+		return resolveLog(store.Resolver, vLog, store.PreserveTypes)
+	}
+
+	return nil
+}
+
+// DecodeMethod decodes a single Ethereum transaction and returns a `DecodedMethod` object that
+// contains the decoded function signature and arguments. This function looks up the ABIs of
+// `Store.AbiList` defining the selector in an index and attempts to decode the transaction using
+// each of them in turn. If the transaction can be decoded by any ABI, it returns a `DecodedMethod`
+// object containing the decoded function signature and arguments. Otherwise, it returns nil, or an
+// Unknown result if KeepUnknown is set. Calldata nested in the params (e.g. of multicalls) is
+// decoded recursively into `Calls`.
+func (store *Storage) DecodeMethod(tx *types.Transaction) *DecodedMethod {
+	decoded := store.decodeMethod(tx, nil)
+	includeTx(store.IncludeTx, tx, decoded)
+	nameMethod(store.ResolveNames, store.GetBackend(), decoded)
+	labelMethod(store.Labels, decoded)
+	keyMethod(decoded)
+	attestMethod(store.Attestor, decoded)
+	return decoded
+}
+
+// decodeMethod decodes the method of the transaction. The sender is screened by the compliance
+// hook, recovered from the signature unless given, e.g. for L2 deposits without one.
+func (store *Storage) decodeMethod(tx *types.Transaction, from *common.Address) *DecodedMethod {
+	// Only the ABIs defining the selector are tried, in the order of AbiList.
+	abis := store.methodABIs(tx.Data())
+
+	// Indexed contracts are decoded with their own ABI first.
+	if tx.To() != nil {
+		if indexed := store.indexedContract(*tx.To()); indexed != nil {
+			abis = append([]abi.ABI{indexed.Abi}, abis...)
+		}
+	}
+
+	// Methods pinned with ResolveAmbiguity take precedence over the order of AbiList.
+	if pinned := store.pinnedMethodABI(tx.Data()); pinned != nil {
+		abis = append([]abi.ABI{*pinned}, abis...)
+	}
+
+	for _, contractAbi := range abis {
+		decoded, err := parseMethod(tx, contractAbi, nil, store.PreserveTypes)
+		if err != nil {
+			// another method sharing the selector, try the next candidate
+			continue
+		}
+		if decoded != nil {
+			store.observeAmbiguity(decoded.SigHash, decoded.Contract)
+			decodeNestedCalls(decoded, store.methodABIs, 0, store.PreserveTypes)
+			if !screenMethod(store.Compliance, tx, from, decoded) {
+				return nil
+			}
+			return decoded
+		}
+	}
+
+	if store.Resolver != nil && tx.To() != nil {
+		if decoded := resolveMethod(store.Resolver, tx.To().Hex(), tx.Data(), store.PreserveTypes); decoded != nil {
+			decoded.TransactionHash = tx.Hash().Hex()
+			decodeNestedCalls(decoded, store.methodABIs, 0, store.PreserveTypes)
+			if !screenMethod(store.Compliance, tx, from, decoded) {
+				return nil
+			}
+			return decoded
+		}
+	}
+
+	if store.KeepUnknown {
+		decoded := unknownTransaction(tx)
+		if !screenMethod(store.Compliance, tx, from, decoded) {
+			return nil
+		}
+		return decoded
+	}
+
+	return nil
+}
+
+// DecodeMethodAt is like DecodeMethod for a transaction included in the given block. Contracts
+// indexed with versions are decoded with the ABI valid at that block first.
+func (store *Storage) DecodeMethodAt(tx *types.Transaction, block uint64) *DecodedMethod {
+	if tx.To() != nil {
+		if indexed := store.indexedContract(*tx.To()); indexed != nil {
+			decoded, err := parseMethod(tx, indexed.AbiAt(block), nil, store.PreserveTypes)
+			if err == nil && decoded != nil {
+				decodeNestedCalls(decoded, store.methodABIs, 0, store.PreserveTypes)
+				if !screenMethod(store.Compliance, tx, nil, decoded) {
+					return nil
+				}
+				includeTx(store.IncludeTx, tx, decoded)
+				nameMethod(store.ResolveNames, store.GetBackend(), decoded)
+				labelMethod(store.Labels, decoded)
+				keyMethod(decoded)
+				attestMethod(store.Attestor, decoded)
+				return decoded
+			}
+		}
+	}
+
+	return store.DecodeMethod(tx)
+}
+
+// indexedContract returns the indexed contract of the address, stored under its checksummed or
+// lower case hex.
+func (store *Storage) indexedContract(address common.Address) *IndexedABI {
+	if indexed := store.GetIndexed(address.Hex()); indexed != nil {
+		return indexed
+	}
+
+	return store.GetIndexed(strings.ToLower(address.Hex()))
+}
+
+func (store *Storage) ParseAndAddABIs(abis ...string) {
+	for _, abi := range abis {
+		parsed := ParseABI(abi)
+
+		store.mu.Lock()
+		store.AbiList = append(store.AbiList, *parsed)
+		store.mu.Unlock()
+	}
+}
+
+// AddABI appends the ABI to AbiList under the given name, which is reported as source of its
+// entries by AllEvents and AllMethods.
+func (store *Storage) AddABI(name string, contractAbi abi.ABI) {
+	if store.names == nil {
+		store.names = make(map[int]string)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.names[len(store.AbiList)] = name
+	store.AbiList = append(store.AbiList, contractAbi)
+}
+
+// RemoveABIBySelector removes the method with the given 4 byte selector from all ABIs of AbiList
+// and returns the number of methods removed. ABIs left without methods, events and errors are
+// dropped. Like AddABI, it must not be called concurrently with decoding.
+func (store *Storage) RemoveABIBySelector(selector string) int {
+	id, err := hexutil.Decode(selector)
+	if err != nil || len(id) != 4 {
+		return 0
+	}
+
+	removed := 0
+	for i := range store.AbiList {
+		for name, method := range store.AbiList[i].Methods {
+			if bytes.Equal(method.ID, id) {
+				store.AbiList[i] = cloneABI(store.AbiList[i])
+				delete(store.AbiList[i].Methods, name)
+				removed++
+			}
+		}
+	}
+
+	store.dropEmpty()
+	return removed
+}
+
+// RemoveABIByTopic removes the event with the given topic from all ABIs of AbiList and returns
+// the number of events removed. ABIs left without methods, events and errors are dropped.
+func (store *Storage) RemoveABIByTopic(topic string) int {
+	id, err := hexutil.Decode(topic)
+	if err != nil || len(id) != common.HashLength {
+		return 0
+	}
+
+	removed := 0
+	for i := range store.AbiList {
+		for name, event := range store.AbiList[i].Events {
+			if event.ID == common.BytesToHash(id) {
+				store.AbiList[i] = cloneABI(store.AbiList[i])
+				delete(store.AbiList[i].Events, name)
+				removed++
+			}
+		}
+	}
+
+	store.dropEmpty()
+	return removed
+}
+
+// Retain keeps the ABIs of AbiList for which the predicate returns true, e.g. to drop governance
+// ABIs on a DEX-only indexer, and returns the number of ABIs dropped. The predicate receives the
+// source name of the ABI, see AddABI.
+func (store *Storage) Retain(keep func(source string, contractAbi abi.ABI) bool) int {
+	return store.filter(func(index int, contractAbi abi.ABI) bool {
+		return keep(store.abiName(index), contractAbi)
+	})
+}
+
+// dropEmpty drops the ABIs without methods, events and errors.
+func (store *Storage) dropEmpty() {
+	store.filter(func(index int, contractAbi abi.ABI) bool {
+		return len(contractAbi.Methods)+len(contractAbi.Events)+len(contractAbi.Errors) > 0
+	})
+}
+
+// filter keeps the ABIs for which keep returns true, moving their source names along.
+func (store *Storage) filter(keep func(index int, contractAbi abi.ABI) bool) int {
+	abis := make([]abi.ABI, 0, len(store.AbiList))
+	names := make(map[int]string)
+
+	for i, contractAbi := range store.AbiList {
+		if !keep(i, contractAbi) {
+			continue
+		}
+
+		if name, ok := store.names[i]; ok {
+			names[len(abis)] = name
+		}
+		abis = append(abis, contractAbi)
+	}
+
+	removed := len(store.AbiList) - len(abis)
+	store.mu.Lock()
+	store.AbiList = abis
+	store.names = names
+	store.mu.Unlock()
+	store.invalidateAmbiguities()
+	store.invalidateSignatures()
+
+	return removed
+}
+
+// cloneABI copies the maps of the ABI, so entries can be removed without touching ABIs shared
+// with other stores or decoders.
+func cloneABI(contractAbi abi.ABI) abi.ABI {
+	result := contractAbi
+	result.Methods = make(map[string]abi.Method, len(contractAbi.Methods))
+	result.Events = make(map[string]abi.Event, len(contractAbi.Events))
+	result.Errors = make(map[string]abi.Error, len(contractAbi.Errors))
+
+	for name, method := range contractAbi.Methods {
+		result.Methods[name] = method
+	}
+
+	for name, event := range contractAbi.Events {
+		result.Events[name] = event
+	}
+
+	for name, customError := range contractAbi.Errors {
+		result.Errors[name] = customError
+	}
+
+	return result
+}
+
+// abiName returns the source name of the ABI at the given index of AbiList.
+func (store *Storage) abiName(index int) string {
+	if name, ok := store.names[index]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("abi[%d]", index)
+}
+
+// SetClient sets the client used for all RPC calls of the store and the contracts it indexes,
+// instead of the global client. Setting the client of the global Store sets the global client, as
+// before.
+func (store *Storage) SetClient(client *ethclient.Client) {
+	store.SetBackend(asBackend(client))
+}
+
+// SetBackend sets the backend of the store, like SetClient for backends other than
+// *ethclient.Client. The token infos of partitions are queried with the same backend.
+func (store *Storage) SetBackend(backend Backend) {
+	if store == &Store {
+		SetBackend(backend)
+		return
+	}
+
+	store.client = backend
+	store.clientChainId = reportedChainID(backend)
+	if store.tokens != nil {
+		store.tokens.SetBackend(backend)
+	}
+}
+
+// GetClient returns the client of the store, the global client if none is set. It is nil if the
+// backend is no *ethclient.Client, see GetBackend.
+func (store *Storage) GetClient() *ethclient.Client {
+	return asClient(store.GetBackend())
+}
+
+// GetBackend returns the backend of the store, the global backend if none is set. It is wrapped
+// with the Retry policy of the store, or the global one.
+func (store *Storage) GetBackend() Backend {
+	if store.client != nil {
+		return retrying(store.client, store.Retry)
+	}
+
+	return retrying(Ctx.eth, store.Retry)
+}
+
+// requireClient returns the backend of the store, see requireClient.
+func (store *Storage) requireClient() (Backend, error) {
+	client, err := requireClient(store.client)
+	return retrying(client, store.Retry), err
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MaxAmbiguityContracts is the number of contracts recorded per ambiguous selector or topic.
+var MaxAmbiguityContracts = 100
+
+// Ambiguity is a struct for holding a selector or topic matching several signatures of the
+// ABIs of a store. Event candidates mark their indexed params, as e.g. the Transfer events of
+// ERC-20 and ERC-721 share their topic.
+type Ambiguity struct {
+	Kind       string   `json:"kind"`                // "function" or "event".
+	Key        string   `json:"key"`                 // Selector or topic.
+	Candidates []string `json:"candidates"`          // Signatures matching the key, sorted.
+	Pinned     string   `json:"pinned,omitempty"`    // Candidate chosen with ResolveAmbiguity.
+	Contracts  []string `json:"contracts,omitempty"` // Contracts the key was decoded for, sorted.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the Ambiguity object.
+func (data *Ambiguity) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the Ambiguity object.
+func (data *Ambiguity) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ambiguityState holds the ambiguous keys of a store, the pinned candidates and the contracts
+// observed per key.
+type ambiguityState struct {
+	size       int // length of AbiList the candidates were computed for, -1 once stale
+	kinds      map[string]string
+	candidates map[string][]string
+	pins       map[string]string
+	observed   map[string]map[string]bool
+}
+
+// Ambiguities returns the selectors and topics matching several signatures of the ABIs of the
+// store, with the contracts they were decoded for.
+func (store *Storage) Ambiguities() []Ambiguity {
+	store.ambiguityMu.Lock()
+	defer store.ambiguityMu.Unlock()
+
+	state := store.ambiguities()
+	result := make([]Ambiguity, 0, len(state.candidates))
+	for key, candidates := range state.candidates {
+		contracts := make([]string, 0, len(state.observed[key]))
+		for contract := range state.observed[key] {
+			contracts = append(contracts, contract)
+		}
+		sort.Strings(contracts)
+
+		result = append(result, Ambiguity{
+			Kind:       state.kinds[key],
+			Key:        key,
+			Candidates: append([]string{}, candidates...),
+			Pinned:     state.pins[key],
+			Contracts:  contracts,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+// ResolveAmbiguity pins the candidate signature future decodes of the selector or topic use,
+// overriding the order of AbiList. An empty signature removes the pin.
+func (store *Storage) ResolveAmbiguity(key string, chosenSignature string) error {
+	key = strings.ToLower(key)
+
+	store.ambiguityMu.Lock()
+	defer store.ambiguityMu.Unlock()
+
+	state := store.ambiguities()
+	if chosenSignature == "" {
+		delete(state.pins, key)
+		return nil
+	}
+
+	for _, candidate := range state.candidates[key] {
+		if candidate == chosenSignature {
+			state.pins[key] = chosenSignature
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is no candidate of %s, see Ambiguities", chosenSignature, key)
+}
+
+// ambiguities returns the state of the store, recomputing the candidates once AbiList changed.
+// It has to be called with the ambiguityMu of the store held.
+func (store *Storage) ambiguities() *ambiguityState {
+	if store.ambiguity == nil {
+		store.ambiguity = &ambiguityState{
+			size:     -1,
+			pins:     make(map[string]string),
+			observed: make(map[string]map[string]bool),
+		}
+	}
+
+	state := store.ambiguity
+	if state.size == len(store.AbiList) {
+		return state
+	}
+
+	all := make(map[string]map[string]bool)
+	state.kinds = make(map[string]string)
+	add := func(kind string, key string, candidate string) {
+		if all[key] == nil {
+			all[key] = make(map[string]bool)
+		}
+		all[key][candidate] = true
+		state.kinds[key] = kind
+	}
+
+	for _, contractAbi := range store.AbiList {
+		for _, method := range contractAbi.Methods {
+			add("function", hexutil.Encode(method.ID), method.Sig)
+		}
+
+		for _, event := range contractAbi.Events {
+			if !event.Anonymous {
+				add("event", strings.ToLower(event.ID.Hex()), eventCandidate(event))
+			}
+		}
+	}
+
+	state.candidates = make(map[string][]string)
+	for key, candidates := range all {
+		if len(candidates) < 2 {
+			delete(state.kinds, key)
+			continue
+		}
+
+		for candidate := range candidates {
+			state.candidates[key] = append(state.candidates[key], candidate)
+		}
+		sort.Strings(state.candidates[key])
+	}
+
+	state.size = len(store.AbiList)
+	return state
+}
+
+// invalidateAmbiguities recomputes the candidates on next use, for changes of AbiList that keep
+// its length.
+func (store *Storage) invalidateAmbiguities() {
+	store.ambiguityMu.Lock()
+	defer store.ambiguityMu.Unlock()
+
+	if store.ambiguity != nil {
+		store.ambiguity.size = -1
+	}
+}
+
+// observeAmbiguity records the contract a selector or topic was decoded for, if it is ambiguous.
+func (store *Storage) observeAmbiguity(key string, contract string) {
+	key = strings.ToLower(key)
+
+	store.ambiguityMu.RLock()
+	state := store.ambiguity
+	fresh := state != nil && state.size == len(store.AbiList)
+	seen := fresh && (state.candidates[key] == nil || state.observed[key][contract] || len(state.observed[key]) >= MaxAmbiguityContracts)
+	store.ambiguityMu.RUnlock()
+
+	if seen {
+		return
+	}
+
+	store.ambiguityMu.Lock()
+	defer store.ambiguityMu.Unlock()
+
+	state = store.ambiguities()
+	if state.candidates[key] == nil {
+		return
+	}
+
+	if state.observed[key] == nil {
+		state.observed[key] = make(map[string]bool)
+	}
+
+	if len(state.observed[key]) < MaxAmbiguityContracts {
+		state.observed[key][contract] = true
+	}
+}
+
+// pinned returns the candidate pinned for the key, if any.
+func (store *Storage) pinned(key string) string {
+	store.ambiguityMu.RLock()
+	defer store.ambiguityMu.RUnlock()
+
+	if store.ambiguity == nil {
+		return ""
+	}
+
+	return store.ambiguity.pins[strings.ToLower(key)]
+}
+
+// pinnedMethodABI returns the ABI holding the method pinned for the selector of the calldata.
+func (store *Storage) pinnedMethodABI(data []byte) *abi.ABI {
+	if len(data) < 4 {
+		return nil
+	}
+
+	signature := store.pinned(hexutil.Encode(data[:4]))
+	if signature == "" {
+		return nil
+	}
+
+	for i := range store.AbiList {
+		if method, err := store.AbiList[i].MethodById(data[:4]); err == nil && method.Sig == signature {
+			return &store.AbiList[i]
+		}
+	}
+
+	return nil
+}
+
+// pinnedEventABI returns the ABI holding the event pinned for the topic of the log.
+func (store *Storage) pinnedEventABI(vLog *types.Log) *abi.ABI {
+	if len(vLog.Topics) == 0 {
+		return nil
+	}
+
+	candidate := store.pinned(vLog.Topics[0].Hex())
+	if candidate == "" {
+		return nil
+	}
+
+	for i := range store.AbiList {
+		if event, err := store.AbiList[i].EventByID(vLog.Topics[0]); err == nil && eventCandidate(*event) == candidate {
+			return &store.AbiList[i]
+		}
+	}
+
+	return nil
+}
+
+// eventCandidate returns the signature of the event with its indexed params marked, e.g.
+// "Transfer(address indexed,address indexed,uint256)".
+func eventCandidate(event abi.Event) string {
+	types := make([]string, len(event.Inputs))
+	for i, input := range event.Inputs {
+		types[i] = input.Type.String()
+		if input.Indexed {
+			types[i] += " indexed"
+		}
+	}
+
+	return event.Name + "(" + strings.Join(types, ",") + ")"
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RoundingMode defines how amounts are rounded when they are cut to a fixed precision.
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // Round to nearest, ties to even (banker's rounding).
+	RoundHalfUp                       // Round to nearest, ties away from zero.
+	RoundTruncate                     // Cut off all digits beyond the precision (toward zero).
+)
+
+// AmountFormat configures how raw token amounts are rendered as human-readable decimals.
+// All arithmetic is done on big.Int, so formatted amounts are exact and reproducible.
+type AmountFormat struct {
+	Precision int          // Digits after the decimal point, negative keeps the full precision.
+	Rounding  RoundingMode // Rounding applied when digits are cut off.
+}
+
+// DefaultAmountFormat is used wherever human-readable amounts are produced, e.g. ledger exports.
+var DefaultAmountFormat = AmountFormat{Precision: -1, Rounding: RoundHalfEven}
+
+// FormatAmount renders the raw amount with the given token decimals using DefaultAmountFormat.
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	return DefaultAmountFormat.Format(amount, decimals)
+}
+
+// Format renders the raw amount, given in the smallest unit of a token with the given decimals,
+// as decimal string. With full precision trailing zeros are removed, with a fixed precision the
+// result always has exactly Precision fractional digits.
+func (format AmountFormat) Format(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return ""
+	}
+
+	value := new(big.Int).Abs(amount)
+	scale := int(decimals)
+
+	if format.Precision >= 0 && format.Precision < scale {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale-format.Precision)), nil)
+		quotient, remainder := new(big.Int).QuoRem(value, divisor, new(big.Int))
+		value = roundQuotient(quotient, remainder, divisor, format.Rounding)
+		scale = format.Precision
+	}
+
+	digits := value.String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	integer := digits[:len(digits)-scale]
+	fraction := digits[len(digits)-scale:]
+
+	if format.Precision < 0 {
+		fraction = strings.TrimRight(fraction, "0")
+	} else if format.Precision > scale {
+		fraction += strings.Repeat("0", format.Precision-scale)
+	}
+
+	result := integer
+	if fraction != "" {
+		result += "." + fraction
+	}
+
+	if amount.Sign() < 0 && value.Sign() != 0 {
+		result = "-" + result
+	}
+
+	return result
+}
+
+// roundQuotient applies the rounding mode to the quotient of a division based on its remainder.
+func roundQuotient(quotient, remainder, divisor *big.Int, mode RoundingMode) *big.Int {
+	if mode == RoundTruncate || remainder.Sign() == 0 {
+		return quotient
+	}
+
+	cmp := new(big.Int).Lsh(remainder, 1).Cmp(divisor)
+	roundUp := cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quotient.Bit(0) == 1))
+
+	if roundUp {
+		quotient.Add(quotient, common.Big1)
+	}
+
+	return quotient
+}
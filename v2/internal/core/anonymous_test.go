@@ -0,0 +1,52 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDecodeAnonymousLog(t *testing.T) {
+	decoder := AbiDecoder{
+		Abi: ParseABI(`[{"anonymous":true,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Locked","type":"event"}]`),
+	}
+
+	owner := common.HexToAddress(target_contract)
+	vLog := &types.Log{
+		Topics: []common.Hash{common.BytesToHash(owner.Bytes())},
+		Data:   common.LeftPadBytes(big.NewInt(42).Bytes(), 32),
+	}
+
+	if decoded := decoder.DecodeLog(vLog); decoded != nil {
+		t.Fatalf("anonymous log decoded without opt-in: %v", decoded.ToJSON())
+	}
+
+	decoder.Anonymous = true
+	decoded := decoder.DecodeLog(vLog)
+	if decoded == nil {
+		t.Fatal("anonymous log not decoded")
+	}
+
+	if !decoded.Anonymous || decoded.Confidence != 1 {
+		t.Fatalf("invalid anonymous flags: %v", decoded.ToJSON())
+	}
+
+	if decoded.Params["owner"] != owner.Hex() || decoded.Params["amount"] != "42" {
+		t.Fatalf("invalid anonymous params: %v", decoded.GetParamsJSON())
+	}
+
+	// candidates of all stored ABIs count, an event defined twice counts once
+	store := &Storage{Anonymous: true}
+	store.ParseAndAddABIs(
+		`[{"anonymous":true,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Locked","type":"event"}]`,
+		`[{"anonymous":true,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Locked","type":"event"}]`,
+		`[{"anonymous":true,"inputs":[{"indexed":true,"name":"account","type":"address"},{"indexed":false,"name":"shares","type":"uint256"}],"name":"Staked","type":"event"}]`,
+	)
+
+	decoded = store.DecodeLog(vLog)
+	if decoded == nil || decoded.Name != "Locked" || decoded.Confidence != 0.5 {
+		t.Fatalf("invalid anonymous confidence across abis: %v", decoded)
+	}
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Attestation is a struct for holding the signature of a decoded result, attesting which signer
+// and ABI set produced it.
+type Attestation struct {
+	Signer        string `json:"signer"`        // Address of the signing key.
+	AbiSet        string `json:"abiSet"`        // Fingerprint of the ABIs used, see AbiSetHash.
+	SchemaVersion int    `json:"schemaVersion"` // SchemaVersion of the signed result.
+	Digest        string `json:"digest"`        // Keccak256 hash of the signed payload.
+	Signature     string `json:"signature"`     // 65 byte secp256k1 signature of the digest.
+}
+
+// Attestor signs the canonical JSON of decoded results with a secp256k1 key, so downstream
+// consumers can verify their provenance and integrity with VerifyMethod and VerifyLog. The
+// Attestation and RequestID fields are not part of the signed payload.
+type Attestor struct {
+	AbiSet string // Fingerprint of the ABI set, e.g. the AbiSetHash of the Storage in use.
+
+	key *ecdsa.PrivateKey
+}
+
+// NewAttestor creates an attestor for the hex encoded private key.
+func NewAttestor(hexKey string, abiSet string) (*Attestor, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestation key: %w", err)
+	}
+
+	return &Attestor{AbiSet: abiSet, key: key}, nil
+}
+
+// Address returns the signer address of the attestor.
+func (a *Attestor) Address() common.Address {
+	return crypto.PubkeyToAddress(a.key.PublicKey)
+}
+
+// AttestMethod signs the decoded method and sets its Attestation.
+func (a *Attestor) AttestMethod(decoded *DecodedMethod) error {
+	attestation, err := a.sign("method", a.AbiSet, canonicalMethod(decoded))
+	if err != nil {
+		return err
+	}
+
+	decoded.Attestation = attestation
+	return nil
+}
+
+// AttestLog signs the decoded log and sets its Attestation.
+func (a *Attestor) AttestLog(decoded *DecodedLog) error {
+	attestation, err := a.sign("log", a.AbiSet, canonicalLog(decoded))
+	if err != nil {
+		return err
+	}
+
+	decoded.Attestation = attestation
+	return nil
+}
+
+// VerifyMethod checks the attestation of the decoded method. If trusted signers are given, the
+// signer has to be one of them.
+func VerifyMethod(decoded *DecodedMethod, trusted ...common.Address) error {
+	return verifyAttestation("method", decoded.Attestation, canonicalMethod(decoded), trusted)
+}
+
+// VerifyLog checks the attestation of the decoded log. If trusted signers are given, the signer
+// has to be one of them.
+func VerifyLog(decoded *DecodedLog, trusted ...common.Address) error {
+	return verifyAttestation("log", decoded.Attestation, canonicalLog(decoded), trusted)
+}
+
+// AbiSetHash returns a fingerprint of the methods and events of all ABIs in AbiList, independent
+// of their order.
+func (store *Storage) AbiSetHash() string {
+	var signatures []string
+	for _, contractAbi := range store.AbiList {
+		for _, method := range contractAbi.Methods {
+			signatures = append(signatures, "function "+method.Sig)
+		}
+		for _, event := range contractAbi.Events {
+			signatures = append(signatures, "event "+event.Sig)
+		}
+	}
+	sort.Strings(signatures)
+
+	return crypto.Keccak256Hash([]byte(strings.Join(signatures, "\n"))).Hex()
+}
+
+// attestMethod attests the method if an attestor is configured. Signing only fails for invalid
+// keys, which NewAttestor rejects, so results are left unattested on errors.
+func attestMethod(attestor *Attestor, decoded *DecodedMethod) {
+	if attestor != nil && decoded != nil {
+		attestor.AttestMethod(decoded)
+	}
+}
+
+// attestLog attests the log if an attestor is configured.
+func attestLog(attestor *Attestor, decoded *DecodedLog) {
+	if attestor != nil && decoded != nil {
+		attestor.AttestLog(decoded)
+	}
+}
+
+func (a *Attestor) sign(kind string, abiSet string, result interface{}) (*Attestation, error) {
+	digest, err := attestationDigest(kind, abiSet, SchemaVersion, result)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := crypto.Sign(digest, a.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attestation{
+		Signer:        a.Address().Hex(),
+		AbiSet:        abiSet,
+		SchemaVersion: SchemaVersion,
+		Digest:        hexutil.Encode(digest),
+		Signature:     hexutil.Encode(signature),
+	}, nil
+}
+
+func verifyAttestation(kind string, attestation *Attestation, result interface{}, trusted []common.Address) error {
+	if attestation == nil {
+		return fmt.Errorf("result is not attested")
+	}
+
+	digest, err := attestationDigest(kind, attestation.AbiSet, attestation.SchemaVersion, result)
+	if err != nil {
+		return err
+	}
+
+	if hexutil.Encode(digest) != attestation.Digest {
+		return fmt.Errorf("attestation digest mismatch: result was modified")
+	}
+
+	signature, err := hexutil.Decode(attestation.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid attestation signature: %w", err)
+	}
+
+	key, err := crypto.SigToPub(digest, signature)
+	if err != nil {
+		return fmt.Errorf("invalid attestation signature: %w", err)
+	}
+
+	signer := crypto.PubkeyToAddress(*key)
+	if signer.Hex() != attestation.Signer {
+		return fmt.Errorf("attestation signed by %s, not %s", signer.Hex(), attestation.Signer)
+	}
+
+	if len(trusted) == 0 {
+		return nil
+	}
+
+	for _, address := range trusted {
+		if address == signer {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("attestation signer %s is not trusted", signer.Hex())
+}
+
+// attestationDigest hashes the canonical JSON of the result together with its kind, ABI set and
+// schema version.
+func attestationDigest(kind string, abiSet string, schemaVersion int, result interface{}) ([]byte, error) {
+	payload, err := json.Marshal(struct {
+		Kind          string      `json:"kind"`
+		AbiSet        string      `json:"abiSet"`
+		SchemaVersion int         `json:"schemaVersion"`
+		Result        interface{} `json:"result"`
+	}{kind, abiSet, schemaVersion, result})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode attested result: %w", err)
+	}
+
+	return crypto.Keccak256(payload), nil
+}
+
+// canonicalMethod returns a copy of the method and its nested calls without the fields excluded
+// from attestations.
+func canonicalMethod(decoded *DecodedMethod) *DecodedMethod {
+	result := *decoded
+	result.Attestation = nil
+	result.RequestID = ""
+
+	if decoded.Calls != nil {
+		result.Calls = make([]*DecodedMethod, len(decoded.Calls))
+		for i, call := range decoded.Calls {
+			result.Calls[i] = canonicalMethod(call)
+		}
+	}
+
+	return &result
+}
+
+// canonicalLog returns a copy of the log without the fields excluded from attestations.
+func canonicalLog(decoded *DecodedLog) *DecodedLog {
+	result := *decoded
+	result.Attestation = nil
+	result.RequestID = ""
+	return &result
+}
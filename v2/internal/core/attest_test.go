@@ -0,0 +1,50 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestAttestation(t *testing.T) {
+	store := Storage{}
+	store.ParseAndAddABIs(abi_erc20)
+
+	attestor, err := NewAttestor("0x"+strings.Repeat("11", 32), store.AbiSetHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Attestor = attestor
+
+	data, _ := ParseABI(abi_erc20).Pack("transfer", common.HexToAddress(target_contract), big.NewInt(5))
+	tx := types.NewTransaction(0, common.HexToAddress(target_erc20), big.NewInt(0), 0, big.NewInt(0), data)
+
+	decoded := store.DecodeMethodContext(WithRequestID(context.Background(), "req-1"), tx)
+	if decoded == nil || decoded.Attestation == nil || decoded.Attestation.AbiSet != store.AbiSetHash() {
+		t.Fatalf("result not attested: %v", decoded)
+	}
+
+	// attestations survive the JSON round trip of sinks and APIs
+	var received DecodedMethod
+	if err := json.Unmarshal(decoded.ToJSONBytes(), &received); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyMethod(&received, attestor.Address()); err != nil {
+		t.Fatalf("valid attestation rejected: %v", err)
+	}
+
+	if err := VerifyMethod(&received, common.HexToAddress(target_contract)); err == nil {
+		t.Fatal("untrusted signer accepted")
+	}
+
+	received.Params["_value"] = "6"
+	if err := VerifyMethod(&received); err == nil || !strings.Contains(err.Error(), "modified") {
+		t.Fatalf("modified result accepted: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Backend is the chain access of decoders and stores. *ethclient.Client implements it, as do the
+// simulated backend of go-ethereum, recorded fixtures or custom RPC wrappers. Backends exposing
+// the JSON-RPC client with Client() *rpc.Client, like *ethclient.Client, get batched lookups,
+// call traces and the L1 fees of OP-stack receipts; the lookups fall back to single calls for
+// other backends.
+type Backend interface {
+	bind.ContractBackend
+	ethereum.ChainReader
+	ethereum.TransactionReader
+}
+
+// unsupportedError is returned for calls the backend does not implement.
+type unsupportedError string
+
+func (e unsupportedError) Error() string {
+	return string(e)
+}
+
+// rpcBackend is implemented by backends exposing their JSON-RPC client.
+type rpcBackend interface {
+	Client() *rpc.Client
+}
+
+// asBackend returns the client as Backend, nil for a nil client.
+func asBackend(client *ethclient.Client) Backend {
+	if client == nil {
+		return nil
+	}
+
+	return client
+}
+
+// asClient returns the backend as *ethclient.Client, nil for other backends. Backends wrapped with
+// a retry policy are unwrapped.
+func asClient(backend Backend) *ethclient.Client {
+	if retry, ok := backend.(*retryBackend); ok {
+		backend = retry.Backend
+	}
+
+	client, _ := backend.(*ethclient.Client)
+	return client
+}
+
+// rpcClient returns the JSON-RPC client of the backend, nil if it exposes none.
+func rpcClient(backend Backend) *rpc.Client {
+	if b, ok := backend.(rpcBackend); ok {
+		return b.Client()
+	}
+
+	return nil
+}
+
+// latestBlockNumber returns the number of the latest block, read from its header for backends
+// without eth_blockNumber.
+func latestBlockNumber(ctx context.Context, backend Backend) (uint64, error) {
+	if reader, ok := backend.(interface {
+		BlockNumber(context.Context) (uint64, error)
+	}); ok {
+		return reader.BlockNumber(ctx)
+	}
+
+	header, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Number.Uint64(), nil
+}
+
+// backendChainID returns the chain id of backends supporting eth_chainId.
+func backendChainID(ctx context.Context, backend Backend) (*big.Int, error) {
+	if reader, ok := backend.(interface {
+		ChainID(context.Context) (*big.Int, error)
+	}); ok {
+		return reader.ChainID(ctx)
+	}
+
+	return nil, unsupportedError("backend does not report its chain id")
+}
+
+// transactionSender returns the sender the node reports for the transaction, for backends
+// supporting it.
+func transactionSender(ctx context.Context, backend Backend, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	if client, ok := backend.(interface {
+		TransactionSender(context.Context, *types.Transaction, common.Hash, uint) (common.Address, error)
+	}); ok {
+		return client.TransactionSender(ctx, tx, block, index)
+	}
+
+	return common.Address{}, unsupportedError("backend does not report transaction senders")
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fixtureBackend serves a test chain without JSON-RPC, like recorded fixtures. Methods not
+// overridden panic on the nil embedded Backend.
+type fixtureBackend struct {
+	Backend
+	chain *testChain
+	txs   map[common.Hash]*types.Transaction
+}
+
+func newFixtureBackend(t *testing.T, blocks uint64) *fixtureBackend {
+	backend := &fixtureBackend{chain: newTestChain(t, blocks), txs: make(map[common.Hash]*types.Transaction)}
+	for number := uint64(1); number <= blocks; number++ {
+		tx := backend.chain.blocks[number]["transactions"].([]*types.Transaction)[0]
+		backend.txs[tx.Hash()] = tx
+	}
+
+	return backend
+}
+
+func (b *fixtureBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if tx := b.txs[hash]; tx != nil {
+		return tx, false, nil
+	}
+
+	return nil, false, ethereum.NotFound
+}
+
+func (b *fixtureBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if receipt := b.chain.receipts[hash]; receipt != nil {
+		return receipt, nil
+	}
+
+	return nil, ethereum.NotFound
+}
+
+func (b *fixtureBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return b.chain.headers[uint64(len(b.chain.headers))], nil
+	}
+
+	if header := b.chain.headers[number.Uint64()]; header != nil {
+		return header, nil
+	}
+
+	return nil, ethereum.NotFound
+}
+
+func (b *fixtureBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	logs := make([]types.Log, 0)
+	for _, receipt := range b.chain.receipts {
+		for _, vLog := range receipt.Logs {
+			if vLog.BlockNumber >= query.FromBlock.Uint64() && vLog.BlockNumber <= query.ToBlock.Uint64() {
+				logs = append(logs, *vLog)
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+func TestBackend(t *testing.T) {
+	backend := newFixtureBackend(t, 3)
+
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	hashes := make([]string, 0)
+	for number := uint64(1); number <= 3; number++ {
+		hashes = append(hashes, backend.chain.blocks[number]["transactions"].([]*types.Transaction)[0].Hash().Hex())
+	}
+
+	decoder := &AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoder.SetBackend(backend)
+	if decoder.GetClient() != nil || decoder.GetBackend() != backend {
+		t.Fatal("backend not set")
+	}
+
+	logs, err := decoder.DecodeReceipt(hashes[0])
+	if err != nil || len(*logs) != 1 || (*logs)[0].Name != "Transfer" {
+		t.Fatalf("receipt not decoded with the backend: %v %v", logs, err)
+	}
+
+	// lookups batched for JSON-RPC clients are done one by one
+	store := &Storage{}
+	store.SetBackend(backend)
+	store.ParseAndAddABIs(abi_erc20)
+
+	decoded, err := store.DecodeTransactions(context.Background(), hashes)
+	if err != nil || len(decoded) != 3 || decoded[2].Method == nil || len(decoded[2].Logs) != 1 {
+		t.Fatalf("transactions not decoded with the backend: %+v %v", decoded, err)
+	}
+
+	// the latest block is read from its header
+	var scanned []uint64
+	err = store.ScanLogs(context.Background(), ethereum.FilterQuery{FromBlock: big.NewInt(2)}, nil, func(decoded *DecodedLog) error {
+		scanned = append(scanned, decoded.BlockNumber)
+		return nil
+	})
+	if err != nil || len(scanned) != 2 || scanned[0] != 2 || scanned[1] != 3 {
+		t.Fatalf("invalid scan: %v %v", scanned, err)
+	}
+
+	if _, err := store.TraceTransaction(context.Background(), hashes[0]); err == nil {
+		t.Fatal("trace without JSON-RPC not reported")
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Kinds of asset movements summarized by SummarizeBalanceChanges.
+const (
+	MovementNative = "native"
+	MovementFee    = "fee"
+	MovementERC20  = "erc20"
+	MovementERC721 = "erc721"
+)
+
+// FeeAccount is the counterparty used for paid transaction fees.
+var FeeAccount = "fees"
+
+// AssetMovement is a single transfer of value between two accounts within a transaction.
+type AssetMovement struct {
+	Kind     string   `json:"kind"`               // One of the Movement* kinds.
+	Asset    string   `json:"asset"`              // Token contract, EtherAddress for the native currency.
+	From     string   `json:"from"`               // Account the value is taken from.
+	To       string   `json:"to"`                 // Account the value is credited to.
+	Amount   *big.Int `json:"amount"`             // Amount in the smallest unit, 1 for NFTs.
+	TokenId  *big.Int `json:"tokenId,omitempty"`  // Token id of NFT movements.
+	LogIndex *uint    `json:"logIndex,omitempty"` // Index of the log the movement was decoded from.
+}
+
+// BalanceSummary holds all asset movements of a transaction and the resulting net balance change
+// per account and asset.
+type BalanceSummary struct {
+	TransactionHash string                         `json:"transactionHash"`
+	Sender          string                         `json:"sender"`
+	Failed          bool                           `json:"failed"`
+	Movements       []AssetMovement                `json:"movements"`
+	Changes         map[string]map[string]*big.Int `json:"changes"` // account -> asset -> delta
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the BalanceSummary object.
+func (data *BalanceSummary) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the BalanceSummary object.
+func (data *BalanceSummary) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// SummarizeBalanceChanges collects the native value transfer, the paid fee (if a receipt is given)
+// and all decoded Transfer logs of a transaction into asset movements and net balance changes.
+// Failed transactions only contain the fee movement.
+func SummarizeBalanceChanges(tx *types.Transaction, receipt *types.Receipt, logs []*DecodedLog) (*BalanceSummary, error) {
+	sender, err := txSender(tx)
+	if err != nil {
+		return nil, fmt.Errorf("can not recover transaction sender: %w", err)
+	}
+
+	result := BalanceSummary{
+		TransactionHash: tx.Hash().Hex(),
+		Sender:          sender.Hex(),
+		Failed:          receipt != nil && receipt.Status == types.ReceiptStatusFailed,
+		Movements:       make([]AssetMovement, 0),
+		Changes:         make(map[string]map[string]*big.Int),
+	}
+
+	if !result.Failed && tx.Value() != nil && tx.Value().Sign() > 0 {
+		to := EtherAddress
+		if tx.To() != nil {
+			to = tx.To().Hex()
+		}
+
+		result.add(AssetMovement{
+			Kind: MovementNative, Asset: EtherAddress,
+			From: sender.Hex(), To: to, Amount: new(big.Int).Set(tx.Value()),
+		})
+	}
+
+	if receipt != nil && receipt.EffectiveGasPrice != nil {
+		result.add(AssetMovement{
+			Kind: MovementFee, Asset: EtherAddress, From: sender.Hex(), To: FeeAccount,
+			Amount: new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)),
+		})
+	}
+
+	// reverted transactions do not move any tokens
+	if result.Failed {
+		return &result, nil
+	}
+
+	for _, decoded := range logs {
+		if movement := transferMovement(decoded); movement != nil {
+			result.add(*movement)
+		}
+	}
+
+	return &result, nil
+}
+
+// add appends the movement and applies it to the net balance changes.
+func (data *BalanceSummary) add(movement AssetMovement) {
+	data.Movements = append(data.Movements, movement)
+	data.change(movement.From, movement.Asset, new(big.Int).Neg(movement.Amount))
+	data.change(movement.To, movement.Asset, movement.Amount)
+}
+
+func (data *BalanceSummary) change(account string, asset string, delta *big.Int) {
+	if data.Changes[account] == nil {
+		data.Changes[account] = make(map[string]*big.Int)
+	}
+
+	if data.Changes[account][asset] == nil {
+		data.Changes[account][asset] = new(big.Int)
+	}
+
+	data.Changes[account][asset].Add(data.Changes[account][asset], delta)
+}
+
+// TransferFromLog converts a decoded ERC20 or ERC721 Transfer log into an asset movement. It
+// returns nil for other logs.
+func TransferFromLog(decoded *DecodedLog) *AssetMovement {
+	return transferMovement(decoded)
+}
+
+// transferMovement converts a decoded ERC20 or ERC721 Transfer log into an asset movement.
+func transferMovement(decoded *DecodedLog) *AssetMovement {
+	if decoded == nil || decoded.Topic != TransferTopic {
+		return nil
+	}
+
+	from, okFrom := parseAddress(decoded.Params["from"])
+	to, okTo := parseAddress(decoded.Params["to"])
+	if !okFrom || !okTo {
+		return nil
+	}
+
+	logIndex := decoded.LogIndex
+	movement := AssetMovement{
+		Asset:    common.HexToAddress(decoded.Contract).Hex(),
+		From:     from.Hex(),
+		To:       to.Hex(),
+		LogIndex: &logIndex,
+	}
+
+	if value, ok := parseAmount(decoded.Params["value"]); ok {
+		movement.Kind = MovementERC20
+		movement.Amount = value
+	} else if tokenId, ok := parseAmount(decoded.Params["tokenId"]); ok {
+		movement.Kind = MovementERC721
+		movement.Amount = big.NewInt(1)
+		movement.TokenId = tokenId
+	} else {
+		return nil
+	}
+
+	return &movement
+}
+
+// parseAmount reads a formatted decimal string or *big.Int param as big.Int.
+func parseAddress(value interface{}) (common.Address, bool) {
+	switch value := value.(type) {
+	case common.Address:
+		return value, true
+	case string:
+		return common.HexToAddress(value), common.IsHexAddress(value)
+	}
+
+	return common.Address{}, false
+}
+
+func parseAmount(value interface{}) (*big.Int, bool) {
+	switch value := value.(type) {
+	case *big.Int:
+		return new(big.Int).Set(value), true
+	case string:
+		return new(big.Int).SetString(value, 10)
+	}
+
+	return nil, false
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSummarizeBalanceChanges(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress(target_contract)
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{
+		To:       &to,
+		Value:    big.NewInt(500),
+		Gas:      21000,
+		GasPrice: big.NewInt(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(2),
+	}
+
+	logs := []*DecodedLog{{
+		Contract: target_erc20,
+		Topic:    TransferTopic,
+		LogIndex: 3,
+		Params:   Params{"from": sender.Hex(), "to": to.Hex(), "value": "1000"},
+	}}
+
+	summary, err := SummarizeBalanceChanges(tx, receipt, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Movements) != 3 {
+		t.Fatalf("invalid movements: %v", summary.ToJSON())
+	}
+
+	if delta := summary.Changes[sender.Hex()][EtherAddress]; delta.Cmp(big.NewInt(-42500)) != 0 {
+		t.Fatalf("invalid native balance change of sender: %v", delta)
+	}
+
+	if delta := summary.Changes[to.Hex()][common.HexToAddress(target_erc20).Hex()]; delta.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("invalid token balance change of receiver: %v", delta)
+	}
+
+	ledger := LedgerFromSummary(summary)
+	if len(ledger) != 6 {
+		t.Fatalf("invalid ledger lines: %v", ledger.ToJSON())
+	}
+
+	var out bytes.Buffer
+	if err := ledger.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Count(out.String(), "\n"); lines != 7 {
+		t.Fatalf("invalid csv rows: %v", lines)
+	}
+
+	rosetta, err := ToRosetta(tx, receipt, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rosetta.Operations) != 5 || rosetta.Operations[1].RelatedOperations[0].Index != 0 {
+		t.Fatalf("invalid rosetta operations: %v", rosetta.ToJSON())
+	}
+}
+
+func TestComputeFees(t *testing.T) {
+	receipt := &types.Receipt{GasUsed: 100, EffectiveGasPrice: big.NewInt(12)}
+	header := &types.Header{BaseFee: big.NewInt(10), Coinbase: common.HexToAddress(target_contract)}
+
+	fees, err := ComputeFees(receipt, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fees.Burned.Int64() != 1000 || fees.Tip.Int64() != 200 || fees.Total.Int64() != 1200 {
+		t.Fatalf("invalid fee breakdown: burned %v, tip %v, total %v", fees.Burned, fees.Tip, fees.Total)
+	}
+
+	fees.AddL1Fees(&L1Fees{L1Fee: (*hexutil.Big)(big.NewInt(300))})
+	if fees.Total.Int64() != 1500 {
+		t.Fatalf("l1 data fee not added to total: %v", fees.Total)
+	}
+
+	legacy, _ := ComputeFees(receipt, &types.Header{})
+	if legacy.Burned.Sign() != 0 || legacy.Tip.Int64() != 1200 {
+		t.Fatalf("invalid legacy fee breakdown: burned %v, tip %v", legacy.Burned, legacy.Tip)
+	}
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchSize is the number of transaction, receipt and header lookups sent in a single batch
+// request by DecodeBlock, SubscribeBlocks, DecodeTransactions, DecodeReceipts and the reorg checks
+// of scan sessions.
+var BatchSize = 100
+
+// batchCall sends the calls in batches of BatchSize with the JSON-RPC client of the backend,
+// batches failing with transport errors are retried with its retry policy. Only transport errors
+// are returned, the errors of single calls are left in the elements.
+func batchCall(ctx context.Context, backend Backend, batch []rpc.BatchElem) error {
+	client, policy := rpcClient(backend), retryPolicyOf(backend)
+
+	size := BatchSize
+	if size <= 0 {
+		size = 100
+	}
+
+	for start := 0; start < len(batch); start += size {
+		end := start + size
+		if end > len(batch) {
+			end = len(batch)
+		}
+
+		err := policy.do(ctx, func() error {
+			return client.BatchCallContext(ctx, batch[start:end])
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchTransactions returns the transactions of the hashes, requested in batches. Backends without
+// a JSON-RPC client are asked one by one.
+func fetchTransactions(ctx context.Context, client Backend, hashes []common.Hash) ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, len(hashes))
+	if rpcClient(client) == nil {
+		for i, hash := range hashes {
+			tx, _, err := client.TransactionByHash(ctx, hash)
+			if err != nil {
+				return nil, fmt.Errorf("error getting transaction %s: %w", hash.Hex(), err)
+			}
+			txs[i] = tx
+		}
+
+		return txs, nil
+	}
+
+	raws := make([]json.RawMessage, len(hashes))
+	batch := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		batch[i] = rpc.BatchElem{Method: "eth_getTransactionByHash", Args: []interface{}{hash}, Result: &raws[i]}
+	}
+
+	if err := batchCall(ctx, client, batch); err != nil {
+		return nil, fmt.Errorf("error getting transactions: %w", err)
+	}
+
+	for i, hash := range hashes {
+		if batch[i].Error != nil {
+			return nil, fmt.Errorf("error getting transaction %s: %w", hash.Hex(), batch[i].Error)
+		}
+
+		tx, err := unmarshalTransaction(raws[i])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding transaction %s: %w", hash.Hex(), err)
+		}
+		if txs[i] = tx; tx == nil {
+			return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+		}
+	}
+
+	return txs, nil
+}
+
+// fetchReceipts returns the receipts of the transaction hashes, requested in batches. Backends
+// without a JSON-RPC client are asked one by one.
+func fetchReceipts(ctx context.Context, client Backend, hashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(hashes))
+	if rpcClient(client) == nil {
+		for i, hash := range hashes {
+			receipt, err := client.TransactionReceipt(ctx, hash)
+			if err != nil {
+				return nil, fmt.Errorf("error getting receipt of %s: %w", hash.Hex(), err)
+			}
+			receipts[i] = receipt
+		}
+
+		return receipts, nil
+	}
+
+	batch := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		batch[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{hash}, Result: &receipts[i]}
+	}
+
+	if err := batchCall(ctx, client, batch); err != nil {
+		return nil, fmt.Errorf("error getting receipts: %w", err)
+	}
+
+	for i, hash := range hashes {
+		if batch[i].Error != nil {
+			return nil, fmt.Errorf("error getting receipt of %s: %w", hash.Hex(), batch[i].Error)
+		}
+		if receipts[i] == nil {
+			return nil, fmt.Errorf("receipt of %s not found", hash.Hex())
+		}
+	}
+
+	return receipts, nil
+}
+
+// fetchHeaders returns the headers of the block numbers, requested in batches. Headers of blocks
+// the node does not know are nil. Backends without a JSON-RPC client are asked one by one.
+func fetchHeaders(ctx context.Context, client Backend, numbers []uint64) ([]*types.Header, error) {
+	headers := make([]*types.Header, len(numbers))
+	if rpcClient(client) == nil {
+		for i, number := range numbers {
+			header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+			if err != nil && !errors.Is(err, ethereum.NotFound) {
+				return nil, fmt.Errorf("error getting block %d: %w", number, err)
+			}
+			headers[i] = header
+		}
+
+		return headers, nil
+	}
+
+	batch := make([]rpc.BatchElem, len(numbers))
+	for i, number := range numbers {
+		batch[i] = rpc.BatchElem{Method: "eth_getBlockByNumber", Args: []interface{}{hexutil.EncodeUint64(number), false}, Result: &headers[i]}
+	}
+
+	if err := batchCall(ctx, client, batch); err != nil {
+		return nil, fmt.Errorf("error getting blocks: %w", err)
+	}
+
+	for i, number := range numbers {
+		if batch[i].Error != nil {
+			return nil, fmt.Errorf("error getting block %d: %w", number, batch[i].Error)
+		}
+	}
+
+	return headers, nil
+}
+
+// DecodeTransactions fetches the transactions and their receipts in batches of BatchSize and
+// decodes the methods and logs against the store, in the order of the hashes. Index is the
+// position of a transaction in its block.
+func (store *Storage) DecodeTransactions(ctx context.Context, transactionHashes []string) ([]DecodedBlockTransaction, error) {
+	client, err := store.requireClient()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]common.Hash, len(transactionHashes))
+	for i, hash := range transactionHashes {
+		hashes[i] = common.HexToHash(hash)
+	}
+
+	txs, err := fetchTransactions(ctx, client, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := fetchReceipts(ctx, client, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DecodedBlockTransaction, len(txs))
+	for i, tx := range txs {
+		receipt := receipts[i]
+		result[i] = store.decodeBlockTransaction(ctx, tx, receipt.TransactionIndex, receipt)
+		if result[i].From == "" {
+			if from, err := transactionSender(ctx, client, tx, receipt.BlockHash, receipt.TransactionIndex); err == nil {
+				result[i].From = from.Hex()
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// DecodeReceipts is the bulk variant of DecodeReceipt: the receipts are fetched in batches of
+// BatchSize and their logs decoded, in the order of the hashes. Failed transactions are not
+// replayed, their (empty) list of logs is returned; use DecodeReceipt for their revert reason.
+func (decoder *AbiDecoder) DecodeReceipts(transactionHashes []string) ([]ScannedLogs, error) {
+	if decoder.client == nil && Ctx.eth == nil {
+		return nil, fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", decoder.ContractAddress)
+	}
+
+	hashes := make([]common.Hash, len(transactionHashes))
+	for i, hash := range transactionHashes {
+		hashes[i] = common.HexToHash(hash)
+	}
+
+	receipts, err := fetchReceipts(context.Background(), decoder.GetBackend(), hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ScannedLogs, len(receipts))
+	for i, receipt := range receipts {
+		result[i] = make(ScannedLogs, 0, len(receipt.Logs))
+		for _, log := range receipt.Logs {
+			if decoded := decoder.DecodeLog(log); decoded != nil {
+				result[i] = append(result[i], *decoded)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestDecodeTransactions(t *testing.T) {
+	chain := newTestChain(t, 3)
+
+	txs := make(map[common.Hash]*types.Transaction)
+	hashes := make([]string, 0)
+	for number := uint64(1); number <= 3; number++ {
+		tx := chain.blocks[number]["transactions"].([]*types.Transaction)[0]
+		txs[tx.Hash()] = tx
+		hashes = append(hashes, tx.Hash().Hex())
+	}
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	lookup := func(found func(common.Hash) interface{}) mockrpc.Handler {
+		return func(params json.RawMessage) (interface{}, error) {
+			var args []common.Hash
+			if err := json.Unmarshal(params, &args); err != nil {
+				return nil, err
+			}
+			return found(args[0]), nil
+		}
+	}
+	server.Handle("eth_getTransactionByHash", lookup(func(hash common.Hash) interface{} { return txs[hash] }))
+	server.Handle("eth_getTransactionReceipt", lookup(func(hash common.Hash) interface{} { return chain.receipts[hash] }))
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eth := Ctx.eth
+	Ctx.eth = client
+	defer func() { Ctx.eth = eth }()
+
+	size := BatchSize
+	BatchSize = 2
+	defer func() { BatchSize = size }()
+
+	store := &Storage{}
+	store.ParseAndAddABIs(abi_erc20)
+
+	decoded, err := store.DecodeTransactions(context.Background(), hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != 3 || len(server.Calls("eth_getTransactionByHash")) != 3 {
+		t.Fatalf("invalid transactions: %+v", decoded)
+	}
+
+	sender := crypto.PubkeyToAddress(chain.key.PublicKey).Hex()
+	for i, tx := range decoded {
+		if tx.Hash != hashes[i] || tx.From != sender || tx.Method == nil || tx.Method.Name != "transfer" || len(tx.Logs) != 1 {
+			t.Fatalf("invalid transaction %d: %+v", i, tx)
+		}
+	}
+
+	decoder := &AbiDecoder{}
+	decoder.SetABI(*ParseABI(abi_erc20))
+
+	receipts, err := decoder.DecodeReceipts(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(receipts) != 3 || len(receipts[2]) != 1 || receipts[2][0].Params["value"] != "1000" {
+		t.Fatalf("invalid receipts: %+v", receipts)
+	}
+
+	// an unknown transaction fails the whole lookup
+	if _, err := store.DecodeTransactions(context.Background(), append(hashes, common.Hash{}.Hex())); err == nil {
+		t.Fatal("missing transaction not reported")
+	}
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// unmarshalTransaction decodes a transaction of an RPC response, nil for JSON null. Blob
+// transactions are accepted with the maxFeePerBlobGas field returned by nodes since Cancun, the
+// go-ethereum version this package is built with only knows its draft name maxFeePerDataGas.
+func unmarshalTransaction(raw json.RawMessage) (*types.Transaction, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	if bytes.Contains(raw, []byte(`"maxFeePerBlobGas"`)) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		if _, ok := fields["maxFeePerDataGas"]; !ok {
+			fields["maxFeePerDataGas"] = fields["maxFeePerBlobGas"]
+			raw, _ = json.Marshal(fields)
+		}
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalJSON(raw); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestBlobTransactions(t *testing.T) {
+	chain := newTestChain(t, 1)
+
+	token := common.HexToAddress(target_erc20)
+	data, _ := ParseABI(abi_erc20).Pack("transfer", common.HexToAddress(target_contract), big.NewInt(7))
+	blobHash := common.HexToHash("0x01b0761f87b081d5cf10757ccc89f12be355c70e2e29df288b65b30710dcbcd1")
+
+	// blob transactions as returned by nodes since Cancun
+	raw, _ := json.Marshal(map[string]interface{}{
+		"type":                 "0x3",
+		"chainId":              "0x1",
+		"nonce":                "0x2",
+		"to":                   token,
+		"gas":                  "0xea60",
+		"maxPriorityFeePerGas": "0x1",
+		"maxFeePerGas":         "0x1e",
+		"maxFeePerBlobGas":     "0x5",
+		"value":                "0x0",
+		"input":                hexutil.Encode(data),
+		"accessList":           []interface{}{},
+		"blobVersionedHashes":  []common.Hash{blobHash},
+		"v":                    "0x0",
+		"r":                    "0x0",
+		"s":                    "0x0",
+	})
+
+	blobTx, err := unmarshalTransaction(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), IncludeTx: true}
+	method := decoder.DecodeMethod(blobTx)
+	if method == nil || method.Params["value"] != "7" {
+		t.Fatal("blob transaction not decoded")
+	}
+
+	info := method.Tx
+	if info.TypeName != "blob" || info.BlobFeeCap != "5" || info.BlobGas != 1<<17 || len(info.BlobHashes) != 1 || info.BlobHashes[0] != blobHash {
+		t.Fatalf("unexpected blob fields: %+v", info)
+	}
+
+	// blocks with transactions of unknown types are decoded without them
+	legacyTx := chain.blocks[1]["transactions"].([]*types.Transaction)[0]
+	unknown := common.HexToHash("0xdead")
+
+	block := make(map[string]interface{})
+	for key, value := range chain.blocks[1] {
+		block[key] = value
+	}
+	block["transactions"] = []interface{}{legacyTx, json.RawMessage(raw), map[string]interface{}{"type": "0x4", "hash": unknown}}
+	block["blobGasUsed"] = "0x20000"
+	block["excessBlobGas"] = "0x40000"
+
+	chain.receipts[blobTx.Hash()] = &types.Receipt{
+		Type:        types.BlobTxType,
+		Status:      types.ReceiptStatusSuccessful,
+		GasUsed:     40000,
+		TxHash:      blobTx.Hash(),
+		BlockHash:   chain.headers[1].Hash(),
+		BlockNumber: big.NewInt(1),
+		Logs:        []*types.Log{},
+	}
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.Respond("eth_getBlockByNumber", block)
+	server.Handle("eth_getTransactionReceipt", func(params json.RawMessage) (interface{}, error) {
+		var args []common.Hash
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		return chain.receipts[args[0]], nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &Storage{}
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+
+	decoded, err := store.DecodeBlock(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Transactions) != 2 || len(decoded.Unsupported) != 1 || decoded.Unsupported[0] != unknown.Hex() {
+		t.Fatalf("unexpected transactions: %s", decoded.ToJSON())
+	}
+	if decoded.BlobGasUsed != 1<<17 || decoded.ExcessBlobGas != 1<<18 {
+		t.Fatalf("unexpected blob gas of the block: %s", decoded.ToJSON())
+	}
+
+	tx := decoded.Transactions[1]
+	if tx.Index != 1 || tx.Type != types.BlobTxType || tx.BlobGas != 1<<17 || len(tx.BlobHashes) != 1 || tx.Method == nil || tx.GasUsed != 40000 {
+		t.Fatalf("unexpected blob transaction: %s", decoded.ToJSON())
+	}
+}
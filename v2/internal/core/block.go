@@ -0,0 +1,392 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DecodedBlock is a struct for holding the decoded transactions of a block.
+type DecodedBlock struct {
+	Number       uint64                    `json:"number"`       // Block number.
+	Hash         string                    `json:"hash"`         // Block hash.
+	ParentHash   string                    `json:"parentHash"`   // Hash of the parent block.
+	Timestamp    uint64                    `json:"timestamp"`    // Block timestamp in seconds.
+	Transactions []DecodedBlockTransaction `json:"transactions"` // Transactions in block order.
+
+	// BlobGasUsed and ExcessBlobGas are the EIP-4844 blob gas fields of blocks since Cancun.
+	BlobGasUsed   uint64 `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas uint64 `json:"excessBlobGas,omitempty"`
+
+	// Unsupported holds the hashes of transactions whose type can not be decoded, e.g. types
+	// introduced after the go-ethereum version of this package. They are left out of Transactions.
+	Unsupported []string `json:"unsupported,omitempty"`
+
+	// Skipped is set if the receipts were not fetched with BloomCheck, as the logsBloom of the
+	// block matched no event of the store. The transactions then have no status and gas used.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedBlock object.
+func (data *DecodedBlock) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedBlock object.
+func (data *DecodedBlock) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// DecodedBlockTransaction is a struct for holding a transaction of a DecodedBlock with its
+// decoded method and logs.
+type DecodedBlockTransaction struct {
+	Hash       string         `json:"hash"`                          // Transaction hash.
+	Index      uint           `json:"index"`                         // Index of the transaction in the block.
+	Type       uint8          `json:"type"`                          // EIP-2718 transaction type, see TxTypeName.
+	From       string         `json:"from"`                          // Recovered sender.
+	To         string         `json:"to,omitempty"`                  // Recipient, empty for deployments.
+	Value      string         `json:"value"`                         // Transferred value in wei.
+	Status     uint64         `json:"status"`                        // Execution status, 1 for success.
+	GasUsed    uint64         `json:"gasUsed"`                       // Gas used by the transaction.
+	BlobGas    uint64         `json:"blobGas,omitempty"`             // Blob gas used by the blobs of blob transactions.
+	BlobHashes []common.Hash  `json:"blobVersionedHashes,omitempty"` // Versioned hashes of the blobs of blob transactions.
+	System     bool           `json:"system,omitempty"`              // Whether the L2 protocol inserted the transaction, e.g. L1 attributes deposits.
+	Deposit    bool           `json:"deposit,omitempty"`             // Whether the transaction was submitted on L1, e.g. OP-stack deposits.
+	Method     *DecodedMethod `json:"method,omitempty"`              // Decoded calldata, nil for plain transfers and deployments.
+	Logs       ScannedLogs    `json:"logs"`                          // Decoded logs of the receipt.
+}
+
+// DecodeBlock fetches the block, nil for the latest one, and the receipts of its transactions in
+// batches of BatchSize, and decodes the methods and logs of all transactions against the
+// store, grouped by transaction.
+func (store *Storage) DecodeBlock(ctx context.Context, blockNumber *big.Int) (*DecodedBlock, error) {
+	client, err := store.requireClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return store.decodeBlock(ctx, client, blockNumber)
+}
+
+// SubscribeBlocks is Store.SubscribeBlocks with the default options.
+func SubscribeBlocks(ctx context.Context) (<-chan DecodedBlock, error) {
+	return Store.SubscribeBlocks(ctx, nil)
+}
+
+// SubscribeBlocks subscribes to new heads and decodes every block with its receipts against the
+// store. The channel is closed once the context is done. Blocks skipped between two heads, e.g.
+// while reconnecting, are decoded as well; a head at or below the last decoded block, as after a
+// reorg, is decoded again. The Dial, Buffer and reconnect settings of the options apply.
+func (store *Storage) SubscribeBlocks(ctx context.Context, options *SubscribeOptions) (<-chan DecodedBlock, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := &blockSubscription{store: store, options: options.withDefaults()}
+
+	// the first subscription fails synchronously, later ones are retried
+	client, sub, heads, err := s.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan DecodedBlock, s.options.Buffer)
+	go s.run(ctx, client, sub, heads, out)
+
+	return out, nil
+}
+
+type blockSubscription struct {
+	store   *Storage
+	options SubscribeOptions
+
+	last    uint64 // number of the last decoded block
+	started bool
+}
+
+func (s *blockSubscription) subscribe(ctx context.Context) (*ethclient.Client, ethereum.Subscription, chan *types.Header, error) {
+	client, err := s.options.Dial(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error connecting for block subscription: %w", err)
+	}
+
+	heads := make(chan *types.Header, s.options.Buffer)
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		closeDialed(client)
+		return nil, nil, nil, fmt.Errorf("error subscribing to new heads: %w", err)
+	}
+
+	return client, sub, heads, nil
+}
+
+func (s *blockSubscription) run(ctx context.Context, client *ethclient.Client, sub ethereum.Subscription, heads chan *types.Header, out chan<- DecodedBlock) {
+	defer close(out)
+
+	for {
+		err := s.pump(ctx, client, sub, heads, out)
+		sub.Unsubscribe()
+		closeDialed(client)
+		if ctx.Err() != nil {
+			return
+		}
+
+		reconnected := s.options.reconnect(ctx, err, func() (err error) {
+			client, sub, heads, err = s.subscribe(ctx)
+			return err
+		})
+		if !reconnected {
+			return
+		}
+	}
+}
+
+// pump decodes the blocks up to every new head until the subscription fails.
+func (s *blockSubscription) pump(ctx context.Context, client *ethclient.Client, sub ethereum.Subscription, heads chan *types.Header, out chan<- DecodedBlock) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			if err == nil {
+				err = fmt.Errorf("block subscription closed")
+			}
+			return err
+		case head := <-heads:
+			number := head.Number.Uint64()
+			from := number
+			if s.started && s.last+1 < number {
+				from = s.last + 1
+			}
+
+			for block := from; block <= number; block++ {
+				decoded, err := s.store.decodeBlock(ctx, client, new(big.Int).SetUint64(block))
+				if err != nil {
+					return err
+				}
+
+				select {
+				case out <- *decoded:
+				case <-ctx.Done():
+					return nil
+				}
+				s.last, s.started = block, true
+			}
+		}
+	}
+}
+
+// decodeBlock fetches the block and the receipts of its transactions and decodes them.
+func (store *Storage) decodeBlock(ctx context.Context, client Backend, number *big.Int) (*DecodedBlock, error) {
+	block, err := fetchBlock(ctx, client, number)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DecodedBlock{
+		Number:        block.header.Number.Uint64(),
+		Hash:          block.hash.Hex(),
+		ParentHash:    block.header.ParentHash.Hex(),
+		Timestamp:     block.header.Time,
+		Transactions:  make([]DecodedBlockTransaction, 0, len(block.txs)),
+		BlobGasUsed:   block.blobGasUsed,
+		ExcessBlobGas: block.excessBlobGas,
+		Unsupported:   block.unsupported,
+	}
+
+	hashes := make([]common.Hash, len(block.txs))
+	for i, entry := range block.txs {
+		hashes[i] = entry.hash()
+	}
+
+	receipts := make([]*types.Receipt, len(block.txs))
+	if store.BloomCheck && !BloomMatches(block.header.Bloom, store) {
+		result.Skipped = true
+	} else if receipts, err = fetchReceipts(ctx, client, hashes); err != nil {
+		return nil, err
+	}
+
+	for i, entry := range block.txs {
+		if entry.l2 != nil {
+			result.Transactions = append(result.Transactions, store.decodeL2Transaction(ctx, entry.l2, entry.index, receipts[i]))
+			continue
+		}
+
+		decoded := store.decodeBlockTransaction(ctx, entry.tx, entry.index, receipts[i])
+		if decoded.From == "" {
+			// e.g. transaction types unknown to the signer, the node reports the sender
+			if from, err := transactionSender(ctx, client, entry.tx, block.hash, entry.index); err == nil {
+				decoded.From = from.Hex()
+			}
+		}
+
+		result.Transactions = append(result.Transactions, decoded)
+	}
+
+	return result, nil
+}
+
+// fetchedBlock is a block with the transactions that could be decoded.
+type fetchedBlock struct {
+	header        *types.Header
+	hash          common.Hash
+	txs           []blockTransaction // decoded transactions in block order
+	unsupported   []string           // hashes of the transactions that could not be decoded
+	blobGasUsed   uint64
+	excessBlobGas uint64
+}
+
+// blockTransaction is a transaction of a fetched block, either one go-ethereum can represent or an
+// L2 transaction.
+type blockTransaction struct {
+	index uint // position in the block
+	tx    *types.Transaction
+	l2    *l2Transaction
+}
+
+func (t blockTransaction) hash() common.Hash {
+	if t.l2 != nil {
+		return t.l2.Hash
+	}
+
+	return t.tx.Hash()
+}
+
+// rpcBlock is the body of an eth_getBlockByNumber response with full transactions.
+type rpcBlock struct {
+	Hash          common.Hash       `json:"hash"`
+	Transactions  []json.RawMessage `json:"transactions"`
+	BlobGasUsed   *hexutil.Uint64   `json:"blobGasUsed"`
+	ExcessBlobGas *hexutil.Uint64   `json:"excessBlobGas"`
+}
+
+// fetchBlock returns the block of the number, nil for the latest one. The transactions are decoded
+// one by one from the JSON-RPC response, so a transaction of a type unknown to go-ethereum is
+// reported as unsupported instead of failing the whole block; blob transactions are accepted with
+// the field names of current nodes, and the L2 transaction types of OP-stack chains and Arbitrum
+// from the fields reported by the node. Backends without a JSON-RPC client return the block of
+// BlockByNumber.
+func fetchBlock(ctx context.Context, client Backend, number *big.Int) (*fetchedBlock, error) {
+	if rpcClient(client) == nil {
+		block, err := client.BlockByNumber(ctx, number)
+		if err != nil {
+			return nil, fmt.Errorf("error getting block %v: %w", number, err)
+		}
+
+		result := &fetchedBlock{header: block.Header(), hash: block.Hash()}
+		for i, tx := range block.Transactions() {
+			result.txs = append(result.txs, blockTransaction{index: uint(i), tx: tx})
+		}
+
+		return result, nil
+	}
+
+	tag := "latest"
+	if number != nil {
+		tag = rpc.BlockNumber(number.Int64()).String()
+	}
+
+	var raw json.RawMessage
+	if err := callContext(ctx, client, &raw, "eth_getBlockByNumber", tag, true); err != nil {
+		return nil, fmt.Errorf("error getting block %v: %w", number, err)
+	}
+
+	var header *types.Header
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("error decoding block %v: %w", number, err)
+	}
+	if header == nil {
+		return nil, fmt.Errorf("error getting block %v: %w", number, ethereum.NotFound)
+	}
+
+	var body rpcBlock
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("error decoding block %v: %w", number, err)
+	}
+
+	// the hash reported by the node, headers of later forks hash differently in go-ethereum v1.12
+	result := &fetchedBlock{header: header, hash: body.Hash}
+	if body.BlobGasUsed != nil {
+		result.blobGasUsed = uint64(*body.BlobGasUsed)
+	}
+	if body.ExcessBlobGas != nil {
+		result.excessBlobGas = uint64(*body.ExcessBlobGas)
+	}
+
+	for i, rawTx := range body.Transactions {
+		if l2, ok := unmarshalL2Transaction(rawTx); ok {
+			result.txs = append(result.txs, blockTransaction{index: uint(i), l2: l2})
+			continue
+		}
+
+		tx, err := unmarshalTransaction(rawTx)
+		if err != nil || tx == nil {
+			var ref struct {
+				Hash common.Hash `json:"hash"`
+			}
+			json.Unmarshal(rawTx, &ref)
+			result.unsupported = append(result.unsupported, ref.Hash.Hex())
+			continue
+		}
+
+		result.txs = append(result.txs, blockTransaction{index: uint(i), tx: tx})
+	}
+
+	return result, nil
+}
+
+// decodeBlockTransaction decodes the method and the receipt logs of a transaction. The receipt is
+// nil for blocks skipped by the bloom check.
+func (store *Storage) decodeBlockTransaction(ctx context.Context, tx *types.Transaction, index uint, receipt *types.Receipt) DecodedBlockTransaction {
+	result := DecodedBlockTransaction{
+		Hash:       tx.Hash().Hex(),
+		Index:      index,
+		Type:       tx.Type(),
+		Value:      tx.Value().String(),
+		BlobGas:    tx.BlobGas(),
+		BlobHashes: tx.BlobHashes(),
+		Logs:       make(ScannedLogs, 0),
+	}
+
+	if from, err := txSender(tx); err == nil {
+		result.From = from.Hex()
+	}
+
+	if tx.To() != nil {
+		result.To = tx.To().Hex()
+		if len(tx.Data()) >= 4 {
+			result.Method = store.DecodeMethodContext(ctx, tx)
+		}
+	}
+
+	store.decodeReceiptLogs(ctx, &result, receipt)
+	return result
+}
+
+// decodeReceiptLogs sets the status and gas used of the receipt on the transaction and decodes its
+// logs. The receipt is nil for blocks skipped by the bloom check.
+func (store *Storage) decodeReceiptLogs(ctx context.Context, result *DecodedBlockTransaction, receipt *types.Receipt) {
+	if receipt == nil {
+		return
+	}
+
+	result.Status, result.GasUsed = receipt.Status, receipt.GasUsed
+	if store.BloomCheck && !BloomMatches(receipt.Bloom, store) {
+		return
+	}
+
+	for _, vLog := range receipt.Logs {
+		if decoded := store.DecodeLogContext(ctx, vLog); decoded != nil {
+			result.Logs = append(result.Logs, *decoded)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package core
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+// testChain builds blocks holding a single ERC-20 transfer each.
+type testChain struct {
+	key      *ecdsa.PrivateKey
+	receipts map[common.Hash]*types.Receipt
+	blocks   map[uint64]map[string]interface{}
+	headers  map[uint64]*types.Header
+}
+
+func newTestChain(t *testing.T, blocks uint64) *testChain {
+	key, _ := crypto.GenerateKey()
+	chain := &testChain{
+		key:      key,
+		receipts: make(map[common.Hash]*types.Receipt),
+		blocks:   make(map[uint64]map[string]interface{}),
+		headers:  make(map[uint64]*types.Header),
+	}
+
+	token := common.HexToAddress(target_erc20)
+	recipient := common.HexToAddress(target_contract)
+	data, err := ParseABI(abi_erc20).Pack("transfer", recipient, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for number := uint64(1); number <= blocks; number++ {
+		tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{
+			Nonce:    number,
+			To:       &token,
+			Gas:      60000,
+			GasPrice: big.NewInt(1),
+			Data:     data,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		receipt := &types.Receipt{
+			Status:  types.ReceiptStatusSuccessful,
+			GasUsed: 51000,
+			TxHash:  tx.Hash(),
+			Logs: []*types.Log{{
+				Address:     token,
+				Topics:      []common.Hash{common.HexToHash(TransferTopic), common.BytesToHash(crypto.PubkeyToAddress(key.PublicKey).Bytes()), common.BytesToHash(recipient.Bytes())},
+				Data:        common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+				BlockNumber: number,
+				TxHash:      tx.Hash(),
+			}},
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		header := &types.Header{
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(0),
+			Time:       1000 + number,
+			TxHash:     tx.Hash(),
+			UncleHash:  types.EmptyUncleHash,
+			Bloom:      receipt.Bloom,
+		}
+		if parent := chain.headers[number-1]; parent != nil {
+			header.ParentHash = parent.Hash()
+		}
+		chain.headers[number] = header
+
+		receipt.BlockHash, receipt.BlockNumber = header.Hash(), header.Number
+		receipt.Logs[0].BlockHash = header.Hash()
+		chain.receipts[tx.Hash()] = receipt
+
+		// the block JSON is the header with the full transactions
+		encoded, _ := json.Marshal(header)
+		block := make(map[string]interface{})
+		json.Unmarshal(encoded, &block)
+		block["transactions"] = []*types.Transaction{tx}
+		block["uncles"] = []common.Hash{}
+		chain.blocks[number] = block
+	}
+
+	return chain
+}
+
+// chainService serves a test chain with new head subscriptions.
+type chainService struct {
+	chain *testChain
+	heads chan *types.Header
+}
+
+func (s *chainService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+
+	go func() {
+		for {
+			select {
+			case head := <-s.heads:
+				notifier.Notify(sub.ID, head)
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+func (s *chainService) GetBlockByNumber(ctx context.Context, number string, full bool) (map[string]interface{}, error) {
+	n, _ := new(big.Int).SetString(strings.TrimPrefix(number, "0x"), 16)
+	return s.chain.blocks[n.Uint64()], nil
+}
+
+func (s *chainService) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return s.chain.receipts[hash], nil
+}
+
+func TestSubscribeBlocks(t *testing.T) {
+	chain := newTestChain(t, 3)
+	service := &chainService{chain: chain, heads: make(chan *types.Header, 4)}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	store := &Storage{}
+	store.ParseAndAddABIs(abi_erc20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blocks, err := store.SubscribeBlocks(ctx, &SubscribeOptions{
+		Dial: func(ctx context.Context) (*ethclient.Client, error) {
+			return ethclient.DialContext(ctx, url)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the head of block 2 is skipped, it is decoded before block 3
+	service.heads <- chain.headers[1]
+	service.heads <- chain.headers[3]
+
+	sender := crypto.PubkeyToAddress(chain.key.PublicKey).Hex()
+	for number := uint64(1); number <= 3; number++ {
+		var block DecodedBlock
+		select {
+		case block = <-blocks:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no block received")
+		}
+
+		if block.Number != number || block.Hash != chain.headers[number].Hash().Hex() || len(block.Transactions) != 1 {
+			t.Fatalf("invalid block %d: %s", number, block.ToJSON())
+		}
+
+		tx := block.Transactions[0]
+		if tx.From != sender || tx.Status != 1 || tx.GasUsed != 51000 {
+			t.Fatalf("invalid transaction: %+v", tx)
+		}
+
+		if tx.Method == nil || tx.Method.Name != "transfer" || len(tx.Logs) != 1 || tx.Logs[0].Name != "Transfer" {
+			t.Fatalf("transaction not decoded: %s", block.ToJSON())
+		}
+	}
+
+	cancel()
+	for range blocks {
+	}
+}
+
+func TestDecodeBlock(t *testing.T) {
+	chain := newTestChain(t, 2)
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.Handle("eth_getBlockByNumber", func(params json.RawMessage) (interface{}, error) {
+		var args []interface{}
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		number, _ := hexutil.DecodeUint64(args[0].(string))
+		return chain.blocks[number], nil
+	})
+	server.Handle("eth_getTransactionReceipt", func(params json.RawMessage) (interface{}, error) {
+		var args []common.Hash
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		return chain.receipts[args[0]], nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the store uses its own client, not the global one
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	store := &Storage{}
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+
+	block, err := store.DecodeBlock(context.Background(), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if block.Number != 2 || block.ParentHash != chain.headers[1].Hash().Hex() || len(block.Transactions) != 1 {
+		t.Fatalf("invalid block: %s", block.ToJSON())
+	}
+
+	tx := block.Transactions[0]
+	if tx.From != crypto.PubkeyToAddress(chain.key.PublicKey).Hex() || tx.To != common.HexToAddress(target_erc20).Hex() || tx.Method.Params["value"] != "1000" {
+		t.Fatalf("invalid transaction: %s", block.ToJSON())
+	}
+
+	if len(tx.Logs) != 1 || tx.Logs[0].Params["value"] != "1000" {
+		t.Fatalf("invalid logs: %s", block.ToJSON())
+	}
+
+	// a receipt the node does not know fails the block
+	server.Respond("eth_getTransactionReceipt", nil)
+	if _, err := store.DecodeBlock(context.Background(), big.NewInt(1)); err == nil {
+		t.Fatal("missing receipt not reported")
+	}
+}
+
+func TestDecodeBlockMalformedTransaction(t *testing.T) {
+	chain := newTestChain(t, 1)
+
+	// replace the transfer by one with truncated arguments
+	token := common.HexToAddress(target_erc20)
+	block := chain.blocks[1]
+	original := block["transactions"].([]*types.Transaction)[0]
+	tx, err := types.SignNewTx(chain.key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{
+		Nonce:    1,
+		To:       &token,
+		Gas:      60000,
+		GasPrice: big.NewInt(1),
+		Data:     original.Data()[:20],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	block["transactions"] = []*types.Transaction{tx}
+	chain.receipts[tx.Hash()] = chain.receipts[original.Hash()]
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+	server.Respond("eth_getBlockByNumber", block)
+	server.Respond("eth_getTransactionReceipt", chain.receipts[tx.Hash()])
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &Storage{}
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+
+	decoded, err := store.DecodeBlock(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Transactions) != 1 || decoded.Transactions[0].Method != nil || len(decoded.Transactions[0].Logs) != 1 {
+		t.Fatalf("unexpected block: %s", decoded.ToJSON())
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BloomMatches returns true if the logs bloom of a block or receipt may contain logs the store can
+// decode: the topic of an event of the ABIs in AbiList or the address of an indexed contract. A
+// false result is definite, a true one may be a false positive of the bloom filter. Stores
+// decoding anonymous events or resolving unknown topics online can decode any log, so the bloom
+// always matches for them.
+func BloomMatches(bloom types.Bloom, store *Storage) bool {
+	if store.Anonymous || store.Resolver != nil {
+		return true
+	}
+
+	for _, contractAbi := range store.AbiList {
+		for _, event := range contractAbi.Events {
+			if !event.Anonymous && types.BloomLookup(bloom, event.ID) {
+				return true
+			}
+		}
+	}
+
+	for address := range store.Indexed {
+		if types.BloomLookup(bloom, common.HexToAddress(address)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestBloomMatches(t *testing.T) {
+	chain := newTestChain(t, 1)
+	bloom := chain.headers[1].Bloom
+
+	tokens := &Storage{}
+	tokens.ParseAndAddABIs(abi_erc20)
+	if !BloomMatches(bloom, tokens) {
+		t.Fatal("transfer topic not matched")
+	}
+
+	diamonds := &Storage{Indexed: make(map[string]*IndexedABI)}
+	diamonds.ParseAndAddABIs(abi_diamond)
+	if BloomMatches(bloom, diamonds) {
+		t.Fatal("unrelated events matched")
+	}
+
+	// indexed contracts match by address
+	diamonds.Indexed[target_erc20] = &IndexedABI{Address: common.HexToAddress(target_erc20)}
+	if !BloomMatches(bloom, diamonds) {
+		t.Fatal("indexed address not matched")
+	}
+
+	if BloomMatches(types.Bloom{}, tokens) {
+		t.Fatal("empty bloom matched")
+	}
+}
+
+func TestDecodeBlockBloomCheck(t *testing.T) {
+	chain := newTestChain(t, 1)
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.Handle("eth_getBlockByNumber", func(params json.RawMessage) (interface{}, error) {
+		var args []interface{}
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		number, _ := hexutil.DecodeUint64(args[0].(string))
+		return chain.blocks[number], nil
+	})
+	server.Handle("eth_getTransactionReceipt", func(params json.RawMessage) (interface{}, error) {
+		var args []common.Hash
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		return chain.receipts[args[0]], nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eth := Ctx.eth
+	Ctx.eth = client
+	defer func() { Ctx.eth = eth }()
+
+	store := &Storage{BloomCheck: true}
+	store.ParseAndAddABIs(abi_diamond)
+
+	block, err := store.DecodeBlock(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !block.Skipped || len(block.Transactions) != 1 || len(server.Calls("eth_getTransactionReceipt")) != 0 {
+		t.Fatalf("block not skipped: %s", block.ToJSON())
+	}
+
+	store.ParseAndAddABIs(abi_erc20)
+	if block, err = store.DecodeBlock(context.Background(), big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if block.Skipped || len(block.Transactions[0].Logs) != 1 || block.Transactions[0].Status != 1 {
+		t.Fatalf("relevant block skipped: %s", block.ToJSON())
+	}
+}
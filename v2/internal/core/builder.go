@@ -0,0 +1,200 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// nonceKey identifies the nonces of an account on a chain, see nextNonce.
+type nonceKey struct {
+	chainId uint64
+	from    common.Address
+}
+
+var (
+	noncesMu sync.Mutex
+	nonces   = make(map[nonceKey]uint64) // next nonce of the accounts built for, guarded by noncesMu
+)
+
+// BuildTx builds a transaction of the sender calling the method of the contract with the global
+// Store, see Storage.BuildTx.
+func BuildTx(ctx context.Context, from, contract common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	return Store.BuildTx(ctx, from, contract, method, args...)
+}
+
+// BuildTx builds an unsigned transaction of the sender calling the method of the contract. The
+// method can be given by name, signature or selector; it is taken from the ABI of the indexed
+// contract, else from the first ABI of AbiList or DefaultABIs defining it. The args are packed
+// like the params of EncodeMethod, so decimal strings are accepted for integers and hex strings
+// for addresses and bytes.
+//
+// The gas limit is estimated by the node. On chains with EIP-1559 the transaction is a dynamic
+// fee transaction with the suggested tip and a fee cap of twice the base fee plus the tip, on
+// other chains a legacy transaction with the suggested gas price. The nonce is the pending nonce
+// of the sender, or the nonce after the last transaction built for the sender if that is higher,
+// so several transactions can be built before the first is mined; see ResetNonce. The
+// transaction sends no ether, see BuildPayableTx, and can be signed with Ctx.SignTx.
+func (store *Storage) BuildTx(ctx context.Context, from, contract common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	return store.BuildPayableTx(ctx, from, contract, nil, method, args...)
+}
+
+// BuildPayableTx is BuildTx for payable methods, sending the value in wei with the call.
+func (store *Storage) BuildPayableTx(ctx context.Context, from, contract common.Address, value *big.Int, method string, args ...interface{}) (*types.Transaction, error) {
+	client, err := store.requireClient()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := store.contractMethod(contract, method)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := packArguments(target, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	chainId, err := backendChainID(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &contract, Value: value, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("error estimate gas of %s: %w", method, err)
+	}
+
+	eip1559 := head.BaseFee != nil
+	if features := probedFeatures(client); features != nil {
+		eip1559 = features.EIP1559
+	}
+
+	var tip, gasPrice *big.Int
+	if eip1559 && head.BaseFee != nil {
+		tip, err = client.SuggestGasTipCap(ctx)
+	} else {
+		gasPrice, err = client.SuggestGasPrice(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := nextNonce(ctx, client, chainId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if tip == nil {
+		return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: gasPrice, Gas: gas, To: &contract, Value: value, Data: data}), nil
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, common.Big2)),
+		Gas:       gas,
+		To:        &contract,
+		Value:     value,
+		Data:      data,
+	}), nil
+}
+
+// ResetNonce drops the nonces of the sender tracked by BuildTx on the chain, e.g. after a built
+// transaction was not sent. The next transaction takes the pending nonce of the node again.
+func ResetNonce(chainId *big.Int, from common.Address) {
+	noncesMu.Lock()
+	defer noncesMu.Unlock()
+	delete(nonces, nonceKey{chainId: chainId.Uint64(), from: from})
+}
+
+// nextNonce returns the nonce of the next transaction of the sender and reserves it.
+func nextNonce(ctx context.Context, client Backend, chainId *big.Int, from common.Address) (uint64, error) {
+	pending, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	noncesMu.Lock()
+	defer noncesMu.Unlock()
+
+	key := nonceKey{chainId: chainId.Uint64(), from: from}
+	nonce := nonces[key]
+	if pending > nonce {
+		nonce = pending
+	}
+	nonces[key] = nonce + 1
+
+	return nonce, nil
+}
+
+// contractMethod returns the method of the contract with the given name, signature or selector,
+// from the ABI of the indexed contract, else the first ABI of AbiList or DefaultABIs defining it.
+func (store *Storage) contractMethod(contract common.Address, name string) (*abi.Method, error) {
+	if indexed := store.indexedContract(contract); indexed != nil {
+		if method, err := findMethod(indexed.Abi, name); err == nil {
+			return method, nil
+		}
+	}
+
+	for _, contractAbi := range store.AbiList {
+		if method, err := findMethod(contractAbi, name); err == nil {
+			return method, nil
+		}
+	}
+
+	for _, registered := range DefaultABIs.Names() {
+		if contractAbi := DefaultABIs.Get(registered); contractAbi != nil {
+			if method, err := findMethod(*contractAbi, name); err == nil {
+				return method, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("method %s of %s not found in stored abis", name, contract.Hex())
+}
+
+// packArguments packs the calldata of the method. The args are converted to the types of the
+// inputs like the params of EncodeMethod.
+func packArguments(method *abi.Method, args ...interface{}) ([]byte, error) {
+	if len(args) != len(method.Inputs) {
+		return nil, fmt.Errorf("%s takes %d args, got %d", method.Sig, len(method.Inputs), len(args))
+	}
+
+	values := make([]interface{}, len(args))
+	for i, input := range method.Inputs {
+		value, err := coerceArgument(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid arg %d of %s: %w", i, method.Sig, err)
+		}
+		values[i] = value
+	}
+
+	data, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("error pack %s: %w", method.Sig, err)
+	}
+
+	return append(append([]byte{}, method.ID...), data...), nil
+}
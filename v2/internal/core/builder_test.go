@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestBuildTx(t *testing.T) {
+	head := *newTestChain(t, 1).headers[1]
+	head.BaseFee = big.NewInt(100)
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.Respond("eth_chainId", "0x1")
+	server.Respond("eth_getBlockByNumber", &head)
+	server.Respond("eth_getTransactionCount", "0x5")
+	server.Respond("eth_estimateGas", "0xc350")
+	server.Respond("eth_maxPriorityFeePerGas", "0xa")
+	server.Respond("eth_gasPrice", "0x64")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous, chainId := Ctx.eth, Ctx.chainId
+	defer func() { Ctx.chainId = chainId }()
+	Ctx.chainId = nil
+	SetClient(client)
+	defer SetBackend(previous)
+
+	store := &Storage{}
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+
+	key, _ := crypto.GenerateKey()
+	from, token := crypto.PubkeyToAddress(key.PublicKey), common.HexToAddress(target_erc20)
+
+	tx, err := store.BuildTx(context.Background(), from, token, "transfer", target_contract, "1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tx.Type() != types.DynamicFeeTxType || tx.Nonce() != 5 || tx.Gas() != 50000 || tx.GasTipCap().Int64() != 10 || tx.GasFeeCap().Int64() != 210 {
+		t.Fatalf("unexpected transaction: type %d, nonce %d, gas %d", tx.Type(), tx.Nonce(), tx.Gas())
+	}
+	if decoded := store.DecodeMethod(tx); decoded == nil || decoded.Name != "transfer" || decoded.Params["value"] != "1000" {
+		t.Fatal("expected the calldata of transfer")
+	}
+
+	signed, err := Ctx.SignTx(tx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := txSender(signed); err != nil || sender != from {
+		t.Fatalf("unexpected sender: %s", sender.Hex())
+	}
+
+	// transactions built before the first is mined take the next nonces
+	if next, _ := store.BuildTx(context.Background(), from, token, "transfer", target_contract, "1"); next == nil || next.Nonce() != 6 {
+		t.Fatal("expected the next nonce")
+	}
+
+	ResetNonce(big.NewInt(1), from)
+	head.BaseFee = nil
+	server.Respond("eth_getBlockByNumber", &head)
+
+	legacy, err := store.BuildTx(context.Background(), from, token, "approve", target_contract, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Type() != types.LegacyTxType || legacy.Nonce() != 5 || legacy.GasPrice().Int64() != 100 {
+		t.Fatalf("unexpected legacy transaction: type %d, nonce %d", legacy.Type(), legacy.Nonce())
+	}
+
+	if _, err := store.BuildTx(context.Background(), from, token, "frobnicate", from); err == nil {
+		t.Fatal("expected methods missing from the stored abis to fail")
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CodeBatchSize is the number of eth_getCode calls sent in a single batch request by GetCodes.
+var CodeBatchSize = 100
+
+// codeCache is the shared bytecode cache of GetCodes and SetIndexed, keyed by address and block.
+// Empty code is not cached, as contracts may still be deployed to the address.
+var codeCache = struct {
+	sync.RWMutex
+	codes map[string][]byte
+}{codes: make(map[string][]byte)}
+
+// ResetCodeCache clears the shared bytecode cache.
+func ResetCodeCache() {
+	codeCache.Lock()
+	defer codeCache.Unlock()
+	codeCache.codes = make(map[string][]byte)
+}
+
+func codeKey(address common.Address, block *big.Int) string {
+	if block == nil {
+		return address.Hex()
+	}
+
+	return address.Hex() + "@" + block.String()
+}
+
+func cachedCode(address common.Address, block *big.Int) ([]byte, bool) {
+	codeCache.RLock()
+	defer codeCache.RUnlock()
+	code, ok := codeCache.codes[codeKey(address, block)]
+	return code, ok
+}
+
+func cacheCode(address common.Address, block *big.Int, code []byte) {
+	if len(code) == 0 {
+		return
+	}
+
+	codeCache.Lock()
+	defer codeCache.Unlock()
+	codeCache.codes[codeKey(address, block)] = code
+}
+
+// GetCodes returns the bytecode of all addresses at the given block, nil for the latest block.
+// Addresses missing in the shared cache are fetched with batched eth_getCode calls of
+// CodeBatchSize each. Addresses without code are returned with empty code.
+func GetCodes(ctx context.Context, addresses []common.Address, block *big.Int) (map[common.Address][]byte, error) {
+	if err := clientRequired(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[common.Address][]byte, len(addresses))
+	var missing []common.Address
+	for _, address := range addresses {
+		if code, ok := cachedCode(address, block); ok {
+			result[address] = code
+		} else if _, ok := result[address]; !ok {
+			result[address] = nil
+			missing = append(missing, address)
+		}
+	}
+
+	blockTag := "latest"
+	if block != nil {
+		blockTag = hexutil.EncodeBig(block)
+	}
+
+	// backends without a JSON-RPC client are asked one by one
+	backend := GetBackend()
+	if rpcClient(backend) == nil {
+		for _, address := range missing {
+			code, err := backend.CodeAt(ctx, address, block)
+			if err != nil {
+				return nil, fmt.Errorf("error getting bytecode of %s: %w", address.Hex(), err)
+			}
+
+			result[address] = code
+			cacheCode(address, block, code)
+		}
+
+		return result, nil
+	}
+
+	size := CodeBatchSize
+	if size <= 0 {
+		size = 100
+	}
+
+	for start := 0; start < len(missing); start += size {
+		end := start + size
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		chunk := missing[start:end]
+		codes := make([]hexutil.Bytes, len(chunk))
+		batch := make([]rpc.BatchElem, len(chunk))
+		for i, address := range chunk {
+			batch[i] = rpc.BatchElem{Method: "eth_getCode", Args: []interface{}{address, blockTag}, Result: &codes[i]}
+		}
+
+		if err := batchCall(ctx, backend, batch); err != nil {
+			return nil, fmt.Errorf("error getting bytecodes: %w", err)
+		}
+
+		for i, address := range chunk {
+			if batch[i].Error != nil {
+				return nil, fmt.Errorf("error getting bytecode of %s: %w", address.Hex(), batch[i].Error)
+			}
+
+			result[address] = codes[i]
+			cacheCode(address, block, codes[i])
+		}
+	}
+
+	return result, nil
+}
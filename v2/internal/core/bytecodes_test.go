@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestGetCodes(t *testing.T) {
+	defer ResetCodeCache()
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	contract, wallet := common.HexToAddress(target_erc20), common.HexToAddress(target_contract)
+	server.Handle("eth_getCode", func(params json.RawMessage) (interface{}, error) {
+		var args []interface{}
+		json.Unmarshal(params, &args)
+		if common.HexToAddress(args[0].(string)) == contract {
+			return "0x6080", nil
+		}
+		return "0x", nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eth := Ctx.eth
+	Ctx.eth = client
+	defer func() { Ctx.eth = eth }()
+
+	CodeBatchSize = 1
+	defer func() { CodeBatchSize = 100 }()
+
+	for i := 0; i < 2; i++ {
+		codes, err := GetCodes(context.Background(), []common.Address{contract, wallet, contract}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(codes) != 2 || common.Bytes2Hex(codes[contract]) != "6080" || len(codes[wallet]) != 0 {
+			t.Fatalf("invalid codes: %v", codes)
+		}
+	}
+
+	// the contract code is cached, the empty code of the wallet is fetched again
+	if calls := len(server.Calls("eth_getCode")); calls != 3 {
+		t.Fatalf("invalid number of eth_getCode calls: %d", calls)
+	}
+}
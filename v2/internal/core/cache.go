@@ -0,0 +1,306 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Cache is a key-value store for decoded metadata, i.e. contract lookups, token infos and resolved
+// signatures, which can be shared between processes, e.g. with RedisCache. Values are JSON
+// encoded. The package uses a MemoryCache unless SetCache is called.
+type Cache interface {
+	// Get returns the value of the key, false if it is not cached or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of the key, expiring it after ttl if it is not 0.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheTTL is the time entries are kept in the Cache, 0 keeps them until they are evicted.
+var CacheTTL = 24 * time.Hour
+
+var sharedCache = struct {
+	sync.RWMutex
+	cache Cache
+}{cache: NewMemoryCache(10000)}
+
+// SetCache replaces the cache shared by the contract lookups, the token stores and the signature
+// resolvers, nil restores an empty MemoryCache.
+func SetCache(cache Cache) {
+	if cache == nil {
+		cache = NewMemoryCache(10000)
+	}
+
+	sharedCache.Lock()
+	defer sharedCache.Unlock()
+	sharedCache.cache = cache
+}
+
+// GetCache returns the cache shared by the contract lookups, the token stores and the signature
+// resolvers.
+func GetCache() Cache {
+	sharedCache.RLock()
+	defer sharedCache.RUnlock()
+	return sharedCache.cache
+}
+
+// cacheGet decodes the cached value of the key into result. Errors of the cache are treated as
+// misses, so an unavailable cache only costs the lookups it would have saved.
+func cacheGet(ctx context.Context, key string, result interface{}) bool {
+	data, ok, err := GetCache().Get(ctx, key)
+	if err != nil || !ok {
+		return false
+	}
+
+	return json.Unmarshal(data, result) == nil
+}
+
+// cacheSet encodes the value into the cache, ignoring errors like cacheGet.
+func cacheSet(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	GetCache().Set(ctx, key, data, CacheTTL)
+}
+
+// contractCacheKey returns the cache key of the lookup of the kind for the address on the chain.
+func contractCacheKey(kind string, chainId *big.Int, address common.Address) string {
+	return fmt.Sprintf("%s:%s:%s", kind, chainId, address.Hex())
+}
+
+// MemoryCache is the in-process Cache, holding at most Size entries and evicting the least
+// recently used one.
+type MemoryCache struct {
+	Size int // Maximum number of entries, unbounded if 0.
+
+	mu      sync.Mutex
+	entries lru[string]
+}
+
+// NewMemoryCache creates a cache of at most size entries.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{Size: size}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.entries.get(key, getClock().Now())
+	if !ok {
+		return nil, false, nil
+	}
+
+	return value.([]byte), true, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expires time.Time
+	if ttl > 0 {
+		expires = getClock().Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.set(key, value, expires, c.Size)
+	return nil
+}
+
+// RedisCache is a Cache backed by a Redis server, speaking the RESP protocol over a single
+// connection, which is redialed after errors.
+type RedisCache struct {
+	Addr     string        // host:port of the server.
+	Password string        // Optional password, sent with AUTH.
+	DB       int           // Database selected after connecting.
+	Prefix   string        // Prefix of all keys, defaults to abidec:.
+	Timeout  time.Duration // Timeout of dialing and of each command, defaults to 5 seconds.
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisCache creates a cache for the Redis server at addr, e.g. "localhost:6379".
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{Addr: addr}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", c.key(key))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if reply == nil {
+		return nil, false, nil
+	}
+
+	return reply.([]byte), true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", c.key(key), string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Close closes the connection to the server.
+func (c *RedisCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func (c *RedisCache) key(key string) string {
+	if c.Prefix == "" {
+		return "abidec:" + key
+	}
+
+	return c.Prefix + key
+}
+
+func (c *RedisCache) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 5 * time.Second
+	}
+
+	return c.Timeout
+}
+
+// do sends the command and returns its reply, nil for nil replies. The connection is dropped
+// after network and protocol errors, but kept after error replies of the server.
+func (c *RedisCache) do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := c.command(ctx, args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.conn.Close()
+		c.conn = nil
+	}
+
+	return reply, err
+}
+
+func (c *RedisCache) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: c.timeout()}
+	conn, err := dialer.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	if c.Password != "" {
+		if _, err := c.command(ctx, "AUTH", c.Password); err != nil {
+			conn.Close()
+			c.conn = nil
+			return err
+		}
+	}
+
+	if c.DB != 0 {
+		if _, err := c.command(ctx, "SELECT", strconv.Itoa(c.DB)); err != nil {
+			conn.Close()
+			c.conn = nil
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *RedisCache) command(ctx context.Context, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(c.timeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	c.conn.SetDeadline(deadline)
+
+	request := fmt.Sprintf("*%d\r\n", len(args))
+	for _, arg := range args {
+		request += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := io.WriteString(c.conn, request); err != nil {
+		return nil, err
+	}
+
+	return readRedisReply(c.reader)
+}
+
+// redisError is an error reply of the server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// readRedisReply reads a simple string, error, integer or bulk string reply.
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("invalid redis reply: %q", line)
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis reply: %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	}
+
+	return nil, fmt.Errorf("unsupported redis reply: %q", line)
+}
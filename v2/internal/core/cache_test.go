@@ -0,0 +1,147 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves GET and SET of a map over RESP.
+func fakeRedis(t *testing.T) (addr string, commands func() []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	var seen []string
+	values := make(map[string]string)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					count, err := readRedisLength(reader, '*')
+					if err != nil {
+						return
+					}
+
+					args := make([]string, count)
+					for i := range args {
+						size, err := readRedisLength(reader, '$')
+						if err != nil {
+							return
+						}
+						data := make([]byte, size+2)
+						if _, err := io.ReadFull(reader, data); err != nil {
+							return
+						}
+						args[i] = string(data[:size])
+					}
+
+					mu.Lock()
+					seen = append(seen, args[0])
+					switch args[0] {
+					case "GET":
+						if value, ok := values[args[1]]; ok {
+							fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+						} else {
+							fmt.Fprint(conn, "$-1\r\n")
+						}
+					case "SET":
+						values[args[1]] = args[2]
+						fmt.Fprint(conn, "+OK\r\n")
+					default:
+						fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+// readRedisLength reads a line like *2 or $5 of a request.
+func readRedisLength(reader *bufio.Reader, prefix byte) (int, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+
+	if len(line) < 3 || line[0] != prefix {
+		return 0, fmt.Errorf("invalid request line: %q", line)
+	}
+
+	return strconv.Atoi(line[1 : len(line)-2])
+}
+
+func TestRedisCache(t *testing.T) {
+	addr, commands := fakeRedis(t)
+	cache := NewRedisCache(addr)
+	defer cache.Close()
+
+	ctx := context.Background()
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("unexpected hit: %v %v", ok, err)
+	}
+
+	if err := cache.Set(ctx, "symbol", []byte(`"TKN"`), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	value, ok, err := cache.Get(ctx, "symbol")
+	if err != nil || !ok || string(value) != `"TKN"` {
+		t.Fatalf("unexpected value: %s %v %v", value, ok, err)
+	}
+
+	// error replies keep the connection
+	if _, err := cache.do(ctx, "PING"); err == nil {
+		t.Fatal("expected an error reply")
+	}
+
+	if got := commands(); len(got) != 4 {
+		t.Fatalf("unexpected commands: %v", got)
+	}
+}
+
+func TestMemoryCacheShared(t *testing.T) {
+	manual := NewManualClock(time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(manual)
+	defer SetClock(nil)
+
+	SetCache(NewMemoryCache(10))
+	defer SetCache(nil)
+
+	ctx := context.Background()
+	cacheSet(ctx, "decimals:1:6", uint8(6))
+
+	var decimals uint8
+	if !cacheGet(ctx, "decimals:1:6", &decimals) || decimals != 6 {
+		t.Fatalf("unexpected value: %d", decimals)
+	}
+
+	manual.Advance(CacheTTL + time.Second)
+	if cacheGet(ctx, "decimals:1:6", &decimals) {
+		t.Fatal("expired entry returned")
+	}
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Call calls the read method of the contract with the global Store, see Storage.Call.
+func Call(ctx context.Context, contract common.Address, method string, args ...interface{}) (Params, error) {
+	return Store.Call(ctx, contract, method, args...)
+}
+
+// Call performs an eth_call of the method of the contract at the latest block and decodes its
+// outputs, e.g. Call(ctx, token, "balanceOf", owner). The method is looked up like for BuildTx, in
+// the ABI of the indexed contract, AbiList and DefaultABIs, and the args are packed like the params
+// of EncodeMethod. Unnamed outputs are keyed by their position.
+func (store *Storage) Call(ctx context.Context, contract common.Address, method string, args ...interface{}) (Params, error) {
+	client, err := store.requireClient()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := store.contractMethod(contract, method)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := packArguments(target, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := unpackArguments(target.Outputs, output)
+	if err != nil {
+		return nil, fmt.Errorf("error unpack output of %s: %w", target.Sig, err)
+	}
+
+	if store.PreserveTypes {
+		return params, nil
+	}
+
+	return formatParameters(params, nil, target.Outputs...), nil
+}
+
+// callMethod performs an eth_call of the method at the latest block and returns its unpacked
+// outputs.
+func callMethod(ctx context.Context, client Backend, contract common.Address, method *abi.Method, args ...interface{}) ([]interface{}, []byte, error) {
+	data, err := packArguments(method, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, output, fmt.Errorf("error unpack output of %s: %w", method.Sig, err)
+	}
+
+	return values, output, nil
+}
+
+// erc20Method returns the method of the ERC20 ABI of DefaultABIs.
+func erc20Method(name string) *abi.Method {
+	method := DefaultABIs.Get("ERC20").Methods[name]
+	return &method
+}
+
+// callText calls a string getter of the contract, like name or symbol of ERC20 tokens. Tokens
+// returning a bytes32, like MKR, are read as text too. It returns nil if the call fails.
+func callText(ctx context.Context, client Backend, contract common.Address, method *abi.Method) *string {
+	values, output, err := callMethod(ctx, client, contract, method)
+	if err == nil {
+		text, _ := values[0].(string)
+		return &text
+	}
+
+	if len(output) != 32 {
+		return nil
+	}
+
+	text := strings.TrimRight(string(output), "\x00")
+	return &text
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestCall(t *testing.T) {
+	erc20 := ParseABI(abi_erc20)
+	token, bytes32Token := common.HexToAddress(target_erc20), common.BigToAddress(big.NewInt(32))
+	owner := common.HexToAddress(target_contract)
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.HandleCall(func(to common.Address, data []byte) ([]byte, error) {
+		method, err := erc20.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		var output []byte
+		switch method.Name {
+		case "balanceOf":
+			if common.BytesToAddress(data[4:36]) != owner {
+				return nil, mockrpc.ErrReverted
+			}
+			output, _ = method.Outputs.Pack(big.NewInt(1000))
+		case "symbol":
+			if to == bytes32Token {
+				output = common.RightPadBytes([]byte("MKR"), 32)
+			} else {
+				output, _ = method.Outputs.Pack("TKN")
+			}
+		case "decimals":
+			output, _ = method.Outputs.Pack(uint8(18))
+		}
+
+		return output, nil
+	})
+	server.Respond("eth_chainId", "0x1")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the method is taken from DefaultABIs without stored ABIs
+	store := &Storage{}
+	store.SetClient(client)
+
+	params, err := store.Call(context.Background(), token, "balanceOf", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["0"] != "1000" {
+		t.Fatalf("unexpected balance: %v", params)
+	}
+
+	if _, err := store.Call(context.Background(), token, "balanceOf(address)", token.Hex()); err == nil {
+		t.Fatal("expected the revert to fail the call")
+	}
+	if _, err := store.Call(context.Background(), token, "frobnicate"); err == nil {
+		t.Fatal("expected unknown methods to fail")
+	}
+
+	SetLookupCache(NewLookupCache(10, 0))
+	defer SetLookupCache(NewLookupCache(10000, 0))
+	SetCache(nil)
+	defer SetCache(nil)
+
+	if symbol := getSymbol(context.Background(), client, token); symbol == nil || *symbol != "TKN" {
+		t.Fatalf("unexpected symbol: %v", symbol)
+	}
+	if symbol := getSymbol(context.Background(), client, bytes32Token); symbol == nil || *symbol != "MKR" {
+		t.Fatalf("unexpected bytes32 symbol: %v", symbol)
+	}
+	if decimals := getDecimals(context.Background(), client, token); decimals == nil || *decimals != 18 {
+		t.Fatalf("unexpected decimals: %v", decimals)
+	}
+	if balance, err := getERC20Balance(context.Background(), client, owner, token); err != nil || balance != 1000 {
+		t.Fatalf("unexpected balance: %d", balance)
+	}
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// EventEntry is a struct for holding the description of an event the decoder can decode.
+type EventEntry struct {
+	Source    string      `json:"source"`              // Name of the ABI defining the event.
+	Name      string      `json:"name"`                // Event name.
+	Signature string      `json:"signature"`           // Canonical event signature.
+	Topic     string      `json:"topic"`               // topics[0] of logs emitted by the event.
+	Anonymous bool        `json:"anonymous,omitempty"` // Anonymous events do not emit their topic.
+	Inputs    []ParamInfo `json:"inputs"`              // Ordered params of the event.
+}
+
+// MethodEntry is a struct for holding the description of a method the decoder can decode.
+type MethodEntry struct {
+	Source          string      `json:"source"`          // Name of the ABI defining the method.
+	Name            string      `json:"name"`            // Method name.
+	Signature       string      `json:"signature"`       // Canonical method signature.
+	Selector        string      `json:"selector"`        // First 4 bytes of calldata calling the method.
+	StateMutability string      `json:"stateMutability"` // pure, view, nonpayable or payable.
+	Inputs          []ParamInfo `json:"inputs"`          // Ordered params of the method.
+	Outputs         []ParamInfo `json:"outputs"`         // Ordered return values of the method.
+}
+
+// Catalog is a struct for holding all events and methods known to a Storage.
+type Catalog struct {
+	Events  []EventEntry  `json:"events"`
+	Methods []MethodEntry `json:"methods"`
+}
+
+// AllEvents returns all events of AbiList sorted by signature. Events defined by several ABIs
+// are listed once, with the first ABI as source, as that one is used for decoding.
+func (store *Storage) AllEvents() []EventEntry {
+	seen := make(map[string]bool)
+	result := make([]EventEntry, 0)
+
+	for i, contractAbi := range store.AbiList {
+		for _, event := range contractAbi.Events {
+			key := event.ID.Hex() + event.Sig
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			result = append(result, EventEntry{
+				Source:    store.abiName(i),
+				Name:      event.Name,
+				Signature: event.Sig,
+				Topic:     event.ID.Hex(),
+				Anonymous: event.Anonymous,
+				Inputs:    paramInfos(event.Inputs),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Signature < result[j].Signature
+	})
+
+	return result
+}
+
+// AllMethods returns all methods of AbiList sorted by signature. Methods defined by several
+// ABIs are listed once, with the first ABI as source, as that one is used for decoding.
+func (store *Storage) AllMethods() []MethodEntry {
+	seen := make(map[string]bool)
+	result := make([]MethodEntry, 0)
+
+	for i, contractAbi := range store.AbiList {
+		for _, method := range contractAbi.Methods {
+			if seen[method.Sig] {
+				continue
+			}
+			seen[method.Sig] = true
+
+			result = append(result, MethodEntry{
+				Source:          store.abiName(i),
+				Name:            method.Name,
+				Signature:       method.Sig,
+				Selector:        fmt.Sprintf("0x%x", method.ID),
+				StateMutability: method.StateMutability,
+				Inputs:          paramInfos(method.Inputs),
+				Outputs:         paramInfos(method.Outputs),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Signature < result[j].Signature
+	})
+
+	return result
+}
+
+// MethodMatch is a struct for holding a method of AbiList together with the ABI defining it.
+type MethodMatch struct {
+	Method abi.Method // The method, for custom unpacking or encoding.
+	Source string     // Name of the ABI defining the method.
+	Index  int        // Index of the ABI in AbiList.
+	Abi    *abi.ABI   // The ABI defining the method.
+}
+
+// EventMatch is a struct for holding an event of AbiList together with the ABI defining it.
+type EventMatch struct {
+	Event  abi.Event // The event, for custom unpacking.
+	Source string    // Name of the ABI defining the event.
+	Index  int       // Index of the ABI in AbiList.
+	Abi    *abi.ABI  // The ABI defining the event.
+}
+
+// MethodBySelector looks up the method of the hex encoded 4 byte selector in AbiList. Like
+// DecodeMethod, the first ABI defining the selector wins.
+func (store *Storage) MethodBySelector(selector string) (*MethodMatch, error) {
+	id, err := hexutil.Decode(selector)
+	if err != nil || len(id) != 4 {
+		return nil, fmt.Errorf("invalid selector: %s", selector)
+	}
+
+	for i := range store.AbiList {
+		if method, err := store.AbiList[i].MethodById(id); err == nil {
+			return &MethodMatch{Method: *method, Source: store.abiName(i), Index: i, Abi: &store.AbiList[i]}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no method with selector %s", selector)
+}
+
+// EventByTopic looks up the event of the hex encoded topic in AbiList. Like DecodeLog, the first
+// ABI defining the topic wins.
+func (store *Storage) EventByTopic(topic string) (*EventMatch, error) {
+	id, err := hexutil.Decode(topic)
+	if err != nil || len(id) != common.HashLength {
+		return nil, fmt.Errorf("invalid topic: %s", topic)
+	}
+
+	for i := range store.AbiList {
+		if event, err := store.AbiList[i].EventByID(common.BytesToHash(id)); err == nil {
+			return &EventMatch{Event: *event, Source: store.abiName(i), Index: i, Abi: &store.AbiList[i]}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no event with topic %s", topic)
+}
+
+// Catalog returns all events and methods the store can decode.
+func (store *Storage) Catalog() *Catalog {
+	return &Catalog{Events: store.AllEvents(), Methods: store.AllMethods()}
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the Catalog object.
+func (data *Catalog) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the Catalog object.
+func (data *Catalog) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// WriteMarkdown renders the catalog as markdown document with one table for events and one for
+// methods.
+func (data *Catalog) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Decoder catalog\n\n")
+	fmt.Fprintf(&b, "## Events (%d)\n\n", len(data.Events))
+	b.WriteString("| Event | Topic | Params | Source |\n|---|---|---|---|\n")
+	for _, event := range data.Events {
+		topic := "`" + event.Topic + "`"
+		if event.Anonymous {
+			topic = "anonymous"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", event.Signature, topic, markdownParams(event.Inputs), event.Source)
+	}
+
+	fmt.Fprintf(&b, "\n## Methods (%d)\n\n", len(data.Methods))
+	b.WriteString("| Method | Selector | Params | Returns | Mutability | Source |\n|---|---|---|---|---|---|\n")
+	for _, method := range data.Methods {
+		fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+			method.Signature, method.Selector, markdownParams(method.Inputs),
+			markdownParams(method.Outputs), method.StateMutability, method.Source)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Markdown returns the catalog as markdown document.
+func (data *Catalog) Markdown() string {
+	var b strings.Builder
+	data.WriteMarkdown(&b)
+	return b.String()
+}
+
+func markdownParams(params []ParamInfo) string {
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		part := param.Type + " " + param.Name
+		if param.Indexed {
+			part = param.Type + " indexed " + param.Name
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	chainlinkAbi = ParseABI(abi_chainlink)
+
+	answerUpdatedTopic = chainlinkAbi.Events["AnswerUpdated"].ID
+)
+
+// PriceFeeds maps tokens to the Chainlink USD price feed their amounts are valued with, see
+// ITknStore.ValueUSD. EtherAddress stands for the native currency. It holds the feeds of Ethereum
+// mainnet, feeds of other chains can be added.
+var PriceFeeds = map[common.Address]common.Address{
+	common.HexToAddress(EtherAddress):                                 common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"), // ETH / USD
+	common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"): common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"), // WETH, ETH / USD
+	common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599"): common.HexToAddress("0xF4030086522a5bEEa4988F8cA5B36dbC97BeE88c"), // WBTC, BTC / USD
+	common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"): common.HexToAddress("0x8fFfFfd4AfB6115b954Bd326cbe7B4BA576818f6"), // USDC / USD
+	common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"): common.HexToAddress("0x3E7d1eAB13ad0104d2750B8863b489D65364e32D"), // USDT / USD
+	common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"): common.HexToAddress("0xAed0c38402a5d19df6E4c03F4E2DceD6e29c1ee9"), // DAI / USD
+}
+
+// USDAmountFormat is used to format the USD values of ValueUSD.
+var USDAmountFormat = AmountFormat{Precision: 2, Rounding: RoundHalfEven}
+
+// PriceRound is a round of a Chainlink price feed as returned by latestRoundData.
+type PriceRound struct {
+	Feed            string   `json:"feed"`            // Feed contract the round was read from.
+	RoundId         *big.Int `json:"roundId"`         // Id of the round.
+	Answer          *big.Int `json:"answer"`          // Price with Decimals decimals.
+	Decimals        uint8    `json:"decimals"`        // Decimals of Answer.
+	StartedAt       uint64   `json:"startedAt"`       // Timestamp the round started at.
+	UpdatedAt       uint64   `json:"updatedAt"`       // Timestamp the answer was updated at.
+	AnsweredInRound *big.Int `json:"answeredInRound"` // Round the answer was computed in.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the PriceRound object.
+func (data *PriceRound) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the PriceRound object.
+func (data *PriceRound) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// Price returns the answer of the round as decimal string.
+func (data *PriceRound) Price() string {
+	return FormatAmount(data.Answer, data.Decimals)
+}
+
+// PriceUpdate is an AnswerUpdated event of a Chainlink aggregator. The event is emitted by the
+// aggregator behind the feed, not by the proxy contract read by LatestRoundData.
+type PriceUpdate struct {
+	Aggregator      string   `json:"aggregator"`      // Aggregator contract that emitted the event.
+	Answer          *big.Int `json:"answer"`          // New price, with the decimals of the feed.
+	RoundId         *big.Int `json:"roundId"`         // Id of the round.
+	UpdatedAt       uint64   `json:"updatedAt"`       // Timestamp the answer was updated at.
+	TransactionHash string   `json:"transactionHash"` // Transaction hash of the log.
+	BlockNumber     uint64   `json:"blockNumber"`     // Block number of the log.
+	LogIndex        uint     `json:"logIndex"`        // Index of the log.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the PriceUpdate object.
+func (data *PriceUpdate) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the PriceUpdate object.
+func (data *PriceUpdate) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// IsAnswerUpdatedLog returns true if the log is an AnswerUpdated event of a Chainlink aggregator.
+func IsAnswerUpdatedLog(vLog *types.Log) bool {
+	return len(vLog.Topics) == 3 && vLog.Topics[0] == answerUpdatedTopic
+}
+
+// DecodeAnswerUpdated decodes the AnswerUpdated log of a Chainlink aggregator. It returns an error
+// for logs of other events.
+func DecodeAnswerUpdated(vLog *types.Log) (*PriceUpdate, error) {
+	if !IsAnswerUpdatedLog(vLog) {
+		return nil, fmt.Errorf("log %d is no price update", vLog.Index)
+	}
+
+	if len(vLog.Data) < 32 {
+		return nil, fmt.Errorf("invalid price update data of log %d", vLog.Index)
+	}
+
+	return &PriceUpdate{
+		Aggregator:      vLog.Address.Hex(),
+		Answer:          math.S256(vLog.Topics[1].Big()),
+		RoundId:         vLog.Topics[2].Big(),
+		UpdatedAt:       new(big.Int).SetBytes(vLog.Data[:32]).Uint64(),
+		TransactionHash: vLog.TxHash.Hex(),
+		BlockNumber:     vLog.BlockNumber,
+		LogIndex:        vLog.Index,
+	}, nil
+}
+
+// LatestRoundData reads the latest round of the price feed with the global TknStore, see
+// ITknStore.LatestRoundData.
+func LatestRoundData(ctx context.Context, feed common.Address) (*PriceRound, error) {
+	return TknStore.LatestRoundData(ctx, feed)
+}
+
+// LatestRoundData reads the latest round of the Chainlink price feed from the backend of the
+// store. The decimals of the feed never change and are cached like the other contract lookups.
+func (store *ITknStore) LatestRoundData(ctx context.Context, feed common.Address) (*PriceRound, error) {
+	client, err := requireClient(store.client)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{To: &feed, Data: chainlinkAbi.Methods["latestRoundData"].ID}
+	output, err := client.CallContract(ctx, msg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error calling latestRoundData of %s: %w", feed.Hex(), err)
+	}
+
+	values, err := chainlinkAbi.Unpack("latestRoundData", output)
+	if err != nil {
+		return nil, fmt.Errorf("error unpack latestRoundData of %s: %w", feed.Hex(), err)
+	}
+
+	decimals := getDecimals(ctx, client, feed)
+	if decimals == nil {
+		return nil, fmt.Errorf("can not read decimals of feed %s", feed.Hex())
+	}
+
+	return &PriceRound{
+		Feed:            feed.Hex(),
+		RoundId:         values[0].(*big.Int),
+		Answer:          values[1].(*big.Int),
+		Decimals:        *decimals,
+		StartedAt:       values[2].(*big.Int).Uint64(),
+		UpdatedAt:       values[3].(*big.Int).Uint64(),
+		AnsweredInRound: values[4].(*big.Int),
+	}, nil
+}
+
+// ValueUSD returns the USD value of the amount of the token with the latest price of its feed in
+// PriceFeeds, formatted with USDAmountFormat. The decimals of the token are resolved through the
+// store. It returns an error if the token has no feed or the feed has no valid price.
+func (store *ITknStore) ValueUSD(ctx context.Context, token common.Address, amount *big.Int) (string, error) {
+	feed, ok := PriceFeeds[token]
+	if !ok {
+		return "", fmt.Errorf("no price feed for %s", token.Hex())
+	}
+
+	round, err := store.LatestRoundData(ctx, feed)
+	if err != nil {
+		return "", err
+	}
+
+	return store.valueUSD(token, amount, round)
+}
+
+// AnnotateUSD sets ValueUSD of the ERC20 and wrapped native token transfers whose token has a feed
+// in PriceFeeds. Every feed is read once, transfers whose value can not be computed are left
+// unchanged.
+func (store *ITknStore) AnnotateUSD(ctx context.Context, transfers []TokenTransfer) {
+	rounds := make(map[common.Address]*PriceRound)
+
+	for i := range transfers {
+		transfer := &transfers[i]
+		if transfer.Standard != StandardERC20 && transfer.Standard != StandardWrapped {
+			continue
+		}
+
+		token := common.HexToAddress(transfer.Token)
+		feed, ok := PriceFeeds[token]
+		if !ok {
+			continue
+		}
+
+		round, ok := rounds[feed]
+		if !ok {
+			round, _ = store.LatestRoundData(ctx, feed)
+			rounds[feed] = round
+		}
+		if round == nil {
+			continue
+		}
+
+		if value, err := store.valueUSD(token, transfer.Amount, round); err == nil {
+			transfer.ValueUSD = value
+		}
+	}
+}
+
+// valueUSD values the amount of the token with the round of its feed.
+func (store *ITknStore) valueUSD(token common.Address, amount *big.Int, round *PriceRound) (string, error) {
+	if round.Answer == nil || round.Answer.Sign() <= 0 {
+		return "", fmt.Errorf("invalid price of feed %s", round.Feed)
+	}
+
+	decimals := uint8(18)
+	if token != common.HexToAddress(EtherAddress) {
+		info, err := store.Get(token)
+		if err != nil || info == nil {
+			return "", fmt.Errorf("can not read decimals of %s", token.Hex())
+		}
+		decimals = info.Decimals
+	}
+
+	scale := int(decimals) + int(round.Decimals)
+	if scale > 255 {
+		return "", fmt.Errorf("decimals of %s out of range", token.Hex())
+	}
+
+	value := new(big.Int).Mul(amount, round.Answer)
+	return USDAmountFormat.Format(value, uint8(scale)), nil
+}
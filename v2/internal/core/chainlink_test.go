@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestDecodeAnswerUpdated(t *testing.T) {
+	aggregator := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	vLog := &types.Log{
+		Address: aggregator,
+		Topics:  []common.Hash{answerUpdatedTopic, common.BytesToHash(math.U256Bytes(big.NewInt(-5))), common.BigToHash(big.NewInt(7))},
+		Data:    math.U256Bytes(big.NewInt(1700000000)),
+	}
+
+	update, err := DecodeAnswerUpdated(vLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if update.Aggregator != aggregator.Hex() || update.Answer.Int64() != -5 || update.RoundId.Int64() != 7 || update.UpdatedAt != 1700000000 {
+		t.Fatalf("unexpected price update: %s", update.ToJSON())
+	}
+
+	if _, err := DecodeAnswerUpdated(&types.Log{Topics: []common.Hash{transferTopic, {}, {}}}); err == nil {
+		t.Fatal("expected an error for other logs")
+	}
+}
+
+func TestAnnotateUSD(t *testing.T) {
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	ethFeed, usdcFeed := PriceFeeds[weth], PriceFeeds[usdc]
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	stringType, _ := abi.NewType("string", "", nil)
+	server.HandleCall(func(to common.Address, data []byte) ([]byte, error) {
+		var output []byte
+		switch hexutil.Encode(data) {
+		case hexutil.Encode(chainlinkAbi.Methods["latestRoundData"].ID):
+			answer := big.NewInt(200012345678) // 2000.12345678
+			if to == usdcFeed {
+				answer = big.NewInt(100010000) // 1.0001
+			}
+			output, _ = chainlinkAbi.Methods["latestRoundData"].Outputs.Pack(big.NewInt(1), answer, big.NewInt(1700000000), big.NewInt(1700000000), big.NewInt(1))
+		case "0x313ce567":
+			decimals := int64(18)
+			switch to {
+			case ethFeed, usdcFeed:
+				decimals = 8
+			case usdc:
+				decimals = 6
+			}
+			output = math.U256Bytes(big.NewInt(decimals))
+		default:
+			output, _ = abi.Arguments{{Type: stringType}}.Pack("Token")
+		}
+		return output, nil
+	})
+	server.Respond("eth_getCode", "0x")
+	server.Respond("eth_chainId", "0x1")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &ITknStore{data: make(map[common.Address]*ITknInfo)}
+	store.SetClient(client)
+
+	round, err := store.LatestRoundData(context.Background(), ethFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if round.Price() != "2000.12345678" || round.Decimals != 8 || round.UpdatedAt != 1700000000 {
+		t.Fatalf("unexpected round: %s", round.ToJSON())
+	}
+
+	oneAndAHalf, _ := new(big.Int).SetString("1500000000000000000", 10)
+	transfers := []TokenTransfer{
+		{Standard: StandardERC20, Token: weth.Hex(), Amount: oneAndAHalf},
+		{Standard: StandardERC20, Token: usdc.Hex(), Amount: big.NewInt(2500000)},
+		{Standard: StandardERC20, Token: other.Hex(), Amount: big.NewInt(1)},
+		{Standard: StandardERC721, Token: usdc.Hex(), Amount: big.NewInt(1)},
+	}
+	store.AnnotateUSD(context.Background(), transfers)
+
+	if transfers[0].ValueUSD != "3000.19" || transfers[1].ValueUSD != "2.50" {
+		t.Fatalf("unexpected values: %s %s", transfers[0].ValueUSD, transfers[1].ValueUSD)
+	}
+	if transfers[2].ValueUSD != "" || transfers[3].ValueUSD != "" {
+		t.Fatal("expected transfers without feed to be skipped")
+	}
+
+	if value, err := store.ValueUSD(context.Background(), common.HexToAddress(EtherAddress), oneAndAHalf); err != nil || value != "3000.19" {
+		t.Fatalf("unexpected ether value: %s %v", value, err)
+	}
+	if _, err := store.ValueUSD(context.Background(), other, big.NewInt(1)); err == nil {
+		t.Fatal("expected an error for tokens without feed")
+	}
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainConfig describes an EVM chain: its name, the explorer its ABIs are fetched from and its
+// known system contracts.
+type ChainConfig struct {
+	ID              uint64                    // Chain id, see EIP-155.
+	Name            string                    // Human-readable name, e.g. OP Mainnet.
+	Explorer        *ExplorerConfig           // Etherscan-compatible API of the chain, nil if none is known.
+	SystemContracts map[common.Address]string // Names of predeploys and protocol contracts by address.
+}
+
+// SystemContract returns the name of the system contract at the address and whether there is one.
+func (config *ChainConfig) SystemContract(address common.Address) (string, bool) {
+	if config == nil {
+		return "", false
+	}
+
+	name, ok := config.SystemContracts[address]
+	return name, ok
+}
+
+// ChainRegistry holds the configs of chains by chain id. It is safe for concurrent use.
+type ChainRegistry struct {
+	mu     sync.RWMutex
+	chains map[uint64]*ChainConfig
+}
+
+// NewChainRegistry returns a registry holding the configs. It panics if a config is invalid, as
+// it is meant for static tables like Chains; use Register to handle invalid configs.
+func NewChainRegistry(configs ...*ChainConfig) *ChainRegistry {
+	registry := &ChainRegistry{chains: make(map[uint64]*ChainConfig)}
+	for _, config := range configs {
+		if err := registry.Register(config); err != nil {
+			panic(err)
+		}
+	}
+
+	return registry
+}
+
+// Register adds the config, replacing the config of the same chain id. An invalid config fails
+// with ValidationErrors and is not added.
+func (r *ChainRegistry) Register(config *ChainConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chains[config.ID] = config
+	return nil
+}
+
+// Get returns the config of the chain, nil if it is unknown.
+func (r *ChainRegistry) Get(chainId uint64) *ChainConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.chains[chainId]
+}
+
+// IDs returns the ids of the registered chains in ascending order.
+func (r *ChainRegistry) IDs() []uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]uint64, 0, len(r.chains))
+	for id := range r.chains {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// Chains is the global chain registry. It holds Ethereum, Sepolia and major L2s and sidechains,
+// their explorers are the public Blockscout instances, which need no API key.
+var Chains = NewChainRegistry(
+	&ChainConfig{
+		ID:       1,
+		Name:     "Ethereum",
+		Explorer: &ExplorerConfig{BaseURL: "https://eth.blockscout.com/api"},
+		SystemContracts: map[common.Address]string{
+			common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa"): "DepositContract",
+			common.HexToAddress("0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02"): "BeaconRoots",
+		},
+	},
+	&ChainConfig{
+		ID:              10,
+		Name:            "OP Mainnet",
+		Explorer:        &ExplorerConfig{BaseURL: "https://optimism.blockscout.com/api"},
+		SystemContracts: opStackContracts(),
+	},
+	&ChainConfig{
+		ID:   56,
+		Name: "BNB Smart Chain",
+		SystemContracts: map[common.Address]string{
+			common.HexToAddress("0x0000000000000000000000000000000000001000"): "ValidatorSet",
+			common.HexToAddress("0x0000000000000000000000000000000000001001"): "SlashIndicator",
+			common.HexToAddress("0x0000000000000000000000000000000000001002"): "SystemReward",
+		},
+	},
+	&ChainConfig{
+		ID:       137,
+		Name:     "Polygon",
+		Explorer: &ExplorerConfig{BaseURL: "https://polygon.blockscout.com/api"},
+		SystemContracts: map[common.Address]string{
+			common.HexToAddress("0x0000000000000000000000000000000000001001"): "StateReceiver",
+			common.HexToAddress("0x0000000000000000000000000000000000001010"): "MRC20",
+		},
+	},
+	&ChainConfig{
+		ID:              8453,
+		Name:            "Base",
+		Explorer:        &ExplorerConfig{BaseURL: "https://base.blockscout.com/api"},
+		SystemContracts: opStackContracts(),
+	},
+	&ChainConfig{
+		ID:       42161,
+		Name:     "Arbitrum One",
+		Explorer: &ExplorerConfig{BaseURL: "https://arbitrum.blockscout.com/api"},
+		SystemContracts: map[common.Address]string{
+			common.HexToAddress("0x0000000000000000000000000000000000000064"): "ArbSys",
+			common.HexToAddress("0x000000000000000000000000000000000000006C"): "ArbGasInfo",
+			common.HexToAddress("0x000000000000000000000000000000000000006E"): "ArbRetryableTx",
+			common.HexToAddress("0x00000000000000000000000000000000000000C8"): "NodeInterface",
+			ArbOSAddress: "ArbOS",
+		},
+	},
+	&ChainConfig{
+		ID:       11155111,
+		Name:     "Sepolia",
+		Explorer: &ExplorerConfig{BaseURL: "https://eth-sepolia.blockscout.com/api"},
+		SystemContracts: map[common.Address]string{
+			common.HexToAddress("0x7f02C3E3c98b133055B8B348B2Ac625669Ed295D"): "DepositContract",
+			common.HexToAddress("0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02"): "BeaconRoots",
+		},
+	},
+)
+
+// opStackContracts returns the predeploys shared by all OP-stack chains.
+func opStackContracts() map[common.Address]string {
+	return map[common.Address]string{
+		common.HexToAddress("0x4200000000000000000000000000000000000006"): "WETH",
+		common.HexToAddress("0x4200000000000000000000000000000000000007"): "L2CrossDomainMessenger",
+		common.HexToAddress("0x420000000000000000000000000000000000000F"): "GasPriceOracle",
+		common.HexToAddress("0x4200000000000000000000000000000000000010"): "L2StandardBridge",
+		common.HexToAddress("0x4200000000000000000000000000000000000011"): "SequencerFeeVault",
+		common.HexToAddress("0x4200000000000000000000000000000000000015"): "L1Block",
+		common.HexToAddress("0x4200000000000000000000000000000000000016"): "L2ToL1MessagePasser",
+		common.HexToAddress("0x4200000000000000000000000000000000000019"): "BaseFeeVault",
+		common.HexToAddress("0x420000000000000000000000000000000000001A"): "L1FeeVault",
+		L1AttributesDepositor: "L1AttributesDepositor",
+	}
+}
+
+// ForChain returns the partition of the store for the chain, created on first use. Partitions
+// keep the ABIs, indexed contracts and token infos of each chain apart, so one process can decode
+// several networks without mixing up contracts deployed at the same address. A new partition has
+// no ABIs and no client, it takes the decoding options of the store.
+func (store *Storage) ForChain(chainId uint64) *Storage {
+	store.partitionsMu.Lock()
+	defer store.partitionsMu.Unlock()
+
+	if partition := store.partitions[chainId]; partition != nil {
+		return partition
+	}
+
+	partition := &Storage{
+		AbiList:       make([]abi.ABI, 0),
+		Indexed:       make(map[string]*IndexedABI),
+		Anonymous:     store.Anonymous,
+		Compliance:    store.Compliance,
+		PreserveTypes: store.PreserveTypes,
+		KeepUnknown:   store.KeepUnknown,
+		ResolveNames:  store.ResolveNames,
+		IncludeTx:     store.IncludeTx,
+		Labels:        store.Labels,
+		Resolver:      store.Resolver,
+		Attestor:      store.Attestor,
+		BloomCheck:    store.BloomCheck,
+		Retry:         store.Retry,
+		chainId:       chainId,
+		tokens:        &ITknStore{data: make(map[common.Address]*ITknInfo)},
+	}
+
+	if store.partitions == nil {
+		store.partitions = make(map[uint64]*Storage)
+	}
+	store.partitions[chainId] = partition
+
+	return partition
+}
+
+// ChainID returns the chain id of a partition created by ForChain, 0 for other stores.
+func (store *Storage) ChainID() uint64 {
+	return store.chainId
+}
+
+// Chain returns the config of the chain of a partition in Chains, nil for other stores and chains
+// that are not registered.
+func (store *Storage) Chain() *ChainConfig {
+	if store.chainId == 0 {
+		return nil
+	}
+
+	return Chains.Get(store.chainId)
+}
+
+// Tokens returns the token infos of a partition, the global TknStore for other stores.
+func (store *Storage) Tokens() *ITknStore {
+	if store.tokens == nil {
+		return &TknStore
+	}
+
+	return store.tokens
+}
+
+// FetchChainABI is FetchABI with the explorer of the chain of a partition, see Chain.
+func (store *Storage) FetchChainABI(address string) (*IndexedABI, error) {
+	chain := store.Chain()
+	if chain == nil || chain.Explorer == nil {
+		return nil, fmt.Errorf("no explorer known for chain %d", store.chainId)
+	}
+
+	return store.FetchABI(address, chain.Explorer)
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestChainRegistry(t *testing.T) {
+	if chain := Chains.Get(8453); chain == nil || chain.Name != "Base" || chain.Explorer == nil {
+		t.Fatalf("unexpected config of Base: %+v", chain)
+	}
+	if name, ok := Chains.Get(10).SystemContract(common.HexToAddress("0x4200000000000000000000000000000000000015")); !ok || name != "L1Block" {
+		t.Fatalf("unexpected system contract: %s", name)
+	}
+	if _, ok := Chains.Get(999).SystemContract(common.Address{}); ok {
+		t.Fatal("expected no system contracts of unknown chains")
+	}
+
+	registry := NewChainRegistry(&ChainConfig{ID: 5, Name: "Goerli"}, &ChainConfig{ID: 1, Name: "Ethereum"})
+	registry.Register(&ChainConfig{ID: 5, Name: "Görli"})
+	if ids := registry.IDs(); len(ids) != 2 || ids[0] != 1 || registry.Get(5).Name != "Görli" {
+		t.Fatalf("unexpected chains: %v", ids)
+	}
+}
+
+func TestStoragePartitions(t *testing.T) {
+	store := &Storage{KeepUnknown: true}
+
+	mainnet, base := store.ForChain(1), store.ForChain(8453)
+	if store.ForChain(1) != mainnet || mainnet.ChainID() != 1 || base.Chain().Name != "Base" || store.Chain() != nil {
+		t.Fatal("unexpected partitions")
+	}
+
+	mainnet.KeepUnknown = false
+	mainnet.ParseAndAddABIs(abi_erc20)
+
+	token := common.HexToAddress(target_erc20)
+	data, _ := ParseABI(abi_erc20).Pack("transfer", common.HexToAddress(target_contract), big.NewInt(1))
+	tx := types.NewTx(&types.LegacyTx{To: &token, Gas: 60000, GasPrice: big.NewInt(1), Data: data})
+
+	if decoded := mainnet.DecodeMethod(tx); decoded == nil || decoded.Name != "transfer" {
+		t.Fatal("transfer not decoded on mainnet")
+	}
+	// the options are taken from the store, the ABIs are not shared
+	if decoded := base.DecodeMethod(tx); decoded == nil || !decoded.Unknown {
+		t.Fatal("expected the ABIs of mainnet not to be used on Base")
+	}
+
+	mainnet.Tokens().Set(&ITknInfo{Address: token, IsERC20: true, Symbol: "TKN"})
+	if !mainnet.Tokens().Has(token) || base.Tokens().Has(token) {
+		t.Fatal("expected the token infos to be partitioned")
+	}
+	if store.Tokens() != &TknStore {
+		t.Fatal("expected the global token store of stores without chain")
+	}
+}
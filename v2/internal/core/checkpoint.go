@@ -0,0 +1,290 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// CheckpointStore persists the last fully processed block of scan sessions by key. Other
+// backends, e.g. Redis, plug in by implementing the two methods.
+type CheckpointStore interface {
+	// Load returns the checkpoint of the key, false if none was saved yet.
+	Load(ctx context.Context, key string) (block uint64, ok bool, err error)
+	// Save stores the checkpoint of the key.
+	Save(ctx context.Context, key string, block uint64) error
+}
+
+// ScanFunc is the signature of Storage.ScanLogs and AbiDecoder.ScanLogs.
+type ScanFunc func(ctx context.Context, filter ethereum.FilterQuery, options *ScanOptions, handle func(*DecodedLog) error) error
+
+// ScanSession is a resumable scan: the last fully processed block is saved to the checkpoint
+// store while scanning, and a later Run with the same key continues after it. Logs are processed
+// at least once, the logs of a block interrupted by a crash or an error of handle are handed out
+// again by the next run.
+type ScanSession struct {
+	Key        string               // Key of the checkpoint, unique per scan.
+	Checkpoint CheckpointStore      // Store of the checkpoints.
+	Scan       ScanFunc             // Scanner, e.g. Store.ScanLogs.
+	Filter     ethereum.FilterQuery // Logs to scan, a nil ToBlock scans to the latest block.
+	Options    *ScanOptions         // Options passed to Scan.
+	Interval   uint64               // Blocks between saved checkpoints, defaults to ScanChunkSize.
+	Client     Backend              // Backend for the latest block and reorg checks, defaults to the global backend.
+
+	// Reorgs optionally tracks the hashes of the recent blocks handled by the session. Blocks that
+	// changed since the last run are reported to handle as logs with Removed set, and scanned
+	// again. The tracker lives in memory only.
+	Reorgs *ReorgTracker
+}
+
+// NewScanSession returns a session scanning the filter with the global store.
+func NewScanSession(key string, checkpoint CheckpointStore, filter ethereum.FilterQuery) *ScanSession {
+	return &ScanSession{Key: key, Checkpoint: checkpoint, Scan: Store.ScanLogs, Filter: filter}
+}
+
+// Run scans from the block after the saved checkpoint, or the FromBlock of the filter, to the
+// ToBlock of the filter and saves the last block as checkpoint once done. A nil ToBlock is
+// resolved to the latest block when Run starts, so repeated runs follow the chain.
+func (session *ScanSession) Run(ctx context.Context, handle func(*DecodedLog) error) error {
+	if session.Scan == nil || session.Checkpoint == nil {
+		return fmt.Errorf("scan session %s needs a scanner and a checkpoint store", session.Key)
+	}
+
+	filter := session.Filter
+	var fromBlock uint64
+	if filter.FromBlock != nil {
+		fromBlock = filter.FromBlock.Uint64()
+	}
+
+	last, ok, err := session.Checkpoint.Load(ctx, session.Key)
+	if err != nil {
+		return fmt.Errorf("error loading checkpoint of %s: %w", session.Key, err)
+	}
+	if ok && last+1 > fromBlock {
+		fromBlock = last + 1
+	}
+
+	client := session.Client
+	if client == nil {
+		client = GetBackend()
+	}
+
+	if session.Reorgs != nil {
+		if client == nil {
+			return fmt.Errorf("no provider set to check the blocks of %s for reorgs", session.Key)
+		}
+
+		fork, removed, ok, err := session.Reorgs.Rewind(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		for i := range removed {
+			if err := handle(&removed[i]); err != nil {
+				return err
+			}
+		}
+
+		// the checkpoint moves before the fork, so a failing rescan starts there again
+		if ok && fork < fromBlock && fork > 0 {
+			if err := session.save(ctx, fork-1); err != nil {
+				return err
+			}
+			fromBlock = fork
+		}
+	}
+
+	var toBlock uint64
+	if filter.ToBlock != nil {
+		toBlock = filter.ToBlock.Uint64()
+	} else {
+		if client == nil {
+			return fmt.Errorf("no provider set to resolve the latest block of %s", session.Key)
+		}
+
+		toBlock, err = latestBlockNumber(ctx, client)
+		if err != nil {
+			return fmt.Errorf("error getting block number: %w", err)
+		}
+	}
+
+	if fromBlock > toBlock {
+		return nil
+	}
+
+	interval := session.Interval
+	if interval == 0 {
+		interval = ScanChunkSize
+	}
+
+	filter.FromBlock = new(big.Int).SetUint64(fromBlock)
+	filter.ToBlock = new(big.Int).SetUint64(toBlock)
+
+	// logs arrive in block order, so all blocks before the block of a log are fully processed
+	saved := fromBlock - 1
+	err = session.Scan(ctx, filter, session.Options, func(decoded *DecodedLog) error {
+		if decoded.BlockNumber > fromBlock && decoded.BlockNumber-1-saved >= interval {
+			if err := session.save(ctx, decoded.BlockNumber-1); err != nil {
+				return err
+			}
+			saved = decoded.BlockNumber - 1
+		}
+
+		if err := handle(decoded); err != nil {
+			return err
+		}
+
+		if session.Reorgs != nil {
+			session.Reorgs.Record(decoded)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return session.save(ctx, toBlock)
+}
+
+func (session *ScanSession) save(ctx context.Context, block uint64) error {
+	if err := session.Checkpoint.Save(ctx, session.Key, block); err != nil {
+		return fmt.Errorf("error saving checkpoint of %s: %w", session.Key, err)
+	}
+
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoints of all keys in a JSON file, which is replaced
+// atomically on every save.
+type FileCheckpointStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (store *FileCheckpointStore) Load(ctx context.Context, key string) (uint64, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	checkpoints, err := store.read()
+	if err != nil {
+		return 0, false, err
+	}
+
+	block, ok := checkpoints[key]
+	return block, ok, nil
+}
+
+func (store *FileCheckpointStore) Save(ctx context.Context, key string, block uint64) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	checkpoints, err := store.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[key] = block
+
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(store.Path, data)
+}
+
+func (store *FileCheckpointStore) read() (map[string]uint64, error) {
+	checkpoints := make(map[string]uint64)
+
+	data, err := os.ReadFile(store.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %w", store.Path, err)
+	}
+
+	return checkpoints, nil
+}
+
+// SQLCheckpointStore keeps the checkpoints in a table with the columns key (text, primary key)
+// and block (bigint), e.g.
+//
+//	CREATE TABLE scan_checkpoints (key TEXT PRIMARY KEY, block BIGINT NOT NULL)
+//
+// The driver is up to the caller. Numbered enables $1 style placeholders as used by Postgres,
+// otherwise ? is used.
+type SQLCheckpointStore struct {
+	DB       *sql.DB
+	Table    string // Defaults to scan_checkpoints.
+	Numbered bool
+}
+
+func (store *SQLCheckpointStore) Load(ctx context.Context, key string) (uint64, bool, error) {
+	var block int64
+	query := fmt.Sprintf("SELECT block FROM %s WHERE key = %s", store.table(), store.placeholder(1))
+
+	err := store.DB.QueryRowContext(ctx, query, key).Scan(&block)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint64(block), true, nil
+}
+
+// Save updates the checkpoint, inserting it if the key has none yet. Both statements run in one
+// transaction, as upserts are not portable between databases.
+func (store *SQLCheckpointStore) Save(ctx context.Context, key string, block uint64) error {
+	tx, err := store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	update := fmt.Sprintf("UPDATE %s SET block = %s WHERE key = %s", store.table(), store.placeholder(1), store.placeholder(2))
+	result, err := tx.ExecContext(ctx, update, int64(block), key)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		insert := fmt.Sprintf("INSERT INTO %s (key, block) VALUES (%s, %s)", store.table(), store.placeholder(1), store.placeholder(2))
+		if _, err := tx.ExecContext(ctx, insert, key, int64(block)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (store *SQLCheckpointStore) table() string {
+	if store.Table == "" {
+		return "scan_checkpoints"
+	}
+
+	return store.Table
+}
+
+func (store *SQLCheckpointStore) placeholder(i int) string {
+	if store.Numbered {
+		return fmt.Sprintf("$%d", i)
+	}
+
+	return "?"
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestScanSession(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	// every block holds a single transfer
+	server.Handle("eth_getLogs", func(params json.RawMessage) (interface{}, error) {
+		var query []struct {
+			FromBlock string `json:"fromBlock"`
+			ToBlock   string `json:"toBlock"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+
+		from, _ := hexutil.DecodeUint64(query[0].FromBlock)
+		to, _ := hexutil.DecodeUint64(query[0].ToBlock)
+		logs := make([]types.Log, 0)
+		for block := from; block <= to; block++ {
+			logs = append(logs, types.Log{
+				Address:     common.HexToAddress(target_erc20),
+				Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+				Data:        common.LeftPadBytes([]byte{1}, 32),
+				BlockNumber: block,
+			})
+		}
+		return logs, nil
+	})
+	server.Respond("eth_blockNumber", "0x3c")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoder.SetClient(client)
+
+	checkpoints := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoints.json")}
+	session := &ScanSession{
+		Key:        "transfers",
+		Checkpoint: checkpoints,
+		Scan:       decoder.ScanLogs,
+		Filter:     ethereum.FilterQuery{FromBlock: big.NewInt(10), ToBlock: big.NewInt(49)},
+		Options:    &ScanOptions{ChunkSize: 10},
+		Interval:   5,
+		Client:     client,
+	}
+
+	// the first run crashes while handling block 27
+	crash := fmt.Errorf("crash")
+	err = session.Run(context.Background(), func(decoded *DecodedLog) error {
+		if decoded.BlockNumber == 27 {
+			return crash
+		}
+		return nil
+	})
+	if err != crash {
+		t.Fatalf("handle error not returned: %v", err)
+	}
+
+	if block, ok, _ := checkpoints.Load(context.Background(), "transfers"); !ok || block != 24 {
+		t.Fatalf("invalid checkpoint: %d", block)
+	}
+
+	// the second run resumes after the checkpoint
+	var blocks []uint64
+	err = session.Run(context.Background(), func(decoded *DecodedLog) error {
+		blocks = append(blocks, decoded.BlockNumber)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(blocks) != 25 || blocks[0] != 25 || blocks[24] != 49 {
+		t.Fatalf("scan not resumed: %v", blocks)
+	}
+
+	if block, _, _ := checkpoints.Load(context.Background(), "transfers"); block != 49 {
+		t.Fatalf("invalid final checkpoint: %d", block)
+	}
+
+	// an open range follows the latest block
+	session.Filter.ToBlock = nil
+	blocks = nil
+	if err := session.Run(context.Background(), func(decoded *DecodedLog) error {
+		blocks = append(blocks, decoded.BlockNumber)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(blocks) != 11 || blocks[0] != 50 || blocks[10] != 60 {
+		t.Fatalf("latest blocks not scanned: %v", blocks)
+	}
+}
+
+func TestScanSessionReorg(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	// blocks from forkBlock on are replaced once fork is set
+	var mu sync.Mutex
+	head, forkBlock, fork := uint64(10), uint64(0), ""
+	header := func(number uint64) *types.Header {
+		mu.Lock()
+		defer mu.Unlock()
+
+		extra := "main"
+		if fork != "" && number >= forkBlock {
+			extra = fork
+		}
+		return &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0), Extra: []byte(extra)}
+	}
+
+	server.Handle("eth_blockNumber", func(json.RawMessage) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return hexutil.Uint64(head), nil
+	})
+	server.Handle("eth_getBlockByNumber", func(params json.RawMessage) (interface{}, error) {
+		var args []interface{}
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		number, _ := hexutil.DecodeUint64(args[0].(string))
+		return header(number), nil
+	})
+	server.Handle("eth_getLogs", func(params json.RawMessage) (interface{}, error) {
+		var query []struct {
+			FromBlock string `json:"fromBlock"`
+			ToBlock   string `json:"toBlock"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+
+		from, _ := hexutil.DecodeUint64(query[0].FromBlock)
+		to, _ := hexutil.DecodeUint64(query[0].ToBlock)
+		logs := make([]types.Log, 0)
+		for block := from; block <= to; block++ {
+			logs = append(logs, types.Log{
+				Address:     common.HexToAddress(target_erc20),
+				Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+				Data:        common.LeftPadBytes([]byte{1}, 32),
+				BlockNumber: block,
+				BlockHash:   header(block).Hash(),
+			})
+		}
+		return logs, nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoder.SetClient(client)
+
+	session := &ScanSession{
+		Key:        "transfers",
+		Checkpoint: &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoints.json")},
+		Scan:       decoder.ScanLogs,
+		Filter:     ethereum.FilterQuery{FromBlock: big.NewInt(1)},
+		Client:     client,
+		Reorgs:     NewReorgTracker(5),
+	}
+
+	var handled []DecodedLog
+	handle := func(decoded *DecodedLog) error {
+		handled = append(handled, *decoded)
+		return nil
+	}
+
+	if err := session.Run(context.Background(), handle); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(handled) != 10 {
+		t.Fatalf("invalid number of logs: %d", len(handled))
+	}
+
+	mu.Lock()
+	head, forkBlock, fork = 12, 8, "fork"
+	mu.Unlock()
+
+	handled = nil
+	if err := session.Run(context.Background(), handle); err != nil {
+		t.Fatal(err)
+	}
+
+	// the logs of blocks 10, 9 and 8 are removed, then blocks 8 - 12 of the fork are scanned
+	expected := []struct {
+		block   uint64
+		removed bool
+	}{{10, true}, {9, true}, {8, true}, {8, false}, {9, false}, {10, false}, {11, false}, {12, false}}
+	if len(handled) != len(expected) {
+		t.Fatalf("invalid logs after reorg: %d", len(handled))
+	}
+
+	for i, log := range handled {
+		if log.BlockNumber != expected[i].block || log.Removed != expected[i].removed {
+			t.Fatalf("invalid log %d after reorg: block %d, removed %v", i, log.BlockNumber, log.Removed)
+		}
+
+		if !log.Removed && log.BlockHash != header(log.BlockNumber).Hash().Hex() {
+			t.Fatalf("log of block %d not from the fork", log.BlockNumber)
+		}
+	}
+}
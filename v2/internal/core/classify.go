@@ -0,0 +1,143 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// pendingLog holds what Hydrate needs to decode the params of a classified log.
+type pendingLog struct {
+	vLog       *types.Log
+	abi        *abi.ABI
+	preserve   bool
+	compliance ComplianceHook
+	attestor   *Attestor
+	names      bool    // see ResolveNames
+	client     Backend // backend the names are looked up with
+	labels     *LabelStore
+	chainId    *big.Int // chain of the idempotency key
+}
+
+// ClassifyLog identifies the event of the log by its topic0 without unpacking any data. Only
+// Contract, Topic, Signature, Name and the position of the log (transaction, block, index and the
+// idempotency key) are set, the params are decoded later by Hydrate. Indexed contracts are
+// looked up first, versioned ones at the block of the log, then all ABIs of the store. Anonymous
+// events and the Resolver are not consulted, as they need the data to match. It returns nil for
+// unknown topics.
+func (store *Storage) ClassifyLog(vLog *types.Log) *DecodedLog {
+	if len(vLog.Topics) == 0 {
+		return nil
+	}
+
+	if indexed := store.indexedContract(vLog.Address); indexed != nil {
+		contractAbi := indexed.AbiAt(vLog.BlockNumber)
+		if classified := store.classify(vLog, &contractAbi); classified != nil {
+			return classified
+		}
+	}
+
+	for i := range store.AbiList {
+		if classified := store.classify(vLog, &store.AbiList[i]); classified != nil {
+			return classified
+		}
+	}
+
+	return nil
+}
+
+func (store *Storage) classify(vLog *types.Log, contractAbi *abi.ABI) *DecodedLog {
+	return classifyLog(vLog, contractAbi, pendingLog{
+		preserve:   store.PreserveTypes,
+		compliance: store.Compliance,
+		attestor:   store.Attestor,
+		names:      store.ResolveNames,
+		client:     store.GetBackend(),
+		labels:     store.Labels,
+		chainId:    store.keyChainID(),
+	})
+}
+
+// ClassifyLog is like Storage.ClassifyLog using the ABI of the decoder.
+func (decoder *AbiDecoder) ClassifyLog(vLog *types.Log) *DecodedLog {
+	checkAbi(decoder)
+
+	return classifyLog(vLog, decoder.Abi, pendingLog{
+		preserve:   decoder.PreserveTypes,
+		compliance: decoder.Compliance,
+		attestor:   decoder.Attestor,
+		names:      decoder.ResolveNames,
+		client:     decoder.GetBackend(),
+		labels:     decoder.Labels,
+		chainId:    decoder.keyChainID(),
+	})
+}
+
+func classifyLog(vLog *types.Log, contractAbi *abi.ABI, pending pendingLog) *DecodedLog {
+	if len(vLog.Topics) == 0 {
+		return nil
+	}
+
+	event, err := contractAbi.EventByID(vLog.Topics[0])
+	if err != nil {
+		return nil
+	}
+
+	pending.vLog = vLog
+	pending.abi = contractAbi
+
+	classified := &DecodedLog{
+		BlockNumber:     vLog.BlockNumber,
+		BlockHash:       vLog.BlockHash.Hex(),
+		Removed:         vLog.Removed,
+		TransactionHash: vLog.TxHash.Hex(),
+		LogIndex:        vLog.Index,
+		Contract:        vLog.Address.Hex(),
+		Topic:           vLog.Topics[0].Hex(),
+		Signature:       event.Sig,
+		Name:            event.Name,
+		pending:         &pending,
+	}
+
+	keyLog(pending.chainId, classified)
+	return classified
+}
+
+// Hydrated returns false for logs returned by ClassifyLog whose params are not decoded yet.
+func (data *DecodedLog) Hydrated() bool {
+	return data.pending == nil
+}
+
+// Hydrate decodes the params, raw topics and data of a log returned by ClassifyLog, with the
+// options of the decoder or store that classified it. The compliance hook screens the result and
+// the Attestor signs it, like DecodeLog does. It is a no-op for logs that are already hydrated.
+func (data *DecodedLog) Hydrate() error {
+	if data.pending == nil {
+		return nil
+	}
+
+	pending := data.pending
+	decoded := parseLog(pending.vLog, *pending.abi, nil, pending.preserve)
+	if decoded == nil {
+		return fmt.Errorf("error unpack %s of log %d in %s", data.Signature, data.LogIndex, data.TransactionHash)
+	}
+
+	if !screenLog(pending.compliance, decoded) {
+		return fmt.Errorf("log %d in %s blocked by compliance hook", data.LogIndex, data.TransactionHash)
+	}
+
+	data.Params = decoded.Params
+	data.Inputs = decoded.Inputs
+	data.ParamsOrdered = decoded.ParamsOrdered
+	data.Topics = decoded.Topics
+	data.Data = decoded.Data
+	data.Flagged = decoded.Flagged
+	data.pending = nil
+
+	nameLog(pending.names, pending.client, data)
+	labelLog(pending.labels, data)
+	attestLog(pending.attestor, data)
+	return nil
+}
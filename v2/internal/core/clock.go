@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock abstracts the time source of the package, so timeouts, tickers and timestamps can be
+// controlled in tests. The package uses SystemClock unless SetClock is called.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks of a Clock in an interval.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock is the Clock backed by the time package.
+var SystemClock Clock = systemClock{}
+
+var clock = struct {
+	sync.RWMutex
+	Clock
+}{Clock: SystemClock}
+
+// SetClock replaces the clock used by the package, nil restores SystemClock.
+func SetClock(c Clock) {
+	if c == nil {
+		c = SystemClock
+	}
+
+	clock.Lock()
+	defer clock.Unlock()
+	clock.Clock = c
+}
+
+// getClock returns the clock currently used by the package.
+func getClock() Clock {
+	clock.RLock()
+	defer clock.RUnlock()
+	return clock.Clock
+}
+
+// withTimeout is context.WithTimeout using the package clock.
+func withTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	c := getClock()
+	if _, ok := c.(systemClock); ok {
+		return context.WithTimeout(parent, timeout)
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	expired := c.After(timeout)
+
+	go func() {
+		select {
+		case <-expired:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTicker(d time.Duration) Ticker       { return systemTicker{time.NewTicker(d)} }
+
+type systemTicker struct{ ticker *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.ticker.C }
+func (t systemTicker) Stop()               { t.ticker.Stop() }
+
+// ManualClock is a Clock for tests that only moves when Advance is called. Timers and tickers
+// fire synchronously during Advance.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*manualWaiter
+}
+
+type manualWaiter struct {
+	deadline time.Time
+	interval time.Duration // zero for one-shot timers
+	ch       chan time.Time
+	stopped  bool
+}
+
+// NewManualClock creates a ManualClock starting at the given time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel receiving the time once the clock advanced by d.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	return c.add(d, 0).ch
+}
+
+// NewTicker returns a ticker firing every d the clock advances.
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for ManualClock.NewTicker")
+	}
+
+	return &manualTicker{clock: c, waiter: c.add(d, d)}
+}
+
+// Advance moves the clock forward and fires all timers and tickers that are due. Like tickers
+// of the time package, ticks are dropped when the receiver is not keeping up.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.stopped {
+			continue
+		}
+
+		for !waiter.deadline.After(c.now) {
+			select {
+			case waiter.ch <- waiter.deadline:
+			default:
+			}
+
+			if waiter.interval == 0 {
+				waiter.stopped = true
+				break
+			}
+			waiter.deadline = waiter.deadline.Add(waiter.interval)
+		}
+
+		if !waiter.stopped {
+			pending = append(pending, waiter)
+		}
+	}
+
+	c.waiters = pending
+}
+
+func (c *ManualClock) add(d time.Duration, interval time.Duration) *manualWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	waiter := &manualWaiter{deadline: c.now.Add(d), interval: interval, ch: make(chan time.Time, 1)}
+	if d <= 0 && interval == 0 {
+		waiter.ch <- c.now
+		waiter.stopped = true
+		return waiter
+	}
+
+	c.waiters = append(c.waiters, waiter)
+
+	return waiter
+}
+
+type manualTicker struct {
+	clock  *ManualClock
+	waiter *manualWaiter
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.waiter.ch }
+
+func (t *manualTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.waiter.stopped = true
+}
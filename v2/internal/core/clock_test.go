@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	manual := NewManualClock(start)
+	SetClock(manual)
+	defer SetClock(nil)
+
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	manual.Advance(9 * time.Second)
+	if ctx.Err() != nil {
+		t.Fatal("context expired before its timeout")
+	}
+
+	manual.Advance(time.Second)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not expired after its timeout")
+	}
+
+	checked := make(chan WatchdogStats, 1)
+	watchdog, err := NewWatchdog(WatchdogConfig{
+		Interval:      time.Minute,
+		MaxGoroutines: 1,
+		OnAlert:       func(alert WatchdogAlert) { checked <- alert.Stats },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watchdogCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	watchdog.Start(watchdogCtx)
+
+	manual.Advance(time.Minute)
+	select {
+	case stats := <-checked:
+		if !stats.Time.Equal(start.Add(10*time.Second + time.Minute)) {
+			t.Fatalf("watchdog not using the clock: %v", stats.Time)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watchdog did not tick")
+	}
+}
@@ -0,0 +1,311 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ComplianceHook screens the addresses appearing in a decoded result (sender, contract, address
+// params, also inside tuples and arrays, and the nested calls). Integrators implement it to flag or block sanctioned addresses.
+type ComplianceHook interface {
+	Screen(addresses []common.Address) ScreeningResult
+}
+
+// ScreeningResult is the verdict of a ComplianceHook. Flagged addresses are attached to the
+// decoded result, Block drops the decoded result entirely.
+type ScreeningResult struct {
+	Flagged []common.Address
+	Block   bool
+}
+
+// screenMethod runs the hook over a decoded method and its transaction sender, the given one or
+// else the one recovered from the signature. It returns false if the result has to be blocked.
+func screenMethod(hook ComplianceHook, tx *types.Transaction, from *common.Address, decoded *DecodedMethod) bool {
+	if hook == nil || decoded == nil {
+		return true
+	}
+
+	addresses := collectMethodAddresses(decoded)
+	if from != nil {
+		addresses = append(addresses, *from)
+	} else if tx != nil {
+		if sender, err := txSender(tx); err == nil {
+			addresses = append(addresses, sender)
+		}
+	}
+
+	result := hook.Screen(addresses)
+	decoded.Flagged = addressesToHex(result.Flagged)
+
+	return !result.Block
+}
+
+// screenLog runs the hook over a decoded log. It returns false if the result has to be blocked.
+func screenLog(hook ComplianceHook, decoded *DecodedLog) bool {
+	if hook == nil || decoded == nil {
+		return true
+	}
+
+	result := hook.Screen(collectAddresses(decoded.Params, decoded.Contract))
+	decoded.Flagged = addressesToHex(result.Flagged)
+
+	return !result.Block
+}
+
+// collectAddresses gathers all unique addresses of the params and the given extras, walking
+// tuples, arrays and maps of formatted and native params.
+func collectAddresses(params Params, extra ...string) []common.Address {
+	collector := &addressCollector{}
+	for _, value := range extra {
+		collector.add(value)
+	}
+	collector.params(params)
+
+	return collector.result
+}
+
+// collectMethodAddresses gathers all unique addresses of the method, its params and its nested
+// calls, recursively.
+func collectMethodAddresses(decoded *DecodedMethod) []common.Address {
+	collector := &addressCollector{}
+	collector.method(decoded)
+
+	return collector.result
+}
+
+// addressCollector gathers unique addresses in the order they are found.
+type addressCollector struct {
+	seen   map[common.Address]bool
+	result []common.Address
+}
+
+func (c *addressCollector) add(value string) {
+	if !common.IsHexAddress(value) {
+		return
+	}
+
+	if c.seen == nil {
+		c.seen = make(map[common.Address]bool)
+		c.result = make([]common.Address, 0)
+	}
+
+	address := common.HexToAddress(value)
+	if !c.seen[address] {
+		c.seen[address] = true
+		c.result = append(c.result, address)
+	}
+}
+
+func (c *addressCollector) method(decoded *DecodedMethod) {
+	c.add(decoded.Contract)
+	c.params(decoded.Params)
+	for _, call := range decoded.Calls {
+		c.method(call)
+	}
+}
+
+func (c *addressCollector) params(params Params) {
+	for _, value := range params {
+		c.walk(reflect.ValueOf(value))
+	}
+}
+
+// walk adds the strings and addresses of the value, recursing into slices, arrays, maps, structs
+// and pointers.
+func (c *addressCollector) walk(value reflect.Value) {
+	if !value.IsValid() {
+		return
+	}
+
+	if value.CanInterface() {
+		if address, ok := value.Interface().(common.Address); ok {
+			c.add(address.Hex())
+			return
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		c.add(value.String())
+	case reflect.Interface, reflect.Pointer:
+		c.walk(value.Elem())
+	case reflect.Slice, reflect.Array:
+		// byte slices and arrays, e.g. hashes, hold no addresses
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			c.walk(value.Index(i))
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			c.walk(iter.Value())
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				c.walk(value.Field(i))
+			}
+		}
+	}
+}
+
+func addressesToHex(addresses []common.Address) []string {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, address.Hex())
+	}
+
+	return result
+}
+
+// ListScreener is a ComplianceHook backed by a file of sanctioned addresses. The file is either a
+// JSON array of addresses or a plain list with one address per line (CSV rows use the first
+// column, lines starting with # are ignored). Watch reloads the list whenever the file changes.
+type ListScreener struct {
+	Path  string // Path of the address list.
+	Block bool   // Whether matches block the decoded result instead of only flagging it.
+
+	mu       sync.RWMutex
+	list     map[common.Address]bool
+	modified time.Time
+}
+
+// NewListScreener creates a ListScreener and loads the address list from the given path.
+func NewListScreener(path string, block bool) (*ListScreener, error) {
+	screener := &ListScreener{Path: path, Block: block}
+	if err := screener.Reload(); err != nil {
+		return nil, err
+	}
+
+	return screener, nil
+}
+
+// Screen flags all addresses present in the list.
+func (s *ListScreener) Screen(addresses []common.Address) ScreeningResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result ScreeningResult
+	for _, address := range addresses {
+		if s.list[address] {
+			result.Flagged = append(result.Flagged, address)
+		}
+	}
+
+	result.Block = s.Block && len(result.Flagged) > 0
+	return result
+}
+
+// Len returns the number of addresses currently loaded.
+func (s *ListScreener) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.list)
+}
+
+// Reload reads the address list from disk and replaces the current one. Lists with entries that
+// are no addresses are rejected with an error, keeping the current list active.
+func (s *ListScreener) Reload() error {
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(s.Path)
+	if err != nil {
+		return err
+	}
+
+	list, err := parseAddressList(content)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.list = list
+	s.modified = info.ModTime()
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Watch polls the list file in the given interval and reloads it when its modification time
+// changed, until the context is cancelled. Reload errors keep the previous list active.
+func (s *ListScreener) Watch(ctx context.Context, interval time.Duration) {
+	ticker := getClock().NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				info, err := os.Stat(s.Path)
+				if err != nil {
+					continue
+				}
+
+				s.mu.RLock()
+				changed := !info.ModTime().Equal(s.modified)
+				s.mu.RUnlock()
+
+				if changed {
+					s.Reload()
+				}
+			}
+		}
+	}()
+}
+
+func parseAddressList(content []byte) (map[common.Address]bool, error) {
+	list := make(map[common.Address]bool)
+
+	if trimmed := strings.TrimSpace(string(content)); strings.HasPrefix(trimmed, "[") {
+		var addresses []string
+		if err := json.Unmarshal([]byte(trimmed), &addresses); err != nil {
+			return nil, err
+		}
+
+		for i, address := range addresses {
+			if !common.IsHexAddress(address) {
+				return nil, fmt.Errorf("invalid address %q at index %d", address, i)
+			}
+			list[common.HexToAddress(address)] = true
+		}
+
+		return list, nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		column := strings.TrimSpace(strings.Split(line, ",")[0])
+		if !common.IsHexAddress(column) {
+			return nil, fmt.Errorf("invalid address %q on line %d", column, number)
+		}
+		list[common.HexToAddress(column)] = true
+	}
+
+	return list, scanner.Err()
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestListScreener(t *testing.T) {
+	sanctioned := common.HexToAddress(target_erc20)
+	path := filepath.Join(t.TempDir(), "sanctioned.csv")
+
+	if err := os.WriteFile(path, []byte("# address,label\n"+sanctioned.Hex()+",mixer\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	screener, err := NewListScreener(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if screener.Len() != 1 {
+		t.Fatalf("invalid list size: %v", screener.Len())
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), Compliance: screener}
+	vLog := &types.Log{
+		Address: common.HexToAddress(target_contract),
+		Topics: []common.Hash{
+			common.HexToHash(TransferTopic),
+			common.BytesToHash(sanctioned.Bytes()),
+			common.BytesToHash(common.HexToAddress(target_erc721).Bytes()),
+		},
+		Data: common.LeftPadBytes(big.NewInt(1).Bytes(), 32),
+	}
+
+	decoded := decoder.DecodeLog(vLog)
+	if decoded == nil || len(decoded.Flagged) != 1 || decoded.Flagged[0] != sanctioned.Hex() {
+		t.Fatalf("sanctioned address not flagged: %v", decoded)
+	}
+
+	screener.Block = true
+	if decoded := decoder.DecodeLog(vLog); decoded != nil {
+		t.Fatalf("sanctioned transfer not blocked: %v", decoded.ToJSON())
+	}
+
+	if err := os.WriteFile(path, []byte(`["`+target_contract+`"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := screener.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := screener.Screen([]common.Address{sanctioned}); len(result.Flagged) != 0 {
+		t.Fatal("reloaded list still contains removed address")
+	}
+
+	// lists with invalid entries are rejected, the previous list stays active
+	if err := os.WriteFile(path, []byte(target_erc20+"\n0x1234\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := screener.Reload(); err == nil {
+		t.Fatal("invalid entry not reported")
+	}
+
+	if result := screener.Screen([]common.Address{common.HexToAddress(target_contract)}); len(result.Flagged) != 1 {
+		t.Fatal("previous list dropped")
+	}
+}
+
+func TestScreenNestedAddresses(t *testing.T) {
+	sanctioned := common.HexToAddress(target_erc20)
+	screener := &ListScreener{list: map[common.Address]bool{sanctioned: true}, Block: true}
+
+	// addresses of tuples and arrays, formatted or native
+	for _, value := range []interface{}{
+		map[string]interface{}{"recipient": sanctioned.Hex()},
+		[]interface{}{"1", []string{sanctioned.Hex()}},
+		struct{ Recipient common.Address }{sanctioned},
+	} {
+		decoded := &DecodedMethod{Contract: target_contract, Params: Params{"order": value}}
+		if screenMethod(screener, nil, nil, decoded) {
+			t.Fatalf("address in %T not screened", value)
+		}
+	}
+
+	// and of nested calls
+	decoded := &DecodedMethod{
+		Contract: target_contract,
+		Params:   Params{"data": []string{"0x"}},
+		Calls: []*DecodedMethod{{
+			Contract: target_contract,
+			Calls:    []*DecodedMethod{{Contract: target_contract, Params: Params{"to": sanctioned.Hex()}}},
+		}},
+	}
+	if screenMethod(screener, nil, nil, decoded) || len(decoded.Flagged) != 1 {
+		t.Fatalf("nested call not screened: %v", decoded.Flagged)
+	}
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// ConnectOptions configures the rpc connection created by ConnectWithOptions and DialWithOptions.
+// It covers the authentication schemes of common enterprise and private RPC providers.
+type ConnectOptions struct {
+	Headers     map[string]string // Additional HTTP headers sent with every request, e.g. API keys.
+	BasicAuth   *BasicAuth        // Optional HTTP basic authentication.
+	BearerToken string            // Optional static bearer token for the Authorization header.
+	JWTSecret   string            // Optional hex encoded 32 byte secret for engine-style JWT authentication.
+
+	HTTPClient     *http.Client  // Optional HTTP client, e.g. with a custom transport. Exclusive with the dialer options.
+	RequestTimeout time.Duration // Optional timeout of single HTTP requests, overriding the timeout of HTTPClient.
+
+	ProxyURL            string        // Optional http://, https:// or socks5:// proxy for all connections.
+	TLSConfig           *tls.Config   // Optional TLS configuration, e.g. custom root CAs or client certificates.
+	DialTimeout         time.Duration // Timeout for establishing TCP connections.
+	TLSHandshakeTimeout time.Duration // Timeout for the TLS handshake.
+	KeepAlive           time.Duration // Keep-alive period of TCP connections.
+	IdleConnTimeout     time.Duration // How long idle HTTP connections are kept open.
+
+	Interceptors    []RPCInterceptor // Hooks invoked around every outbound HTTP RPC call.
+	RequestIDHeader string           // Optional HTTP header, e.g. X-Request-ID, carrying the request id of the call context.
+
+	RateLimit float64 // Optional limit of HTTP RPC calls per second, e.g. 25 for public endpoints, see RateLimiter.
+	RateBurst int     // Calls allowed at once within RateLimit, defaults to one second worth of calls.
+}
+
+// BasicAuth holds the credentials for HTTP basic authentication.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// clientOptions converts the options into rpc client options.
+func (options *ConnectOptions) clientOptions() ([]rpc.ClientOption, error) {
+	var result []rpc.ClientOption
+
+	headers := http.Header{}
+	for key, value := range options.Headers {
+		headers.Set(key, value)
+	}
+
+	if options.BasicAuth != nil {
+		request := http.Request{Header: http.Header{}}
+		request.SetBasicAuth(options.BasicAuth.Username, options.BasicAuth.Password)
+		headers.Set("Authorization", request.Header.Get("Authorization"))
+	}
+
+	if options.BearerToken != "" {
+		headers.Set("Authorization", "Bearer "+options.BearerToken)
+	}
+
+	if len(headers) > 0 {
+		result = append(result, rpc.WithHeaders(headers))
+	}
+
+	if options.hasTransport() {
+		httpClient := &http.Client{}
+		var roundTripper http.RoundTripper
+
+		if options.HTTPClient != nil {
+			// the client of the caller is left untouched
+			*httpClient = *options.HTTPClient
+			roundTripper = httpClient.Transport
+			if roundTripper == nil {
+				roundTripper = http.DefaultTransport
+			}
+		} else {
+			transport, err := options.transport()
+			if err != nil {
+				return nil, err
+			}
+
+			dialer := websocket.Dialer{
+				Proxy:            transport.Proxy,
+				TLSClientConfig:  transport.TLSClientConfig,
+				NetDialContext:   transport.DialContext,
+				HandshakeTimeout: options.TLSHandshakeTimeout,
+			}
+
+			roundTripper = transport
+			result = append(result, rpc.WithWebsocketDialer(dialer))
+		}
+
+		interceptors := options.Interceptors
+		if options.RateLimit > 0 {
+			interceptors = append([]RPCInterceptor{NewRateLimiter(options.RateLimit, options.RateBurst)}, interceptors...)
+		}
+
+		if len(interceptors) > 0 || options.RequestIDHeader != "" {
+			roundTripper = &interceptTransport{base: roundTripper, interceptors: interceptors, header: options.RequestIDHeader}
+		}
+
+		httpClient.Transport = roundTripper
+		if options.RequestTimeout > 0 {
+			httpClient.Timeout = options.RequestTimeout
+		}
+
+		result = append(result, rpc.WithHTTPClient(httpClient))
+	}
+
+	if options.JWTSecret != "" {
+		secret := common.FromHex(options.JWTSecret)
+		if len(secret) != 32 {
+			return nil, fmt.Errorf("invalid jwt secret: expected 32 bytes, got %v", len(secret))
+		}
+
+		result = append(result, rpc.WithHTTPAuth(jwtAuth(secret)))
+	}
+
+	return result, nil
+}
+
+// hasTransport returns true if any of the HTTP client, dialer options, interceptors, the request id
+// header or the rate limit is set.
+func (options *ConnectOptions) hasTransport() bool {
+	return options.HTTPClient != nil || options.RequestTimeout > 0 || options.hasDialer() ||
+		len(options.Interceptors) > 0 || options.RequestIDHeader != "" || options.RateLimit > 0
+}
+
+// hasDialer returns true if any of the dialer options is set.
+func (options *ConnectOptions) hasDialer() bool {
+	return options.ProxyURL != "" || options.TLSConfig != nil || options.DialTimeout > 0 ||
+		options.TLSHandshakeTimeout > 0 || options.KeepAlive > 0 || options.IdleConnTimeout > 0
+}
+
+// transport builds the HTTP transport for the dialer options, based on http.DefaultTransport.
+func (options *ConnectOptions) transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if options.ProxyURL != "" {
+		proxy, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if options.TLSConfig != nil {
+		transport.TLSClientConfig = options.TLSConfig.Clone()
+	}
+
+	if options.DialTimeout > 0 || options.KeepAlive > 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if options.DialTimeout > 0 {
+			dialer.Timeout = options.DialTimeout
+		}
+		if options.KeepAlive > 0 {
+			dialer.KeepAlive = options.KeepAlive
+		}
+
+		transport.DialContext = dialer.DialContext
+	}
+
+	if options.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = options.TLSHandshakeTimeout
+	}
+
+	if options.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = options.IdleConnTimeout
+	}
+
+	return transport, nil
+}
+
+// jwtAuth creates an HS256 token with a fresh iat claim for every request, as required by
+// engine API style authentication.
+func jwtAuth(secret []byte) rpc.HTTPAuth {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+	return func(h http.Header) error {
+		claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iat":%d}`, getClock().Now().Unix())))
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(header + "." + claims))
+		signature := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+		h.Set("Authorization", "Bearer "+header+"."+claims+"."+signature)
+		return nil
+	}
+}
+
+// DialWithOptions validates the connection options and creates a new client for the given node
+// url without touching the global Ctx.
+func DialWithOptions(ctx context.Context, nodeUrl string, options ConnectOptions) (*ethclient.Client, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	clientOptions, err := options.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := rpc.DialOptions(ctx, nodeUrl, clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ethclient.NewClient(client), nil
+}
+
+// ConnectWithOptions connects to the given node url using the connection options and sets the
+// client as global client like Connect. The options are kept for reconnects.
+func ConnectWithOptions(nodeUrl string, options ConnectOptions) (*ethclient.Client, error) {
+	client, err := DialWithOptions(context.Background(), nodeUrl, options)
+	if err != nil {
+		return nil, err
+	}
+
+	SetClient(client)
+	Ctx.connection = &nodeUrl
+	Ctx.options = &options
+
+	return client, nil
+}
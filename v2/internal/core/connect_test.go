@@ -0,0 +1,248 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDialWithOptions(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer server.Close()
+
+	client, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{
+		Headers:   map[string]string{"X-Api-Key": "secret"},
+		JWTSecret: "0x" + strings.Repeat("ab", 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ChainID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if headers.Get("X-Api-Key") != "secret" {
+		t.Fatalf("api key header not sent: %v", headers)
+	}
+
+	if token := headers.Get("Authorization"); !strings.HasPrefix(token, "Bearer ") || strings.Count(token, ".") != 2 {
+		t.Fatalf("invalid jwt authorization header: %v", token)
+	}
+
+	if _, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{JWTSecret: "0x01"}); err == nil {
+		t.Fatal("short jwt secret accepted")
+	}
+}
+
+func TestConnectTransport(t *testing.T) {
+	options := ConnectOptions{ProxyURL: "socks5://127.0.0.1:1080", DialTimeout: time.Second}
+	if !options.hasTransport() {
+		t.Fatal("dialer options not detected")
+	}
+
+	transport, err := options.transport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, "https://rpc.example.org", nil)
+	proxy, err := transport.Proxy(request)
+	if err != nil || proxy.String() != options.ProxyURL {
+		t.Fatalf("invalid proxy: %v %v", proxy, err)
+	}
+
+	if _, err := (&ConnectOptions{ProxyURL: "://invalid"}).transport(); err == nil {
+		t.Fatal("invalid proxy url accepted")
+	}
+}
+
+type headerTransport struct {
+	requests int
+}
+
+func (h *headerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	h.requests++
+	request = request.Clone(request.Context())
+	request.Header.Set("X-Gateway", "custom")
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestConnectHTTPClient(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		if r.Header.Get("X-Slow") != "" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	defer server.Close()
+
+	transport := &headerTransport{}
+	httpClient := &http.Client{Transport: transport}
+	interceptor := &recordingInterceptor{}
+
+	client, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{
+		HTTPClient:   httpClient,
+		BearerToken:  "token",
+		Interceptors: []RPCInterceptor{interceptor},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ChainID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if transport.requests != 1 || headers.Get("X-Gateway") != "custom" || headers.Get("Authorization") != "Bearer token" {
+		t.Fatalf("custom client not used: %v %v", transport.requests, headers)
+	}
+
+	if len(interceptor.calls) != 1 || httpClient.Transport != transport {
+		t.Fatalf("interceptors not applied or client modified: %+v", interceptor.calls)
+	}
+
+	slow, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{
+		Headers:        map[string]string{"X-Slow": "1"},
+		RequestTimeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := slow.ChainID(context.Background()); err == nil {
+		t.Fatal("request timeout not applied")
+	}
+
+	if err := (&ConnectOptions{HTTPClient: httpClient, ProxyURL: "http://127.0.0.1:8080"}).Validate(); err == nil {
+		t.Fatal("http client combined with dialer options accepted")
+	}
+}
+
+type recordingInterceptor struct {
+	calls []RPCCall
+	block string
+}
+
+func (r *recordingInterceptor) Before(ctx context.Context, call *RPCCall) error {
+	if call.Method == r.block {
+		return fmt.Errorf("blocked %s", call.Method)
+	}
+	return nil
+}
+
+func (r *recordingInterceptor) After(ctx context.Context, call *RPCCall) {
+	r.calls = append(r.calls, *call)
+}
+
+func TestInterceptors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if request.Method == "eth_blockNumber" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"limit exceeded"}}`, request.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, request.ID)
+	}))
+	defer server.Close()
+
+	interceptor := &recordingInterceptor{block: "eth_gasPrice"}
+	client, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{Interceptors: []RPCInterceptor{interceptor}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ChainID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.BlockNumber(context.Background()); err == nil {
+		t.Fatal("expected rpc error")
+	}
+
+	if _, err := client.SuggestGasPrice(context.Background()); err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Fatalf("call not aborted by interceptor: %v", err)
+	}
+
+	if len(interceptor.calls) != 2 || interceptor.calls[0].Method != "eth_chainId" || interceptor.calls[0].Err != nil {
+		t.Fatalf("invalid intercepted calls: %+v", interceptor.calls)
+	}
+
+	if interceptor.calls[1].Err == nil || !strings.Contains(interceptor.calls[1].Err.Error(), "limit exceeded") {
+		t.Fatalf("rpc error not passed to interceptor: %+v", interceptor.calls[1])
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Request-ID")
+		var request struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x1"}`, request.ID)
+	}))
+	defer server.Close()
+
+	interceptor := &recordingInterceptor{}
+	client, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{
+		Interceptors:    []RPCInterceptor{interceptor},
+		RequestIDHeader: "X-Request-ID",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ChainID(WithRequestID(context.Background(), "req-42")); err != nil {
+		t.Fatal(err)
+	}
+
+	if header != "req-42" || len(interceptor.calls) != 1 || interceptor.calls[0].RequestID != "req-42" {
+		t.Fatalf("request id not propagated: header %q, calls %+v", header, interceptor.calls)
+	}
+}
+
+func TestValidateConnectOptions(t *testing.T) {
+	options := ConnectOptions{
+		BasicAuth:   &BasicAuth{},
+		BearerToken: "token",
+		ProxyURL:    "ftp://proxy.example.org",
+		DialTimeout: -time.Second,
+	}
+
+	err := options.Validate()
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 4 {
+		t.Fatalf("invalid validation errors: %v", err)
+	}
+
+	if errs[0].Field != "ConnectOptions.BasicAuth.Username" {
+		t.Fatalf("invalid field path: %v", errs[0].Field)
+	}
+
+	if err := (&ConnectOptions{ProxyURL: "socks5://127.0.0.1:1080"}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+type ctxType struct {
+	connection  *string
+	options     *ConnectOptions
+	initialized bool
+	isLegacy    *bool
+	chainId     *big.Int
+	signer      types.Signer
+	eth         Backend
+	features    *ChainFeatures // see ProbeChain, nil until probed
+}
+
+// Ctx holds the global client, used by the package-level functions and by decoders and stores
+// without a client of their own.
+//
+// Deprecated: set the client of each AbiDecoder, Storage, IndexedABI or ITknStore with SetClient
+// instead of relying on the global client.
+var Ctx = ctxType{
+	initialized: false,
+}
+
+// NewCtx is an initializer function for TxnSign.
+func NewCtx(chainId *big.Int) ctxType {
+	if Ctx.eth == nil {
+		return ctxType{
+			initialized: false,
+			isLegacy:    nil,
+			chainId:     nil,
+		}
+	}
+
+	if chainId != nil && Ctx.chainId == chainId && Ctx.initialized {
+		return ctxType{
+			initialized: Ctx.initialized,
+			connection:  Ctx.connection,
+			options:     Ctx.options,
+			isLegacy:    Ctx.isLegacy,
+			chainId:     Ctx.chainId,
+			signer:      Ctx.signer,
+			eth:         Ctx.eth,
+			features:    Ctx.features,
+		}
+	}
+
+	var signer types.Signer
+	ctx := context.Background()
+
+	if chainId == nil && Ctx.chainId == nil {
+		id, err := backendChainID(ctx, GetBackend())
+		if err == nil && id != nil {
+			Ctx.chainId = id
+			chainId = id
+		}
+	} else if chainId == nil && Ctx.chainId != nil {
+		chainId = Ctx.chainId
+	}
+
+	if Ctx.chainId == nil || Ctx.chainId != chainId {
+		Ctx.chainId = chainId
+	}
+
+	if Ctx.isLegacy == nil {
+		is, err := IsEIP1559(GetBackend(), ctx)
+		if err == nil && is != nil {
+			isLegacy := !*is
+			Ctx.isLegacy = &isLegacy
+		}
+	}
+
+	if Ctx.features != nil {
+		signer = Ctx.features.Signer()
+	} else if Ctx.isLegacy != nil && *Ctx.isLegacy {
+		signer = types.NewEIP155Signer(chainId)
+	} else {
+		signer = types.NewLondonSigner(chainId)
+	}
+
+	Ctx.initialized = true
+
+	return ctxType{
+		isLegacy: Ctx.isLegacy, eth: Ctx.eth,
+		chainId: chainId, signer: signer, features: Ctx.features,
+		initialized: true, connection: Ctx.connection, options: Ctx.options,
+	}
+}
+
+func SetClient(client *ethclient.Client) *ethclient.Client {
+	SetBackend(asBackend(client))
+	return client
+}
+
+// SetBackend sets the global backend, like SetClient for backends other than *ethclient.Client.
+// The features of the previous backend, see ProbeChain, are dropped.
+func SetBackend(backend Backend) Backend {
+	Ctx.eth = backend
+	Ctx.isLegacy, Ctx.features = nil, nil
+	Ctx = NewCtx(nil)
+	return Ctx.eth
+}
+
+// GetClient returns the global client, nil if the global backend is no *ethclient.Client.
+func GetClient() *ethclient.Client {
+	return asClient(Ctx.eth)
+}
+
+// GetBackend returns the global backend, wrapped with the global retry policy if one is set.
+func GetBackend() Backend {
+	return retrying(Ctx.eth, nil)
+}
+
+func Connect(nodeUrl string) *ethclient.Client {
+	Ctx.connection = &nodeUrl
+	Ctx.options = nil
+	client, err := ethclient.Dial(nodeUrl)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return SetClient(client)
+}
+
+func (s *ctxType) ReloadCtx() {
+	Ctx = NewCtx(Ctx.chainId)
+}
+
+// GetTxFrom returns the recovered sender of the transaction, nil if it can not be recovered. The
+// signer matches the type and chain id of the transaction, see txSender.
+func (s *ctxType) GetTxFrom(tx *types.Transaction) *string {
+	if from, err := txSender(tx); err == nil {
+		sender := from.Hex()
+		return &sender
+	}
+
+	return nil
+}
+
+// GetTxFromJSON is GetTxFrom for a transaction of an RPC response, e.g. of eth_getBlockByNumber.
+// L2 transactions have no signature and can not be represented as types.Transaction, like the
+// deposits of OP-stack chains and the retryables of Arbitrum; their sender is the from field
+// reported by the node. It returns nil if the sender can not be determined.
+func (s *ctxType) GetTxFromJSON(raw []byte) *string {
+	if tx, ok := unmarshalL2Transaction(raw); ok {
+		sender := tx.From.Hex()
+		return &sender
+	}
+
+	tx, err := unmarshalTransaction(raw)
+	if err != nil || tx == nil {
+		return nil
+	}
+
+	return s.GetTxFrom(tx)
+}
+
+// Signer returns the signer of the chain of the global backend, nil without a backend. It is
+// picked by ProbeChain, or from the chain id and the base fee of the latest block.
+func (s *ctxType) Signer() types.Signer {
+	return s.signer
+}
+
+// SignTx signs the transaction with the key and the signer of Ctx, e.g. a transaction built with
+// BuildTx.
+func (s *ctxType) SignTx(tx *types.Transaction, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	if s.signer == nil {
+		return nil, fmt.Errorf("no signer in Ctx, set a client with SetClient first")
+	}
+
+	return types.SignTx(tx, s.signer, key)
+}
+
+func (*ctxType) GetMinerAndNonce(block *types.Block) (miner string, nonce string) {
+	return GetMinerAndNonce(block)
+}
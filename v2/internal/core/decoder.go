@@ -0,0 +1,475 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// AbiDecoder is a struct used to decode contract ABIs.
+type AbiDecoder struct {
+	IsVerified      bool           // Indicates whether the contract is verified
+	ContractAddress *string        // The contract's address
+	Abi             *abi.ABI       // The contract's ABI
+	Debug           *bool          // Deprecated: aborts the process on decode errors, use ExplainMethod and ExplainLog instead
+	Anonymous       bool           // Whether unmatched logs are tried against anonymous events
+	Compliance      ComplianceHook // Optional hook screening all addresses of decoded results
+	PreserveTypes   bool           // Whether params keep native Go types (*big.Int, common.Address, []byte) instead of strings
+	KeepUnknown     bool           // Whether calls with unknown selectors are returned flagged as Unknown instead of nil
+	ResolveNames    bool           // Whether the address params of decoded results are annotated with their ENS names
+	IncludeTx       bool           // Whether decoded methods include the type, sender, value, nonce and gas of their transaction
+	Labels          *LabelStore    // Optional labels of the contract and address params of decoded results
+	Attestor        *Attestor      // Optional signer attesting all decoded results
+	Retry           *RetryPolicy   // Optional retry policy of RPC calls, overriding the global one
+	client          Backend        // The backend instance for decoder
+	clientChainId   *big.Int       // The chain reported by client, see keyChainID
+}
+
+// checkAbi checks if the ABI has been loaded into the decoder instance.
+// If not, it throws a fatal error.
+func checkAbi(decoder *AbiDecoder) {
+	if decoder.Abi == nil {
+		log.Fatal("NO ABI LOADED TO INSTANCE", decoder)
+	}
+}
+
+// nestedABIs returns the ABIs nested calls are decoded with, the decoder's ABI followed by the
+// ABIs of the global store defining the selector of the calldata.
+func (decoder *AbiDecoder) nestedABIs(data []byte) []abi.ABI {
+	return append([]abi.ABI{*decoder.Abi}, Store.methodABIs(data)...)
+}
+
+// SetABI sets the contract ABI in the decoder instance and returns it.
+func (decoder *AbiDecoder) SetABI(contractAbi abi.ABI) abi.ABI {
+	decoder.Abi = &contractAbi
+	return *decoder.Abi
+}
+
+// FromJSON decodes the ABI from JSON and sets it in the decoder instance.
+// It returns the contract ABI.
+func (decoder *AbiDecoder) FromJSON(abis string) abi.ABI {
+	contractAbi, err := abi.JSON(strings.NewReader(abis))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	decoder.Abi = &contractAbi
+	return *decoder.Abi
+}
+
+func (s *AbiDecoder) MergeAddABIs(abis ...string) abi.ABI {
+	s.Abi = MergeABIs(abis...)
+	return *s.Abi
+}
+
+// DecodeLog decodes the log and returns the decoded log.
+// It checks if the ABI has been loaded in the decoder instance. If Anonymous is enabled and the
+// log does not match any event signature, the anonymous events of the ABI are tried as well.
+func (decoder *AbiDecoder) DecodeLog(vLog *types.Log) *DecodedLog {
+	checkAbi(decoder)
+
+	decoded := parseLog(vLog, *decoder.Abi, decoder.Debug, decoder.PreserveTypes)
+	if decoded == nil && decoder.Anonymous {
+		decoded = parseAnonymousLog(vLog, []abi.ABI{*decoder.Abi}, decoder.Debug, decoder.PreserveTypes)
+	}
+
+	if !screenLog(decoder.Compliance, decoded) {
+		return nil
+	}
+
+	nameLog(decoder.ResolveNames, decoder.GetBackend(), decoded)
+	labelLog(decoder.Labels, decoded)
+	keyLog(decoder.keyChainID(), decoded)
+	attestLog(decoder.Attestor, decoded)
+	return decoded
+}
+
+// DecodeRawLog decodes a log given only by its topics and data, e.g. from database rows or
+// third-party APIs that don't expose *types.Log. The contract of the result is the decoder's
+// ContractAddress if set. It returns nil if the log could not be decoded.
+func (decoder *AbiDecoder) DecodeRawLog(topics []common.Hash, data []byte) *DecodedLog {
+	vLog := types.Log{Topics: topics, Data: data}
+	if decoder.ContractAddress != nil {
+		vLog.Address = common.HexToAddress(*decoder.ContractAddress)
+	}
+
+	return decoder.DecodeLog(&vLog)
+}
+
+// DecodeLogs decodes a slice of Ethereum logs using the ABI specified in the `AbiDecoder`. It
+// returns a slice of `DecodedLog` objects that contain the decoded event signature and arguments
+// for each log. The function first checks that an ABI has been specified using the `checkAbi()`
+// helper function, and then iterates through each log, calling the `parseLog()` function to
+// attempt to decode the log using the specified ABI. If the log can be decoded, a `DecodedLog`
+// object is added to the result slice. Finally, the function returns the result slice.
+func (decoder *AbiDecoder) DecodeLogs(vLogs []*types.Log) []*DecodedLog {
+	checkAbi(decoder)
+	result := make([]*DecodedLog, 0, len(vLogs))
+
+	for _, v := range vLogs {
+		if decoded := decoder.DecodeLog(v); decoded != nil {
+			result = append(result, decoded)
+		}
+	}
+
+	return result
+}
+
+// DecodeMethod decodes the method of a given transaction using the ABI loaded in the decoder.
+// It takes a types.Transaction as an input and returns a pointer to a DecodedMethod if the
+// method was successfully decoded, or nil if not. With KeepUnknown, calls with an unknown
+// selector are returned flagged as Unknown.
+func (decoder *AbiDecoder) DecodeMethod(tx *types.Transaction) *DecodedMethod {
+	// Check if the ABI has been loaded
+	checkAbi(decoder)
+
+	// Parse the method and the calls nested in its params
+	decoded, err := parseMethod(tx, *decoder.Abi, decoder.Debug, decoder.PreserveTypes)
+	if err != nil {
+		return nil
+	}
+	if decoded == nil && decoder.KeepUnknown {
+		decoded = unknownTransaction(tx)
+	}
+	decodeNestedCalls(decoded, decoder.nestedABIs, 0, decoder.PreserveTypes)
+
+	// Screen the addresses, blocked results are dropped
+	if !screenMethod(decoder.Compliance, tx, nil, decoded) {
+		return nil
+	}
+
+	includeTx(decoder.IncludeTx, tx, decoded)
+	nameMethod(decoder.ResolveNames, decoder.GetBackend(), decoded)
+	labelMethod(decoder.Labels, decoded)
+	keyMethod(decoded)
+	attestMethod(decoder.Attestor, decoded)
+	return decoded
+}
+
+// DecodeCalldata decodes raw calldata, e.g. from an API or a mempool feed, without a
+// types.Transaction. The contract of the result is the decoder's ContractAddress if set.
+// It returns an error if the selector is unknown to the decoder's ABI, unless KeepUnknown is set.
+func (decoder *AbiDecoder) DecodeCalldata(data []byte) (*DecodedMethod, error) {
+	checkAbi(decoder)
+
+	contract := EtherAddress
+	if decoder.ContractAddress != nil {
+		contract = *decoder.ContractAddress
+	}
+
+	decoded, err := parseCalldata(contract, data, *decoder.Abi, decoder.Debug, decoder.PreserveTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoded == nil && decoder.KeepUnknown {
+		decoded = unknownMethod(contract, data)
+	}
+
+	if decoded == nil {
+		return nil, fmt.Errorf("no method found for calldata: %s", hexutil.Encode(data))
+	}
+
+	decodeNestedCalls(decoded, decoder.nestedABIs, 0, decoder.PreserveTypes)
+
+	if !screenMethod(decoder.Compliance, nil, nil, decoded) {
+		return nil, fmt.Errorf("calldata blocked by compliance hook: %s", decoded.Signature)
+	}
+
+	nameMethod(decoder.ResolveNames, decoder.GetBackend(), decoded)
+	labelMethod(decoder.Labels, decoded)
+	attestMethod(decoder.Attestor, decoded)
+	return decoded, nil
+}
+
+// DecodeCalldataHex decodes calldata given as hex string, with or without 0x prefix.
+func (decoder *AbiDecoder) DecodeCalldataHex(data string) (*DecodedMethod, error) {
+	bytes, err := hexutil.Decode("0x" + strings.TrimPrefix(data, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid calldata hex: %w", err)
+	}
+
+	return decoder.DecodeCalldata(bytes)
+}
+
+// DecodeOutput decodes the return data of a method call. The method can be given by name,
+// by signature (e.g. "balanceOf(address)") or by its 4 byte selector as hex string.
+// Unnamed outputs are keyed by their position.
+func (decoder *AbiDecoder) DecodeOutput(methodSig string, data []byte) (Params, error) {
+	checkAbi(decoder)
+
+	method, err := findMethod(*decoder.Abi, methodSig)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := unpackArguments(method.Outputs, data)
+	if err != nil {
+		return nil, fmt.Errorf("error unpack output of %s: %w", method.Sig, err)
+	}
+
+	if decoder.PreserveTypes {
+		return params, nil
+	}
+
+	return formatParameters(params, decoder.Debug, method.Outputs...), nil
+}
+
+// CallAndDecode packs the given arguments for the method, performs an eth_call against the
+// decoder's contract address at the latest block and decodes the returned data.
+func (decoder *AbiDecoder) CallAndDecode(ctx context.Context, methodSig string, args ...interface{}) (Params, error) {
+	checkAbi(decoder)
+
+	if decoder.ContractAddress == nil {
+		return nil, fmt.Errorf("no contract address set for decoder")
+	}
+
+	if decoder.client == nil && Ctx.eth == nil {
+		return nil, fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", *decoder.ContractAddress)
+	}
+
+	method, err := findMethod(*decoder.Abi, methodSig)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("error pack input of %s: %w", method.Sig, err)
+	}
+
+	contract := common.HexToAddress(*decoder.ContractAddress)
+	output, err := decoder.GetBackend().CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: append(append([]byte{}, method.ID...), input...),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.DecodeOutput(method.Sig, output)
+}
+
+func (decoder *AbiDecoder) SetClient(client *ethclient.Client) {
+	decoder.SetBackend(asBackend(client))
+}
+
+// SetBackend sets the backend of the decoder, like SetClient for backends other than
+// *ethclient.Client, e.g. simulated backends or recorded fixtures in tests.
+func (decoder *AbiDecoder) SetBackend(backend Backend) {
+	decoder.client = backend
+	decoder.clientChainId = reportedChainID(backend)
+}
+
+// GetClient returns the client of the decoder, the global client if none is set. It is nil if the
+// backend is no *ethclient.Client, see GetBackend.
+func (decoder *AbiDecoder) GetClient() *ethclient.Client {
+	return asClient(decoder.GetBackend())
+}
+
+// GetBackend returns the backend of the decoder, the global backend if none is set. It is wrapped
+// with the Retry policy of the decoder, or the global one.
+func (decoder *AbiDecoder) GetBackend() Backend {
+	if decoder.client != nil {
+		return retrying(decoder.client, decoder.Retry)
+	}
+
+	return retrying(Ctx.eth, decoder.Retry)
+}
+
+func (decoder *AbiDecoder) RemoveClient() {
+	decoder.client = nil
+}
+
+func (decoder *AbiDecoder) Reset() {
+	decoder.client = nil
+	decoder.Abi = nil
+	decoder.ContractAddress = nil
+	decoder.Debug = nil
+}
+
+func (decoder *AbiDecoder) FilterLogEvents(filter ethereum.FilterQuery) (*ScannedLogs, error) {
+	if decoder.client == nil && Ctx.eth == nil {
+		return nil, fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", decoder.ContractAddress)
+	}
+
+	client := decoder.GetBackend()
+	logs, err := client.FilterLogs(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(ScannedLogs, 0)
+	for _, log := range logs {
+		decoded := decoder.DecodeLog(&log)
+
+		if decoded != nil {
+			events = append(events, *decoded)
+		}
+	}
+
+	return &events, nil
+}
+
+// DecodeReceipt fetches the receipt of the given transaction and decodes its logs. Failed
+// transactions are no error, see DecodeReceiptDetails for their decoded revert reason.
+func (decoder *AbiDecoder) DecodeReceipt(transactionHash string) (*ScannedLogs, error) {
+	if decoder.client == nil && Ctx.eth == nil {
+		return nil, fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", decoder.ContractAddress)
+	}
+
+	client := decoder.GetBackend()
+	receipt, err := client.TransactionReceipt(
+		context.Background(), common.HexToHash(transactionHash),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(ScannedLogs, 0)
+	if receipt.Logs != nil && len(receipt.Logs) > 0 {
+		for _, log := range receipt.Logs {
+			decoded := decoder.DecodeLog(log)
+
+			if decoded != nil {
+				events = append(events, *decoded)
+			}
+		}
+	}
+
+	return &events, nil
+}
+
+// DecodeReceiptDetails fetches the receipt and block header of the given transaction and returns
+// the decoded logs, the decoded revert reason of failed transactions and the fee breakdown,
+// including the L1 data fee on OP-stack chains.
+func (decoder *AbiDecoder) DecodeReceiptDetails(transactionHash string) (*DecodedReceipt, error) {
+	if decoder.client == nil && Ctx.eth == nil {
+		return nil, fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", decoder.ContractAddress)
+	}
+
+	ctx := context.Background()
+	client := decoder.GetBackend()
+	receipt, l1, err := fetchReceipt(ctx, client, common.HexToHash(transactionHash))
+	if err != nil {
+		return nil, err
+	}
+
+	result := DecodedReceipt{
+		TransactionHash: receipt.TxHash.Hex(),
+		Status:          receipt.Status,
+		GasUsed:         receipt.GasUsed,
+		Logs:            make(ScannedLogs, 0),
+	}
+
+	if receipt.BlockNumber != nil {
+		result.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+
+	for _, log := range receipt.Logs {
+		if decoded := decoder.DecodeLog(log); decoded != nil {
+			result.Logs = append(result.Logs, *decoded)
+		}
+	}
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		if revert, ok := decoder.decodeFailure(client, receipt).(*DecodedError); ok {
+			result.Revert = revert
+		}
+	}
+
+	header, err := client.HeaderByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Fees, err = ComputeFees(receipt, header); err != nil {
+		return nil, err
+	}
+
+	result.Fees.AddL1Fees(l1)
+
+	return &result, nil
+}
+
+// decodeFailure replays the failed transaction of the receipt and decodes its revert payload.
+// If the payload can not be fetched or decoded, a generic revert error is returned.
+func (decoder *AbiDecoder) decodeFailure(client Backend, receipt *types.Receipt) error {
+	ctx := context.Background()
+	transaction, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return err
+	}
+
+	payload, err := fetchRevertData(ctx, client, transaction, receipt)
+	if err != nil {
+		return fmt.Errorf("transaction reverted: %s: %w", receipt.TxHash.Hex(), err)
+	}
+
+	decoded, err := decoder.DecodeRevert(payload)
+	if err != nil {
+		return fmt.Errorf("transaction reverted: %s: %w", receipt.TxHash.Hex(), err)
+	}
+
+	decoded.TransactionHash = receipt.TxHash.Hex()
+	return decoded
+}
+
+func (decoder *AbiDecoder) DecodeTransaction(transactionHash string) (*DecodedMethod, error) {
+	if decoder.client == nil && Ctx.eth == nil {
+		return nil, fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", decoder.ContractAddress)
+	}
+
+	client := decoder.GetBackend()
+	hash := common.HexToHash(transactionHash)
+	transaction, _, err := client.TransactionByHash(context.Background(), hash)
+	if err != nil {
+		return nil, err
+	}
+
+	method := decoder.DecodeMethod(transaction)
+	return method, nil
+}
+
+// gets all signature hashes of given IndexedABI
+func (d *AbiDecoder) GetSigHashes() []string {
+	result := make([]string, 0)
+
+	for _, method := range d.Abi.Methods {
+		sigHash := ToSHA3(method.Sig)
+		result = append(result, sigHash[:10])
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i]) < len(result[j])
+	})
+
+	return result
+}
+
+// gets all signature hashes of given IndexedABI
+func (d *AbiDecoder) GetTopics() []string {
+	result := make([]string, 0)
+
+	for _, event := range d.Abi.Events {
+		topic := ToSHA3(event.Sig)
+		result = append(result, topic)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i]) < len(result[j])
+	})
+
+	return result
+}
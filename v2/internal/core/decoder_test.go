@@ -0,0 +1,962 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/exp/maps"
+)
+
+var (
+	// target_provider = "https://rpc-devnet-cardano-evm.c1.milkomeda.com"
+	// target_tx_hash  = "0x4f2316c83db20be4833c81f529f0eb51758ff14e4e455b4cbb203482053477f5"
+	// target_contract = "0xd22861049f6582BcAd6b7a33F211e6fC701DBBBB"
+	// target_erc20    = "0x09eac2100FF33c3083a822F8DCe9f92415b77B48"
+
+	target_provider = "http://localhost:8556"
+	target_tx_hash  = "0x818c265a4fbc77e4dde8462cf3071b3e0ccff21d3e8c386b9ae158797a4bda12"
+	target_contract = "0xBBd0AeC7527d1beD439C94DE4b11b0298177097B"
+	target_erc20    = "0x594cBC09284981fF5e45F00d65d07f81f4C8B23d"
+	target_erc721   = "0xFCf480d9b5E42666763fa6E3F834611571b0Dc35"
+	all_abis_parsed = MergeABIs(ALL_DEFAULT_ABIS...)
+)
+
+var user_abi = `
+[
+    {
+    "anonymous": false,
+    "inputs": [
+      {
+        "indexed": true,
+        "internalType": "address",
+        "name": "delegator",
+        "type": "address"
+      },
+      {
+        "indexed": true,
+        "internalType": "address",
+        "name": "fromDelegate",
+        "type": "address"
+      },
+      {
+        "indexed": true,
+        "internalType": "address",
+        "name": "toDelegate",
+        "type": "address"
+      }
+    ],
+    "name": "DelegateChanged",
+    "type": "event"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "delegatee",
+        "type": "address"
+      }
+    ],
+    "name": "delegate",
+    "outputs": [],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  }
+]
+`
+
+func init() {
+	fmt.Println("initializing provider", target_provider)
+	client, err := ethclient.Dial(target_provider)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	Store.SetClient(client)
+}
+
+func TestMergeAbISs(t *testing.T) {
+	abis := MergeABIs(ALL_DEFAULT_ABIS...)
+
+	t.Log("merged json")
+	t.Log("methods parsed:", len(abis.Methods))
+}
+
+func TestAbiStore(t *testing.T) {
+	Store.ParseAndAddABIs(ALL_DEFAULT_ABIS...)
+	t.Logf("%v ABIS added to Store", len(Store.AbiList))
+}
+
+func TestDecodeMethod(t *testing.T) {
+	txHash := common.HexToHash(target_tx_hash)
+
+	// Create a new instance of the ABI decoder
+	decoder := AbiDecoder{
+		client: Store.GetBackend(),
+	}
+
+	// // Add the ABI to the decoder
+	// decoder.SetABI(ParseABI(user_abi))
+	decoder.SetABI(*all_abis_parsed)
+	transaction, _, err := decoder.client.TransactionByHash(context.Background(), txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decode a method call
+	method := decoder.DecodeMethod(transaction)
+	t.Logf("Decoded method: %s - sigHash: %s", method.Signature, method.SigHash)
+
+	method = Store.DecodeMethod(transaction)
+	t.Logf("Decoded method (Store): %s - sigHash: %s", method.Signature, method.SigHash)
+
+	t.Logf(method.ToJSON())
+}
+
+func TestDecodeLogs(t *testing.T) {
+	txHash := common.HexToHash(target_tx_hash)
+
+	// Create a new instance of the ABI decoder
+	decoder := AbiDecoder{
+		Abi:    all_abis_parsed,
+		client: Store.GetBackend(),
+	}
+
+	// Add the ABI to the decoder
+	// decoder.FromJSON(abi_dao_token)
+
+	receipt, err := decoder.client.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decode an event
+	for _, event := range decoder.DecodeLogs(receipt.Logs) {
+		t.Logf("Decoded event: %s - sigHash: %s - contract: %s", event.GetSig(), event.Topic, event.Contract)
+		t.Log(event.GetParamsJSON())
+	}
+
+	// Decode an event using Store Decoder
+	for _, event := range Store.DecodeLogs(receipt.Logs) {
+		t.Logf("Decoded event (Store): %s - topic: %s - contract: %s", event.GetSig(), event.Topic, event.Contract)
+		t.Log(event.GetParamsJSON())
+	}
+}
+
+func TestIndexedDecoder(t *testing.T) {
+	Store.SetIndexed(target_contract, *ParseABI(ALL_DEFAULT_ABIS[12]), true, false, nil)
+	s := Store.GetIndexed(target_contract)
+	t.Logf(`bytecode: %v - size: %v`, s.Bytecode, len(*s.Bytecode))
+
+	if s.Address.Hex() != target_contract {
+		t.Fatalf("invalid result address: %s", s.Address)
+	}
+
+	decoder := s.GetDecoder()
+
+	maps.Keys(decoder.Abi.Methods)
+
+	t.Logf("Decoder contains %v methods and %v events", len(decoder.Abi.Methods), len(decoder.Abi.Events))
+	t.Logf("Decoder assigned to contract %v", *decoder.ContractAddress)
+
+	for signature, sigHash := range s.GetSigHashes() {
+		t.Logf("signature: %v - sigHash: %v", signature, sigHash)
+	}
+
+	for _, topic := range s.GetTopics() {
+		t.Logf("topic: %v", topic)
+	}
+
+	for _, signature := range s.GetSignatures() {
+		t.Logf("signature: %v", signature)
+	}
+
+	t.Logf("isToken: %v", IsToken(*s.Bytecode))
+	t.Logf("isERC20: %v", IsERC20(*s.Bytecode))
+	t.Logf("isERC721: %v", IsERC721(*s.Bytecode))
+
+	t.Logf("topics in bytecode: %v", DetectBytecodes(*s.Bytecode, s.GetSigHashes()))
+}
+
+func TestSHA3(t *testing.T) {
+	hash := ToSHA3("DelegateChanged(address,address,address)")
+	if hash != "0x3134e8a2e6d97e929a7e54011ea5485d7d196dd5f0ba4d4ef95803e8e3fc257f" {
+		t.Fatalf("solidity returned incorrect hash: %v", hash)
+	}
+}
+
+func TestScanLogs(t *testing.T) {
+	decoder := AbiDecoder{
+		Abi:             all_abis_parsed,
+		ContractAddress: &target_contract,
+	}
+
+	blockNumber, err := decoder.GetClient().BlockNumber(context.Background())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if big.NewInt(int64(blockNumber)).Uint64() <= 0 {
+		t.Fatal("error getting block number")
+	}
+
+	events, err := decoder.FilterLogEvents(ethereum.FilterQuery{
+		// FromBlock: big.NewInt(int64(blockNumber - 100000)),
+		// ToBlock:   big.NewInt(int64(blockNumber)),
+		// FromBlock: big.NewInt(10142711),
+		// ToBlock:   big.NewInt(10730523),
+		Addresses: []common.Address{
+			common.HexToAddress(target_contract),
+		},
+	})
+
+	if err != nil {
+		t.Fatal("error scanning logs", err)
+	}
+
+	if events == nil || len(*events) < 1 {
+		t.Fatal("no events found")
+	}
+
+	t.Logf("%v events found", len(*events))
+}
+
+func TestScanTransaction(t *testing.T) {
+	decoder := AbiDecoder{
+		Abi:             all_abis_parsed,
+		ContractAddress: &target_contract,
+	}
+
+	method, err := decoder.DecodeTransaction(target_tx_hash)
+	if err != nil {
+		t.Fatal("error scanning transaction", err)
+	}
+
+	t.Log(string(method.ToJSONBytes()))
+}
+
+func TestERC20(t *testing.T) {
+	addr := "0x21540074Ac4c37da80BAC3E6674E10a2242fc2B4"
+	i := Store.SetIndexed(addr, *ParseABI(abi_dao_token), false, true, nil)
+
+	decoder := i.GetDecoder()
+
+	if decoder.ContractAddress == &target_contract {
+		t.Log("Indexed Decoder Loaded")
+	}
+
+	events, err := decoder.FilterLogEvents(ethereum.FilterQuery{
+		Addresses: []common.Address{
+			common.HexToAddress(addr),
+		},
+	})
+
+	if err != nil {
+		t.Fatal("error scanning erc721 logs", err)
+	}
+
+	t.Log(string(events.ToJSONBytes()))
+
+	if !i.IsToken {
+		t.Fatalf("given contract not recognized as token")
+	}
+
+	if i.IsToken && i.IsERC721 != nil && *i.IsERC721 {
+		t.Fatalf("given contract is a ERC721 token")
+	}
+}
+
+func TestDecodeRawLog(t *testing.T) {
+	contract := target_erc20
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), ContractAddress: &contract}
+
+	from := common.HexToAddress(target_contract)
+	to := common.HexToAddress(target_erc721)
+	topics := []common.Hash{
+		decoder.Abi.Events["Transfer"].ID,
+		common.BytesToHash(from.Bytes()),
+		common.BytesToHash(to.Bytes()),
+	}
+
+	decoded := decoder.DecodeRawLog(topics, common.LeftPadBytes(big.NewInt(1000).Bytes(), 32))
+	if decoded == nil {
+		t.Fatal("raw log not decoded")
+	}
+
+	if decoded.Params["from"] != from.Hex() || decoded.Params["to"] != to.Hex() || decoded.Params["value"] != "1000" {
+		t.Fatalf("invalid raw log params: %v", decoded.GetParamsJSON())
+	}
+
+	if decoded.Contract != common.HexToAddress(target_erc20).Hex() {
+		t.Fatalf("invalid raw log contract: %v", decoded.Contract)
+	}
+
+	if decoded.Name != "Transfer" || len(decoded.Inputs) != 3 || !decoded.Inputs[0].Indexed || decoded.Inputs[2].Type != "uint256" {
+		t.Fatalf("invalid raw log metadata: %v %+v", decoded.Name, decoded.Inputs)
+	}
+
+	if len(decoded.Topics) != 3 || decoded.Data != hexutil.Encode(common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)) {
+		t.Fatalf("invalid raw log data: %v %v", decoded.Topics, decoded.Data)
+	}
+}
+
+func TestExplainLog(t *testing.T) {
+	store := Storage{}
+	store.AddABI("erc721", *ParseABI(`[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}],"name":"Transfer","type":"event"}]`))
+	store.AddABI("erc20", *ParseABI(abi_erc20))
+
+	// an ERC20 transfer, the ERC721 Transfer event expects the token id as third topic
+	vLog := &types.Log{
+		Topics: []common.Hash{
+			common.HexToHash(TransferTopic),
+			common.BytesToHash(common.HexToAddress(target_contract).Bytes()),
+			common.BytesToHash(common.HexToAddress(target_erc721).Bytes()),
+		},
+		Data: common.LeftPadBytes(big.NewInt(1000).Bytes(), 32),
+	}
+
+	trace := store.ExplainLog(vLog)
+	if len(trace.Attempts) != 2 || trace.Key != TransferTopic {
+		t.Fatalf("invalid trace: %v", trace.ToJSON())
+	}
+
+	if !strings.Contains(trace.Attempts[0].Reason, "topic count mismatch") || !trace.Attempts[0].Matched {
+		t.Fatalf("erc721 mismatch not explained: %+v", trace.Attempts[0])
+	}
+
+	if !strings.Contains(trace.Attempts[1].Reason, "shadowed by") {
+		t.Fatalf("shadowed erc20 event not explained: %+v", trace.Attempts[1])
+	}
+
+	data, _ := ParseABI(abi_erc20).Pack("transfer", common.HexToAddress(target_contract), big.NewInt(1))
+	method := store.ExplainMethod(types.NewTransaction(0, common.HexToAddress(target_erc20), big.NewInt(0), 0, big.NewInt(0), data))
+	if method.Key != "0xa9059cbb" || method.Choice != "transfer(address,uint256)" || method.Attempts[0].Reason != "selector not in abi" {
+		t.Fatalf("invalid method trace: %v", method.ToJSON())
+	}
+}
+
+func TestDecodeDeployment(t *testing.T) {
+	decoder := AbiDecoder{
+		Abi: ParseABI(`[{"inputs":[{"name":"name","type":"string"},{"name":"supply","type":"uint256"}],"stateMutability":"nonpayable","type":"constructor"}]`),
+	}
+
+	args, err := decoder.Abi.Constructor.Inputs.Pack("Token", big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, _ := crypto.GenerateKey()
+	initCode := common.FromHex("0x6080604052348015600f57600080fd5b50603f80601d6000396000f3fe")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{
+		Nonce: 7,
+		Gas:   100000,
+		Data:  append(initCode, args...),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deployment, err := decoder.DecodeDeployment(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creator := crypto.PubkeyToAddress(key.PublicKey)
+	if deployment.Contract != crypto.CreateAddress(creator, 7).Hex() {
+		t.Fatalf("invalid created address: %v", deployment.Contract)
+	}
+
+	if deployment.Bytecode != hexutil.Encode(initCode) {
+		t.Fatalf("invalid init code split: %v", deployment.Bytecode)
+	}
+
+	if deployment.Params["name"] != "Token" || deployment.Params["supply"] != "1000" {
+		t.Fatalf("invalid constructor params: %v", deployment.ToJSON())
+	}
+}
+
+func TestDecodeOutput(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+
+	data := common.LeftPadBytes(big.NewInt(1500).Bytes(), 32)
+	for _, sig := range []string{"balanceOf", "balanceOf(address)", "0x70a08231"} {
+		params, err := decoder.DecodeOutput(sig, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if params["0"] != "1500" {
+			t.Fatalf("invalid output of %s: %v", sig, params)
+		}
+	}
+}
+
+func TestDecodeCalldata(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+
+	data, _ := decoder.Abi.Pack("transfer", common.HexToAddress(target_erc20), big.NewInt(42))
+	decoded, err := decoder.DecodeCalldataHex(common.Bytes2Hex(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Signature != "transfer(address,uint256)" || decoded.Params["value"] != "42" {
+		t.Fatalf("invalid decoded calldata: %v", decoded.ToJSON())
+	}
+
+	if decoded.Name != "transfer" || decoded.Inputs[0].Name != "to" || decoded.Inputs[0].Type != "address" || decoded.Data != hexutil.Encode(data) {
+		t.Fatalf("invalid calldata metadata: %v", decoded.ToJSON())
+	}
+
+	if _, err := decoder.DecodeCalldata(common.FromHex("0xdeadbeef")); err == nil {
+		t.Fatalf("expected error for unknown selector")
+	}
+}
+
+func TestIncludeTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	token := common.HexToAddress(target_erc20)
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), IncludeTx: true}
+	data, _ := decoder.Abi.Pack("transfer", common.HexToAddress(target_contract), big.NewInt(42))
+
+	chainId := big.NewInt(5)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainId), &types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     3,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(30),
+		Gas:       60000,
+		To:        &token,
+		Value:     big.NewInt(0),
+		Data:      data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := decoder.DecodeMethod(tx)
+	if decoded == nil || decoded.Tx == nil {
+		t.Fatal("transaction fields not included")
+	}
+
+	info := decoded.Tx
+	if info.From != sender.Hex() || info.Nonce != 3 || info.Gas != 60000 || info.Value != "0" {
+		t.Fatalf("unexpected transaction fields: %+v", info)
+	}
+	if info.GasFeeCap != "30" || info.GasTipCap != "2" || info.GasPrice != "" || info.ChainId == nil || *info.ChainId != 5 {
+		t.Fatalf("unexpected gas fields: %+v", info)
+	}
+	if info.Type != types.DynamicFeeTxType || info.TypeName != "dynamicFee" {
+		t.Fatalf("unexpected type fields: %+v", info)
+	}
+
+	accessList := types.AccessList{{Address: token, StorageKeys: []common.Hash{common.HexToHash("0x01")}}}
+	tx2930, err := types.SignNewTx(key, types.LatestSignerForChainID(chainId), &types.AccessListTx{
+		ChainID:    chainId,
+		Nonce:      4,
+		GasPrice:   big.NewInt(20),
+		Gas:        60000,
+		To:         &token,
+		Value:      big.NewInt(0),
+		Data:       data,
+		AccessList: accessList,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info = decoder.DecodeMethod(tx2930).Tx
+	if info.TypeName != "accessList" || info.GasPrice != "20" || info.GasFeeCap != "" || len(info.AccessList) != 1 || info.AccessList[0].StorageKeys[0] != accessList[0].StorageKeys[0] {
+		t.Fatalf("unexpected access list fields: %+v", info)
+	}
+
+	if name := TxTypeName(SetCodeTxType); name != "setCode" {
+		t.Fatalf("unexpected name of set code transactions: %s", name)
+	}
+	if name := TxTypeName(0x50); name != "0x50" {
+		t.Fatalf("unexpected name of unknown types: %s", name)
+	}
+
+	if from := Ctx.GetTxFrom(tx); from == nil || *from != sender.Hex() {
+		t.Fatalf("unexpected sender of the context: %v", from)
+	}
+
+	decoder.IncludeTx = false
+	if decoded := decoder.DecodeMethod(tx); decoded.Tx != nil {
+		t.Fatal("expected no transaction fields without IncludeTx")
+	}
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	defer ResetFormatters()
+
+	RegisterFormatter("uint256", func(name string, value interface{}) interface{} {
+		return FormatAmount(value.(*big.Int), 18)
+	})
+	RegisterParamFormatter("to", func(name string, value interface{}) interface{} {
+		return "to:" + value.(common.Address).Hex()
+	})
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	data, _ := decoder.Abi.Pack("transfer", common.HexToAddress(target_erc20), big.NewInt(1500000000000000000))
+	decoded, err := decoder.DecodeCalldata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Params["value"] != "1.5" || decoded.Params["to"] != "to:"+common.HexToAddress(target_erc20).Hex() {
+		t.Fatalf("formatters not applied: %v", decoded.Params)
+	}
+
+	params, err := decoder.DecodeOutput("balanceOf", common.LeftPadBytes(big.NewInt(2e18).Bytes(), 32))
+	if err != nil || params["0"] != "2" {
+		t.Fatalf("formatter not applied to unnamed output: %v %v", params, err)
+	}
+}
+
+func TestEncodeMethod(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+
+	data, _ := decoder.Abi.Pack("transfer", common.HexToAddress(target_erc20), big.NewInt(42))
+	decoded, err := decoder.DecodeCalldata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := decoder.EncodeMethod("transfer", decoded.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Fatalf("invalid round trip: %x != %x", encoded, data)
+	}
+
+	decoder.Abi = ParseABI(`[{"inputs":[{"name":"ids","type":"uint16[]"},{"name":"key","type":"bytes32"},{"components":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"order","type":"tuple"}],"name":"submit","outputs":[],"type":"function"}]`)
+	encoded, err = decoder.EncodeMethod("submit", Params{
+		"ids":   []string{"1", "0x10"},
+		"key":   common.HexToHash("0x01").Hex(),
+		"order": map[string]interface{}{"to": target_contract, "amount": "1000000000000000000000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err = decoder.DecodeCalldata(encoded)
+	if err != nil || decoded.Params["key"] != common.HexToHash("0x01").Hex() {
+		t.Fatalf("invalid encoded calldata: %v %v", decoded, err)
+	}
+
+	if ids := decoded.Params["ids"].([]uint16); len(ids) != 2 || ids[1] != 16 {
+		t.Fatalf("invalid encoded ids: %v", ids)
+	}
+
+	if _, err := decoder.EncodeMethod("submit", Params{"ids": []string{"70000"}}); err == nil {
+		t.Fatal("expected error for overflowing uint16")
+	}
+}
+
+func TestDecodeRevert(t *testing.T) {
+	decoder := AbiDecoder{
+		Abi: ParseABI(`[{"inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}],"name":"InsufficientBalance","type":"error"}]`),
+	}
+
+	reason, _ := abi.NewType("string", "", nil)
+	payload, _ := abi.Arguments{{Type: reason}}.Pack("not owner")
+	decoded, err := decoder.DecodeRevert(append(common.FromHex("0x08c379a0"), payload...))
+	if err != nil || decoded.Reason != "not owner" {
+		t.Fatalf("invalid Error(string) revert: %v %v", decoded, err)
+	}
+
+	decoded, err = decoder.DecodeRevert(append(common.FromHex("0x4e487b71"), common.LeftPadBytes([]byte{0x11}, 32)...))
+	if err != nil || decoded.Params["code"] != "17" {
+		t.Fatalf("invalid Panic(uint256) revert: %v %v", decoded, err)
+	}
+
+	customError := decoder.Abi.Errors["InsufficientBalance"]
+	args, _ := customError.Inputs.Pack(big.NewInt(10), big.NewInt(20))
+	decoded, err = decoder.DecodeRevert(append(customError.ID[:4], args...))
+	if err != nil || decoded.Signature != "InsufficientBalance(uint256,uint256)" || decoded.Params["required"] != "20" {
+		t.Fatalf("invalid custom error revert: %v %v", decoded, err)
+	}
+}
+
+func TestDecodeReceiptOfFailedTransaction(t *testing.T) {
+	backend := newFixtureBackend(t, 1)
+	tx := backend.chain.blocks[1]["transactions"].([]*types.Transaction)[0]
+	backend.chain.receipts[tx.Hash()].Status = types.ReceiptStatusFailed
+
+	decoder := &AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoder.SetBackend(backend)
+
+	// the revert reason is reported by DecodeReceiptDetails, the events are returned as before
+	logs, err := decoder.DecodeReceipt(tx.Hash().Hex())
+	if err != nil || len(*logs) != 1 || (*logs)[0].Name != "Transfer" {
+		t.Fatalf("events of the failed transaction not returned: %v %v", logs, err)
+	}
+}
+
+func TestDecodeNestedCalls(t *testing.T) {
+	multicallAbi := `[
+		{"inputs":[{"name":"data","type":"bytes[]"}],"name":"multicall","outputs":[],"stateMutability":"payable","type":"function"},
+		{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[],"stateMutability":"payable","type":"function"}
+	]`
+	decoder := AbiDecoder{Abi: MergeABIs(abi_erc20, multicallAbi)}
+
+	transfer, _ := decoder.Abi.Pack("transfer", common.HexToAddress(target_erc20), big.NewInt(5))
+	inner, _ := decoder.Abi.Pack("multicall", [][]byte{transfer, transfer})
+
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	outer, err := decoder.Abi.Pack("aggregate3", []call3{{Target: common.HexToAddress(target_contract), CallData: inner}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	to := common.HexToAddress(target_erc721)
+	decoded := decoder.DecodeMethod(types.NewTx(&types.LegacyTx{To: &to, Data: outer}))
+	if decoded == nil || len(decoded.Calls) != 1 {
+		t.Fatalf("multicall not decoded: %v", decoded)
+	}
+
+	if decoded.Calls[0].Contract != target_contract || len(decoded.Calls[0].Calls) != 2 {
+		t.Fatalf("invalid nested multicall: %v", decoded.ToJSON())
+	}
+
+	if decoded.Calls[0].Calls[1].Signature != "transfer(address,uint256)" || decoded.Calls[0].Calls[1].Params["value"] != "5" {
+		t.Fatalf("invalid nested transfer: %v", decoded.ToJSON())
+	}
+}
+
+func TestDecodeSafeTransaction(t *testing.T) {
+	decoder := AbiDecoder{Abi: MergeABIs(abi_safe, abi_erc20)}
+
+	transfer, _ := decoder.Abi.Pack("transfer", common.HexToAddress(target_erc721), big.NewInt(7))
+
+	// multiSend packs operation, to, value, data length and data of every transaction
+	var packed []byte
+	for i := 0; i < 2; i++ {
+		packed = append(packed, 0)
+		packed = append(packed, common.HexToAddress(target_erc20).Bytes()...)
+		packed = append(packed, common.LeftPadBytes(nil, 32)...)
+		packed = append(packed, common.LeftPadBytes(big.NewInt(int64(len(transfer))).Bytes(), 32)...)
+		packed = append(packed, transfer...)
+	}
+
+	multiSend, _ := decoder.Abi.Pack("multiSend", packed)
+	data, err := decoder.Abi.Pack("execTransaction",
+		common.HexToAddress(target_contract), big.NewInt(0), multiSend, uint8(1),
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, []byte{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	safe := common.HexToAddress(target_erc721)
+	decoded := decoder.DecodeMethod(types.NewTx(&types.LegacyTx{To: &safe, Data: data}))
+	if decoded == nil || len(decoded.Calls) != 1 || decoded.Calls[0].Signature != "multiSend(bytes)" {
+		t.Fatalf("safe inner call not decoded: %v", decoded)
+	}
+
+	batch := decoded.Calls[0].Calls
+	if len(batch) != 2 || batch[0].Contract != target_erc20 || batch[1].Params["value"] != "7" {
+		t.Fatalf("invalid multiSend batch: %v", decoded.ToJSON())
+	}
+
+	// transactions with lengths past the batch end it, including lengths overflowing the offsets
+	for _, length := range []*big.Int{big.NewInt(int64(len(transfer) + 1)), big.NewInt(math.MaxInt64 - 10), new(big.Int).Lsh(common.Big1, 255)} {
+		crafted := append([]byte{}, packed[:len(packed)/2]...)
+		crafted = append(crafted, 0)
+		crafted = append(crafted, common.HexToAddress(target_erc20).Bytes()...)
+		crafted = append(crafted, common.LeftPadBytes(nil, 32)...)
+		crafted = append(crafted, common.LeftPadBytes(length.Bytes(), 32)...)
+		crafted = append(crafted, transfer...)
+
+		multiSend, _ := decoder.Abi.Pack("multiSend", crafted)
+		decoded := decoder.DecodeMethod(types.NewTx(&types.LegacyTx{To: &safe, Data: multiSend}))
+		if decoded == nil || len(decoded.Calls) != 1 || decoded.Calls[0].Params["value"] != "7" {
+			t.Fatalf("invalid batch with length %s: %v", length, decoded)
+		}
+	}
+}
+
+func TestDecodeCallFrame(t *testing.T) {
+	erc20 := ParseABI(abi_erc20)
+	input, _ := erc20.Pack("balanceOf", common.HexToAddress(target_contract))
+
+	var frame callFrame
+	err := json.Unmarshal([]byte(`{
+		"type": "CALL", "from": "`+target_contract+`", "to": "`+target_erc20+`", "value": "0x1", "gasUsed": "0x5208",
+		"input": "0x", "calls": [{"type": "STATICCALL", "from": "`+target_erc20+`", "to": "`+target_erc20+`",
+		"input": "`+hexutil.Encode(input)+`", "output": "0x`+common.Bytes2Hex(common.LeftPadBytes([]byte{9}, 32))+`"}]
+	}`), &frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := decodeCallFrame(frame, []abi.ABI{*erc20})
+	if tree.Value != "1" || tree.GasUsed != 21000 || len(tree.Calls) != 1 {
+		t.Fatalf("invalid root frame: %v", tree.ToJSON())
+	}
+
+	if call := tree.Calls[0]; call.Signature != "balanceOf(address)" || call.Output["0"] != "9" {
+		t.Fatalf("invalid sub call: %v", tree.ToJSON())
+	}
+}
+
+func TestDecodeCloneDeployment(t *testing.T) {
+	implementation := common.HexToAddress(target_erc20)
+	initCode := append(append(common.FromHex("0x3d602d80600a3d3981f3363d3d373d3d3d363d73"), implementation.Bytes()...),
+		common.FromHex("0x5af43d82803e903d91602b57fd5bf3")...)
+
+	key, _ := crypto.GenerateKey()
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{Gas: 100000, Data: initCode})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(indexed map[string]*IndexedABI) { Store.Indexed = indexed }(Store.Indexed)
+	Store.Indexed = map[string]*IndexedABI{implementation.Hex(): {Address: implementation, Abi: *ParseABI(abi_erc20), Verified: true}}
+
+	decoder := AbiDecoder{Abi: ParseABI(`[{"inputs":[{"name":"owner","type":"address"}],"type":"constructor"}]`)}
+	deployment, err := decoder.DecodeDeployment(tx)
+	if err != nil || deployment.Implementation != implementation.Hex() {
+		t.Fatalf("minimal proxy not detected: %v %v", deployment, err)
+	}
+
+	clone := Store.GetIndexed(deployment.Contract)
+	if clone == nil || clone.Address.Hex() != deployment.Contract || clone.Abi.Methods["transfer"].Sig != "transfer(address,uint256)" {
+		t.Fatalf("implementation abi not assigned to clone: %v", clone)
+	}
+}
+
+func TestVersionedIndexedABI(t *testing.T) {
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	v1 := ParseABI(`[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Transfer","type":"event"}]`)
+	v2 := ParseABI(abi_erc20)
+
+	store := Storage{Indexed: make(map[string]*IndexedABI)}
+	contract := common.HexToAddress(target_erc20)
+	store.SetIndexed(contract.Hex(), *v1, true, false, nil, BlockRange{From: 0, To: 99})
+	store.SetIndexed(contract.Hex(), *v2, true, false, nil, BlockRange{From: 100})
+
+	log := func(block uint64) *DecodedLog {
+		return store.DecodeLog(&types.Log{
+			Address:     contract,
+			Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+			Data:        common.LeftPadBytes([]byte{1}, 32),
+			BlockNumber: block,
+		})
+	}
+
+	if decoded := log(50); decoded == nil || decoded.Params["amount"] != "1" {
+		t.Fatalf("v1 abi not used: %v", decoded)
+	}
+
+	if decoded := log(150); decoded == nil || decoded.Params["value"] != "1" {
+		t.Fatalf("v2 abi not used: %v", decoded)
+	}
+
+	if indexed := store.GetIndexed(contract.Hex()); len(indexed.Versions) != 2 || indexed.Abi.Methods["transfer"].Sig == "" {
+		t.Fatalf("invalid versions: %v", indexed.Versions)
+	}
+}
+
+func TestAddressScopedDecoding(t *testing.T) {
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	// ERC-20 and ERC-721 share the topic of Transfer and the selector of transferFrom
+	erc721 := ParseABI(`[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}],"name":"Transfer","type":"event"},{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"name":"transferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"}]`)
+	store := Storage{AbiList: []abi.ABI{*ParseABI(abi_erc20)}, Indexed: make(map[string]*IndexedABI)}
+	nft := common.HexToAddress(target_contract)
+	store.SetIndexed(strings.ToLower(nft.Hex()), *erc721, true, true, nil)
+
+	vLog := &types.Log{
+		Address: nft,
+		Topics:  []common.Hash{common.HexToHash(TransferTopic), {}, {}, common.BigToHash(big.NewInt(7))},
+	}
+	if decoded := store.DecodeLog(vLog); decoded == nil || decoded.Params["tokenId"] == nil {
+		t.Fatalf("indexed abi not used for log: %v", decoded)
+	}
+
+	data, _ := erc721.Pack("transferFrom", common.Address{}, common.Address{}, big.NewInt(7))
+	tx := types.NewTx(&types.LegacyTx{To: &nft, Data: data})
+	if decoded := store.DecodeMethod(tx); decoded == nil || decoded.Params["tokenId"] != "7" {
+		t.Fatalf("indexed abi not used for call: %v", decoded)
+	}
+
+	// other addresses fall back to the global list
+	token := common.HexToAddress(target_erc20)
+	tx = types.NewTx(&types.LegacyTx{To: &token, Data: data})
+	if decoded := store.DecodeMethod(tx); decoded == nil || decoded.Params["value"] != "7" {
+		t.Fatalf("global abi not used: %v", decoded)
+	}
+}
+
+func TestDecodeWithABI(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc721), PreserveTypes: true}
+	vLog := &types.Log{
+		Address: common.HexToAddress(target_erc20),
+		Topics:  []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+		Data:    common.LeftPadBytes([]byte{1}, 32),
+	}
+
+	decoded := decoder.DecodeLogWithABI(vLog, *ParseABI(abi_erc20))
+	if decoded == nil || decoded.Params["value"].(*big.Int).Int64() != 1 {
+		t.Fatalf("override abi not used: %v", decoded)
+	}
+
+	if _, ok := decoder.Abi.Methods["transfer"]; ok {
+		t.Fatal("decoder abi replaced by override")
+	}
+
+	store := Storage{}
+	if decoded := store.DecodeLogWithABI(vLog, *ParseABI(abi_erc20)); decoded == nil || len(store.AbiList) != 0 {
+		t.Fatalf("invalid store override: %v", decoded)
+	}
+}
+
+func TestClassifyLog(t *testing.T) {
+	store := Storage{AbiList: []abi.ABI{*ParseABI(abi_erc20)}}
+	vLog := &types.Log{
+		Address: common.HexToAddress(target_erc20),
+		Topics:  []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+		Data:    common.LeftPadBytes([]byte{1}, 32),
+		Index:   3,
+	}
+
+	classified := store.ClassifyLog(vLog)
+	if classified == nil || classified.Name != "Transfer" || classified.Params != nil || classified.Hydrated() {
+		t.Fatalf("invalid classification: %v", classified)
+	}
+
+	if err := classified.Hydrate(); err != nil || !classified.Hydrated() || classified.Params["value"] != "1" {
+		t.Fatalf("invalid hydration: %v %v", classified.Params, err)
+	}
+
+	if decoded := store.DecodeLog(vLog); decoded.ToJSON() != classified.ToJSON() {
+		t.Fatalf("hydrated log differs:\n%s\n%s", decoded.ToJSON(), classified.ToJSON())
+	}
+
+	if store.ClassifyLog(&types.Log{Topics: []common.Hash{{}}}) != nil {
+		t.Fatal("unknown topic classified")
+	}
+}
+
+func TestLazyHandles(t *testing.T) {
+	store := Storage{AbiList: []abi.ABI{*ParseABI(abi_erc20)}}
+	vLog := &types.Log{
+		Address: common.HexToAddress(target_erc20),
+		Topics:  []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+		Data:    common.LeftPadBytes([]byte{1}, 32),
+	}
+
+	handles := store.LazyLogs([]*types.Log{vLog, {Topics: []common.Hash{{}}}})
+	if len(handles) != 1 || handles[0].Name != "Transfer" || handles[0].Raw() != vLog {
+		t.Fatalf("invalid log handles: %v", handles)
+	}
+
+	first, err := handles[0].Hydrate()
+	if second, _ := handles[0].Hydrate(); err != nil || first != second || handles[0].Params()["value"] != "1" {
+		t.Fatalf("log not memoized: %v %v", first, err)
+	}
+
+	data, _ := ParseABI(abi_erc20).Pack("transfer", common.HexToAddress(target_erc20), big.NewInt(7))
+	handle := store.LazyMethod(types.NewTransaction(0, common.HexToAddress(target_erc20), big.NewInt(0), 0, big.NewInt(0), data))
+	if handle == nil || handle.Name != "transfer" || handle.Params()["value"] != "7" {
+		t.Fatalf("invalid method handle: %v", handle)
+	}
+
+	if decoded, err := handle.Hydrate(); err != nil || decoded.IdempotencyKey == "" {
+		t.Fatalf("invalid method hydration: %v %v", decoded, err)
+	}
+}
+
+func TestBuildFilter(t *testing.T) {
+	contract := target_erc20
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), ContractAddress: &contract}
+
+	filter, err := decoder.BuildFilter("Transfer", map[string]interface{}{
+		"to": []string{target_contract, target_erc20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(filter.Addresses) != 1 || len(filter.Topics) != 3 || filter.Topics[0][0] != common.HexToHash(TransferTopic) {
+		t.Fatalf("invalid filter: %+v", filter)
+	}
+
+	if filter.Topics[1] != nil || len(filter.Topics[2]) != 2 || filter.Topics[2][0] != common.BytesToHash(common.HexToAddress(target_contract).Bytes()) {
+		t.Fatalf("invalid address topics: %+v", filter.Topics)
+	}
+
+	if _, err := decoder.BuildFilter("Transfer", map[string]interface{}{"value": "1"}); err == nil {
+		t.Fatal("non indexed param filtered")
+	}
+
+	store := Storage{AbiList: []abi.ABI{*ParseABI(abi_erc20)}}
+	if filter, err := store.BuildFilter("Transfer(address,address,uint256)", nil); err != nil || len(filter.Topics) != 1 {
+		t.Fatalf("invalid store filter: %+v %v", filter, err)
+	}
+}
+
+func TestAmbiguities(t *testing.T) {
+	nft := `[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}],"name":"Transfer","type":"event"}]`
+	store := Storage{AbiList: []abi.ABI{*ParseABI(abi_erc20), *ParseABI(nft)}}
+
+	vLog := &types.Log{
+		Address: common.HexToAddress(target_erc20),
+		Topics:  []common.Hash{common.HexToHash(TransferTopic), {}, {}, common.BigToHash(big.NewInt(5))},
+	}
+
+	if decoded := store.DecodeLog(vLog); decoded == nil || decoded.Params["tokenId"] != nil {
+		t.Fatalf("log not decoded with the first abi: %v", decoded)
+	}
+
+	ambiguities := store.Ambiguities()
+	if len(ambiguities) != 1 || ambiguities[0].Key != TransferTopic || len(ambiguities[0].Candidates) != 2 {
+		t.Fatalf("invalid ambiguities: %v", ambiguities)
+	}
+
+	if len(ambiguities[0].Contracts) != 1 || ambiguities[0].Contracts[0] != common.HexToAddress(target_erc20).Hex() {
+		t.Fatalf("contract not observed: %v", ambiguities[0].Contracts)
+	}
+
+	if err := store.ResolveAmbiguity(TransferTopic, "Transfer(address,address,uint256)"); err == nil {
+		t.Fatal("pinned signature without indexed markers")
+	}
+
+	if err := store.ResolveAmbiguity(TransferTopic, "Transfer(address indexed,address indexed,uint256 indexed)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded := store.DecodeLog(vLog); decoded == nil || decoded.Params["tokenId"] == nil {
+		t.Fatalf("log not decoded with the pinned abi: %v", decoded)
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// minimalProxy holds the runtime code of an EIP-1167 minimal proxy around the implementation
+// address, and the init code deploying it.
+var (
+	minimalProxyInit   = common.FromHex("0x3d602d80600a3d3981f3")
+	minimalProxyPrefix = common.FromHex("0x363d3d373d3d3d363d73")
+	minimalProxySuffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+// DecodeDeployment decodes a contract-creation transaction. It recovers the creator, computes the
+// address of the created contract, splits the init bytecode from the appended constructor arguments
+// and decodes those arguments using the constructor definition of the loaded ABI. If the ABI has no
+// constructor, the deployment is still returned without params. Deployments of EIP-1167 minimal
+// proxies carry their Implementation, and the ABI of an implementation indexed in Store is
+// assigned to the clone.
+func (decoder *AbiDecoder) DecodeDeployment(tx *types.Transaction) (*DecodedDeployment, error) {
+	checkAbi(decoder)
+
+	deployment, err := parseDeployment(tx, *decoder.Abi)
+	if err != nil {
+		return nil, err
+	}
+
+	if deployment.Implementation != "" {
+		Store.IndexClone(deployment.Contract, deployment.Implementation)
+	}
+
+	return deployment, nil
+}
+
+// MinimalProxyTarget returns the implementation address of EIP-1167 minimal proxy code. Both the
+// runtime code of a clone and the init code deploying it are recognized.
+func MinimalProxyTarget(code []byte) (common.Address, bool) {
+	code = bytes.TrimPrefix(code, minimalProxyInit)
+
+	size := len(minimalProxyPrefix) + common.AddressLength + len(minimalProxySuffix)
+	if len(code) != size || !bytes.HasPrefix(code, minimalProxyPrefix) || !bytes.HasSuffix(code, minimalProxySuffix) {
+		return common.Address{}, false
+	}
+
+	return common.BytesToAddress(code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+common.AddressLength]), true
+}
+
+// IndexClone indexes a minimal proxy clone with the ABI of its implementation, if the
+// implementation is indexed. It returns the indexed clone, or nil.
+func (store *Storage) IndexClone(clone string, implementation string) *IndexedABI {
+	target := store.GetIndexed(implementation)
+	if target == nil {
+		target = store.GetIndexed(common.HexToAddress(implementation).Hex())
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	indexed := *target
+	indexed.Address = common.HexToAddress(clone)
+	indexed.Bytecode = nil
+	store.putIndexed(clone, &indexed)
+
+	return &indexed
+}
+
+// IsDeployment returns true if the given transaction creates a contract.
+func IsDeployment(tx *types.Transaction) bool {
+	return tx.To() == nil && len(tx.Data()) > 0
+}
+
+// parseDeployment extracts the creator, created address, init code and constructor params of a
+// contract-creation transaction using the constructor of the given contract ABI.
+func parseDeployment(tx *types.Transaction, contractAbi abi.ABI) (*DecodedDeployment, error) {
+	if !IsDeployment(tx) {
+		return nil, fmt.Errorf("transaction is not a contract creation: %s", tx.Hash().Hex())
+	}
+
+	creator, err := txSender(tx)
+	if err != nil {
+		return nil, fmt.Errorf("can not recover deployment creator: %w", err)
+	}
+
+	data := tx.Data()
+	result := DecodedDeployment{
+		TransactionHash: tx.Hash().Hex(),
+		Creator:         creator.Hex(),
+		Contract:        crypto.CreateAddress(creator, tx.Nonce()).Hex(),
+		Bytecode:        "0x" + common.Bytes2Hex(data),
+		Arguments:       "0x",
+		Params:          Params{},
+	}
+
+	// clones have no constructor, the init code only copies the proxy runtime code
+	if implementation, ok := MinimalProxyTarget(data); ok {
+		result.Implementation = implementation.Hex()
+		return &result, nil
+	}
+
+	inputs := contractAbi.Constructor.Inputs
+	if len(inputs) == 0 {
+		return &result, nil
+	}
+
+	split, ok := splitConstructorArgs(inputs, data)
+	if !ok {
+		return nil, fmt.Errorf("can not locate constructor arguments in init code: %s", tx.Hash().Hex())
+	}
+
+	params := make(map[string]interface{})
+	if err := inputs.UnpackIntoMap(params, data[split:]); err != nil {
+		return nil, err
+	}
+
+	result.Bytecode = "0x" + common.Bytes2Hex(data[:split])
+	result.Arguments = "0x" + common.Bytes2Hex(data[split:])
+	result.Signature = contractAbi.Constructor.Sig
+	result.Params = formatParameters(params, nil, inputs...)
+
+	return &result, nil
+}
+
+// splitConstructorArgs returns the offset in the init code where the constructor arguments start.
+// For static arguments the size is known upfront. Dynamic arguments are located by trying every
+// 32 byte aligned tail, starting with the shortest, and keeping the first one that survives an
+// unpack and re-pack round trip.
+func splitConstructorArgs(inputs abi.Arguments, data []byte) (int, bool) {
+	size := 0
+	static := true
+	for _, input := range inputs {
+		argSize, isStatic := staticTypeSize(input.Type)
+		if !isStatic {
+			static = false
+			break
+		}
+		size += argSize
+	}
+
+	if static {
+		if size > len(data) {
+			return 0, false
+		}
+		return len(data) - size, true
+	}
+
+	for tail := 32 * len(inputs); tail <= len(data); tail += 32 {
+		args := data[len(data)-tail:]
+		values, err := inputs.Unpack(args)
+		if err != nil {
+			continue
+		}
+
+		packed, err := inputs.Pack(values...)
+		if err == nil && bytes.Equal(packed, args) {
+			return len(data) - tail, true
+		}
+	}
+
+	return 0, false
+}
+
+// txSender recovers the sender of a transaction using the signer matching its type and chain id.
+func txSender(tx *types.Transaction) (common.Address, error) {
+	var signer types.Signer
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	} else {
+		signer = types.HomesteadSigner{}
+	}
+
+	return types.Sender(signer, tx)
+}
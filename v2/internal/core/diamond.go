@@ -0,0 +1,259 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// abi_diamond holds the loupe facets() function and the DiamondCut event of EIP-2535.
+const abi_diamond = `[{"inputs":[],"name":"facets","outputs":[{"components":[{"name":"facetAddress","type":"address"},{"name":"functionSelectors","type":"bytes4[]"}],"name":"facets_","type":"tuple[]"}],"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"components":[{"name":"facetAddress","type":"address"},{"name":"action","type":"uint8"},{"name":"functionSelectors","type":"bytes4[]"}],"indexed":false,"name":"_diamondCut","type":"tuple[]"},{"indexed":false,"name":"_init","type":"address"},{"indexed":false,"name":"_calldata","type":"bytes"}],"name":"DiamondCut","type":"event"}]`
+
+var diamondAbi = ParseABI(abi_diamond)
+
+// FacetResolver returns the ABI of a diamond facet, e.g. from Store.Indexed or an explorer.
+type FacetResolver func(facet common.Address) (*abi.ABI, error)
+
+// DiamondFacet is a struct for holding a facet of a diamond and the selectors routed to it.
+type DiamondFacet struct {
+	Address   common.Address `json:"address"`
+	Selectors []string       `json:"selectors"` // Hex encoded 4 byte selectors.
+}
+
+// Diamond keeps the merged ABI of an EIP-2535 diamond proxy up to date. The merged ABI holds the
+// methods routed to each facet, the events of all facets and the DiamondCut event, and is indexed
+// in the Storage under the diamond's address. It is flagged Verified once the ABIs of all facets
+// are resolved.
+type Diamond struct {
+	Address common.Address
+
+	store   *Storage
+	resolve FacetResolver
+
+	mu     sync.RWMutex
+	facets map[common.Address][]string
+	abis   map[common.Address]*abi.ABI
+}
+
+// LoadDiamond enumerates the facets of the diamond with the loupe facets() function, resolves
+// their ABIs and indexes the merged ABI under the diamond's address. Facets whose ABI cannot be
+// resolved are kept, their selectors stay undecodable until a later DiamondCut replaces them.
+func (store *Storage) LoadDiamond(ctx context.Context, address string, resolve FacetResolver) (*Diamond, error) {
+	client := store.GetBackend()
+	if client == nil {
+		return nil, fmt.Errorf("no provider set for store nor set in CTX - diamond: %v", address)
+	}
+
+	diamond := common.HexToAddress(address)
+	output, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &diamond,
+		Data: diamondAbi.Methods["facets"].ID,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error calling facets() of %s: %w", address, err)
+	}
+
+	unpacked, err := diamondAbi.Unpack("facets", output)
+	if err != nil || len(unpacked) != 1 {
+		return nil, fmt.Errorf("error unpack facets() of %s: %v", address, err)
+	}
+
+	facets := *abi.ConvertType(unpacked[0], new([]struct {
+		FacetAddress      common.Address
+		FunctionSelectors [][4]byte
+	})).(*[]struct {
+		FacetAddress      common.Address
+		FunctionSelectors [][4]byte
+	})
+
+	result := &Diamond{
+		Address: diamond,
+		store:   store,
+		resolve: resolve,
+		facets:  make(map[common.Address][]string),
+		abis:    make(map[common.Address]*abi.ABI),
+	}
+
+	for _, facet := range facets {
+		for _, selector := range facet.FunctionSelectors {
+			result.facets[facet.FacetAddress] = append(result.facets[facet.FacetAddress], hexutil.Encode(selector[:]))
+		}
+	}
+
+	result.mu.Lock()
+	result.index()
+	result.mu.Unlock()
+
+	return result, nil
+}
+
+// Facets returns the facets of the diamond, sorted by address.
+func (d *Diamond) Facets() []DiamondFacet {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	result := make([]DiamondFacet, 0, len(d.facets))
+	for address, selectors := range d.facets {
+		result = append(result, DiamondFacet{Address: address, Selectors: append([]string{}, selectors...)})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Address.Hex() < result[j].Address.Hex()
+	})
+
+	return result
+}
+
+// Decoder returns a decoder for the merged ABI of the diamond.
+func (d *Diamond) Decoder() *AbiDecoder {
+	address := d.Address.Hex()
+	indexed := d.store.GetIndexed(address)
+	if indexed == nil {
+		return nil
+	}
+
+	contractAbi := indexed.Abi
+	return &AbiDecoder{Abi: &contractAbi, ContractAddress: &address, IsVerified: indexed.Verified}
+}
+
+// ApplyLog applies a DiamondCut log of the diamond to its facets and re-indexes the merged ABI.
+// It returns false for other logs.
+func (d *Diamond) ApplyLog(vLog *types.Log) (bool, error) {
+	event := diamondAbi.Events["DiamondCut"]
+	if vLog.Address != d.Address || len(vLog.Topics) == 0 || vLog.Topics[0] != event.ID {
+		return false, nil
+	}
+
+	unpacked, err := event.Inputs.Unpack(vLog.Data)
+	if err != nil || len(unpacked) != 3 {
+		return true, fmt.Errorf("error unpack DiamondCut of %s: %v", d.Address.Hex(), err)
+	}
+
+	cuts := *abi.ConvertType(unpacked[0], new([]struct {
+		FacetAddress      common.Address
+		Action            uint8
+		FunctionSelectors [][4]byte
+	})).(*[]struct {
+		FacetAddress      common.Address
+		Action            uint8
+		FunctionSelectors [][4]byte
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, cut := range cuts {
+		for _, raw := range cut.FunctionSelectors {
+			selector := hexutil.Encode(raw[:])
+
+			// every action first detaches the selector from its current facet
+			for facet, selectors := range d.facets {
+				d.facets[facet] = removeSelector(selectors, selector)
+				if len(d.facets[facet]) == 0 {
+					delete(d.facets, facet)
+				}
+			}
+
+			switch cut.Action {
+			case 0, 1: // Add, Replace
+				d.facets[cut.FacetAddress] = append(d.facets[cut.FacetAddress], selector)
+			case 2: // Remove
+			default:
+				return true, fmt.Errorf("invalid DiamondCut action %d of %s", cut.Action, d.Address.Hex())
+			}
+		}
+	}
+
+	d.index()
+	return true, nil
+}
+
+// index rebuilds the merged ABI and indexes it in the Storage. It has to be called with the lock
+// held.
+func (d *Diamond) index() {
+	merged := abi.ABI{
+		Methods: make(map[string]abi.Method),
+		Events:  make(map[string]abi.Event),
+		Errors:  make(map[string]abi.Error),
+	}
+	merged.Events["DiamondCut"] = diamondAbi.Events["DiamondCut"]
+	merged.Methods["facets"] = diamondAbi.Methods["facets"]
+
+	verified := true
+	for facet, selectors := range d.facets {
+		facetAbi, ok := d.abis[facet]
+		if !ok && d.resolve != nil {
+			facetAbi, _ = d.resolve(facet)
+			if facetAbi != nil {
+				d.abis[facet] = facetAbi
+			}
+		}
+
+		if facetAbi == nil {
+			verified = false
+			continue
+		}
+
+		for _, method := range facetAbi.Methods {
+			if !containsSelector(selectors, hexutil.Encode(method.ID)) {
+				continue
+			}
+
+			// overloads of different facets may share a name, keep them apart like abi.JSON does
+			name := method.Name
+			for i := 0; ; i++ {
+				if existing, ok := merged.Methods[name]; !ok || existing.Sig == method.Sig {
+					break
+				}
+				name = fmt.Sprintf("%s%d", method.Name, i)
+			}
+			merged.Methods[name] = method
+		}
+
+		for name, event := range facetAbi.Events {
+			merged.Events[name] = event
+		}
+
+		for name, customError := range facetAbi.Errors {
+			merged.Errors[name] = customError
+		}
+	}
+
+	address := d.Address.Hex()
+	if indexed := d.store.GetIndexed(address); indexed != nil {
+		indexed.Abi = merged
+		indexed.Verified = verified
+		return
+	}
+
+	d.store.putIndexed(address, &IndexedABI{Address: d.Address, Abi: merged, Verified: verified})
+}
+
+func containsSelector(selectors []string, selector string) bool {
+	for _, s := range selectors {
+		if strings.EqualFold(s, selector) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeSelector(selectors []string, selector string) []string {
+	result := selectors[:0]
+	for _, s := range selectors {
+		if !strings.EqualFold(s, selector) {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
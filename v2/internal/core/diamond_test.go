@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+type diamondFacet struct {
+	FacetAddress      common.Address
+	FunctionSelectors [][4]byte
+}
+
+type diamondCut struct {
+	FacetAddress      common.Address
+	Action            uint8
+	FunctionSelectors [][4]byte
+}
+
+func selector(contractAbi *abi.ABI, name string) [4]byte {
+	var result [4]byte
+	copy(result[:], contractAbi.Methods[name].ID)
+	return result
+}
+
+func TestDiamond(t *testing.T) {
+	erc20, erc721 := ParseABI(abi_erc20), ParseABI(abi_erc721)
+	erc20Facet, erc721Facet := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	output, _ := diamondAbi.Methods["facets"].Outputs.Pack([]diamondFacet{
+		{FacetAddress: erc20Facet, FunctionSelectors: [][4]byte{selector(erc20, "transfer")}},
+	})
+	server.Respond("eth_call", hexutil.Encode(output))
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eth := Ctx.eth
+	Ctx.eth = client
+	defer func() { Ctx.eth = eth }()
+
+	store := Storage{Indexed: make(map[string]*IndexedABI)}
+	diamond, err := store.LoadDiamond(context.Background(), target_contract, func(facet common.Address) (*abi.ABI, error) {
+		switch facet {
+		case erc20Facet:
+			return erc20, nil
+		case erc721Facet:
+			return erc721, nil
+		}
+		return nil, fmt.Errorf("unknown facet %s", facet.Hex())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := diamond.Decoder()
+	if _, ok := decoder.Abi.Methods["transfer"]; !ok || len(decoder.Abi.Methods) != 2 || !decoder.IsVerified {
+		t.Fatalf("invalid merged abi: %v", decoder.Abi.Methods)
+	}
+
+	// route safeTransferFrom to the erc721 facet and remove transfer
+	data, _ := diamondAbi.Events["DiamondCut"].Inputs.Pack([]diamondCut{
+		{FacetAddress: erc721Facet, Action: 0, FunctionSelectors: [][4]byte{selector(erc721, "safeTransferFrom")}},
+		{FacetAddress: common.Address{}, Action: 2, FunctionSelectors: [][4]byte{selector(erc20, "transfer")}},
+	}, common.Address{}, []byte{})
+
+	applied, err := diamond.ApplyLog(&types.Log{
+		Address: common.HexToAddress(target_contract),
+		Topics:  []common.Hash{diamondAbi.Events["DiamondCut"].ID},
+		Data:    data,
+	})
+	if !applied || err != nil {
+		t.Fatalf("DiamondCut not applied: %v", err)
+	}
+
+	facets := diamond.Facets()
+	if len(facets) != 1 || facets[0].Address != erc721Facet {
+		t.Fatalf("invalid facets: %+v", facets)
+	}
+
+	decoder = diamond.Decoder()
+	if _, ok := decoder.Abi.Methods["transfer"]; ok || decoder.Abi.Methods["safeTransferFrom"].Sig != "safeTransferFrom(address,address,uint256)" {
+		t.Fatalf("merged abi not updated: %v", decoder.Abi.Methods)
+	}
+
+	call, _ := erc721.Pack("safeTransferFrom", common.HexToAddress(target_erc20), common.HexToAddress(target_erc721), big.NewInt(7))
+	if decoded, err := decoder.DecodeCalldata(call); err != nil || decoded.Contract != common.HexToAddress(target_contract).Hex() {
+		t.Fatalf("invalid decoded diamond call: %v %v", decoded, err)
+	}
+
+}
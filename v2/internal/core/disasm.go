@@ -0,0 +1,150 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Instruction is a struct for holding a disassembled opcode and its push data.
+type Instruction struct {
+	PC      int       `json:"pc"`                // Offset of the opcode in the bytecode.
+	Op      vm.OpCode `json:"-"`                 // Raw opcode.
+	Name    string    `json:"name"`              // Mnemonic, e.g. PUSH4.
+	Operand []byte    `json:"operand,omitempty"` // Push data, truncated at the end of the code.
+}
+
+// String returns the instruction in assembly notation, e.g. "0x000c PUSH4 0xa9059cbb".
+func (data Instruction) String() string {
+	if len(data.Operand) == 0 {
+		return fmt.Sprintf("0x%04x %s", data.PC, data.Name)
+	}
+
+	return fmt.Sprintf("0x%04x %s 0x%x", data.PC, data.Name, data.Operand)
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the Instruction object.
+func (data *Instruction) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the Instruction object.
+func (data *Instruction) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// Disassemble walks the opcodes of the hex encoded bytecode. Push data is attached to its
+// opcode, so bytes of constants are never read as opcodes.
+func Disassemble(bytecode string) ([]Instruction, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(bytecode), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode: %w", err)
+	}
+
+	result := make([]Instruction, 0, len(raw))
+	for pc := 0; pc < len(raw); pc++ {
+		op := vm.OpCode(raw[pc])
+		instruction := Instruction{PC: pc, Op: op, Name: op.String()}
+
+		if op.IsPush() {
+			size := int(op-vm.PUSH1) + 1
+			end := pc + 1 + size
+			if end > len(raw) {
+				end = len(raw)
+			}
+
+			instruction.Operand = raw[pc+1 : end]
+			pc += size
+		}
+
+		result = append(result, instruction)
+	}
+
+	return result, nil
+}
+
+// ExtractSelectors returns the 4 byte function selectors pushed by the bytecode, hex encoded with
+// 0x prefix and sorted. Operands of PUSH4 which are masks (0xffffffff) or zero are skipped.
+func ExtractSelectors(bytecode string) []string {
+	selectors, _ := pushOperands(bytecode)
+	return prefixed(sortedKeys(selectors))
+}
+
+// ExtractTopics returns the 32 byte words pushed by the bytecode, hex encoded with 0x prefix and
+// sorted. Solidity pushes event topics with PUSH32 before LOG, so these include all topics of the
+// events emitted, next to other 32 byte constants.
+func ExtractTopics(bytecode string) []string {
+	_, topics := pushOperands(bytecode)
+	return prefixed(sortedKeys(topics))
+}
+
+// pushOperands returns the hex encoded operands of all complete PUSH4 and PUSH32 opcodes of the
+// code.
+func pushOperands(code string) (map[string]bool, map[string]bool) {
+	selectors := make(map[string]bool)
+	topics := make(map[string]bool)
+
+	instructions, err := Disassemble(code)
+	if err != nil {
+		return selectors, topics
+	}
+
+	for _, instruction := range instructions {
+		switch {
+		case instruction.Op == vm.PUSH4 && len(instruction.Operand) == 4:
+			operand := hex.EncodeToString(instruction.Operand)
+			if operand != "ffffffff" && operand != "00000000" {
+				selectors[operand] = true
+			}
+		case instruction.Op == vm.PUSH32 && len(instruction.Operand) == 32:
+			topics[hex.EncodeToString(instruction.Operand)] = true
+		}
+	}
+
+	return selectors, topics
+}
+
+// shortSelectors returns the operands of all complete PUSH1 to PUSH3 opcodes of the bytecode, left
+// padded to 4 bytes. solc pushes selectors starting with zero bytes with the shortest PUSH, e.g.
+// 0x00fdd58e of the ERC1155 balanceOf with PUSH3. They are kept apart from the PUSH4 operands, as
+// most short operands are offsets and small constants rather than selectors.
+func shortSelectors(code string) map[string]bool {
+	selectors := make(map[string]bool)
+
+	instructions, err := Disassemble(code)
+	if err != nil {
+		return selectors
+	}
+
+	for _, instruction := range instructions {
+		if instruction.Op >= vm.PUSH1 && instruction.Op <= vm.PUSH3 && len(instruction.Operand) == int(instruction.Op-vm.PUSH1)+1 {
+			selectors[hex.EncodeToString(common.LeftPadBytes(instruction.Operand, 4))] = true
+		}
+	}
+
+	return selectors
+}
+
+func sortedKeys(set map[string]bool) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+func prefixed(values []string) []string {
+	for i, value := range values {
+		values[i] = "0x" + value
+	}
+
+	return values
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// signatureIndex maps the selectors and event topics of the ABIs in AbiList to the indices of the
+// ABIs defining them, in AbiList order, so logs and calldata are only unpacked with ABIs that know
+// them.
+type signatureIndex struct {
+	abis    []abiFingerprint // the ABIs of AbiList the index was built for
+	methods map[[4]byte][]int
+	events  map[common.Hash][]int
+}
+
+// abiFingerprint identifies an ABI of AbiList by its method and event maps, which differ once the
+// ABI is replaced, and by their sizes, which differ once methods or events are added or removed.
+// It holds the maps, so their addresses are not reused while the index is in use.
+type abiFingerprint struct {
+	methods map[string]abi.Method
+	events  map[string]abi.Event
+	size    int
+}
+
+func fingerprint(contractAbi *abi.ABI) abiFingerprint {
+	return abiFingerprint{
+		methods: contractAbi.Methods,
+		events:  contractAbi.Events,
+		size:    len(contractAbi.Methods) + len(contractAbi.Events),
+	}
+}
+
+func (f abiFingerprint) matches(contractAbi *abi.ABI) bool {
+	return f.size == len(contractAbi.Methods)+len(contractAbi.Events) &&
+		reflect.ValueOf(f.methods).Pointer() == reflect.ValueOf(contractAbi.Methods).Pointer() &&
+		reflect.ValueOf(f.events).Pointer() == reflect.ValueOf(contractAbi.Events).Pointer()
+}
+
+// signatures returns the index of the store, rebuilt once AbiList changed: once ABIs are added,
+// removed or replaced, also by assigning the field or its elements directly. Indices are never
+// modified after they are built, so they can be used without holding the lock.
+func (store *Storage) signatures() *signatureIndex {
+	store.signatureIndexMu.RLock()
+	index := store.signatureIndex
+	store.signatureIndexMu.RUnlock()
+
+	if index.builtFor(store.AbiList) {
+		return index
+	}
+
+	store.signatureIndexMu.Lock()
+	defer store.signatureIndexMu.Unlock()
+
+	if index = store.signatureIndex; index.builtFor(store.AbiList) {
+		return index
+	}
+
+	index = &signatureIndex{
+		abis:    make([]abiFingerprint, len(store.AbiList)),
+		methods: make(map[[4]byte][]int),
+		events:  make(map[common.Hash][]int),
+	}
+	for i, contractAbi := range store.AbiList {
+		index.abis[i] = fingerprint(&store.AbiList[i])
+
+		for _, method := range contractAbi.Methods {
+			var selector [4]byte
+			copy(selector[:], method.ID)
+			index.methods[selector] = append(index.methods[selector], i)
+		}
+
+		for _, event := range contractAbi.Events {
+			if !event.Anonymous {
+				index.events[event.ID] = append(index.events[event.ID], i)
+			}
+		}
+	}
+
+	store.signatureIndex = index
+	return index
+}
+
+// builtFor returns true if the index was built for the list, comparing the fingerprints of all
+// ABIs. This is a few pointer comparisons per ABI, far less than unpacking with every ABI.
+func (index *signatureIndex) builtFor(abis []abi.ABI) bool {
+	if index == nil || len(index.abis) != len(abis) {
+		return false
+	}
+
+	for i := range abis {
+		if !index.abis[i].matches(&abis[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// invalidateSignatures rebuilds the index on next use, for changes of AbiList the fingerprints
+// can not notice, e.g. methods replaced in place.
+func (store *Storage) invalidateSignatures() {
+	store.signatureIndexMu.Lock()
+	defer store.signatureIndexMu.Unlock()
+
+	store.signatureIndex = nil
+}
+
+// methodABIs returns the ABIs of AbiList defining the selector of the calldata.
+func (store *Storage) methodABIs(data []byte) []abi.ABI {
+	if len(data) < 4 {
+		return nil
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	return store.indexedABIs(store.signatures().methods[selector])
+}
+
+// eventABIs returns the ABIs of AbiList defining the event of the topic.
+func (store *Storage) eventABIs(topic common.Hash) []abi.ABI {
+	return store.indexedABIs(store.signatures().events[topic])
+}
+
+func (store *Storage) indexedABIs(indices []int) []abi.ABI {
+	abis := make([]abi.ABI, 0, len(indices))
+	for _, i := range indices {
+		if i < len(store.AbiList) {
+			abis = append(abis, store.AbiList[i])
+		}
+	}
+
+	return abis
+}
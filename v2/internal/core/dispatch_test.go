@@ -0,0 +1,79 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSignatureIndex(t *testing.T) {
+	chain := newTestChain(t, 1)
+	tx := chain.blocks[1]["transactions"].([]*types.Transaction)[0]
+	vLog := chain.receipts[tx.Hash()].Logs[0]
+
+	store := &Storage{}
+	store.ParseAndAddABIs(abi_diamond, abi_erc20)
+
+	if decoded := store.DecodeLog(vLog); decoded == nil || decoded.Name != "Transfer" {
+		t.Fatalf("log not decoded: %+v", decoded)
+	}
+
+	if decoded := store.DecodeMethod(tx); decoded == nil || decoded.Name != "transfer" {
+		t.Fatalf("method not decoded: %+v", decoded)
+	}
+
+	// removals rebuild the index
+	if store.RemoveABIByTopic(TransferTopic) != 1 {
+		t.Fatal("transfer event not removed")
+	}
+
+	if decoded := store.DecodeLog(vLog); decoded != nil {
+		t.Fatalf("removed event decoded: %+v", decoded)
+	}
+
+	if decoded := store.DecodeMethod(tx); decoded == nil || decoded.Name != "transfer" {
+		t.Fatalf("method lost with the event: %+v", decoded)
+	}
+
+	// so do appends and lists replaced by lists of the same length
+	store.AddABI("erc20", *ParseABI(abi_erc20))
+	if decoded := store.DecodeLog(vLog); decoded == nil || decoded.Name != "Transfer" {
+		t.Fatalf("added event not decoded: %+v", decoded)
+	}
+
+	store.AbiList = []abi.ABI{*ParseABI(abi_diamond), *ParseABI(abi_diamond)}
+	if decoded := store.DecodeMethod(tx); decoded != nil {
+		t.Fatalf("replaced ABI decoded: %+v", decoded)
+	}
+
+	// elements replaced in place and lists re-sliced into the same array are noticed too
+	store.AbiList[1] = *ParseABI(abi_erc20)
+	if decoded := store.DecodeMethod(tx); decoded == nil || decoded.Name != "transfer" {
+		t.Fatalf("ABI replaced in place not used: %+v", decoded)
+	}
+
+	store.AbiList = append(store.AbiList[:1], *ParseABI(abi_diamond))
+	if decoded := store.DecodeMethod(tx); decoded != nil {
+		t.Fatalf("ABI of the re-sliced list decoded: %+v", decoded)
+	}
+}
+
+func TestSelectorCollision(t *testing.T) {
+	chain := newTestChain(t, 1)
+	tx := chain.blocks[1]["transactions"].([]*types.Transaction)[0]
+
+	// a method sharing the selector of transfer whose arguments can not be unpacked
+	colliding := *ParseABI(`[{"type":"function","name":"flag","inputs":[{"name":"on","type":"bool"}],"outputs":[]}]`)
+	flag := colliding.Methods["flag"]
+	flag.ID = tx.Data()[:4]
+	colliding.Methods["flag"] = flag
+
+	store := &Storage{}
+	store.AddABI("colliding", colliding)
+	store.ParseAndAddABIs(abi_erc20)
+
+	if decoded := store.DecodeMethod(tx); decoded == nil || decoded.Name != "transfer" {
+		t.Fatalf("next candidate not tried: %+v", decoded)
+	}
+}
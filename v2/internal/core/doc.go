@@ -0,0 +1,22 @@
+// Package core implements the decoder behind the public packages of the module. The subsystems
+// share the Storage, AbiDecoder and the global client, so they are kept in one package and exported
+// by subsystem from decode, store, tokens, scan, rpc, sinks and protocols:
+//
+//   - decode: decoder.go (AbiDecoder), helpers.go, multicall.go, revert.go, trace.go,
+//     deployment.go, encode.go, formatters.go, resolver.go, selectordb.go, explain.go, diamond.go,
+//     override.go, interfaces.go, call.go, disasm.go, classify.go, lazy.go, recover.go, keccak.go,
+//     filter.go, ambiguity.go, dispatch.go, eip712.go, types.go, abis.go, utils.go, catalog.go,
+//     metadata.go, labels.go, compliance.go, validate.go, transaction.go, profiler.go
+//   - store: abi-store.go (Storage), registry.go, indexed.go, lookupcache.go, cache.go,
+//     watchdog.go, persist.go
+//   - tokens: tokens.go, amounts.go, balances.go, ledger.go, fees.go, rosetta.go, transfers.go,
+//     txclass.go, permits.go
+//   - scan: scan.go, checkpoint.go, subscribe.go, reorg.go, block.go, batch.go, bloom.go
+//   - rpc: context.go, backend.go, connect.go, interceptor.go, requestid.go, bytecodes.go,
+//     explorer.go, sourcify.go, probe.go, builder.go, multiclient.go, retry.go, ratelimit.go,
+//     chains.go, clock.go
+//   - sinks: storedriver.go, attest.go, version.go, idempotency.go
+//   - protocols: swaps.go, router.go, nftsales.go, vaults.go, chainlink.go, ens.go, l2.go
+//
+// New exported identifiers are added to the package of their subsystem as well.
+package core
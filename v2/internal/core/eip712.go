@@ -0,0 +1,318 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// TypedDataTypes holds the EIP-712 types of well-known signed payloads by their primary type:
+// EIP-2612 Permit, the Permit2 permits and Seaport orders. DecodeTypedParam decodes params holding
+// them.
+var TypedDataTypes = map[string]apitypes.Types{
+	"Permit": {
+		"Permit": {
+			{Name: "owner", Type: "address"},
+			{Name: "spender", Type: "address"},
+			{Name: "value", Type: "uint256"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+	},
+	"PermitSingle": {
+		"PermitSingle": {
+			{Name: "details", Type: "PermitDetails"},
+			{Name: "spender", Type: "address"},
+			{Name: "sigDeadline", Type: "uint256"},
+		},
+		"PermitDetails": permitDetailsType,
+	},
+	"PermitBatch": {
+		"PermitBatch": {
+			{Name: "details", Type: "PermitDetails[]"},
+			{Name: "spender", Type: "address"},
+			{Name: "sigDeadline", Type: "uint256"},
+		},
+		"PermitDetails": permitDetailsType,
+	},
+	"PermitTransferFrom": {
+		"PermitTransferFrom": {
+			{Name: "permitted", Type: "TokenPermissions"},
+			{Name: "spender", Type: "address"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+		"TokenPermissions": {
+			{Name: "token", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+		},
+	},
+	"OrderComponents": {
+		"OrderComponents": {
+			{Name: "offerer", Type: "address"},
+			{Name: "zone", Type: "address"},
+			{Name: "offer", Type: "OfferItem[]"},
+			{Name: "consideration", Type: "ConsiderationItem[]"},
+			{Name: "orderType", Type: "uint8"},
+			{Name: "startTime", Type: "uint256"},
+			{Name: "endTime", Type: "uint256"},
+			{Name: "zoneHash", Type: "bytes32"},
+			{Name: "salt", Type: "uint256"},
+			{Name: "conduitKey", Type: "bytes32"},
+			{Name: "counter", Type: "uint256"},
+		},
+		"OfferItem": {
+			{Name: "itemType", Type: "uint8"},
+			{Name: "token", Type: "address"},
+			{Name: "identifierOrCriteria", Type: "uint256"},
+			{Name: "startAmount", Type: "uint256"},
+			{Name: "endAmount", Type: "uint256"},
+		},
+		"ConsiderationItem": {
+			{Name: "itemType", Type: "uint8"},
+			{Name: "token", Type: "address"},
+			{Name: "identifierOrCriteria", Type: "uint256"},
+			{Name: "startAmount", Type: "uint256"},
+			{Name: "endAmount", Type: "uint256"},
+			{Name: "recipient", Type: "address"},
+		},
+	},
+}
+
+var permitDetailsType = []apitypes.Type{
+	{Name: "token", Type: "address"},
+	{Name: "amount", Type: "uint160"},
+	{Name: "expiration", Type: "uint48"},
+	{Name: "nonce", Type: "uint48"},
+}
+
+// ParseTypedData parses EIP-712 typed data in the JSON format of eth_signTypedData_v4.
+func ParseTypedData(data []byte) (*apitypes.TypedData, error) {
+	var typed apitypes.TypedData
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, fmt.Errorf("error parsing typed data: %w", err)
+	}
+
+	return &typed, nil
+}
+
+// NewTypedData returns the typed data of the message. The EIP712Domain type is derived from the
+// fields set in the domain, the types are not modified.
+func NewTypedData(types apitypes.Types, primaryType string, domain apitypes.TypedDataDomain, message apitypes.TypedDataMessage) apitypes.TypedData {
+	result := apitypes.TypedData{
+		Types:       make(apitypes.Types, len(types)+1),
+		PrimaryType: primaryType,
+		Domain:      domain,
+		Message:     message,
+	}
+
+	for name, fields := range types {
+		result.Types[name] = fields
+	}
+
+	var fields []apitypes.Type
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	result.Types["EIP712Domain"] = fields
+
+	return result
+}
+
+// DomainSeparator returns the hash of the domain of the typed data, as returned by the
+// DOMAIN_SEPARATOR of the verifying contract.
+func DomainSeparator(typed apitypes.TypedData) (common.Hash, error) {
+	hash, err := typed.HashStruct("EIP712Domain", typed.Domain.Map())
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.BytesToHash(hash), nil
+}
+
+// TypedDataDigest returns the EIP-712 digest of the typed data, the hash signed by its signer.
+func TypedDataDigest(typed apitypes.TypedData) (common.Hash, error) {
+	digest, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.BytesToHash(digest), nil
+}
+
+// RecoverTypedDataSigner returns the address that signed the typed data. The recovery id of a
+// 65 byte signature may be 0/1 or 27/28, 64 byte signatures are read as EIP-2098 compact
+// signatures.
+func RecoverTypedDataSigner(typed apitypes.TypedData, signature []byte) (common.Address, error) {
+	var sig []byte
+	switch len(signature) {
+	case crypto.SignatureLength:
+		sig = common.CopyBytes(signature)
+		if sig[crypto.RecoveryIDOffset] >= 27 {
+			sig[crypto.RecoveryIDOffset] -= 27
+		}
+	case crypto.SignatureLength - 1:
+		// the recovery id is the highest bit of s
+		sig = append(common.CopyBytes(signature), signature[32]>>7)
+		sig[32] &= 0x7f
+	default:
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+
+	digest, err := TypedDataDigest(typed)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	pubkey, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// DecodeTypedStruct decodes the ABI encoded struct of the primary type, as passed to contracts
+// verifying the signature, into a message of the typed data. Addresses are checksummed, integers
+// decimal strings and bytes hex strings.
+func DecodeTypedStruct(types apitypes.Types, primaryType string, data []byte) (apitypes.TypedDataMessage, error) {
+	structType, err := typedStructType(types, primaryType, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := abi.NewType(structType.Type, "", structType.Components)
+	if err != nil {
+		return nil, fmt.Errorf("error creating abi type of %s: %w", primaryType, err)
+	}
+
+	values, err := abi.Arguments{{Type: t}}.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking %s: %w", primaryType, err)
+	}
+
+	return typedValue(t, reflect.ValueOf(values[0])).(map[string]interface{}), nil
+}
+
+// DecodeTypedParam decodes a bytes param of the decoded method holding a struct of one of the
+// TypedDataTypes, see DecodeTypedStruct.
+func DecodeTypedParam(method *DecodedMethod, param string, primaryType string) (apitypes.TypedDataMessage, error) {
+	types, ok := TypedDataTypes[primaryType]
+	if !ok {
+		return nil, fmt.Errorf("unknown typed data type: %s", primaryType)
+	}
+
+	var data []byte
+	switch value := method.Params[param].(type) {
+	case []byte:
+		data = value
+	case string:
+		decoded, err := hexutil.Decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("param %s of %s is no bytes: %w", param, method.Name, err)
+		}
+		data = decoded
+	default:
+		return nil, fmt.Errorf("param %s of %s is no bytes", param, method.Name)
+	}
+
+	return DecodeTypedStruct(types, primaryType, data)
+}
+
+// typedStructType converts the EIP-712 struct type into an ABI tuple. Struct types referencing
+// themselves can not be ABI encoded and return an error.
+func typedStructType(types apitypes.Types, name string, parents []string) (abi.ArgumentMarshaling, error) {
+	fields, ok := types[name]
+	if !ok {
+		return abi.ArgumentMarshaling{}, fmt.Errorf("type %s not defined", name)
+	}
+
+	for _, parent := range parents {
+		if parent == name {
+			return abi.ArgumentMarshaling{}, fmt.Errorf("type %s references itself", name)
+		}
+	}
+	parents = append(parents, name)
+
+	result := abi.ArgumentMarshaling{Name: name, Type: "tuple"}
+	for _, field := range fields {
+		base, suffix := field.Type, ""
+		if i := strings.Index(field.Type, "["); i >= 0 {
+			base, suffix = field.Type[:i], field.Type[i:]
+		}
+
+		if _, ok := types[base]; !ok {
+			result.Components = append(result.Components, abi.ArgumentMarshaling{Name: field.Name, Type: field.Type})
+			continue
+		}
+
+		component, err := typedStructType(types, base, parents)
+		if err != nil {
+			return abi.ArgumentMarshaling{}, err
+		}
+		component.Name = field.Name
+		component.Type += suffix
+		result.Components = append(result.Components, component)
+	}
+
+	return result, nil
+}
+
+// typedValue converts an unpacked value into the types accepted in messages of typed data: maps
+// for structs, lists for arrays and strings for addresses, integers and bytes.
+func typedValue(t abi.Type, value reflect.Value) interface{} {
+	switch t.T {
+	case abi.TupleTy:
+		result := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			result[t.TupleRawNames[i]] = typedValue(*elem, value.Field(i))
+		}
+		return result
+
+	case abi.SliceTy, abi.ArrayTy:
+		result := make([]interface{}, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			result = append(result, typedValue(*t.Elem, value.Index(i)))
+		}
+		return result
+
+	case abi.AddressTy:
+		return value.Interface().(common.Address).Hex()
+
+	case abi.IntTy, abi.UintTy:
+		if number, ok := value.Interface().(*big.Int); ok {
+			return number.String()
+		}
+		return fmt.Sprint(value.Interface())
+
+	case abi.BytesTy:
+		return hexutil.Encode(value.Bytes())
+
+	case abi.FixedBytesTy:
+		data := make([]byte, value.Len())
+		reflect.Copy(reflect.ValueOf(data), value)
+		return hexutil.Encode(data)
+	}
+
+	return value.Interface()
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// the example of EIP-712
+const typedMail = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+		"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+	},
+	"primaryType": "Mail",
+	"domain": {"name": "Ether Mail", "version": "1", "chainId": "1", "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedDataDigest(t *testing.T) {
+	typed, err := ParseTypedData([]byte(typedMail))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	separator, err := DomainSeparator(*typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if separator.Hex() != "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f" {
+		t.Fatalf("unexpected domain separator: %s", separator.Hex())
+	}
+
+	digest, err := TypedDataDigest(*typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest.Hex() != "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Fatalf("unexpected digest: %s", digest.Hex())
+	}
+}
+
+func TestDecodeTypedStruct(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	permit2 := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	token := common.HexToAddress(target_erc20)
+
+	structType, err := typedStructType(TypedDataTypes["PermitSingle"], "PermitSingle", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	permitType, _ := abi.NewType(structType.Type, "", structType.Components)
+
+	type permitDetails struct {
+		Token      common.Address
+		Amount     *big.Int
+		Expiration *big.Int
+		Nonce      *big.Int
+	}
+	data, err := abi.Arguments{{Type: permitType}}.Pack(struct {
+		Details     permitDetails
+		Spender     common.Address
+		SigDeadline *big.Int
+	}{permitDetails{token, big.NewInt(100), big.NewInt(1700000000), big.NewInt(3)}, permit2, big.NewInt(1700000001)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	method := &DecodedMethod{Name: "permit", Params: Params{"permitSingle": hexutil.Encode(data)}}
+	message, err := DecodeTypedParam(method, "permitSingle", "PermitSingle")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details := message["details"].(map[string]interface{})
+	if details["token"] != token.Hex() || details["amount"] != "100" || details["nonce"] != "3" || message["spender"] != permit2.Hex() {
+		t.Fatalf("unexpected message: %v", message)
+	}
+
+	domain := apitypes.TypedDataDomain{Name: "Permit2", ChainId: math.NewHexOrDecimal256(1), VerifyingContract: permit2.Hex()}
+	typed := NewTypedData(TypedDataTypes["PermitSingle"], "PermitSingle", domain, message)
+
+	digest, err := TypedDataDigest(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+
+	signer, err := RecoverTypedDataSigner(typed, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != owner {
+		t.Fatalf("unexpected signer: %s", signer.Hex())
+	}
+
+	if _, err := DecodeTypedParam(method, "permitSingle", "Unknown"); err == nil {
+		t.Fatal("expected an error for an unknown type")
+	}
+}
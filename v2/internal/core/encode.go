@@ -0,0 +1,220 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EncodeMethod packs calldata for the method with the given name, signature or selector. The
+// params use the same representation as decoded results: integers as decimal (or 0x hex)
+// strings, addresses and bytes as hex strings and tuples as maps keyed by component name, so a
+// DecodedMethod's Params can be encoded back into its calldata. Unnamed inputs are looked up by
+// their position.
+func (decoder *AbiDecoder) EncodeMethod(name string, params Params) ([]byte, error) {
+	checkAbi(decoder)
+
+	method, err := findMethod(*decoder.Abi, name)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, len(method.Inputs))
+	for i, input := range method.Inputs {
+		key := input.Name
+		if key == "" {
+			key = fmt.Sprint(i)
+		}
+
+		value, ok := params[key]
+		if !ok {
+			return nil, fmt.Errorf("missing param %s of %s", key, method.Sig)
+		}
+
+		arg, err := coerceArgument(input.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid param %s of %s: %w", key, method.Sig, err)
+		}
+
+		args = append(args, arg)
+	}
+
+	data, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("error pack %s: %w", method.Sig, err)
+	}
+
+	return append(append([]byte{}, method.ID...), data...), nil
+}
+
+// coerceArgument converts a formatted param value into the Go type expected by the abi packer.
+// Values that already have the expected type are returned as they are.
+func coerceArgument(t abi.Type, value interface{}) (interface{}, error) {
+	goType := t.GetType()
+	if value != nil && reflect.TypeOf(value) == goType {
+		return value, nil
+	}
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		number, err := parseBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if goType == reflect.TypeOf(number) {
+			return number, nil
+		}
+
+		result := reflect.New(goType).Elem()
+		if t.T == abi.IntTy {
+			if !number.IsInt64() || result.OverflowInt(number.Int64()) {
+				return nil, fmt.Errorf("%s overflows %s", number, t)
+			}
+			result.SetInt(number.Int64())
+		} else {
+			if number.Sign() < 0 || !number.IsUint64() || result.OverflowUint(number.Uint64()) {
+				return nil, fmt.Errorf("%s overflows %s", number, t)
+			}
+			result.SetUint(number.Uint64())
+		}
+
+		return result.Interface(), nil
+
+	case abi.BoolTy:
+		switch value := value.(type) {
+		case bool:
+			return value, nil
+		case string:
+			switch strings.ToLower(value) {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			}
+		}
+
+	case abi.StringTy:
+		if value, ok := value.(string); ok {
+			return value, nil
+		}
+
+	case abi.AddressTy:
+		if value, ok := value.(string); ok && common.IsHexAddress(value) {
+			return common.HexToAddress(value), nil
+		}
+
+	case abi.BytesTy:
+		if value, ok := value.(string); ok {
+			return common.FromHex(value), nil
+		}
+
+	case abi.FixedBytesTy:
+		var raw []byte
+		switch value := value.(type) {
+		case string:
+			raw = common.FromHex(value)
+		case []byte:
+			raw = value
+		case common.Hash:
+			raw = value.Bytes()
+		}
+
+		if raw != nil {
+			if len(raw) != t.Size {
+				return nil, fmt.Errorf("expected %d bytes for %s, got %d", t.Size, t, len(raw))
+			}
+
+			result := reflect.New(goType).Elem()
+			reflect.Copy(result, reflect.ValueOf(raw))
+			return result.Interface(), nil
+		}
+
+	case abi.SliceTy, abi.ArrayTy:
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			break
+		}
+
+		if t.T == abi.ArrayTy && list.Len() != t.Size {
+			return nil, fmt.Errorf("expected %d elements for %s, got %d", t.Size, t, list.Len())
+		}
+
+		var result reflect.Value
+		if t.T == abi.ArrayTy {
+			result = reflect.New(goType).Elem()
+		} else {
+			result = reflect.MakeSlice(goType, list.Len(), list.Len())
+		}
+
+		for i := 0; i < list.Len(); i++ {
+			elem, err := coerceArgument(*t.Elem, list.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			result.Index(i).Set(reflect.ValueOf(elem))
+		}
+
+		return result.Interface(), nil
+
+	case abi.TupleTy:
+		fields, ok := value.(map[string]interface{})
+		if params, isParams := value.(Params); isParams {
+			fields, ok = params, true
+		}
+		if !ok {
+			break
+		}
+
+		result := reflect.New(goType).Elem()
+		for i, elem := range t.TupleElems {
+			name := t.TupleRawNames[i]
+			field, ok := fields[name]
+			if !ok {
+				return nil, fmt.Errorf("missing tuple field %s", name)
+			}
+
+			coerced, err := coerceArgument(*elem, field)
+			if err != nil {
+				return nil, fmt.Errorf("tuple field %s: %w", name, err)
+			}
+			result.Field(i).Set(reflect.ValueOf(coerced))
+		}
+
+		return result.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("can not convert %v (%T) to %s", value, value, t)
+}
+
+// parseBigInt parses decimal or 0x prefixed hex strings and native integers into a big.Int.
+func parseBigInt(value interface{}) (*big.Int, error) {
+	switch value := value.(type) {
+	case *big.Int:
+		return value, nil
+	case string:
+		base := 10
+		if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+			value, base = value[2:], 16
+		}
+
+		if number, ok := new(big.Int).SetString(value, base); ok {
+			return number, nil
+		}
+		return nil, fmt.Errorf("invalid integer: %s", value)
+	}
+
+	number := reflect.ValueOf(value)
+	switch number.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(number.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(number.Uint()), nil
+	}
+
+	return nil, fmt.Errorf("invalid integer: %v (%T)", value, value)
+}
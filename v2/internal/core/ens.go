@@ -0,0 +1,206 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// abi_ens_resolver holds the addr and name functions of ENS public resolvers.
+const abi_ens_resolver = `[{"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"node","type":"bytes32"}],"name":"name","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"}]`
+
+// ENSRegistry is the ENS registry, deployed at the same address on Ethereum mainnet and its
+// testnets.
+var ENSRegistry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+var (
+	ensAbi         = ParseABI(abi_ens)
+	ensResolverAbi = ParseABI(abi_ens_resolver)
+)
+
+// ResolveENS returns the address the ENS name resolves to with the global backend. Names are only
+// lowercased, not fully normalized.
+func ResolveENS(name string) (common.Address, error) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := requireClient(nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return resolveENS(ctx, client, name)
+}
+
+// LookupENS returns the primary ENS name of the address with the global backend. Reverse records
+// are only returned if the name resolves back to the address, as anyone can claim any name in
+// their reverse record. Names, and the absence of a name, are cached like the other contract
+// lookups.
+func LookupENS(address common.Address) (string, error) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := requireClient(nil)
+	if err != nil {
+		return "", err
+	}
+
+	return lookupENS(ctx, client, address)
+}
+
+// ensNamehash returns the node of the ENS name, see EIP-137.
+func ensNamehash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), crypto.Keccak256([]byte(labels[i])))
+	}
+
+	return node
+}
+
+func resolveENS(ctx context.Context, client Backend, name string) (common.Address, error) {
+	node := ensNamehash(name)
+
+	resolver, err := ensResolver(ctx, client, node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if resolver == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("no resolver for %s", name)
+	}
+
+	values, err := ensCall(ctx, client, resolver, ensResolverAbi, "addr", node)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	address := values[0].(common.Address)
+	if address == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%s does not resolve to an address", name)
+	}
+
+	return address, nil
+}
+
+func lookupENS(ctx context.Context, client Backend, address common.Address) (string, error) {
+	name := cachedLookup(ctx, client, address, LookupENSName, func() *string {
+		node := ensNamehash(strings.ToLower(address.Hex()[2:]) + ".addr.reverse")
+
+		resolver, err := ensResolver(ctx, client, node)
+		if err != nil {
+			return nil
+		}
+
+		// addresses without reverse record, or with a name not resolving back, have no name
+		result := ""
+		if resolver == (common.Address{}) {
+			return &result
+		}
+
+		values, err := ensCall(ctx, client, resolver, ensResolverAbi, "name", node)
+		if err != nil || values[0].(string) == "" {
+			return &result
+		}
+
+		if forward, err := resolveENS(ctx, client, values[0].(string)); err == nil && forward == address {
+			result = values[0].(string)
+		}
+		return &result
+	})
+
+	if name == nil {
+		return "", fmt.Errorf("can not look up ENS name of %s", address.Hex())
+	}
+	if *name == "" {
+		return "", fmt.Errorf("no ENS name for %s", address.Hex())
+	}
+
+	return *name, nil
+}
+
+// ensResolver returns the resolver of the node in the registry, the zero address if it has none.
+func ensResolver(ctx context.Context, client Backend, node common.Hash) (common.Address, error) {
+	values, err := ensCall(ctx, client, ENSRegistry, ensAbi, "resolver", node)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return values[0].(common.Address), nil
+}
+
+func ensCall(ctx context.Context, client Backend, contract common.Address, contractAbi *abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error calling %s of %s: %w", method, contract.Hex(), err)
+	}
+
+	values, err := contractAbi.Unpack(method, output)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("error unpack %s of %s: %v", method, contract.Hex(), err)
+	}
+
+	return values, nil
+}
+
+// nameMethod sets the ENS names of the address params of the decoded method and its nested calls,
+// if enabled.
+func nameMethod(enabled bool, client Backend, decoded *DecodedMethod) {
+	if !enabled || decoded == nil {
+		return
+	}
+
+	decoded.ENSNames = ensNames(client, decoded.Params)
+	for _, call := range decoded.Calls {
+		nameMethod(enabled, client, call)
+	}
+}
+
+// nameLog sets the ENS names of the address params of the decoded log, if enabled.
+func nameLog(enabled bool, client Backend, decoded *DecodedLog) {
+	if !enabled || decoded == nil {
+		return
+	}
+
+	decoded.ENSNames = ensNames(client, decoded.Params)
+}
+
+// ensNames looks up the ENS names of the address params, keyed by address. Addresses without name
+// are left out.
+func ensNames(client Backend, params Params) AddressNames {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := requireClient(client)
+	if err != nil {
+		return nil
+	}
+
+	result := make(AddressNames)
+	for _, address := range collectAddresses(params) {
+		if name, err := lookupENS(ctx, client, address); err == nil {
+			result[address.Hex()] = name
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestENSNamehash(t *testing.T) {
+	// vectors of EIP-137
+	if node := ensNamehash(""); node != (common.Hash{}) {
+		t.Fatalf("unexpected node of the root: %s", node.Hex())
+	}
+	if node := ensNamehash("foo.eth"); node != common.HexToHash("0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f") {
+		t.Fatalf("unexpected node of foo.eth: %s", node.Hex())
+	}
+}
+
+func TestENSNames(t *testing.T) {
+	resolver := common.HexToAddress("0x00000000000000000000000000000000000000e5")
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	mallory := common.HexToAddress("0x000000000000000000000000000000000000bad0")
+
+	reverse := func(address common.Address) common.Hash {
+		return ensNamehash(address.Hex()[2:] + ".addr.reverse")
+	}
+	resolvers := map[common.Hash]bool{ensNamehash("alice.eth"): true, reverse(alice): true, reverse(mallory): true}
+	addrs := map[common.Hash]common.Address{ensNamehash("alice.eth"): alice}
+	// mallory claims the name of alice in the reverse record
+	names := map[common.Hash]string{reverse(alice): "alice.eth", reverse(mallory): "alice.eth"}
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.HandleCall(func(to common.Address, data []byte) ([]byte, error) {
+		node := common.BytesToHash(data[4:36])
+
+		var output []byte
+		switch to {
+		case ENSRegistry:
+			result := common.Address{}
+			if resolvers[node] {
+				result = resolver
+			}
+			output, _ = ensAbi.Methods["resolver"].Outputs.Pack(result)
+		case resolver:
+			method, _ := ensResolverAbi.MethodById(data[:4])
+			if method.RawName == "addr" {
+				output, _ = method.Outputs.Pack(addrs[node])
+			} else {
+				output, _ = method.Outputs.Pack(names[node])
+			}
+		}
+		return output, nil
+	})
+	server.Respond("eth_chainId", "0x1")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := Ctx.eth
+	SetClient(client)
+	defer SetBackend(previous)
+
+	if address, err := ResolveENS("Alice.eth"); err != nil || address != alice {
+		t.Fatalf("unexpected address: %s %v", address.Hex(), err)
+	}
+	if _, err := ResolveENS("bob.eth"); err == nil {
+		t.Fatal("expected an error for names without resolver")
+	}
+
+	if name, err := LookupENS(alice); err != nil || name != "alice.eth" {
+		t.Fatalf("unexpected name: %s %v", name, err)
+	}
+	if name, err := LookupENS(mallory); err == nil {
+		t.Fatalf("expected names not resolving back to be rejected, got %s", name)
+	}
+
+	// the params of decoded results are annotated
+	store := &Storage{ResolveNames: true}
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+
+	decoded := store.DecodeLog(&types.Log{
+		Address: common.HexToAddress("0x00000000000000000000000000000000000000c0"),
+		Topics:  []common.Hash{transferTopic, alice.Hash(), mallory.Hash()},
+		Data:    math.U256Bytes(big.NewInt(1)),
+	})
+	if decoded == nil {
+		t.Fatal("transfer not decoded")
+	}
+
+	if len(decoded.ENSNames) != 1 || decoded.ENSNames[alice.Hex()] != "alice.eth" {
+		t.Fatalf("unexpected names: %v", decoded.ENSNames)
+	}
+}
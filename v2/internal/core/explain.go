@@ -0,0 +1,207 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/exp/slices"
+)
+
+// DecodeAttempt is a struct for holding a single ABI candidate tried while decoding.
+type DecodeAttempt struct {
+	Source    string `json:"source"`              // Name of the ABI the candidate was taken from.
+	Candidate string `json:"candidate,omitempty"` // Signature of the candidate, empty if the ABI had none.
+	Matched   bool   `json:"matched"`             // Whether the candidate decoded the input.
+	Reason    string `json:"reason,omitempty"`    // Why the candidate was rejected, or notes on an accepted one.
+}
+
+// DecodeTrace is a struct for holding every decision taken while decoding a transaction or log,
+// in the order the decoder takes them.
+type DecodeTrace struct {
+	Kind     string          `json:"kind"`             // "method" or "log".
+	Target   string          `json:"target"`           // Transaction hash, or transaction hash and log index.
+	Key      string          `json:"key"`              // Selector or topics[0] looked up in the ABIs.
+	Attempts []DecodeAttempt `json:"attempts"`         // All candidates tried, in order.
+	Choice   string          `json:"choice,omitempty"` // Signature of the candidate used, empty if none matched.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodeTrace object.
+func (data *DecodeTrace) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodeTrace object.
+func (data *DecodeTrace) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ExplainMethod records why the decoder's ABI does or does not decode the transaction.
+func (decoder *AbiDecoder) ExplainMethod(tx *types.Transaction) *DecodeTrace {
+	checkAbi(decoder)
+	return explainMethod(tx, []abi.ABI{*decoder.Abi}, func(int) string { return "decoder" })
+}
+
+// ExplainLog records why the decoder's ABI does or does not decode the log, including the
+// anonymous events if Anonymous is set.
+func (decoder *AbiDecoder) ExplainLog(vLog *types.Log) *DecodeTrace {
+	checkAbi(decoder)
+	return explainLog(vLog, []abi.ABI{*decoder.Abi}, decoder.Anonymous, func(int) string { return "decoder" })
+}
+
+// ExplainMethod records every ABI of AbiList tried for the transaction, why each failed and which
+// one DecodeMethod picks.
+func (store *Storage) ExplainMethod(tx *types.Transaction) *DecodeTrace {
+	return explainMethod(tx, store.AbiList, store.abiName)
+}
+
+// ExplainLog records every ABI of AbiList tried for the log, why each failed and which one
+// DecodeLog picks. This is the first thing to look at when a log decodes to the wrong event.
+func (store *Storage) ExplainLog(vLog *types.Log) *DecodeTrace {
+	return explainLog(vLog, store.AbiList, store.Anonymous, store.abiName)
+}
+
+func explainMethod(tx *types.Transaction, abis []abi.ABI, name func(int) string) *DecodeTrace {
+	data := tx.Data()
+	trace := DecodeTrace{Kind: "method", Target: tx.Hash().Hex(), Attempts: []DecodeAttempt{}}
+
+	if len(data) < 4 {
+		trace.Attempts = append(trace.Attempts, DecodeAttempt{Reason: fmt.Sprintf("calldata too short: %d bytes", len(data))})
+		return &trace
+	}
+
+	trace.Key = hexutil.Encode(data[:4])
+	for i, contractAbi := range abis {
+		attempt := DecodeAttempt{Source: name(i)}
+
+		method, err := contractAbi.MethodById(data[:4])
+		if err != nil {
+			attempt.Reason = "selector not in abi"
+			trace.Attempts = append(trace.Attempts, attempt)
+			continue
+		}
+
+		attempt.Candidate = method.Sig
+		if _, err := method.Inputs.Unpack(data[4:]); err != nil {
+			attempt.Reason = fmt.Sprintf("unpack error: %v", err)
+		} else {
+			attempt.Matched = true
+			if trace.Choice == "" {
+				trace.Choice = method.Sig
+			} else {
+				attempt.Reason = "shadowed by " + trace.Choice
+			}
+		}
+
+		trace.Attempts = append(trace.Attempts, attempt)
+	}
+
+	return &trace
+}
+
+func explainLog(vLog *types.Log, abis []abi.ABI, anonymous bool, name func(int) string) *DecodeTrace {
+	trace := DecodeTrace{
+		Kind:     "log",
+		Target:   fmt.Sprintf("%s:%d", vLog.TxHash.Hex(), vLog.Index),
+		Attempts: []DecodeAttempt{},
+	}
+
+	if len(vLog.Topics) == 0 {
+		trace.Attempts = append(trace.Attempts, DecodeAttempt{Reason: "log has no topics"})
+	} else {
+		trace.Key = vLog.Topics[0].Hex()
+	}
+
+	for i, contractAbi := range abis {
+		if len(vLog.Topics) == 0 {
+			break
+		}
+
+		attempt := DecodeAttempt{Source: name(i)}
+
+		event, err := contractAbi.EventByID(vLog.Topics[0])
+		if err != nil {
+			attempt.Reason = "topic not in abi"
+			trace.Attempts = append(trace.Attempts, attempt)
+			continue
+		}
+
+		attempt.Candidate = event.Sig
+		indexed := 0
+		for _, argument := range event.Inputs {
+			if argument.Indexed {
+				indexed++
+			}
+		}
+
+		if indexed != len(vLog.Topics)-1 {
+			attempt.Reason = fmt.Sprintf("topic count mismatch: %d indexed params, %d topics", indexed, len(vLog.Topics)-1)
+		}
+
+		// mirror parseLog, which tolerates unpack errors of some events and of empty data
+		accepted := true
+		if _, err := event.Inputs.NonIndexed().Unpack(vLog.Data); err != nil {
+			accepted = slices.Contains(tolerantEvents, event.Name) || len(vLog.Data) == 0
+			reason := fmt.Sprintf("unpack error: %v", err)
+			if accepted {
+				reason += " (tolerated)"
+			}
+			attempt.Reason = joinReason(attempt.Reason, reason)
+		}
+
+		if accepted {
+			attempt.Matched = true
+			if trace.Choice == "" {
+				trace.Choice = event.Sig
+			} else {
+				attempt.Reason = joinReason(attempt.Reason, "shadowed by "+trace.Choice)
+			}
+		}
+
+		trace.Attempts = append(trace.Attempts, attempt)
+	}
+
+	if !anonymous || trace.Choice != "" {
+		return &trace
+	}
+
+	for i, contractAbi := range abis {
+		names := make([]string, 0, len(contractAbi.Events))
+		for name, event := range contractAbi.Events {
+			if event.Anonymous {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, eventName := range names {
+			event := contractAbi.Events[eventName]
+
+			attempt := DecodeAttempt{Source: name(i), Candidate: event.Sig + " (anonymous)"}
+			if matchesAnonymousLog(event, vLog) {
+				attempt.Matched = true
+				if trace.Choice == "" {
+					trace.Choice = event.Sig
+				}
+			} else {
+				attempt.Reason = "topic count or data length mismatch"
+			}
+
+			trace.Attempts = append(trace.Attempts, attempt)
+		}
+	}
+
+	return &trace
+}
+
+func joinReason(reason string, extra string) string {
+	if reason == "" {
+		return extra
+	}
+
+	return reason + "; " + extra
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrNotVerified is returned by FetchABI for contracts without verified source on the explorer.
+var ErrNotVerified = errors.New("contract source code not verified")
+
+// ExplorerConfig configures the Etherscan-compatible API used by FetchABI, e.g. Etherscan and its
+// forks or Blockscout. The config keeps the rate limiter and the cache of unverified contracts,
+// so the same pointer should be reused for all fetches against one explorer.
+type ExplorerConfig struct {
+	BaseURL        string        // API endpoint, e.g. https://api.etherscan.io/api or https://eth.blockscout.com/api.
+	APIKey         string        // Optional API key, sent as apikey query parameter.
+	Client         *http.Client  // Defaults to http.DefaultClient.
+	Timeout        time.Duration // Timeout of a single request, defaults to 10 seconds.
+	RateLimit      time.Duration // Minimum interval between requests, e.g. 200ms for 5 requests per second.
+	NotVerifiedTTL time.Duration // How long unverified contracts are cached, defaults to one hour.
+
+	mu          sync.Mutex
+	last        time.Time
+	notVerified map[string]time.Time
+}
+
+// FetchABI returns the indexed contract of the address, fetching its verified ABI from the
+// explorer and indexing it with SetIndexed if it is not indexed yet. Contracts reported as not
+// verified are cached and fail with ErrNotVerified without a request until NotVerifiedTTL passed.
+// An invalid config fails with ValidationErrors.
+func (store *Storage) FetchABI(address string, config *ExplorerConfig) (*IndexedABI, error) {
+	if indexed := store.GetIndexed(address); indexed != nil {
+		return indexed, nil
+	}
+
+	if config == nil {
+		return nil, fmt.Errorf("explorer config required for lookup of %s", address)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid address: %s", address)
+	}
+
+	source, err := config.fetchABI(address)
+	if err != nil {
+		return nil, err
+	}
+
+	contractAbi, err := abi.JSON(strings.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi of %s: %w", address, err)
+	}
+
+	return store.SetIndexed(address, contractAbi, true, false, nil), nil
+}
+
+func (config *ExplorerConfig) fetchABI(address string) (string, error) {
+	key := strings.ToLower(address)
+	if config.isNotVerified(key) {
+		return "", fmt.Errorf("%s: %w", address, ErrNotVerified)
+	}
+
+	config.wait()
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := withTimeout(context.Background(), timeout)
+	defer cancel()
+
+	query := url.Values{"module": {"contract"}, "action": {"getabi"}, "address": {address}}
+	if config.APIKey != "" {
+		query.Set("apikey", config.APIKey)
+	}
+
+	var response struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Result  string `json:"result"`
+	}
+
+	separator := "?"
+	if strings.Contains(config.BaseURL, "?") {
+		separator = "&"
+	}
+
+	if err := getJSON(ctx, config.Client, config.BaseURL+separator+query.Encode(), &response); err != nil {
+		return "", fmt.Errorf("explorer request failed: %w", err)
+	}
+
+	if response.Status != "1" {
+		if strings.Contains(strings.ToLower(response.Result), "not verified") {
+			config.setNotVerified(key)
+			return "", fmt.Errorf("%s: %w", address, ErrNotVerified)
+		}
+		return "", fmt.Errorf("explorer error: %s %s", response.Message, response.Result)
+	}
+
+	return response.Result, nil
+}
+
+// wait blocks until the rate limit allows the next request.
+func (config *ExplorerConfig) wait() {
+	if config.RateLimit <= 0 {
+		return
+	}
+
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	clock := getClock()
+	if delay := config.last.Add(config.RateLimit).Sub(clock.Now()); delay > 0 {
+		<-clock.After(delay)
+	}
+	config.last = clock.Now()
+}
+
+func (config *ExplorerConfig) isNotVerified(key string) bool {
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	cached, ok := config.notVerified[key]
+	if !ok {
+		return false
+	}
+
+	ttl := config.NotVerifiedTTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	if getClock().Now().Sub(cached) > ttl {
+		delete(config.notVerified, key)
+		return false
+	}
+
+	return true
+}
+
+func (config *ExplorerConfig) setNotVerified(key string) {
+	config.mu.Lock()
+	defer config.mu.Unlock()
+
+	if config.notVerified == nil {
+		config.notVerified = make(map[string]time.Time)
+	}
+	config.notVerified[key] = getClock().Now()
+}
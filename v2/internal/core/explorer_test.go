@@ -0,0 +1,97 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestFetchABI(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("apikey") != "key" || r.URL.Query().Get("action") != "getabi" {
+			t.Errorf("invalid query: %v", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("address") == target_erc20 {
+			w.Write([]byte(`{"status":"1","message":"OK","result":` + strconv.Quote(abi_erc20) + `}`))
+			return
+		}
+		w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`))
+	}))
+	defer server.Close()
+
+	// SetIndexed fetches the bytecode if a client is connected
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	store := Storage{Indexed: make(map[string]*IndexedABI)}
+	config := &ExplorerConfig{BaseURL: server.URL, APIKey: "key"}
+
+	indexed, err := store.FetchABI(target_erc20, config)
+	if err != nil || !indexed.Verified || indexed.Abi.Methods["transfer"].Sig != "transfer(address,uint256)" {
+		t.Fatalf("invalid fetched abi: %v %v", indexed, err)
+	}
+
+	if _, err := store.FetchABI(target_erc20, config); err != nil || requests != 1 {
+		t.Fatalf("indexed contract fetched again: %v, %d requests", err, requests)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := store.FetchABI(target_erc721, config); !errors.Is(err, ErrNotVerified) {
+			t.Fatalf("expected not verified error: %v", err)
+		}
+	}
+
+	if requests != 2 {
+		t.Fatalf("not verified result not cached: %d requests", requests)
+	}
+}
+
+func TestFetchSourcify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/files/any/1/"+target_erc721 {
+			http.NotFound(w, r)
+			return
+		}
+
+		metadata := `{"compiler":{"version":"0.8.19+commit.7dd6d404"},"output":{"abi":` + abi_erc20 + `},"settings":{"compilationTarget":{"contracts/Token.sol":"Token"}}}`
+		files, _ := json.Marshal(map[string]interface{}{
+			"status": "partial",
+			"files": []map[string]string{
+				{"name": "metadata.json", "path": "/contracts/partial_match/1/x/metadata.json", "content": metadata},
+				{"name": "Token.sol", "path": "/contracts/partial_match/1/x/sources/contracts/Token.sol", "content": "contract Token {}"},
+			},
+		})
+		w.Write(files)
+	}))
+	defer server.Close()
+
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	store := Storage{Indexed: make(map[string]*IndexedABI)}
+	config := &SourcifyConfig{BaseURL: server.URL}
+
+	indexed, err := store.FetchSourcify(target_erc20, big.NewInt(1), config)
+	if err != nil || indexed.Verified || *indexed.Name != "Token" || *indexed.Pragma != "0.8.19" || *indexed.Source != "contract Token {}" {
+		t.Fatalf("invalid sourcify contract: %+v %v", indexed, err)
+	}
+
+	if _, err := store.FetchSourcify(target_erc721, big.NewInt(1), config); !errors.Is(err, ErrNotVerified) {
+		t.Fatalf("expected not verified error: %v", err)
+	}
+
+	config.FullMatchOnly = true
+	if _, err := store.FetchSourcify(target_contract, big.NewInt(1), config); !errors.Is(err, ErrNotVerified) {
+		t.Fatalf("expected not verified error: %v", err)
+	}
+}
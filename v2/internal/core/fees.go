@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// FeeBreakdown attributes the fee paid by a transaction. On EIP-1559 chains the base fee part is
+// burned and only the priority tip goes to the block builder, before London the builder receives
+// the full gas price. OP-stack chains additionally charge an L1 data fee that is not part of
+// gasUsed * effectiveGasPrice.
+type FeeBreakdown struct {
+	GasUsed           uint64   `json:"gasUsed"`             // Gas used by the transaction.
+	EffectiveGasPrice *big.Int `json:"effectiveGasPrice"`   // Price per gas actually paid.
+	BaseFee           *big.Int `json:"baseFee,omitempty"`   // Base fee per gas of the block, nil before London.
+	Burned            *big.Int `json:"burned"`              // Base fee burned (baseFee * gasUsed).
+	Tip               *big.Int `json:"tip"`                 // Fee paid to the builder.
+	Builder           string   `json:"builder"`             // Coinbase of the block receiving the tip.
+	L1DataFee         *big.Int `json:"l1DataFee,omitempty"` // L1 data fee of OP-stack chains.
+	Total             *big.Int `json:"total"`               // Total fee paid by the sender.
+}
+
+// L1Fees holds the OP-stack specific fee fields of a receipt.
+type L1Fees struct {
+	L1Fee       *hexutil.Big `json:"l1Fee"`
+	L1GasUsed   *hexutil.Big `json:"l1GasUsed"`
+	L1GasPrice  *hexutil.Big `json:"l1GasPrice"`
+	L1FeeScalar string       `json:"l1FeeScalar"`
+}
+
+// ComputeFees computes the fee breakdown of a receipt using the header of its block.
+func ComputeFees(receipt *types.Receipt, header *types.Header) (*FeeBreakdown, error) {
+	if receipt.EffectiveGasPrice == nil {
+		return nil, fmt.Errorf("receipt has no effective gas price: %s", receipt.TxHash.Hex())
+	}
+
+	gasUsed := new(big.Int).SetUint64(receipt.GasUsed)
+	result := FeeBreakdown{
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: new(big.Int).Set(receipt.EffectiveGasPrice),
+		Burned:            new(big.Int),
+		Total:             new(big.Int).Mul(receipt.EffectiveGasPrice, gasUsed),
+		Builder:           header.Coinbase.Hex(),
+	}
+
+	tipPerGas := new(big.Int).Set(receipt.EffectiveGasPrice)
+	if header.BaseFee != nil {
+		result.BaseFee = new(big.Int).Set(header.BaseFee)
+		result.Burned.Mul(header.BaseFee, gasUsed)
+		tipPerGas.Sub(tipPerGas, header.BaseFee)
+
+		// deposit and system transactions of L2s may pay less than the base fee
+		if tipPerGas.Sign() < 0 {
+			tipPerGas.SetInt64(0)
+			result.Burned.Set(result.Total)
+		}
+	}
+
+	result.Tip = tipPerGas.Mul(tipPerGas, gasUsed)
+
+	return &result, nil
+}
+
+// AddL1Fees adds the L1 data fee of an OP-stack receipt to the breakdown.
+func (fees *FeeBreakdown) AddL1Fees(l1 *L1Fees) {
+	if l1 == nil || l1.L1Fee == nil {
+		return
+	}
+
+	fees.L1DataFee = new(big.Int).Set(l1.L1Fee.ToInt())
+	fees.Total.Add(fees.Total, fees.L1DataFee)
+}
+
+// fetchReceipt fetches a receipt as raw JSON, so chain specific fields like the OP-stack L1 fees
+// can be read next to the standard receipt fields. Backends without a JSON-RPC client return the
+// standard receipt only.
+func fetchReceipt(ctx context.Context, client Backend, hash common.Hash) (*types.Receipt, *L1Fees, error) {
+	if rpcClient(client) == nil {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		return receipt, nil, err
+	}
+
+	var raw json.RawMessage
+	if err := callContext(ctx, client, &raw, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, nil, err
+	}
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, ethereum.NotFound
+	}
+
+	var receipt types.Receipt
+	if err := json.Unmarshal(raw, &receipt); err != nil {
+		return nil, nil, err
+	}
+
+	var l1 L1Fees
+	if err := json.Unmarshal(raw, &l1); err != nil || l1.L1Fee == nil {
+		return &receipt, nil, nil
+	}
+
+	return &receipt, &l1, nil
+}
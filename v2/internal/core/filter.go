@@ -0,0 +1,149 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BuildFilter returns a filter for the logs of the event with the given name or signature in the
+// decoder's ABI, restricted to the decoder's ContractAddress if set. The arg filters are keyed by
+// the names of indexed params and use the representation of decoded params (hex addresses,
+// decimal or 0x hex integers); a slice of values matches any of them. The block range is left for
+// the caller to set.
+func (decoder *AbiDecoder) BuildFilter(eventName string, argFilters map[string]interface{}) (ethereum.FilterQuery, error) {
+	checkAbi(decoder)
+
+	event, ok := findEvent(*decoder.Abi, eventName)
+	if !ok {
+		return ethereum.FilterQuery{}, fmt.Errorf("event %s not in abi", eventName)
+	}
+
+	filter, err := buildFilter(event, argFilters)
+	if err != nil {
+		return filter, err
+	}
+
+	if decoder.ContractAddress != nil {
+		filter.Addresses = []common.Address{common.HexToAddress(*decoder.ContractAddress)}
+	}
+
+	return filter, nil
+}
+
+// BuildFilter is like AbiDecoder.BuildFilter, looking up the event in all ABIs of the store. The
+// first event whose indexed params include all filtered args is used, as tokens disagree on
+// which params of e.g. Transfer are indexed. The filter is not restricted to any contract.
+func (store *Storage) BuildFilter(eventName string, argFilters map[string]interface{}) (ethereum.FilterQuery, error) {
+	var lastErr error
+	for _, contractAbi := range store.AbiList {
+		event, ok := findEvent(contractAbi, eventName)
+		if !ok {
+			continue
+		}
+
+		filter, err := buildFilter(event, argFilters)
+		if err == nil {
+			return filter, nil
+		}
+		lastErr = err
+	}
+
+	if lastErr != nil {
+		return ethereum.FilterQuery{}, lastErr
+	}
+
+	return ethereum.FilterQuery{}, fmt.Errorf("event %s not in any abi", eventName)
+}
+
+// findEvent looks up the event by name, or by signature if the name contains parentheses.
+func findEvent(contractAbi abi.ABI, name string) (abi.Event, bool) {
+	if !strings.Contains(name, "(") {
+		event, ok := contractAbi.Events[name]
+		return event, ok
+	}
+
+	for _, event := range contractAbi.Events {
+		if event.Sig == name {
+			return event, true
+		}
+	}
+
+	return abi.Event{}, false
+}
+
+// buildFilter computes topic0 of the event and encodes the arg filters into the topics of the
+// indexed params.
+func buildFilter(event abi.Event, argFilters map[string]interface{}) (ethereum.FilterQuery, error) {
+	if event.Anonymous {
+		return ethereum.FilterQuery{}, fmt.Errorf("event %s is anonymous, it has no topic to filter on", event.Name)
+	}
+
+	used := 0
+	var rules [][]interface{}
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			if _, ok := argFilters[input.Name]; ok {
+				return ethereum.FilterQuery{}, fmt.Errorf("param %s of %s is not indexed", input.Name, event.Sig)
+			}
+			continue
+		}
+
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprint(i)
+		}
+
+		value, ok := argFilters[name]
+		if !ok {
+			rules = append(rules, nil)
+			continue
+		}
+		used++
+
+		var rule []interface{}
+		for _, candidate := range filterValues(value) {
+			coerced, err := coerceArgument(input.Type, candidate)
+			if err != nil {
+				return ethereum.FilterQuery{}, fmt.Errorf("invalid filter of %s: %w", name, err)
+			}
+			rule = append(rule, coerced)
+		}
+		rules = append(rules, rule)
+	}
+
+	if used != len(argFilters) {
+		return ethereum.FilterQuery{}, fmt.Errorf("unknown params in filter of %s", event.Sig)
+	}
+
+	topics, err := abi.MakeTopics(rules...)
+	if err != nil {
+		return ethereum.FilterQuery{}, fmt.Errorf("error encoding topics of %s: %w", event.Sig, err)
+	}
+
+	// trailing wildcards are left out, they only make the query longer
+	for len(topics) > 0 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
+
+	return ethereum.FilterQuery{Topics: append([][]common.Hash{{event.ID}}, topics...)}, nil
+}
+
+// filterValues splits a slice of alternatives into its values. Byte slices are a single value.
+func filterValues(value interface{}) []interface{} {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice || list.Type().Elem().Kind() == reflect.Uint8 {
+		return []interface{}{value}
+	}
+
+	result := make([]interface{}, list.Len())
+	for i := range result {
+		result[i] = list.Index(i).Interface()
+	}
+
+	return result
+}
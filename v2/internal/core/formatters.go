@@ -0,0 +1,70 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// FormatterFunc formats a decoded param value. It receives the param name and the value as
+// unpacked by the abi package (e.g. *big.Int, common.Address, [4]byte) and returns the value
+// stored in Params.
+type FormatterFunc func(name string, value interface{}) interface{}
+
+var formatters = struct {
+	sync.RWMutex
+	types map[string]FormatterFunc
+	names map[string]FormatterFunc
+}{
+	types: make(map[string]FormatterFunc),
+	names: make(map[string]FormatterFunc),
+}
+
+// RegisterFormatter registers a formatter for all params of the given ABI type, e.g. "uint256"
+// or "bytes4". It replaces the default formatting of these params in all decoded results, except
+// for decoders preserving native types.
+func RegisterFormatter(abiType string, fn FormatterFunc) {
+	formatters.Lock()
+	defer formatters.Unlock()
+	formatters.types[abiType] = fn
+}
+
+// RegisterParamFormatter registers a formatter for all params with the given name. Name
+// formatters take precedence over type formatters.
+func RegisterParamFormatter(name string, fn FormatterFunc) {
+	formatters.Lock()
+	defer formatters.Unlock()
+	formatters.names[name] = fn
+}
+
+// ResetFormatters removes all registered formatters.
+func ResetFormatters() {
+	formatters.Lock()
+	defer formatters.Unlock()
+	formatters.types = make(map[string]FormatterFunc)
+	formatters.names = make(map[string]FormatterFunc)
+}
+
+// lookupFormatter returns the formatter registered for the param name, or for the ABI type of
+// the argument with that name. Unnamed arguments are matched by their position.
+func lookupFormatter(name string, arguments abi.Arguments) FormatterFunc {
+	formatters.RLock()
+	defer formatters.RUnlock()
+
+	if len(formatters.names) == 0 && len(formatters.types) == 0 {
+		return nil
+	}
+
+	if fn, ok := formatters.names[name]; ok {
+		return fn
+	}
+
+	for i, argument := range arguments {
+		if argument.Name == name || (argument.Name == "" && fmt.Sprint(i) == name) {
+			return formatters.types[argument.Type.String()]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,798 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/exp/slices"
+)
+
+// parseMethod extracts the method signature and its parameters from the input data of a transaction, using
+// the provided contract ABI to decode the input data. It returns a DecodedMethod object containing the contract
+// address, method signature, signature hash, and the decoded method parameters as a map[string]interface{}.
+// It returns nil if the method signature is not found in the ABI, and an error if the input data can not be
+// unpacked with it, e.g. for truncated arguments or another method sharing the selector.
+// The debug argument is optional, and if set to true, will log the transactions without 'to' address and the
+// input data that could not be unpacked.
+func parseMethod(tx *types.Transaction, contractAbi abi.ABI, debug *bool, preserve bool) (*DecodedMethod, error) {
+	// initialize the contract variable
+	var contract string
+
+	// if the transaction destination is not nil, set the contract to its address
+	if tx.To() != nil {
+		contract = tx.To().Hex()
+	} else { // otherwise set it to a default address and log a warning if debug is enabled
+		contract = EtherAddress
+		if debug != nil && *debug {
+			log.Println(`decoder: no tx.to in transaction:`, tx.Hash().String())
+		}
+	}
+
+	decoded, err := parseCalldata(contract, tx.Data(), contractAbi, debug, preserve)
+
+	// truncated or malformed arguments are not decoded, they must not stop a scan or subscription
+	if err != nil {
+		if debug != nil && *debug {
+			log.Println(
+				"error unpack method into map:", tx.Hash().Hex(),
+				">> input:", hexutil.Encode(tx.Data()),
+				">> error:", err,
+			)
+		}
+		return nil, fmt.Errorf("error unpack method of %s: %w", tx.Hash().Hex(), err)
+	}
+
+	if decoded != nil {
+		decoded.TransactionHash = tx.Hash().Hex()
+
+		// permits are verified against the chain of replay protected transactions
+		if tx.Protected() {
+			VerifyPermits(decoded, tx.ChainId())
+		}
+	}
+
+	return decoded, nil
+}
+
+// SetCodeTxType is the EIP-7702 set code transaction type. Transactions of this type are not
+// supported by the signer of the go-ethereum version this package is built with, and fail to
+// decode from RPC responses.
+const SetCodeTxType = 0x04
+
+// txTypeNames are the names of the EIP-2718 transaction types, including the L2 types of OP-stack
+// chains and Arbitrum, see TxTypeName.
+var txTypeNames = map[uint8]string{
+	types.LegacyTxType:     "legacy",
+	types.AccessListTxType: "accessList",
+	types.DynamicFeeTxType: "dynamicFee",
+	types.BlobTxType:       "blob",
+	SetCodeTxType:          "setCode",
+
+	DepositTxType:                 "deposit",
+	ArbitrumDepositTxType:         "arbitrumDeposit",
+	ArbitrumUnsignedTxType:        "arbitrumUnsigned",
+	ArbitrumContractTxType:        "arbitrumContract",
+	ArbitrumRetryTxType:           "arbitrumRetry",
+	ArbitrumSubmitRetryableTxType: "arbitrumSubmitRetryable",
+	ArbitrumInternalTxType:        "arbitrumInternal",
+	ArbitrumLegacyTxType:          "arbitrumLegacy",
+}
+
+// TxTypeName returns the name of the EIP-2718 transaction type, e.g. dynamicFee for EIP-1559
+// transactions, or the type in hex for unknown types.
+func TxTypeName(txType uint8) string {
+	if name, ok := txTypeNames[txType]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("0x%02x", txType)
+}
+
+// includeTx sets the type, sender, value, nonce, gas, access list and blob hashes of the
+// transaction on the decoded method, if enabled.
+func includeTx(enabled bool, tx *types.Transaction, decoded *DecodedMethod) {
+	if !enabled || tx == nil || decoded == nil {
+		return
+	}
+
+	info := TxInfo{
+		Type:     tx.Type(),
+		TypeName: TxTypeName(tx.Type()),
+		Value:    tx.Value().String(),
+		Nonce:    tx.Nonce(),
+		Gas:      tx.Gas(),
+	}
+
+	if sender, err := txSender(tx); err == nil {
+		info.From = sender.Hex()
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		info.GasPrice = tx.GasPrice().String()
+	default:
+		info.GasFeeCap = tx.GasFeeCap().String()
+		info.GasTipCap = tx.GasTipCap().String()
+	}
+
+	if tx.Type() == types.BlobTxType {
+		info.BlobFeeCap = tx.BlobGasFeeCap().String()
+		info.BlobGas = tx.BlobGas()
+		info.BlobHashes = tx.BlobHashes()
+	}
+
+	if tx.Type() != types.LegacyTxType {
+		info.AccessList = tx.AccessList()
+	}
+
+	if tx.Protected() {
+		chainId := tx.ChainId().Uint64()
+		info.ChainId = &chainId
+	}
+
+	decoded.Tx = &info
+}
+
+// unknownMethod creates the fallback result for calldata whose selector is unknown to all ABIs.
+// It returns nil for calldata without a selector, e.g. plain transfers.
+func unknownMethod(contract string, data []byte) *DecodedMethod {
+	if len(data) < 4 {
+		return nil
+	}
+
+	return &DecodedMethod{
+		Contract: contract,
+		SigHash:  hexutil.Encode(data[:4]),
+		Params:   Params{},
+		Data:     hexutil.Encode(data),
+		Unknown:  true,
+	}
+}
+
+// unknownTransaction creates the fallback result for a transaction whose selector is unknown.
+func unknownTransaction(tx *types.Transaction) *DecodedMethod {
+	contract := EtherAddress
+	if tx.To() != nil {
+		contract = tx.To().Hex()
+	}
+
+	decoded := unknownMethod(contract, tx.Data())
+	if decoded != nil {
+		decoded.TransactionHash = tx.Hash().Hex()
+	}
+
+	return decoded
+}
+
+// parseCalldata decodes raw calldata sent to the given contract address using the provided contract ABI.
+// It returns nil without error if the calldata is too short or its selector is not part of the ABI,
+// and an error if the selector matched but the arguments could not be unpacked.
+func parseCalldata(contract string, data []byte, contractAbi abi.ABI, debug *bool, preserve bool) (*DecodedMethod, error) {
+	// initialize an empty map to store method parameters
+	params := make(map[string]interface{})
+
+	// check if the calldata contains at least a 4 byte selector
+	if len(data) < 4 {
+		return nil, nil
+	}
+
+	// split the selector from the encoded arguments
+	sigHash := common.Bytes2Hex(data[:4])
+	inputBytes := data[4:]
+
+	// find the method corresponding to the signature hash in the ABI
+	method, err := contractAbi.MethodById(data[:4])
+
+	// if there is an error or the method is not found, return nil
+	if err != nil || method == nil {
+		return nil, nil
+	}
+
+	// unpack the method inputs into the params map
+	if err := method.Inputs.UnpackIntoMap(params, inputBytes); err != nil {
+		return nil, fmt.Errorf("%s (0x%s): %w", method.Name, sigHash, err)
+	}
+
+	// remember calldata nested in the params before they get formatted
+	nested := collectNestedCalls(contract, method, params)
+
+	// format the parameters and update the params map, unless native types are preserved
+	if !preserve {
+		params = formatParameters(params, debug, method.Inputs...)
+	}
+
+	// decode signed approvals into their fields
+	var permits []PermitInfo
+	if method.RawName == "permit" || method.RawName == "permitTransferFrom" {
+		permits = DecodePermits(contract, data)
+	}
+
+	// return the decoded method as a pointer to a DecodedMethod struct
+	return &DecodedMethod{
+		Contract:      contract,
+		SigHash:       "0x" + sigHash,
+		Signature:     method.Sig,
+		Name:          method.Name,
+		Params:        params,
+		Inputs:        paramInfos(method.Inputs),
+		ParamsOrdered: orderParams(params, method.Inputs),
+		Data:          hexutil.Encode(data),
+		Permits:       permits,
+		nested:        nested,
+	}, nil
+}
+
+// parseLog parses a Ethereum log entry and decodes its event parameters according to a given contract ABI.
+// It returns a DecodedLog struct containing the decoded parameters, or nil if the log entry could not be decoded.
+// vLog: the log entry to be decoded.
+// contractAbi: the ABI of the contract where the log entry originated from.
+// debug: if true, additional debug messages will be printed.
+// tolerantEvents are decoded even if their data can not be unpacked, as some tokens emit them
+// with different signatures than their ABI.
+var tolerantEvents = []string{
+	"Approval",
+	"Transfer",
+	"Deposit",
+}
+
+func parseLog(vLog *types.Log, contractAbi abi.ABI, debug *bool, preserve bool) *DecodedLog {
+	// Check if the log entry has at least one topic (the event signature hash).
+	if len(vLog.Topics) <= 0 {
+		return nil
+	}
+
+	// Get the event corresponding to the signature hash.
+	topic0 := vLog.Topics[0]
+	params := Params{}
+	event, err := contractAbi.EventByID(vLog.Topics[0])
+	if err != nil {
+		return nil
+	}
+
+	// Unpack the event parameters from the log data.
+	err = contractAbi.UnpackIntoMap(params, event.Name, vLog.Data)
+	if err != nil {
+		// Some events may have different signatures than their ABI, or may contain invalid data.
+		// If we cannot unpack the parameters, we check if the event is in a list of known skipped events,
+		// or if the log data is empty. If so, we skip the event, otherwise we return nil.
+		if !slices.Contains(tolerantEvents, event.Name) {
+			if hexutil.Encode(vLog.Data) != "0x" {
+				fmt.Println("ERROR UNPACK LOG DATA", err, event.Name)
+				return nil
+			}
+		} else {
+			if debug != nil && *debug {
+				log.Fatal(`unpack error`, event.Name, vLog.TxHash.String(), err)
+			}
+		}
+	}
+
+	// Decode indexed parameters by iterating through all inputs and looking for indexed values.
+	if len(vLog.Topics) > 1 {
+		idxIndexedTopics := 1
+		for _, argument := range event.Inputs {
+			if idxIndexedTopics >= len(vLog.Topics) {
+				// Check if the number of indexed topics matches the expected number of inputs.
+				var abi []byte
+				contractAbi.UnmarshalJSON(abi)
+				continue
+			}
+			if argument.Indexed {
+				t := argument.Type.String()
+				topicData := vLog.Topics[idxIndexedTopics]
+
+				// Unpack the indexed parameter value and add it to the parameters map.
+				value, err := contractAbi.Unpack(t, topicData.Bytes())
+				if err != nil {
+					if debug != nil && *debug {
+						log.Fatal(fmt.Sprintf("failed to decode indexed parameter %s: %s\n", argument.Name, err))
+					}
+
+					td := topicData.String()
+					if td[0:26] == "0x000000000000000000000000" {
+						params[argument.Name] = common.HexToAddress(topicData.String()).Hex()
+						if preserve {
+							params[argument.Name] = common.HexToAddress(topicData.String())
+						}
+						if debug != nil && *debug {
+							fmt.Printf(`key: %v - value: %v\n`, argument.Name, params[argument.Name])
+						}
+					}
+
+				} else {
+					params[argument.Name] = value
+				}
+				idxIndexedTopics++
+			}
+		}
+	}
+
+	// Format the decoded parameters, unless native types are preserved, and return the DecodedLog struct.
+	if !preserve {
+		params = formatParameters(params, debug, event.Inputs...)
+	}
+	return &DecodedLog{
+		BlockNumber:     vLog.BlockNumber,
+		BlockHash:       vLog.BlockHash.Hex(),
+		Removed:         vLog.Removed,
+		TransactionHash: vLog.TxHash.Hex(),
+		LogIndex:        vLog.Index,
+		Contract:        vLog.Address.Hex(),
+		Topic:           topic0.Hex(),
+		Signature:       event.Sig,
+		Name:            event.Name,
+		Params:          params,
+		Inputs:          paramInfos(event.Inputs),
+		ParamsOrdered:   orderParams(params, event.Inputs),
+		Topics:          topicsToHex(vLog.Topics),
+		Data:            hexutil.Encode(vLog.Data),
+	}
+}
+
+// parseAnonymousLog decodes a log entry emitted by an anonymous event. Anonymous events do not
+// publish their signature hash in topics[0], so every topic holds an indexed argument instead.
+// Candidates are the anonymous events of all given ABIs whose indexed argument count matches the
+// number of topics, whose static data length matches the log data and whose non-indexed arguments
+// unpack cleanly; an event defined by several ABIs counts once. The first candidate, in the order
+// of the ABIs and by name within an ABI, is returned with a Confidence of 1/len(candidates).
+func parseAnonymousLog(vLog *types.Log, abis []abi.ABI, debug *bool, preserve bool) *DecodedLog {
+	var candidates []abi.Event
+	seen := make(map[common.Hash]bool)
+
+	for _, contractAbi := range abis {
+		var matched []abi.Event
+		for _, event := range contractAbi.Events {
+			if !event.Anonymous || seen[event.ID] || !matchesAnonymousLog(event, vLog) {
+				continue
+			}
+
+			seen[event.ID] = true
+			matched = append(matched, event)
+		}
+
+		// keep the result deterministic, maps are iterated in random order
+		sort.Slice(matched, func(i, j int) bool {
+			return matched[i].Name < matched[j].Name
+		})
+		candidates = append(candidates, matched...)
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	event := candidates[0]
+	params := Params{}
+
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(params, vLog.Data); err != nil {
+		return nil
+	}
+
+	var indexed abi.Arguments
+	for _, argument := range event.Inputs {
+		if argument.Indexed {
+			indexed = append(indexed, argument)
+		}
+	}
+
+	if err := abi.ParseTopicsIntoMap(params, indexed, vLog.Topics); err != nil {
+		if debug != nil && *debug {
+			log.Println(`anonymous topics error`, event.Name, vLog.TxHash.String(), err)
+		}
+		return nil
+	}
+
+	if !preserve {
+		params = formatParameters(params, debug, event.Inputs...)
+	}
+
+	return &DecodedLog{
+		BlockNumber:     vLog.BlockNumber,
+		BlockHash:       vLog.BlockHash.Hex(),
+		Removed:         vLog.Removed,
+		TransactionHash: vLog.TxHash.Hex(),
+		LogIndex:        vLog.Index,
+		Contract:        vLog.Address.Hex(),
+		Signature:       event.Sig,
+		Name:            event.Name,
+		Params:          params,
+		Inputs:          paramInfos(event.Inputs),
+		ParamsOrdered:   orderParams(params, event.Inputs),
+		Topics:          topicsToHex(vLog.Topics),
+		Data:            hexutil.Encode(vLog.Data),
+		Anonymous:       true,
+		Confidence:      1 / float64(len(candidates)),
+	}
+}
+
+// matchesAnonymousLog checks whether the given anonymous event could have produced the log entry,
+// by comparing the indexed argument count with the topic count and the expected data length.
+func matchesAnonymousLog(event abi.Event, vLog *types.Log) bool {
+	indexed := 0
+	for _, argument := range event.Inputs {
+		if argument.Indexed {
+			indexed++
+		}
+	}
+
+	if indexed != len(vLog.Topics) {
+		return false
+	}
+
+	size := 0
+	for _, argument := range event.Inputs.NonIndexed() {
+		argSize, static := staticTypeSize(argument.Type)
+		if !static {
+			// dynamic arguments can not be measured upfront, unpacking decides
+			_, err := event.Inputs.NonIndexed().Unpack(vLog.Data)
+			return err == nil
+		}
+		size += argSize
+	}
+
+	return size == len(vLog.Data)
+}
+
+// staticTypeSize returns the encoded size of a static abi type, or false if the type is dynamic.
+func staticTypeSize(t abi.Type) (int, bool) {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy:
+		return 0, false
+	case abi.ArrayTy:
+		size, static := staticTypeSize(*t.Elem)
+		return size * t.Size, static
+	case abi.TupleTy:
+		total := 0
+		for _, elem := range t.TupleElems {
+			size, static := staticTypeSize(*elem)
+			if !static {
+				return 0, false
+			}
+			total += size
+		}
+		return total, true
+	default:
+		return 32, true
+	}
+}
+
+// findMethod looks up a method of the contract ABI by name, signature or hex selector.
+func findMethod(contractAbi abi.ABI, methodSig string) (*abi.Method, error) {
+	if method, ok := contractAbi.Methods[methodSig]; ok {
+		return &method, nil
+	}
+
+	for _, method := range contractAbi.Methods {
+		if method.Sig == methodSig {
+			return &method, nil
+		}
+	}
+
+	if selector := common.FromHex(methodSig); len(selector) == 4 {
+		return contractAbi.MethodById(selector)
+	}
+
+	return nil, fmt.Errorf("method not found in abi: %s", methodSig)
+}
+
+// unpackArguments unpacks the data into a map keyed by argument name. Unnamed arguments, as
+// common for method outputs, are keyed by their position.
+func unpackArguments(arguments abi.Arguments, data []byte) (map[string]interface{}, error) {
+	values, err := arguments.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		name := arguments[i].Name
+		if name == "" {
+			name = fmt.Sprint(i)
+		}
+		result[name] = value
+	}
+
+	return result, nil
+}
+
+// paramInfos returns the ABI metadata of the arguments, named like the keys of unpackArguments.
+func paramInfos(arguments abi.Arguments) []ParamInfo {
+	result := make([]ParamInfo, 0, len(arguments))
+	for i, argument := range arguments {
+		name := argument.Name
+		if name == "" {
+			name = fmt.Sprint(i)
+		}
+
+		result = append(result, ParamInfo{
+			Name:    name,
+			Type:    argument.Type.String(),
+			Indexed: argument.Indexed,
+		})
+	}
+
+	return result
+}
+
+// orderParams lists the params in the order of the ABI arguments.
+func orderParams(params Params, arguments abi.Arguments) []DecodedParam {
+	result := make([]DecodedParam, 0, len(arguments))
+	for _, info := range paramInfos(arguments) {
+		result = append(result, DecodedParam{
+			Name:    info.Name,
+			Type:    info.Type,
+			Value:   params[info.Name],
+			Indexed: info.Indexed,
+		})
+	}
+
+	return result
+}
+
+func topicsToHex(topics []common.Hash) []string {
+	result := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		result = append(result, topic.Hex())
+	}
+
+	return result
+}
+
+// formatParameters will iterate through objects and will parse big.Int to string.
+// it will also parse addresses and return them as checksum addresses.
+// The ABI arguments of the values are used to look up registered formatters.
+func formatParameters(decoded map[string]interface{}, debug *bool, arguments ...abi.Argument) Params {
+	for key, value := range decoded {
+		// Registered formatters take precedence over the default formatting
+		if formatter := lookupFormatter(key, arguments); formatter != nil {
+			decoded[key] = formatter(key, value)
+			continue
+		}
+
+		switch value := value.(type) {
+		// For *big.Int types, parse the value to string
+		case *big.Int:
+			decoded[key] = value.String()
+
+		// For common.Address types, convert to a checksum address
+		case *common.Address:
+			decoded[key] = value.Hex()
+		case common.Address:
+			decoded[key] = value.Hex()
+
+		// For [][]uint8 types, convert to a list of hex strings
+		case [][]uint8:
+			parsed := make([]string, 0, len(value))
+			for _, arr := range value {
+				parsed = append(parsed, "0x"+common.Bytes2Hex(arr))
+			}
+			decoded[key] = parsed
+
+		// For []*big.Int types, convert to a list of strings
+		case []*big.Int:
+			parsed := make([]string, 0, len(value))
+			for _, v := range value {
+				parsed = append(parsed, v.String())
+			}
+			decoded[key] = parsed
+
+		// For []common.Address types, convert to a list of checksum addresses
+		case []common.Address:
+			parsed := make([]string, 0, len(value))
+			for _, address := range value {
+				parsed = append(parsed, address.Hex())
+			}
+			decoded[key] = parsed
+		// For []uint8 types, convert to a hex string
+		case []uint8:
+			decoded[key] = "0x" + common.Bytes2Hex(value)
+		// for strings we check for address and checksum it
+		case string:
+			if value != EtherAddress && common.IsHexAddress(value) {
+				decoded[key] = common.HexToAddress(value).Hex()
+			}
+		// For booleans, and uint8 types, no parsing necessary
+		case bool, uint8:
+		// For [32]uint8 types, convert to a checksum address
+		case [32]uint8:
+			ba := make([]byte, 0, 32)
+			for _, b := range value {
+				ba = append(ba, b)
+			}
+			decoded[key] = common.BytesToHash(ba).Hex()
+
+		// For all other types, log a warning message if debug mode is enabled
+		default:
+			if debug != nil && *debug {
+				log.Fatal(`key:`, key, `value:`, value, `type:`, reflect.TypeOf(value))
+			}
+		}
+
+		// If debug mode is enabled, log the formatted value
+		if debug != nil && *debug {
+			log.Fatal(`formatted value:`, decoded[key])
+		}
+	}
+
+	return decoded
+}
+
+// getBytecode returns the code of the address, fetched with the backend or the global backend if
+// it is nil. It returns nil without a backend.
+func getBytecode(client Backend, address common.Address) *string {
+	if client = retrying(client, nil); client == nil {
+		client = GetBackend()
+	}
+	if client == nil {
+		return nil
+	}
+
+	res := cachedLookup(context.Background(), client, address, LookupBytecode, func() *string {
+		code, ok := cachedCode(address, nil)
+		if !ok {
+			var err error
+			if code, err = client.CodeAt(context.Background(), address, nil); err != nil {
+				log.Fatal("error getting bytecode:", address, err)
+				zeroHex := "0x"
+				return &zeroHex
+			}
+			cacheCode(address, nil, code)
+		}
+
+		// empty code is not cached, as contracts may still be deployed to the address
+		if len(code) == 0 {
+			return nil
+		}
+
+		res := strings.Join([]string{"0x", common.Bytes2Hex(code)}, "")
+		return &res
+	})
+
+	if res == nil {
+		zeroHex := "0x"
+		return &zeroHex
+	}
+
+	return res
+}
+
+func clientRequired() error {
+	if Ctx.eth == nil && Ctx.connection == nil {
+		return fmt.Errorf("no client connected or connection string attached to decoder.Ctx.eth")
+	}
+
+	if Ctx.eth == nil && Ctx.connection != nil && Ctx.options != nil {
+		if _, err := ConnectWithOptions(*Ctx.connection, *Ctx.options); err != nil {
+			return err
+		}
+	} else if Ctx.eth == nil && Ctx.connection != nil {
+		Connect(*Ctx.connection)
+	}
+
+	return nil
+}
+
+// requireClient returns the backend, or the global backend of Ctx if it is nil, which is connected
+// first if only its connection string is set.
+func requireClient(client Backend) (Backend, error) {
+	if client != nil {
+		return retrying(client, nil), nil
+	}
+
+	if err := clientRequired(); err != nil {
+		return nil, err
+	}
+
+	return GetBackend(), nil
+}
+
+func getSymbol(ctx context.Context, client Backend, contract common.Address) *string {
+	client, err := requireClient(client)
+	if err != nil {
+		return nil
+	}
+
+	return cachedLookup(ctx, client, contract, LookupSymbol, func() *string {
+		return callText(ctx, client, contract, erc20Method("symbol"))
+	})
+}
+
+func getName(ctx context.Context, client Backend, contract common.Address) *string {
+	client, err := requireClient(client)
+	if err != nil {
+		return nil
+	}
+
+	return cachedLookup(ctx, client, contract, LookupName, func() *string {
+		return callText(ctx, client, contract, erc20Method("name"))
+	})
+}
+
+func getDecimals(ctx context.Context, client Backend, contract common.Address) *uint8 {
+	client, err := requireClient(client)
+	if err != nil {
+		return nil
+	}
+
+	return cachedLookup(ctx, client, contract, LookupDecimals, func() *uint8 {
+		values, _, err := callMethod(ctx, client, contract, erc20Method("decimals"))
+		if err != nil {
+			return nil
+		}
+
+		result, _ := values[0].(uint8)
+		return &result
+	})
+}
+
+func getERC20Balance(ctx context.Context, client Backend, address common.Address, contractAddress common.Address) (uint64, error) {
+	client, err := requireClient(client)
+	if err != nil {
+		return 0, err
+	}
+
+	values, _, err := callMethod(ctx, client, contractAddress, erc20Method("balanceOf"), address)
+	if err != nil {
+		return 0, err
+	}
+
+	balance, _ := values[0].(*big.Int)
+	if balance == nil {
+		return 0, nil
+	}
+
+	return balance.Uint64(), nil
+}
+
+func queryTokenInfo(ctx context.Context, client Backend, address common.Address, bytecodes ...string) ITknInfo {
+	var code *string
+	if len(bytecodes) > 0 {
+		var byteSlice []string
+		for _, bc := range bytecodes {
+			byteSlice = append(byteSlice, strings.TrimPrefix(bc, "0x"))
+		}
+
+		joined := "0x" + strings.TrimPrefix(strings.Join(byteSlice, ""), "0x")
+		code = &joined
+
+	} else {
+		code = getBytecode(client, address)
+	}
+
+	symbol := getSymbol(ctx, client, address)
+	name := getName(ctx, client, address)
+	decimals := getDecimals(ctx, client, address)
+
+	result := ITknInfo{
+		Address: address,
+		Meta:    "{}",
+	}
+
+	// contracts without the optional ERC20 metadata methods keep their zero values
+	if code != nil {
+		report := AnalyzeBytecode(*code)
+		result.IsERC20 = report.Supports("ERC20")
+		result.IsERC721 = report.Supports("ERC721")
+		result.IsERC1155 = report.Supports("ERC1155")
+	}
+	if name != nil {
+		result.Name = *name
+	}
+	if symbol != nil {
+		result.Symbol = *symbol
+	}
+	if decimals != nil {
+		result.Decimals = *decimals
+	}
+
+	return result
+}
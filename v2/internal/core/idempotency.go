@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// LogIdempotencyKey returns the deterministic deduplication key of a log, formatted as
+// chainId:blockHash:txHash:logIndex. The block hash keeps logs of reorged blocks apart. A nil
+// chain id is formatted as 0.
+func LogIdempotencyKey(chainId *big.Int, blockHash string, txHash string, logIndex uint) string {
+	if chainId == nil {
+		chainId = big.NewInt(0)
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%d", chainId, strings.ToLower(blockHash), strings.ToLower(txHash), logIndex)
+}
+
+// MethodIdempotencyKey returns the deterministic deduplication key of a decoded transaction,
+// its lowercase hash.
+func MethodIdempotencyKey(txHash string) string {
+	return strings.ToLower(txHash)
+}
+
+// keyLog sets the idempotency key of the log, using the chain id of the store or decoder that
+// decoded it.
+func keyLog(chainId *big.Int, decoded *DecodedLog) {
+	if decoded != nil {
+		decoded.IdempotencyKey = LogIdempotencyKey(chainId, decoded.BlockHash, decoded.TransactionHash, decoded.LogIndex)
+	}
+}
+
+// keyChainID returns the chain id of the idempotency keys of the store: the chain of a partition,
+// else the chain reported by the client of the store, else the one of the global client.
+func (store *Storage) keyChainID() *big.Int {
+	if store.chainId != 0 {
+		return new(big.Int).SetUint64(store.chainId)
+	}
+
+	if store.client != nil {
+		return store.clientChainId
+	}
+
+	return Ctx.chainId
+}
+
+// keyChainID returns the chain id of the idempotency keys of the decoder: the chain reported by
+// the client of the decoder, else the one of the global client.
+func (decoder *AbiDecoder) keyChainID() *big.Int {
+	if decoder.client != nil {
+		return decoder.clientChainId
+	}
+
+	return Ctx.chainId
+}
+
+// reportedChainID returns the chain id the backend reports, nil if it does not report one.
+func reportedChainID(backend Backend) *big.Int {
+	if backend == nil {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	chainId, err := backendChainID(ctx, backend)
+	if err != nil {
+		return nil
+	}
+
+	return chainId
+}
+
+// keyMethod sets the idempotency key of the method, if it was decoded from a transaction.
+func keyMethod(decoded *DecodedMethod) {
+	if decoded != nil && decoded.TransactionHash != "" {
+		decoded.IdempotencyKey = MethodIdempotencyKey(decoded.TransactionHash)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoded := decoder.DecodeLog(&types.Log{
+		Address:   common.HexToAddress(target_erc20),
+		Topics:    []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+		Data:      common.LeftPadBytes([]byte{1}, 32),
+		BlockHash: common.HexToHash("0xAB"),
+		TxHash:    common.HexToHash("0xCD"),
+		Index:     3,
+	})
+
+	expected := LogIdempotencyKey(Ctx.chainId, common.HexToHash("0xab").Hex(), common.HexToHash("0xcd").Hex(), 3)
+	if decoded == nil || decoded.IdempotencyKey != expected || !strings.HasSuffix(expected, "00cd:3") {
+		t.Fatalf("invalid idempotency key: %v, expected %v", decoded, expected)
+	}
+
+	if key := MethodIdempotencyKey("0xABCD"); key != "0xabcd" {
+		t.Fatalf("invalid method key: %v", key)
+	}
+}
+
+func TestIdempotencyKeyChain(t *testing.T) {
+	vLog := &types.Log{
+		Address:   common.HexToAddress(target_erc20),
+		Topics:    []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+		Data:      common.LeftPadBytes([]byte{1}, 32),
+		BlockHash: common.HexToHash("0xab"),
+		TxHash:    common.HexToHash("0xcd"),
+	}
+
+	// partitions key logs with their chain
+	store := &Storage{}
+	partition := store.ForChain(10)
+	partition.ParseAndAddABIs(abi_erc20)
+	if decoded := partition.DecodeLog(vLog); decoded == nil || !strings.HasPrefix(decoded.IdempotencyKey, "10:") {
+		t.Fatalf("invalid key of the partition: %v", decoded)
+	}
+
+	// stores and decoders with a client of their own with the chain of the client
+	server := mockrpc.NewServer()
+	defer server.Close()
+	server.Respond("eth_chainId", "0x89")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+	if decoded := store.DecodeLog(vLog); decoded == nil || !strings.HasPrefix(decoded.IdempotencyKey, "137:") {
+		t.Fatalf("invalid key of the store: %v", decoded)
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoder.SetClient(client)
+	if decoded := decoder.ClassifyLog(vLog); decoded == nil || !strings.HasPrefix(decoded.IdempotencyKey, "137:") {
+		t.Fatalf("invalid key of the decoder: %v", decoded)
+	}
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// IndexedABI is a struct for holding Ethereum ABIs.
+type IndexedABI struct {
+	Address  common.Address    `json:"address"`            // Address of the contract the ABI belongs to.
+	Abi      abi.ABI           `json:"abi"`                // The ABI of the contract.
+	Bytecode *string           `json:"bytecode,omitempty"` // Bytecode of the contract the ABI belongs to.
+	IsToken  bool              `json:"isToken"`            // Current ABI is a Token
+	Verified bool              `json:"verified"`           // Whether the ABI has been verified.
+	IsERC721 *bool             `json:"isERC721,omitempty"` // contract is NFT Token
+	Name     *string           `json:"name,omitempty"`     // Name of the contract
+	Pragma   *string           `json:"pragma,omitempty"`   // Pragma Solidity Version of contract
+	Source   *string           `json:"source,omitempty"`   // Solidity source code of contract
+	Guessed  bool              `json:"guessed,omitempty"`  // ABI recovered from the bytecode, see RecoverABI.
+	Metadata *ContractMetadata `json:"metadata,omitempty"` // CBOR metadata of the bytecode, see ExtractMetadata.
+
+	Versions []AbiVersion `json:"versions,omitempty"` // ABIs valid in block ranges, for upgradeable contracts.
+
+	client Backend // see SetClient, nil for the global backend
+}
+
+// BlockRange is an inclusive range of blocks. A To of 0 leaves the range open ended.
+type BlockRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to,omitempty"`
+}
+
+// Contains returns true if the block is within the range.
+func (r BlockRange) Contains(block uint64) bool {
+	return block >= r.From && (r.To == 0 || block <= r.To)
+}
+
+// AbiVersion is a struct for holding the ABI of a contract valid in a block range.
+type AbiVersion struct {
+	Range BlockRange `json:"range"`
+	Abi   abi.ABI    `json:"abi"`
+}
+
+// AbiAt returns the ABI version valid at the block. Without a matching version, Abi is returned.
+func (data *IndexedABI) AbiAt(block uint64) abi.ABI {
+	for _, version := range data.Versions {
+		if version.Range.Contains(block) {
+			return version.Abi
+		}
+	}
+
+	return data.Abi
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the IndexedABI object.
+func (data *IndexedABI) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the IndexedABI object.
+func (data *IndexedABI) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ToJSON returns the JSON-encoded string of the IndexedABI object.
+func (data *IndexedABI) GetBytecode() *string {
+	if data.Bytecode == nil && data.GetBackend() != nil {
+		data.Bytecode = getBytecode(data.GetBackend(), data.Address)
+	}
+
+	return data.Bytecode
+}
+
+// returns a single decoder instance of given IndexedABI object
+func (data *IndexedABI) GetDecoder() AbiDecoder {
+	contractAddress := data.Address.Hex()
+
+	if data.Bytecode == nil {
+		data.Bytecode = data.GetBytecode()
+		fmt.Println("bytecode loaded")
+	}
+
+	return AbiDecoder{
+		ContractAddress: &contractAddress,
+		Abi:             &data.Abi,
+		IsVerified:      data.Verified,
+		client:          data.client,
+	}
+}
+
+// gets all signature hashes of given IndexedABI
+func (data *IndexedABI) GetSigHashes() []string {
+	result := make([]string, 0)
+
+	for _, method := range data.Abi.Methods {
+		sigHash := ToSHA3(method.Sig)
+		result = append(result, sigHash[:10])
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i]) < len(result[j])
+	})
+
+	return result
+}
+
+// gets all signature hashes of given IndexedABI
+func (data *IndexedABI) GetTopics() []string {
+	result := make([]string, 0)
+
+	for _, event := range data.Abi.Events {
+		topic := ToSHA3(event.Sig)
+		result = append(result, topic)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i]) < len(result[j])
+	})
+
+	return result
+}
+
+// gets all signatures of given IndexedABI
+func (data *IndexedABI) GetSignatures() []string {
+	result := make([]string, 0)
+
+	for _, event := range data.Abi.Events {
+		result = append(result, event.Sig)
+	}
+
+	for _, method := range data.Abi.Methods {
+		result = append(result, method.Sig)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i]) < len(result[j])
+	})
+
+	return result
+}
+
+// ValidateBytecodes checks whether all selectors and topics of the ABI are pushed by the bytecode,
+// see ExtractSelectors. It returns nil without bytecode.
+func (data *IndexedABI) ValidateBytecodes() *bool {
+	if data.Bytecode == nil {
+		return nil
+	}
+	sigs := make([]string, 0)
+	sigs = append(sigs, data.GetSigHashes()...)
+	sigs = append(sigs, data.GetTopics()...)
+	valid := DetectBytecodes(*data.Bytecode, sigs)
+	return &valid
+}
+
+// SetClient sets the client of the contract, used for its bytecode and decoders instead of the
+// global client.
+func (indexed *IndexedABI) SetClient(client *ethclient.Client) {
+	indexed.client = asBackend(client)
+}
+
+// SetBackend sets the backend of the contract, like SetClient for backends other than
+// *ethclient.Client.
+func (indexed *IndexedABI) SetBackend(backend Backend) {
+	indexed.client = backend
+}
+
+// GetClient returns the client of the contract, the global client if none is set. It is nil if
+// the backend is no *ethclient.Client, see GetBackend.
+func (indexed *IndexedABI) GetClient() *ethclient.Client {
+	return asClient(indexed.GetBackend())
+}
+
+// GetBackend returns the backend of the contract, the global backend if none is set. It is wrapped
+// with the global retry policy.
+func (indexed *IndexedABI) GetBackend() Backend {
+	if indexed.client != nil {
+		return retrying(indexed.client, nil)
+	}
+
+	return GetBackend()
+}
+
+// RemoveClient removes the client of the contract, it falls back to the global client again.
+func (indexed *IndexedABI) RemoveClient() {
+	indexed.client = nil
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RPCCall describes a single outbound JSON-RPC call seen by an RPCInterceptor. Duration and Err
+// are only set when it is passed to After.
+type RPCCall struct {
+	Method    string          // JSON-RPC method, e.g. eth_getLogs.
+	RequestID string          // Request id of the call context, see WithRequestID.
+	Params    json.RawMessage // Raw JSON params of the call.
+	Duration  time.Duration   // Round trip time of the request carrying the call.
+	Err       error           // Transport error, HTTP error or JSON-RPC error of the call.
+}
+
+// RPCInterceptor hooks into every outbound RPC call of connections created with ConnectOptions,
+// e.g. for logging, quota accounting or chaos testing. Returning an error from Before aborts the
+// request with that error. Batch requests invoke the hooks once per call. Interceptors apply to
+// HTTP connections only.
+type RPCInterceptor interface {
+	Before(ctx context.Context, call *RPCCall) error
+	After(ctx context.Context, call *RPCCall)
+}
+
+// interceptTransport is a http.RoundTripper invoking the interceptors around every request.
+// The request id of the context is forwarded in the header, if set.
+type interceptTransport struct {
+	base         http.RoundTripper
+	interceptors []RPCInterceptor
+	header       string
+}
+
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *interceptTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		if body, err = io.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	ctx := request.Context()
+	id := RequestIDFromContext(ctx)
+	if t.header != "" && id != "" {
+		request = request.Clone(ctx)
+		request.Header.Set(t.header, id)
+	}
+
+	messages := parseRPCMessages(body)
+	calls := make([]*RPCCall, len(messages))
+
+	for i, message := range messages {
+		calls[i] = &RPCCall{Method: message.Method, RequestID: id, Params: message.Params}
+		for _, interceptor := range t.interceptors {
+			if err := interceptor.Before(ctx, calls[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	start := time.Now()
+	response, err := t.base.RoundTrip(request)
+	duration := time.Since(start)
+
+	callErr := err
+	errs := make(map[string]error)
+	if err == nil {
+		errs, callErr = readRPCErrors(response)
+	}
+
+	for i, call := range calls {
+		call.Duration = duration
+		call.Err = callErr
+		if call.Err == nil {
+			call.Err = errs[string(messages[i].ID)]
+		}
+
+		for _, interceptor := range t.interceptors {
+			interceptor.After(ctx, call)
+		}
+	}
+
+	return response, err
+}
+
+// parseRPCMessages parses a single or a batch JSON-RPC request or response body.
+func parseRPCMessages(body []byte) []rpcMessage {
+	body = bytes.TrimSpace(body)
+
+	var messages []rpcMessage
+	if len(body) > 0 && body[0] == '[' {
+		json.Unmarshal(body, &messages)
+		return messages
+	}
+
+	var message rpcMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil
+	}
+
+	return append(messages, message)
+}
+
+// readRPCErrors reads the response body, restores it for the rpc client and returns the JSON-RPC
+// errors keyed by the raw message id. Non-2xx responses are returned as error.
+func readRPCErrors(response *http.Response) (map[string]error, error) {
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("rpc http error: %s", response.Status)
+	}
+
+	result := make(map[string]error)
+	for _, message := range parseRPCMessages(body) {
+		if message.Error != nil {
+			result[string(message.ID)] = fmt.Errorf("rpc error %d: %s", message.Error.Code, message.Error.Message)
+		}
+	}
+
+	return result, nil
+}
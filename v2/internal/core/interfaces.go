@@ -0,0 +1,215 @@
+package core
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// InterfaceThreshold is the share of selectors and topics of an interface that has to be found in
+// the bytecode for the interface to be reported as supported.
+var InterfaceThreshold = 0.9
+
+// InterfaceMatch is a struct for holding the score of an interface in a bytecode.
+type InterfaceMatch struct {
+	Name      string   `json:"name"`
+	Score     float64  `json:"score"`     // Share of the selectors and topics found, between 0 and 1.
+	Supported bool     `json:"supported"` // Whether Score reaches InterfaceThreshold.
+	Missing   []string `json:"missing,omitempty"`
+}
+
+// InterfaceReport is a struct for holding the result of a bytecode analysis.
+type InterfaceReport struct {
+	Selectors  []string         `json:"selectors"`  // PUSH4 operands, the selectors of the dispatcher.
+	Topics     []string         `json:"topics"`     // PUSH32 operands, the event topics emitted.
+	Interfaces []InterfaceMatch `json:"interfaces"` // Scores of the known interfaces, best first.
+	Standard   string           `json:"standard"`   // Most specific token standard supported, or UNKNOWN.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the InterfaceReport object.
+func (data *InterfaceReport) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the InterfaceReport object.
+func (data *InterfaceReport) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// Supports returns whether the named interface is supported.
+func (data *InterfaceReport) Supports(name string) bool {
+	for _, match := range data.Interfaces {
+		if match.Name == name {
+			return match.Supported
+		}
+	}
+
+	return false
+}
+
+// knownInterface lists the functions and events of an interface, in the order of specificity
+// used to pick the token standard of a report.
+type knownInterface struct {
+	name      string
+	functions []string
+	events    []string
+}
+
+var knownInterfaces = []knownInterface{
+	{
+		name: "ERC1155",
+		functions: []string{
+			"balanceOf(address,uint256)",
+			"balanceOfBatch(address[],uint256[])",
+			"setApprovalForAll(address,bool)",
+			"isApprovedForAll(address,address)",
+			"safeTransferFrom(address,address,uint256,uint256,bytes)",
+			"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+		},
+		events: []string{
+			"TransferSingle(address,address,address,uint256,uint256)",
+			"TransferBatch(address,address,address,uint256[],uint256[])",
+			"ApprovalForAll(address,address,bool)",
+		},
+	},
+	{
+		name: "ERC721",
+		functions: []string{
+			"balanceOf(address)",
+			"ownerOf(uint256)",
+			"safeTransferFrom(address,address,uint256)",
+			"safeTransferFrom(address,address,uint256,bytes)",
+			"transferFrom(address,address,uint256)",
+			"approve(address,uint256)",
+			"setApprovalForAll(address,bool)",
+			"getApproved(uint256)",
+			"isApprovedForAll(address,address)",
+		},
+		events: []string{
+			"Transfer(address,address,uint256)",
+			"Approval(address,address,uint256)",
+			"ApprovalForAll(address,address,bool)",
+		},
+	},
+	{
+		name: "ERC4626",
+		functions: []string{
+			"asset()",
+			"totalAssets()",
+			"convertToShares(uint256)",
+			"convertToAssets(uint256)",
+			"maxDeposit(address)",
+			"previewDeposit(uint256)",
+			"deposit(uint256,address)",
+			"maxMint(address)",
+			"previewMint(uint256)",
+			"mint(uint256,address)",
+			"maxWithdraw(address)",
+			"previewWithdraw(uint256)",
+			"withdraw(uint256,address,address)",
+			"maxRedeem(address)",
+			"previewRedeem(uint256)",
+			"redeem(uint256,address,address)",
+		},
+		events: []string{
+			"Deposit(address,address,uint256,uint256)",
+			"Withdraw(address,address,address,uint256,uint256)",
+		},
+	},
+	{
+		name: "ERC777",
+		functions: []string{
+			"granularity()",
+			"defaultOperators()",
+			"send(address,uint256,bytes)",
+			"burn(uint256,bytes)",
+			"isOperatorFor(address,address)",
+			"authorizeOperator(address)",
+			"revokeOperator(address)",
+			"operatorSend(address,address,uint256,bytes,bytes)",
+			"operatorBurn(address,uint256,bytes,bytes)",
+		},
+		events: []string{
+			"Sent(address,address,address,uint256,bytes,bytes)",
+			"Minted(address,address,uint256,bytes,bytes)",
+			"Burned(address,address,uint256,bytes,bytes)",
+			"AuthorizedOperator(address,address)",
+			"RevokedOperator(address,address)",
+		},
+	},
+	{
+		name: "ERC20",
+		functions: []string{
+			"totalSupply()",
+			"balanceOf(address)",
+			"transfer(address,uint256)",
+			"transferFrom(address,address,uint256)",
+			"approve(address,uint256)",
+			"allowance(address,address)",
+		},
+		events: []string{
+			"Transfer(address,address,uint256)",
+			"Approval(address,address,uint256)",
+		},
+	},
+}
+
+// AnalyzeBytecode extracts the PUSH4 and PUSH32 operands of the runtime bytecode and scores the
+// known token interfaces against them. Walking the opcodes skips the push data, so selectors are
+// only found where the dispatcher pushes them and not inside unrelated constants. Constructors
+// and metadata are not stripped, their bytes may add spurious operands but never hide real ones.
+// Selectors starting with zero bytes, which solc pushes with PUSH1 to PUSH3, are matched too and
+// reported in Selectors if they belong to a known interface.
+func AnalyzeBytecode(code string) InterfaceReport {
+	selectors, topics := pushOperands(code)
+	short := shortSelectors(code)
+	for _, known := range knownInterfaces {
+		for _, function := range known.functions {
+			if selector := ToSHA3(function)[2:10]; short[selector] {
+				selectors[selector] = true
+			}
+		}
+	}
+
+	report := InterfaceReport{
+		Selectors: sortedKeys(selectors),
+		Topics:    sortedKeys(topics),
+		Standard:  "UNKNOWN",
+	}
+
+	for _, known := range knownInterfaces {
+		match := InterfaceMatch{Name: known.name}
+		total := len(known.functions) + len(known.events)
+		found := 0
+
+		for _, function := range known.functions {
+			if selectors[ToSHA3(function)[2:10]] {
+				found++
+			} else {
+				match.Missing = append(match.Missing, function)
+			}
+		}
+
+		for _, event := range known.events {
+			if topics[ToSHA3(event)[2:]] {
+				found++
+			} else {
+				match.Missing = append(match.Missing, event)
+			}
+		}
+
+		match.Score = float64(found) / float64(total)
+		match.Supported = match.Score >= InterfaceThreshold
+		if match.Supported && report.Standard == "UNKNOWN" {
+			report.Standard = known.name
+		}
+
+		report.Interfaces = append(report.Interfaces, match)
+	}
+
+	sort.SliceStable(report.Interfaces, func(i, j int) bool {
+		return report.Interfaces[i].Score > report.Interfaces[j].Score
+	})
+
+	return report
+}
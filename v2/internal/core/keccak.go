@@ -0,0 +1,73 @@
+package core
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeccakFunc computes the keccak256 hash of the data.
+type KeccakFunc func(data []byte) []byte
+
+// keccak holds the keccak implementation used for selectors and topics, and the memoized hashes
+// of ToSHA3 keyed by signature.
+var keccak = struct {
+	sync.RWMutex
+	fn     KeccakFunc
+	hashes map[string]string
+}{fn: defaultKeccak, hashes: make(map[string]string)}
+
+func defaultKeccak(data []byte) []byte {
+	return crypto.Keccak256(data)
+}
+
+// SetKeccak replaces the keccak256 implementation used to compute selectors and topics, e.g. with
+// an assembly optimized one. A nil function restores the go-ethereum implementation. The memoized
+// hashes are dropped. Attestations always use the go-ethereum implementation.
+func SetKeccak(fn KeccakFunc) {
+	if fn == nil {
+		fn = defaultKeccak
+	}
+
+	keccak.Lock()
+	defer keccak.Unlock()
+	keccak.fn = fn
+	keccak.hashes = make(map[string]string)
+}
+
+// ResetSHA3Cache drops the memoized hashes of ToSHA3.
+func ResetSHA3Cache() {
+	keccak.Lock()
+	defer keccak.Unlock()
+	keccak.hashes = make(map[string]string)
+}
+
+// keccak256 hashes the data with the configured implementation.
+func keccak256(data []byte) []byte {
+	keccak.RLock()
+	fn := keccak.fn
+	keccak.RUnlock()
+
+	return fn(data)
+}
+
+// sha3Hex returns the memoized 0x prefixed hex hash of the signature.
+func sha3Hex(signature string) string {
+	keccak.RLock()
+	hash, ok := keccak.hashes[signature]
+	fn := keccak.fn
+	keccak.RUnlock()
+
+	if ok {
+		return hash
+	}
+
+	hash = "0x" + hex.EncodeToString(fn([]byte(signature)))
+
+	keccak.Lock()
+	keccak.hashes[signature] = hash
+	keccak.Unlock()
+
+	return hash
+}
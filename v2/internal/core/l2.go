@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Transaction types of L2 chains. They have no signature, the sender is reported by the node, and
+// go-ethereum can not represent them as types.Transaction.
+const (
+	DepositTxType                 = 0x7e // OP-stack deposit, submitted on L1 or inserted by the protocol.
+	ArbitrumDepositTxType         = 0x64 // ETH deposited from L1.
+	ArbitrumUnsignedTxType        = 0x65 // Call of an L1 account without signature.
+	ArbitrumContractTxType        = 0x66 // Call of an L1 contract.
+	ArbitrumRetryTxType           = 0x68 // Redeem attempt of a retryable ticket.
+	ArbitrumSubmitRetryableTxType = 0x69 // Retryable ticket submitted on L1.
+	ArbitrumInternalTxType        = 0x6a // Internal transaction of ArbOS, e.g. the L1 block info.
+	ArbitrumLegacyTxType          = 0x78 // Transaction of the classic, pre-Nitro chain.
+)
+
+var (
+	// L1AttributesDepositor sends the L1 attributes deposit starting every OP-stack block.
+	L1AttributesDepositor = common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001")
+
+	// ArbOSAddress sends the internal transactions of Arbitrum.
+	ArbOSAddress = common.HexToAddress("0x00000000000000000000000000000000000A4B05")
+)
+
+// IsL2TxType reports whether the transaction type is one of the L2 types of OP-stack chains or
+// Arbitrum.
+func IsL2TxType(txType uint8) bool {
+	switch txType {
+	case DepositTxType, ArbitrumDepositTxType, ArbitrumUnsignedTxType, ArbitrumContractTxType,
+		ArbitrumRetryTxType, ArbitrumSubmitRetryableTxType, ArbitrumInternalTxType, ArbitrumLegacyTxType:
+		return true
+	}
+
+	return false
+}
+
+// l2Transaction is an L2 transaction of an RPC response, with the fields its types share.
+type l2Transaction struct {
+	Type       hexutil.Uint64  `json:"type"`
+	Hash       common.Hash     `json:"hash"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"`
+	Value      *hexutil.Big    `json:"value"`
+	Gas        hexutil.Uint64  `json:"gas"`
+	Nonce      hexutil.Uint64  `json:"nonce"`
+	Input      hexutil.Bytes   `json:"input"`
+	IsSystemTx bool            `json:"isSystemTx"` // set on OP-stack system deposits before Regolith
+}
+
+// unmarshalL2Transaction decodes the transaction of an RPC response if it has an L2 type.
+func unmarshalL2Transaction(raw json.RawMessage) (*l2Transaction, bool) {
+	var tx l2Transaction
+	if err := json.Unmarshal(raw, &tx); err != nil || tx.Type > 0xff || !IsL2TxType(uint8(tx.Type)) {
+		return nil, false
+	}
+
+	return &tx, true
+}
+
+func (tx *l2Transaction) value() *big.Int {
+	if tx.Value == nil {
+		return new(big.Int)
+	}
+
+	return tx.Value.ToInt()
+}
+
+// system reports whether the L2 protocol inserted the transaction: the L1 attributes deposits of
+// OP-stack chains and the internal transactions of Arbitrum.
+func (tx *l2Transaction) system() bool {
+	switch uint8(tx.Type) {
+	case DepositTxType:
+		return tx.IsSystemTx || tx.From == L1AttributesDepositor
+	case ArbitrumInternalTxType:
+		return true
+	}
+
+	return false
+}
+
+// deposit reports whether the transaction was submitted on L1.
+func (tx *l2Transaction) deposit() bool {
+	switch uint8(tx.Type) {
+	case DepositTxType, ArbitrumDepositTxType, ArbitrumUnsignedTxType, ArbitrumContractTxType, ArbitrumSubmitRetryableTxType:
+		return true
+	}
+
+	return false
+}
+
+// decodeL2Transaction decodes the method and the receipt logs of an L2 transaction.
+func (store *Storage) decodeL2Transaction(ctx context.Context, tx *l2Transaction, index uint, receipt *types.Receipt) DecodedBlockTransaction {
+	result := DecodedBlockTransaction{
+		Hash:    tx.Hash.Hex(),
+		Index:   index,
+		Type:    uint8(tx.Type),
+		From:    tx.From.Hex(),
+		Value:   tx.value().String(),
+		System:  tx.system(),
+		Deposit: tx.deposit(),
+		Logs:    make(ScannedLogs, 0),
+	}
+
+	if tx.To != nil {
+		result.To = tx.To.Hex()
+		if len(tx.Input) >= 4 {
+			result.Method = store.decodeL2Method(ctx, tx)
+		}
+	}
+
+	store.decodeReceiptLogs(ctx, &result, receipt)
+	return result
+}
+
+// decodeL2Method decodes the calldata of an L2 transaction like DecodeMethodContext. The methods
+// are decoded with an unsigned stand-in transaction of the same recipient, value and calldata,
+// screened with the sender reported by the node, then tagged with the hash of the L2 transaction.
+func (store *Storage) decodeL2Method(ctx context.Context, tx *l2Transaction) *DecodedMethod {
+	standIn := types.NewTx(&types.LegacyTx{
+		Nonce:    uint64(tx.Nonce),
+		To:       tx.To,
+		Gas:      uint64(tx.Gas),
+		GasPrice: new(big.Int),
+		Value:    tx.value(),
+		Data:     tx.Input,
+	})
+
+	decoded := store.decodeMethod(standIn, &tx.From)
+	if decoded == nil {
+		return nil
+	}
+
+	setMethodHash(decoded, tx.Hash.Hex())
+	if store.IncludeTx {
+		decoded.Tx = &TxInfo{
+			Type:     uint8(tx.Type),
+			TypeName: TxTypeName(uint8(tx.Type)),
+			From:     tx.From.Hex(),
+			Value:    tx.value().String(),
+			Nonce:    uint64(tx.Nonce),
+			Gas:      uint64(tx.Gas),
+		}
+	}
+
+	nameMethod(store.ResolveNames, store.GetBackend(), decoded)
+	labelMethod(store.Labels, decoded)
+	keyMethod(decoded)
+	attestMethod(store.Attestor, decoded)
+	tagMethod(decoded, RequestIDFromContext(ctx))
+	return decoded
+}
+
+// setMethodHash sets the transaction hash of the decoded method and its nested calls.
+func setMethodHash(decoded *DecodedMethod, hash string) {
+	decoded.TransactionHash = hash
+	for _, call := range decoded.Calls {
+		setMethodHash(call, hash)
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestL2Transactions(t *testing.T) {
+	chain := newTestChain(t, 1)
+
+	token := common.HexToAddress(target_erc20)
+	l1Block := common.HexToAddress("0x4200000000000000000000000000000000000015")
+	depositor := common.HexToAddress("0x00000000000000000000000000000000000d0d0")
+	data, _ := ParseABI(abi_erc20).Pack("transfer", common.HexToAddress(target_contract), big.NewInt(5))
+
+	deposit := func(hash common.Hash, from, to common.Address, input []byte) map[string]interface{} {
+		return map[string]interface{}{
+			"type":       "0x7e",
+			"hash":       hash,
+			"sourceHash": common.HexToHash("0x5c"),
+			"from":       from,
+			"to":         to,
+			"mint":       "0x0",
+			"value":      "0x0",
+			"gas":        "0xf4240",
+			"isSystemTx": false,
+			"input":      hexutil.Encode(input),
+			"nonce":      "0x9",
+		}
+	}
+
+	// OP-stack blocks start with the L1 attributes deposit
+	attributesHash, userHash := common.HexToHash("0xa1"), common.HexToHash("0xa2")
+	legacyTx := chain.blocks[1]["transactions"].([]*types.Transaction)[0]
+
+	block := make(map[string]interface{})
+	for key, value := range chain.blocks[1] {
+		block[key] = value
+	}
+	block["transactions"] = []interface{}{
+		deposit(attributesHash, L1AttributesDepositor, l1Block, hexutil.MustDecode("0x440a5e20")),
+		deposit(userHash, depositor, token, data),
+		legacyTx,
+	}
+
+	for _, hash := range []common.Hash{attributesHash, userHash} {
+		chain.receipts[hash] = &types.Receipt{
+			Type:        DepositTxType,
+			Status:      types.ReceiptStatusSuccessful,
+			TxHash:      hash,
+			BlockHash:   chain.headers[1].Hash(),
+			BlockNumber: big.NewInt(1),
+			Logs:        []*types.Log{},
+		}
+	}
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.Respond("eth_getBlockByNumber", block)
+	server.Handle("eth_getTransactionReceipt", func(params json.RawMessage) (interface{}, error) {
+		var args []common.Hash
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		return chain.receipts[args[0]], nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &Storage{IncludeTx: true}
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+
+	decoded, err := store.DecodeBlock(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Transactions) != 3 || len(decoded.Unsupported) != 0 {
+		t.Fatalf("unexpected transactions: %s", decoded.ToJSON())
+	}
+
+	attributes, user, legacy := decoded.Transactions[0], decoded.Transactions[1], decoded.Transactions[2]
+	if !attributes.System || !attributes.Deposit || attributes.From != L1AttributesDepositor.Hex() || attributes.Method != nil {
+		t.Fatalf("unexpected L1 attributes deposit: %s", decoded.ToJSON())
+	}
+	if user.System || !user.Deposit || user.From != depositor.Hex() || user.Index != 1 || user.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("unexpected user deposit: %s", decoded.ToJSON())
+	}
+	if user.Method == nil || user.Method.TransactionHash != userHash.Hex() || user.Method.Params["value"] != "5" || user.Method.Tx.TypeName != "deposit" {
+		t.Fatalf("unexpected method of the user deposit: %s", decoded.ToJSON())
+	}
+	if legacy.Deposit || legacy.Index != 2 || legacy.From != crypto.PubkeyToAddress(chain.key.PublicKey).Hex() {
+		t.Fatalf("unexpected legacy transaction: %s", decoded.ToJSON())
+	}
+
+	// deposits are screened with the sender reported by the node
+	store.Compliance = &ListScreener{list: map[common.Address]bool{depositor: true}}
+	if decoded, err = store.DecodeBlock(context.Background(), big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if method := decoded.Transactions[1].Method; method == nil || len(method.Flagged) != 1 || method.Flagged[0] != depositor.Hex() {
+		t.Fatalf("sender of the deposit not screened: %s", decoded.ToJSON())
+	}
+
+	// the sender of L2 transactions is the one reported by the node
+	raw, _ := json.Marshal(deposit(userHash, depositor, token, data))
+	if from := Ctx.GetTxFromJSON(raw); from == nil || *from != depositor.Hex() {
+		t.Fatalf("unexpected sender of the deposit: %v", from)
+	}
+
+	raw, _ = json.Marshal(legacyTx)
+	if from := Ctx.GetTxFromJSON(raw); from == nil || *from != legacy.From {
+		t.Fatalf("unexpected sender of the legacy transaction: %v", from)
+	}
+}
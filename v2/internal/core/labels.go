@@ -0,0 +1,188 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Labels is the global label store. Set it as Labels of a Storage or AbiDecoder to label their
+// decoded results.
+var Labels = NewLabelStore()
+
+// LabelStore holds human-readable labels of addresses, e.g. "Binance 14". It is safe for
+// concurrent use.
+type LabelStore struct {
+	mu     sync.RWMutex
+	labels map[common.Address]string
+}
+
+// NewLabelStore returns an empty LabelStore.
+func NewLabelStore() *LabelStore {
+	return &LabelStore{labels: make(map[common.Address]string)}
+}
+
+// Set labels the address, replacing its previous label. An empty label removes it.
+func (s *LabelStore) Set(address common.Address, label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if label == "" {
+		delete(s.labels, address)
+		return
+	}
+
+	s.labels[address] = label
+}
+
+// Get returns the label of the address and whether it has one.
+func (s *LabelStore) Get(address common.Address) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	label, ok := s.labels[address]
+	return label, ok
+}
+
+// Len returns the number of labeled addresses.
+func (s *LabelStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.labels)
+}
+
+// Import adds the labels of the content, which is either a JSON object of labels by address, a
+// JSON array of objects with address and label, or CSV with address and label columns. CSV rows
+// not starting with an address, like headers and lines starting with #, are ignored. It returns
+// the number of labels imported, no label is imported on errors.
+func (s *LabelStore) Import(content []byte) (int, error) {
+	labels, err := parseLabels(content)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for address, label := range labels {
+		s.labels[address] = label
+	}
+
+	return len(labels), nil
+}
+
+// ImportFile imports the labels of the file at path, see Import.
+func (s *LabelStore) ImportFile(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.Import(content)
+}
+
+// lookup returns the labels of the labeled addresses, nil if none is labeled.
+func (s *LabelStore) lookup(addresses []common.Address) AddressNames {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result AddressNames
+	for _, address := range addresses {
+		if label, ok := s.labels[address]; ok {
+			if result == nil {
+				result = make(AddressNames)
+			}
+			result[address.Hex()] = label
+		}
+	}
+
+	return result
+}
+
+func parseLabels(content []byte) (map[common.Address]string, error) {
+	labels := make(map[common.Address]string)
+	add := func(address, label string) error {
+		address, label = strings.TrimSpace(address), strings.TrimSpace(label)
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid address: %s", address)
+		}
+		if label != "" {
+			labels[common.HexToAddress(address)] = label
+		}
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		var entries map[string]string
+		if err := json.Unmarshal([]byte(trimmed), &entries); err != nil {
+			return nil, err
+		}
+		for address, label := range entries {
+			if err := add(address, label); err != nil {
+				return nil, err
+			}
+		}
+
+	case strings.HasPrefix(trimmed, "["):
+		var entries []struct {
+			Address string `json:"address"`
+			Label   string `json:"label"`
+		}
+		if err := json.Unmarshal([]byte(trimmed), &entries); err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if err := add(entry.Address, entry.Label); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		reader := csv.NewReader(strings.NewReader(trimmed))
+		reader.Comment = '#'
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if len(record) < 2 || !common.IsHexAddress(strings.TrimSpace(record[0])) {
+				continue
+			}
+			add(record[0], record[1])
+		}
+	}
+
+	return labels, nil
+}
+
+// labelMethod sets the labels of the contract and address params of the decoded method and its
+// nested calls, if a label store is set.
+func labelMethod(labels *LabelStore, decoded *DecodedMethod) {
+	if labels == nil || decoded == nil {
+		return
+	}
+
+	decoded.Labels = labels.lookup(collectAddresses(decoded.Params, decoded.Contract))
+	for _, call := range decoded.Calls {
+		labelMethod(labels, call)
+	}
+}
+
+// labelLog sets the labels of the contract and address params of the decoded log, if a label
+// store is set.
+func labelLog(labels *LabelStore, decoded *DecodedLog) {
+	if labels == nil || decoded == nil {
+		return
+	}
+
+	decoded.Labels = labels.lookup(collectAddresses(decoded.Params, decoded.Contract))
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestLabelStore(t *testing.T) {
+	binance := common.HexToAddress("0x28C6c06298d514Db089934071355E5743bf21d60")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	trader := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+
+	labels := NewLabelStore()
+	labels.Set(binance, "Binance 14")
+
+	if n, err := labels.Import([]byte(`{"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": "USDC"}`)); err != nil || n != 1 {
+		t.Fatalf("unexpected JSON import: %d %v", n, err)
+	}
+	if n, err := labels.Import([]byte("address,label\n# traders\n0x00000000000000000000000000000000000000ee,\"Trader, Inc.\"\n")); err != nil || n != 1 {
+		t.Fatalf("unexpected CSV import: %d %v", n, err)
+	}
+	if _, err := labels.Import([]byte(`[{"address": "0x01", "label": "invalid"}]`)); err == nil {
+		t.Fatal("expected an error for invalid addresses")
+	}
+
+	if label, ok := labels.Get(trader); !ok || label != "Trader, Inc." || labels.Len() != 3 {
+		t.Fatalf("unexpected labels: %s %d", label, labels.Len())
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), Labels: labels}
+	decoded := decoder.DecodeLog(&types.Log{
+		Address: usdc,
+		Topics:  []common.Hash{transferTopic, binance.Hash(), common.HexToAddress("0x01").Hash()},
+		Data:    math.U256Bytes(big.NewInt(1)),
+	})
+	if decoded == nil {
+		t.Fatal("transfer not decoded")
+	}
+
+	if len(decoded.Labels) != 2 || decoded.Labels[usdc.Hex()] != "USDC" || decoded.Labels[binance.Hex()] != "Binance 14" {
+		t.Fatalf("unexpected labels of the log: %v", decoded.Labels)
+	}
+
+	labels.Set(binance, "")
+	if _, ok := labels.Get(binance); ok {
+		t.Fatal("expected the label to be removed")
+	}
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LazyLog is a handle of a classified log which decodes its params on first use. The handle is
+// safe for concurrent use, the decoded result is memoized.
+type LazyLog struct {
+	Contract  string // Contract address of the log.
+	Topic     string // Event topic hash of the log.
+	Signature string // Event signature of the log.
+	Name      string // Event name of the log.
+
+	vLog       *types.Log
+	classified *DecodedLog
+
+	once    sync.Once
+	decoded *DecodedLog
+	err     error
+}
+
+// LazyLog classifies the log like ClassifyLog and returns a handle decoding its params on first
+// use. It returns nil for unknown topics.
+func (store *Storage) LazyLog(vLog *types.Log) *LazyLog {
+	classified := store.ClassifyLog(vLog)
+	if classified == nil {
+		return nil
+	}
+
+	return &LazyLog{
+		Contract:   classified.Contract,
+		Topic:      classified.Topic,
+		Signature:  classified.Signature,
+		Name:       classified.Name,
+		vLog:       vLog,
+		classified: classified,
+	}
+}
+
+// LazyLogs returns the handles of all logs with a known topic.
+func (store *Storage) LazyLogs(vLogs []*types.Log) []*LazyLog {
+	result := make([]*LazyLog, 0, len(vLogs))
+	for _, vLog := range vLogs {
+		if handle := store.LazyLog(vLog); handle != nil {
+			result = append(result, handle)
+		}
+	}
+
+	return result
+}
+
+// Raw returns the log the handle was created for.
+func (handle *LazyLog) Raw() *types.Log {
+	return handle.vLog
+}
+
+// Hydrate decodes the log on the first call and returns the memoized result afterwards.
+func (handle *LazyLog) Hydrate() (*DecodedLog, error) {
+	handle.once.Do(func() {
+		if handle.err = handle.classified.Hydrate(); handle.err == nil {
+			handle.decoded = handle.classified
+		}
+		handle.classified = nil
+	})
+
+	return handle.decoded, handle.err
+}
+
+// Params returns the decoded params of the log, or nil if it could not be decoded.
+func (handle *LazyLog) Params() Params {
+	decoded, _ := handle.Hydrate()
+	if decoded == nil {
+		return nil
+	}
+
+	return decoded.Params
+}
+
+// LazyMethod is a handle of a classified transaction which decodes its calldata on first use. The
+// handle is safe for concurrent use, the decoded result is memoized.
+type LazyMethod struct {
+	Contract  string // Contract address the transaction is sent to.
+	SigHash   string // 4 byte selector of the method.
+	Signature string // Signature of the method.
+	Name      string // Name of the method.
+
+	tx    *types.Transaction
+	abi   *abi.ABI
+	store *Storage
+
+	once    sync.Once
+	decoded *DecodedMethod
+	err     error
+}
+
+// LazyMethod identifies the method of the transaction by its selector without unpacking the
+// calldata and returns a handle decoding it on first use. It returns nil for contract creations
+// and unknown selectors.
+func (store *Storage) LazyMethod(tx *types.Transaction) *LazyMethod {
+	data := tx.Data()
+	if tx.To() == nil || len(data) < 4 {
+		return nil
+	}
+
+	for i := range store.AbiList {
+		method, err := store.AbiList[i].MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+
+		return &LazyMethod{
+			Contract:  tx.To().Hex(),
+			SigHash:   hexutil.Encode(data[:4]),
+			Signature: method.Sig,
+			Name:      method.Name,
+			tx:        tx,
+			abi:       &store.AbiList[i],
+			store:     store,
+		}
+	}
+
+	return nil
+}
+
+// Raw returns the transaction the handle was created for.
+func (handle *LazyMethod) Raw() *types.Transaction {
+	return handle.tx
+}
+
+// Hydrate decodes the calldata on the first call, with the nested calls, compliance screening
+// and attestation of DecodeMethod, and returns the memoized result afterwards.
+func (handle *LazyMethod) Hydrate() (*DecodedMethod, error) {
+	handle.once.Do(func() {
+		store := handle.store
+		decoded, err := parseCalldata(handle.Contract, handle.tx.Data(), *handle.abi, nil, store.PreserveTypes)
+		if err != nil || decoded == nil {
+			handle.err = fmt.Errorf("error unpack %s of %s: %v", handle.Signature, handle.tx.Hash().Hex(), err)
+			return
+		}
+
+		decoded.TransactionHash = handle.tx.Hash().Hex()
+		decodeNestedCalls(decoded, store.methodABIs, 0, store.PreserveTypes)
+		if !screenMethod(store.Compliance, handle.tx, nil, decoded) {
+			handle.err = fmt.Errorf("transaction %s blocked by compliance hook", decoded.TransactionHash)
+			return
+		}
+
+		includeTx(store.IncludeTx, handle.tx, decoded)
+		nameMethod(store.ResolveNames, store.GetBackend(), decoded)
+		labelMethod(store.Labels, decoded)
+		keyMethod(decoded)
+		attestMethod(store.Attestor, decoded)
+		handle.decoded = decoded
+	})
+
+	return handle.decoded, handle.err
+}
+
+// Params returns the decoded params of the calldata, or nil if it could not be decoded.
+func (handle *LazyMethod) Params() Params {
+	decoded, _ := handle.Hydrate()
+	if decoded == nil {
+		return nil
+	}
+
+	return decoded.Params
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Ledger entry sides.
+const (
+	LedgerDebit  = "debit"
+	LedgerCredit = "credit"
+)
+
+// LedgerLine is one side of a double-entry booking derived from an asset movement. Every movement
+// produces a credit line for the account the value leaves and a debit line for the account
+// receiving it, so the lines of a transaction always balance per asset.
+type LedgerLine struct {
+	TransactionHash string `json:"transactionHash"`    // Transaction hash the booking belongs to.
+	Entry           string `json:"entry"`              // LedgerDebit or LedgerCredit.
+	Account         string `json:"account"`            // Account booked by this line.
+	Counterparty    string `json:"counterparty"`       // Account on the other side of the booking.
+	Asset           string `json:"asset"`              // Token contract, EtherAddress for the native currency.
+	Symbol          string `json:"symbol"`             // Asset symbol if known, otherwise empty.
+	Amount          string `json:"amount"`             // Unsigned amount in the smallest unit.
+	Value           string `json:"value,omitempty"`    // Amount in whole units formatted with DefaultAmountFormat, empty if decimals are unknown.
+	Kind            string `json:"kind"`               // Movement kind (native, fee, erc20, erc721).
+	TokenId         string `json:"tokenId,omitempty"`  // Token id of NFT movements.
+	LogIndex        *uint  `json:"logIndex,omitempty"` // Index of the log the movement was decoded from.
+}
+
+// Ledger is a list of double-entry ledger lines.
+type Ledger []LedgerLine
+
+var ledgerHeader = []string{
+	"transactionHash", "entry", "account", "counterparty", "asset",
+	"symbol", "amount", "value", "kind", "tokenId", "logIndex",
+}
+
+// LedgerFromSummary converts all movements of a balance summary into ledger lines.
+func LedgerFromSummary(summary *BalanceSummary) Ledger {
+	result := make(Ledger, 0, len(summary.Movements)*2)
+
+	for _, movement := range summary.Movements {
+		symbol, value := "", ""
+		if movement.Asset == EtherAddress {
+			symbol = RosettaNativeCurrency.Symbol
+			value = FormatAmount(movement.Amount, RosettaNativeCurrency.Decimals)
+		} else if movement.Kind == MovementERC721 {
+			value = movement.Amount.String()
+		} else if info := TknStore.Cached(common.HexToAddress(movement.Asset)); info != nil {
+			symbol = info.Symbol
+			value = FormatAmount(movement.Amount, info.Decimals)
+		}
+
+		tokenId := ""
+		if movement.TokenId != nil {
+			tokenId = movement.TokenId.String()
+		}
+
+		line := LedgerLine{
+			TransactionHash: summary.TransactionHash,
+			Asset:           movement.Asset,
+			Symbol:          symbol,
+			Amount:          movement.Amount.String(),
+			Value:           value,
+			Kind:            movement.Kind,
+			TokenId:         tokenId,
+			LogIndex:        movement.LogIndex,
+		}
+
+		credit := line
+		credit.Entry = LedgerCredit
+		credit.Account = movement.From
+		credit.Counterparty = movement.To
+
+		debit := line
+		debit.Entry = LedgerDebit
+		debit.Account = movement.To
+		debit.Counterparty = movement.From
+
+		result = append(result, credit, debit)
+	}
+
+	return result
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the Ledger.
+func (l Ledger) ToJSONBytes() []byte {
+	b, _ := json.Marshal(l)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the Ledger.
+func (l Ledger) ToJSON() string {
+	return string(l.ToJSONBytes())
+}
+
+// WriteCSV writes the ledger including a header row as CSV.
+func (l Ledger) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ledgerHeader); err != nil {
+		return err
+	}
+
+	for _, line := range l {
+		logIndex := ""
+		if line.LogIndex != nil {
+			logIndex = fmt.Sprint(*line.LogIndex)
+		}
+
+		record := []string{
+			line.TransactionHash, line.Entry, line.Account, line.Counterparty, line.Asset,
+			line.Symbol, line.Amount, line.Value, line.Kind, line.TokenId, logIndex,
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
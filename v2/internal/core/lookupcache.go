@@ -0,0 +1,256 @@
+package core
+
+import (
+	"container/list"
+	"context"
+	"math/big"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Kinds of the lookups cached by LookupCache.
+const (
+	LookupBytecode = "bytecode"
+	LookupSymbol   = "symbol"
+	LookupName     = "name"
+	LookupDecimals = "decimals"
+	LookupENSName  = "ensName"
+)
+
+// LookupCache caches the bytecode, symbol, name and decimals of contracts by chain id and address,
+// so getBytecode, getSymbol, getName and getDecimals, and with them queryTokenInfo and
+// IndexedABI.GetBytecode, only hit the RPC once per contract. It holds at most Size entries,
+// evicting the least recently used one, and expires entries older than TTL, if set. Failed
+// lookups, and lookups on backends not reporting their chain id, are not cached.
+type LookupCache struct {
+	Size int           // Maximum number of entries, unbounded if 0.
+	TTL  time.Duration // Maximum age of entries, unlimited if 0.
+
+	mu      sync.Mutex
+	entries lru[lookupKey]
+}
+
+type lookupKey struct {
+	chain   string
+	address common.Address
+	kind    string
+}
+
+// NewLookupCache creates a cache of at most size entries, expiring them after ttl if it is not 0.
+func NewLookupCache(size int, ttl time.Duration) *LookupCache {
+	return &LookupCache{Size: size, TTL: ttl}
+}
+
+var lookups = struct {
+	sync.RWMutex
+	cache *LookupCache
+}{cache: NewLookupCache(10000, 0)}
+
+// SetLookupCache replaces the in-process cache of the contract lookups, nil disables it, leaving
+// only the shared Cache, see SetCache.
+func SetLookupCache(cache *LookupCache) {
+	lookups.Lock()
+	defer lookups.Unlock()
+	lookups.cache = cache
+}
+
+// GetLookupCache returns the in-process cache of the contract lookups, nil if it is disabled.
+func GetLookupCache() *LookupCache {
+	lookups.RLock()
+	defer lookups.RUnlock()
+	return lookups.cache
+}
+
+// Get returns the value of the lookup of the kind for the address on the chain, false if it is
+// not cached or expired.
+func (c *LookupCache) Get(chainId *big.Int, address common.Address, kind string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.get(newLookupKey(chainId, address, kind), getClock().Now())
+}
+
+// Set stores the value of the lookup, evicting the least recently used entry if the cache is full.
+func (c *LookupCache) Set(chainId *big.Int, address common.Address, kind string, value interface{}) {
+	if c == nil {
+		return
+	}
+
+	var expires time.Time
+	if c.TTL > 0 {
+		expires = getClock().Now().Add(c.TTL)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.set(newLookupKey(chainId, address, kind), value, expires, c.Size)
+}
+
+// Len returns the number of cached entries, including expired ones not evicted yet.
+func (c *LookupCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.len()
+}
+
+// Purge drops all cached entries.
+func (c *LookupCache) Purge() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.purge()
+}
+
+// lru is a least recently used list of entries expiring at a deadline, shared by LookupCache and
+// MemoryCache. It is not safe for concurrent use.
+type lru[K comparable] struct {
+	entries map[K]*list.Element
+	order   *list.List // most recently used first
+}
+
+type lruEntry[K comparable] struct {
+	key     K
+	value   interface{}
+	expires time.Time // zero for entries that do not expire
+}
+
+func (l *lru[K]) get(key K, now time.Time) (interface{}, bool) {
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry[K])
+	if !entry.expires.IsZero() && now.After(entry.expires) {
+		l.remove(element)
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// set stores the entry and evicts the least recently used entries beyond size, if it is not 0.
+func (l *lru[K]) set(key K, value interface{}, expires time.Time, size int) {
+	if l.entries == nil {
+		l.entries = make(map[K]*list.Element)
+		l.order = list.New()
+	}
+
+	entry := &lruEntry[K]{key: key, value: value, expires: expires}
+	if element, ok := l.entries[key]; ok {
+		element.Value = entry
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(entry)
+	for size > 0 && l.order.Len() > size {
+		l.remove(l.order.Back())
+	}
+}
+
+func (l *lru[K]) len() int {
+	return len(l.entries)
+}
+
+func (l *lru[K]) purge() {
+	l.entries = nil
+	l.order = nil
+}
+
+func (l *lru[K]) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*lruEntry[K]).key)
+}
+
+func newLookupKey(chainId *big.Int, address common.Address, kind string) lookupKey {
+	key := lookupKey{address: address, kind: kind}
+	if chainId != nil {
+		key.chain = chainId.String()
+	}
+
+	return key
+}
+
+// chainIDs memoizes the chain ids of backends, so cache keys do not cost an eth_chainId call.
+var chainIDs sync.Map
+
+// lookupChainID returns the chain id of the backend, nil if it does not report one.
+func lookupChainID(ctx context.Context, backend Backend) *big.Int {
+	if retry, ok := backend.(*retryBackend); ok {
+		backend = retry.Backend
+	}
+	if backend == nil {
+		return nil
+	}
+
+	memoize := reflect.TypeOf(backend).Comparable()
+	if memoize {
+		if id, ok := chainIDs.Load(backend); ok {
+			return id.(*big.Int)
+		}
+	}
+
+	// backends failing to report their chain id are remembered as well, with a nil id
+	id, err := backendChainID(ctx, backend)
+	if err != nil {
+		id = nil
+	}
+
+	if memoize {
+		chainIDs.Store(backend, id)
+	}
+
+	return id
+}
+
+// cachedLookup returns the cached lookup of the kind for the address, looked up in the LookupCache
+// and then in the shared Cache, fetching and caching it on a miss. Lookups returning nil, and
+// lookups on backends not reporting their chain id, are not cached, as their results could not be
+// told apart from those of other chains.
+func cachedLookup[T any](ctx context.Context, client Backend, address common.Address, kind string, fetch func() *T) *T {
+	if client == nil {
+		return fetch()
+	}
+
+	chainId := lookupChainID(ctx, client)
+	if chainId == nil {
+		return fetch()
+	}
+
+	cache := GetLookupCache()
+	if value, ok := cache.Get(chainId, address, kind); ok {
+		if result, ok := value.(T); ok {
+			return &result
+		}
+	}
+
+	key := contractCacheKey(kind, chainId, address)
+	var shared T
+	if cacheGet(ctx, key, &shared) {
+		cache.Set(chainId, address, kind, shared)
+		return &shared
+	}
+
+	result := fetch()
+	if result != nil {
+		cache.Set(chainId, address, kind, *result)
+		cacheSet(ctx, key, *result)
+	}
+
+	return result
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestLookupCacheEviction(t *testing.T) {
+	manual := NewManualClock(time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(manual)
+	defer SetClock(nil)
+
+	chain := big.NewInt(1)
+	cache := NewLookupCache(2, time.Minute)
+	cache.Set(chain, common.BigToAddress(big.NewInt(1)), LookupSymbol, "A")
+	cache.Set(chain, common.BigToAddress(big.NewInt(2)), LookupSymbol, "B")
+
+	// reading 1 makes 2 the least recently used entry
+	if value, ok := cache.Get(chain, common.BigToAddress(big.NewInt(1)), LookupSymbol); !ok || value != "A" {
+		t.Fatalf("unexpected entry: %v %v", value, ok)
+	}
+
+	cache.Set(chain, common.BigToAddress(big.NewInt(3)), LookupSymbol, "C")
+	if _, ok := cache.Get(chain, common.BigToAddress(big.NewInt(2)), LookupSymbol); ok || cache.Len() != 2 {
+		t.Fatal("least recently used entry not evicted")
+	}
+
+	// entries are scoped to their chain
+	if _, ok := cache.Get(big.NewInt(10), common.BigToAddress(big.NewInt(1)), LookupSymbol); ok {
+		t.Fatal("entry leaked to another chain")
+	}
+
+	manual.Advance(2 * time.Minute)
+	if _, ok := cache.Get(chain, common.BigToAddress(big.NewInt(1)), LookupSymbol); ok {
+		t.Fatal("expired entry returned")
+	}
+}
+
+func TestLookupCacheQueries(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	uintType, _ := abi.NewType("uint8", "", nil)
+	server.HandleCall(func(common.Address, []byte) ([]byte, error) {
+		return abi.Arguments{{Type: uintType}}.Pack(uint8(6))
+	})
+	server.Respond("eth_chainId", "0x1")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetLookupCache(NewLookupCache(10, 0))
+	defer SetLookupCache(NewLookupCache(10000, 0))
+	SetCache(nil)
+	defer SetCache(nil)
+
+	token := common.BigToAddress(big.NewInt(6))
+	for i := 0; i < 3; i++ {
+		if decimals := getDecimals(context.Background(), client, token); decimals == nil || *decimals != 6 {
+			t.Fatalf("unexpected decimals: %v", decimals)
+		}
+	}
+
+	if calls := len(server.Calls("eth_call")); calls != 1 {
+		t.Fatalf("decimals queried %d times", calls)
+	}
+
+	if calls := len(server.Calls("eth_chainId")); calls != 1 {
+		t.Fatalf("chain id queried %d times", calls)
+	}
+}
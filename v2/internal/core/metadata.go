@@ -0,0 +1,182 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ContractMetadata is a struct for holding the CBOR metadata solc appends to the runtime bytecode.
+type ContractMetadata struct {
+	Solc         string `json:"solc,omitempty"`         // Compiler version, e.g. "0.8.19".
+	IPFS         string `json:"ipfs,omitempty"`         // Base58 CID of the metadata.json on IPFS.
+	Bzzr0        string `json:"bzzr0,omitempty"`        // Swarm hash of the metadata.json (solc < 0.5.12).
+	Bzzr1        string `json:"bzzr1,omitempty"`        // Swarm hash of the metadata.json (solc 0.5.12 - 0.6.0).
+	Experimental bool   `json:"experimental,omitempty"` // Whether experimental compiler features were used.
+	Raw          string `json:"raw"`                    // Hex encoded CBOR blob.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the ContractMetadata object.
+func (data *ContractMetadata) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the ContractMetadata object.
+func (data *ContractMetadata) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ExtractMetadata parses the CBOR metadata at the end of the runtime bytecode. solc appends the
+// blob followed by its length as 2 byte big endian integer. The IPFS hash identifies the
+// metadata.json of the compilation, e.g. for a Sourcify lookup, the solc version fingerprints
+// the compiler. Unknown keys are ignored.
+func ExtractMetadata(bytecode string) (*ContractMetadata, error) {
+	code, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(bytecode), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode: %w", err)
+	}
+
+	if len(code) < 2 {
+		return nil, fmt.Errorf("no metadata in bytecode")
+	}
+
+	size := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	if size == 0 || size > len(code)-2 {
+		return nil, fmt.Errorf("no metadata in bytecode")
+	}
+
+	blob := code[len(code)-2-size : len(code)-2]
+	reader := cborReader{data: blob}
+
+	major, count, err := reader.head()
+	if err != nil || major != 5 {
+		return nil, fmt.Errorf("no metadata in bytecode: blob is not a cbor map")
+	}
+
+	result := ContractMetadata{Raw: hex.EncodeToString(blob)}
+	for i := 0; i < count; i++ {
+		key, err := reader.text()
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata key: %w", err)
+		}
+
+		major, value, err := reader.value()
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata value of %s: %w", key, err)
+		}
+
+		switch {
+		case key == "ipfs" && major == 2:
+			result.IPFS = base58(value)
+		case key == "bzzr0" && major == 2:
+			result.Bzzr0 = "0x" + hex.EncodeToString(value)
+		case key == "bzzr1" && major == 2:
+			result.Bzzr1 = "0x" + hex.EncodeToString(value)
+		case key == "solc" && major == 2 && len(value) == 3: // releases are encoded as 3 bytes
+			result.Solc = fmt.Sprintf("%d.%d.%d", value[0], value[1], value[2])
+		case key == "solc" && major == 3: // prereleases as full version string
+			result.Solc = string(value)
+		case key == "experimental" && major == 7:
+			result.Experimental = len(value) == 1 && value[0] == 21
+		}
+	}
+
+	return &result, nil
+}
+
+// cborReader reads the subset of CBOR used by the solc metadata: maps of text keys to byte
+// strings, text strings and simple values.
+type cborReader struct {
+	data []byte
+	pos  int
+}
+
+// head reads the major type and argument of the next item.
+func (r *cborReader) head() (int, int, error) {
+	if r.pos >= len(r.data) {
+		return 0, 0, fmt.Errorf("unexpected end of cbor")
+	}
+
+	initial := r.data[r.pos]
+	r.pos++
+
+	major, info := int(initial>>5), int(initial&0x1f)
+	switch {
+	case info < 24:
+		return major, info, nil
+	case info == 24 && r.pos+1 <= len(r.data):
+		r.pos++
+		return major, int(r.data[r.pos-1]), nil
+	case info == 25 && r.pos+2 <= len(r.data):
+		r.pos += 2
+		return major, int(binary.BigEndian.Uint16(r.data[r.pos-2:])), nil
+	}
+
+	return 0, 0, fmt.Errorf("unsupported cbor argument %d", info)
+}
+
+// value reads the next item, returning the content of strings and the argument of simple values.
+func (r *cborReader) value() (int, []byte, error) {
+	major, argument, err := r.head()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	switch major {
+	case 2, 3:
+		if r.pos+argument > len(r.data) {
+			return 0, nil, fmt.Errorf("unexpected end of cbor")
+		}
+		r.pos += argument
+		return major, r.data[r.pos-argument : r.pos], nil
+	case 7:
+		return major, []byte{byte(argument)}, nil
+	}
+
+	return 0, nil, fmt.Errorf("unsupported cbor type %d", major)
+}
+
+func (r *cborReader) text() (string, error) {
+	major, value, err := r.value()
+	if err != nil {
+		return "", err
+	}
+
+	if major != 3 {
+		return "", fmt.Errorf("expected cbor text, got type %d", major)
+	}
+
+	return string(value), nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58 encodes the bytes with the bitcoin alphabet used by IPFS CIDv0.
+func base58(input []byte) string {
+	number := new(big.Int).SetBytes(input)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var result []byte
+	for number.Sign() > 0 {
+		number.DivMod(number, radix, mod)
+		result = append(result, base58Alphabet[mod.Int64()])
+	}
+
+	for _, b := range input {
+		if b != 0 {
+			break
+		}
+		result = append(result, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return string(result)
+}
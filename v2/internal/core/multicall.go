@@ -0,0 +1,174 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MaxNestedDepth limits how deep nested calldata (multicalls inside multicalls) is decoded.
+var MaxNestedDepth = 4
+
+// nestedCall is calldata found inside the params of a decoded method, together with the contract
+// it is executed against.
+type nestedCall struct {
+	target string
+	data   []byte
+}
+
+// collectNestedCalls looks for calldata inside the unpacked params of a method. It detects
+//   - Safe multiSend(bytes) batches, which are unpacked into their single transactions,
+//   - a `bytes` param next to an `address` param (e.g. Safe execTransaction(to, value, data)),
+//     which is executed against that address,
+//   - `bytes[]` params, executed against a parallel `address[]` param of the same length
+//     (e.g. timelock executeBatch) or otherwise against the called contract itself
+//     (e.g. multicall(bytes[])),
+//   - lists of tuples holding an address and a bytes field (e.g. Multicall3 aggregate3),
+//     which are executed against the address of each tuple.
+func collectNestedCalls(contract string, method *abi.Method, params map[string]interface{}) []nestedCall {
+	var result []nestedCall
+
+	if method.Name == "multiSend" && len(method.Inputs) == 1 && method.Inputs[0].Type.T == abi.BytesTy {
+		packed, _ := params[method.Inputs[0].Name].([]byte)
+		return unpackMultiSend(packed)
+	}
+
+	var target *common.Address
+	var targets []common.Address
+	for _, input := range method.Inputs {
+		switch value := params[input.Name].(type) {
+		case common.Address:
+			if target == nil {
+				target = &value
+			}
+		case []common.Address:
+			if targets == nil {
+				targets = value
+			}
+		}
+	}
+
+	for _, input := range method.Inputs {
+		if input.Type.T == abi.BytesTy {
+			if data, ok := params[input.Name].([]byte); ok && len(data) >= 4 && target != nil {
+				result = append(result, nestedCall{target: target.Hex(), data: data})
+			}
+			continue
+		}
+
+		if input.Type.T != abi.SliceTy && input.Type.T != abi.ArrayTy {
+			continue
+		}
+
+		value := reflect.ValueOf(params[input.Name])
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			continue
+		}
+
+		switch input.Type.Elem.T {
+		case abi.BytesTy:
+			for i := 0; i < value.Len(); i++ {
+				data, ok := value.Index(i).Interface().([]byte)
+				if !ok || len(data) < 4 {
+					continue
+				}
+
+				callTarget := contract
+				if len(targets) == value.Len() {
+					callTarget = targets[i].Hex()
+				}
+
+				result = append(result, nestedCall{target: callTarget, data: data})
+			}
+
+		case abi.TupleTy:
+			for i := 0; i < value.Len(); i++ {
+				if call := tupleCall(value.Index(i)); call != nil {
+					result = append(result, *call)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// unpackMultiSend splits the packed transactions of a Safe multiSend call. Every transaction is
+// encoded as operation (1 byte), to (20 bytes), value (32 bytes), data length (32 bytes) and data.
+func unpackMultiSend(packed []byte) []nestedCall {
+	var result []nestedCall
+
+	for offset := 0; offset+85 <= len(packed); {
+		to := common.BytesToAddress(packed[offset+1 : offset+21])
+		length := new(big.Int).SetBytes(packed[offset+53 : offset+85])
+		start := offset + 85
+
+		// lengths past the packed data, however large, end the batch
+		if !length.IsUint64() || length.Uint64() > uint64(len(packed)-start) {
+			break
+		}
+
+		end := start + int(length.Uint64())
+		if data := packed[start:end]; len(data) >= 4 {
+			result = append(result, nestedCall{target: to.Hex(), data: data})
+		}
+
+		offset = end
+	}
+
+	return result
+}
+
+// tupleCall extracts the first address and the first bytes field of an unpacked tuple.
+func tupleCall(tuple reflect.Value) *nestedCall {
+	if tuple.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var target *common.Address
+	var data []byte
+
+	for i := 0; i < tuple.NumField(); i++ {
+		switch field := tuple.Field(i).Interface().(type) {
+		case common.Address:
+			if target == nil {
+				target = &field
+			}
+		case []byte:
+			if data == nil {
+				data = field
+			}
+		}
+	}
+
+	if target == nil || len(data) < 4 {
+		return nil
+	}
+
+	return &nestedCall{target: target.Hex(), data: data}
+}
+
+// decodeNestedCalls decodes the calldata nested in the decoded method with the ABIs returned for
+// its selector and attaches the results as Calls, recursing until MaxNestedDepth is reached. Calls
+// that can not be decoded with any of the ABIs are skipped.
+func decodeNestedCalls(decoded *DecodedMethod, abis func(data []byte) []abi.ABI, depth int, preserve bool) {
+	if decoded == nil || len(decoded.nested) == 0 || depth >= MaxNestedDepth {
+		return
+	}
+
+	for _, call := range decoded.nested {
+		for _, contractAbi := range abis(call.data) {
+			child, err := parseCalldata(call.target, call.data, contractAbi, nil, preserve)
+			if err != nil || child == nil {
+				continue
+			}
+
+			child.TransactionHash = decoded.TransactionHash
+			decodeNestedCalls(child, abis, depth+1, preserve)
+			decoded.Calls = append(decoded.Calls, child)
+			break
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// MultiClientOptions configures the endpoints of a MultiClient.
+type MultiClientOptions struct {
+	Connect        ConnectOptions // Connection options used for every endpoint.
+	RoundRobin     bool           // Whether calls are spread over the healthy endpoints instead of preferring them in order.
+	MaxFailures    int            // Consecutive failures marking an endpoint unhealthy, defaults to 3.
+	MaxBlockLag    uint64         // Endpoints lagging this many blocks behind the highest one are unhealthy, 0 disables the check.
+	HealthInterval time.Duration  // How often Start checks the endpoints, defaults to 30 seconds.
+	HealthTimeout  time.Duration  // Timeout of a single health check, defaults to 5 seconds.
+}
+
+// EndpointStatus is a struct for holding the health of a single MultiClient endpoint.
+type EndpointStatus struct {
+	URL         string `json:"url"`
+	Healthy     bool   `json:"healthy"`
+	Failures    int    `json:"failures"`            // Consecutive failed calls.
+	BlockNumber uint64 `json:"blockNumber"`         // Latest block seen by the last health check.
+	LastError   string `json:"lastError,omitempty"` // Last transport error of the endpoint.
+}
+
+// multiEndpoint is a single endpoint of a MultiClient, its state is guarded by the client mutex.
+type multiEndpoint struct {
+	url         string
+	client      *ethclient.Client
+	healthy     bool
+	failures    int
+	blockNumber uint64
+	lagging     bool // set by the health checks, see MaxBlockLag
+	lastErr     error
+}
+
+// MultiClient is a Backend spreading its calls over several RPC endpoints. Calls failing with
+// transport errors, HTTP errors or rate limits are retried on the next endpoint, endpoints failing
+// MaxFailures times in a row are skipped until a health check or call succeeds again. JSON-RPC
+// errors of the node, e.g. reverts, and ethereum.NotFound are returned as is. Lagging endpoints
+// stay unhealthy until a health check finds them caught up. Set it with SetBackend or the
+// SetBackend methods of decoders, stores and indexed contracts.
+//
+// Transactions are sent once, to the preferred endpoint, as a failed send may still have reached
+// the node. Batched lookups, call traces and subscriptions use the endpoint preferred when they
+// start and do not fail over.
+type MultiClient struct {
+	options MultiClientOptions
+
+	mu        sync.Mutex
+	endpoints []*multiEndpoint
+	next      int
+}
+
+// DialMulti creates a MultiClient for the node urls, dialed with the connection options of
+// options. All endpoints start healthy. Invalid options fail with ValidationErrors.
+func DialMulti(ctx context.Context, urls []string, options MultiClientOptions) (*MultiClient, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no endpoints given")
+	}
+	for i, url := range urls {
+		if strings.TrimSpace(url) == "" {
+			return nil, fmt.Errorf("empty endpoint url at index %d", i)
+		}
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	if options.MaxFailures <= 0 {
+		options.MaxFailures = 3
+	}
+	if options.HealthInterval <= 0 {
+		options.HealthInterval = 30 * time.Second
+	}
+	if options.HealthTimeout <= 0 {
+		options.HealthTimeout = 5 * time.Second
+	}
+
+	multi := &MultiClient{options: options}
+	for _, url := range urls {
+		client, err := DialWithOptions(ctx, url, options.Connect)
+		if err != nil {
+			multi.Close()
+			return nil, fmt.Errorf("error dialing %s: %w", url, err)
+		}
+
+		multi.endpoints = append(multi.endpoints, &multiEndpoint{url: url, client: client, healthy: true})
+	}
+
+	return multi, nil
+}
+
+// Close closes the connections of all endpoints.
+func (m *MultiClient) Close() {
+	for _, endpoint := range m.endpoints {
+		endpoint.client.Close()
+	}
+}
+
+// Start checks the health of the endpoints in the configured interval until the context is
+// cancelled.
+func (m *MultiClient) Start(ctx context.Context) {
+	ticker := getClock().NewTicker(m.options.HealthInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				m.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Check requests the latest block of every endpoint and updates their health. Endpoints failing
+// the request or lagging more than MaxBlockLag blocks behind the highest one are unhealthy.
+func (m *MultiClient) Check(ctx context.Context) []EndpointStatus {
+	numbers := make([]uint64, len(m.endpoints))
+	errs := make([]error, len(m.endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range m.endpoints {
+		wg.Add(1)
+		go func(i int, client *ethclient.Client) {
+			defer wg.Done()
+			checkCtx, cancel := withTimeout(ctx, m.options.HealthTimeout)
+			defer cancel()
+			numbers[i], errs[i] = client.BlockNumber(checkCtx)
+		}(i, endpoint.client)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for i := range numbers {
+		if errs[i] == nil && numbers[i] > highest {
+			highest = numbers[i]
+		}
+	}
+
+	m.mu.Lock()
+	for i, endpoint := range m.endpoints {
+		if errs[i] != nil {
+			endpoint.healthy, endpoint.lastErr = false, errs[i]
+			continue
+		}
+
+		endpoint.blockNumber = numbers[i]
+		endpoint.failures = 0
+		endpoint.lagging = m.options.MaxBlockLag > 0 && highest-numbers[i] > m.options.MaxBlockLag
+		endpoint.healthy = !endpoint.lagging
+	}
+	m.mu.Unlock()
+
+	return m.Status()
+}
+
+// Status returns the health of the endpoints, in the order of their urls.
+func (m *MultiClient) Status() []EndpointStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]EndpointStatus, len(m.endpoints))
+	for i, endpoint := range m.endpoints {
+		result[i] = EndpointStatus{
+			URL:         endpoint.url,
+			Healthy:     endpoint.healthy,
+			Failures:    endpoint.failures,
+			BlockNumber: endpoint.blockNumber,
+		}
+		if endpoint.lastErr != nil {
+			result[i].LastError = endpoint.lastErr.Error()
+		}
+	}
+
+	return result
+}
+
+// order returns the endpoints in the order they are tried: the healthy ones first, starting at
+// the next one for round-robin, then the unhealthy ones as last resort.
+func (m *MultiClient) order() []*multiEndpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	start := 0
+	if m.options.RoundRobin {
+		start = m.next % len(m.endpoints)
+		m.next++
+	}
+
+	healthy := make([]*multiEndpoint, 0, len(m.endpoints))
+	unhealthy := make([]*multiEndpoint, 0)
+	for i := range m.endpoints {
+		endpoint := m.endpoints[(start+i)%len(m.endpoints)]
+		if endpoint.healthy {
+			healthy = append(healthy, endpoint)
+		} else {
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// report records the outcome of a call on the endpoint. Successful calls recover endpoints marked
+// unhealthy by failures, not lagging ones.
+func (m *MultiClient) report(endpoint *multiEndpoint, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err == nil {
+		endpoint.failures = 0
+		endpoint.healthy = !endpoint.lagging
+		return
+	}
+
+	endpoint.failures++
+	endpoint.lastErr = err
+	if endpoint.failures >= m.options.MaxFailures {
+		endpoint.healthy = false
+	}
+}
+
+// isFailover reports whether the call error is caused by the endpoint rather than the request,
+// so the call is worth retrying on another endpoint.
+func isFailover(err error) bool {
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// limit exceeded and rate limits of common providers
+		return rpcErr.ErrorCode() == -32005 || rpcErr.ErrorCode() == 429
+	}
+
+	return true
+}
+
+// do runs the call on the endpoints in order until it succeeds or fails for reasons other than
+// the endpoint. The error of the last endpoint tried is returned.
+func (m *MultiClient) do(ctx context.Context, call func(client *ethclient.Client) error) error {
+	var err error
+	for _, endpoint := range m.order() {
+		err = call(endpoint.client)
+		if err != nil && isFailover(err) {
+			m.report(endpoint, err)
+			if ctx.Err() != nil {
+				return err
+			}
+			continue
+		}
+
+		m.report(endpoint, nil)
+		return err
+	}
+
+	return err
+}
+
+// once runs the call on the preferred endpoint only, for calls that must not be repeated.
+func (m *MultiClient) once(call func(client *ethclient.Client) error) error {
+	endpoint := m.order()[0]
+	err := call(endpoint.client)
+	if err != nil && isFailover(err) {
+		m.report(endpoint, err)
+	} else {
+		m.report(endpoint, nil)
+	}
+
+	return err
+}
+
+// preferred returns the client of the endpoint the next call would be sent to.
+func (m *MultiClient) preferred() *ethclient.Client {
+	return m.order()[0].client
+}
+
+// Client returns the JSON-RPC client of the preferred endpoint, used for batched lookups and
+// call traces.
+func (m *MultiClient) Client() *rpc.Client {
+	return m.preferred().Client()
+}
+
+func (m *MultiClient) ChainID(ctx context.Context) (id *big.Int, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		id, err = client.ChainID(ctx)
+		return err
+	})
+	return id, err
+}
+
+func (m *MultiClient) BlockNumber(ctx context.Context) (number uint64, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		number, err = client.BlockNumber(ctx)
+		return err
+	})
+	return number, err
+}
+
+func (m *MultiClient) BlockByHash(ctx context.Context, hash common.Hash) (block *types.Block, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		block, err = client.BlockByHash(ctx, hash)
+		return err
+	})
+	return block, err
+}
+
+func (m *MultiClient) BlockByNumber(ctx context.Context, number *big.Int) (block *types.Block, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		block, err = client.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+func (m *MultiClient) HeaderByHash(ctx context.Context, hash common.Hash) (header *types.Header, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		header, err = client.HeaderByHash(ctx, hash)
+		return err
+	})
+	return header, err
+}
+
+func (m *MultiClient) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (m *MultiClient) TransactionCount(ctx context.Context, blockHash common.Hash) (count uint, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		count, err = client.TransactionCount(ctx, blockHash)
+		return err
+	})
+	return count, err
+}
+
+func (m *MultiClient) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (tx *types.Transaction, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		tx, err = client.TransactionInBlock(ctx, blockHash, index)
+		return err
+	})
+	return tx, err
+}
+
+func (m *MultiClient) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		tx, isPending, err = client.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+func (m *MultiClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (sender common.Address, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		sender, err = client.TransactionSender(ctx, tx, block, index)
+		return err
+	})
+	return sender, err
+}
+
+func (m *MultiClient) TransactionReceipt(ctx context.Context, hash common.Hash) (receipt *types.Receipt, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		receipt, err = client.TransactionReceipt(ctx, hash)
+		return err
+	})
+	return receipt, err
+}
+
+func (m *MultiClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return m.preferred().SubscribeNewHead(ctx, ch)
+}
+
+func (m *MultiClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		code, err = client.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+func (m *MultiClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		result, err = client.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (m *MultiClient) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		code, err = client.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+func (m *MultiClient) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) (result []byte, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		result, err = client.PendingCallContract(ctx, msg)
+		return err
+	})
+	return result, err
+}
+
+func (m *MultiClient) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (m *MultiClient) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		price, err = client.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+func (m *MultiClient) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		tip, err = client.SuggestGasTipCap(ctx)
+		return err
+	})
+	return tip, err
+}
+
+func (m *MultiClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		gas, err = client.EstimateGas(ctx, msg)
+		return err
+	})
+	return gas, err
+}
+
+func (m *MultiClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return m.once(func(client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
+
+func (m *MultiClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = m.do(ctx, func(client *ethclient.Client) error {
+		logs, err = client.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+func (m *MultiClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return m.preferred().SubscribeFilterLogs(ctx, query, ch)
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+var _ Backend = (*MultiClient)(nil)
+
+func TestMultiClient(t *testing.T) {
+	flaky, backup := mockrpc.NewServer(), mockrpc.NewServer()
+	defer flaky.Close()
+	defer backup.Close()
+
+	flaky.Respond("eth_blockNumber", "0x10")
+	backup.Respond("eth_blockNumber", "0x20")
+
+	ctx := context.Background()
+	multi, err := DialMulti(ctx, []string{flaky.URL, backup.URL}, MultiClientOptions{MaxFailures: 2})
+	if err != nil {
+		t.Fatalf("error dialing: %v", err)
+	}
+	defer multi.Close()
+
+	// transport failures are retried on the next endpoint
+	flaky.Enqueue("eth_blockNumber", mockrpc.Response{Status: 502}, mockrpc.Response{Status: 502})
+	for i := 0; i < 2; i++ {
+		if number, err := multi.BlockNumber(ctx); err != nil || number != 0x20 {
+			t.Fatalf("no failover: %v %v", number, err)
+		}
+	}
+
+	status := multi.Status()
+	if status[0].Healthy || status[0].Failures != 2 || status[0].LastError == "" || !status[1].Healthy {
+		t.Fatalf("invalid status: %+v", status)
+	}
+
+	// unhealthy endpoints are skipped until they recover
+	if number, _ := multi.BlockNumber(ctx); number != 0x20 || len(flaky.Calls("eth_blockNumber")) != 2 {
+		t.Fatalf("unhealthy endpoint used: %v", number)
+	}
+
+	if status := multi.Check(ctx); !status[0].Healthy || status[0].BlockNumber != 0x10 {
+		t.Fatalf("endpoint not recovered: %+v", status)
+	}
+
+	if number, _ := multi.BlockNumber(ctx); number != 0x10 {
+		t.Fatalf("preferred endpoint not used: %v", number)
+	}
+
+	// missing results are no endpoint failure
+	flaky.Respond("eth_getTransactionReceipt", nil)
+	if _, err := multi.TransactionReceipt(ctx, common.Hash{}); !errors.Is(err, ethereum.NotFound) || len(backup.Calls("eth_getTransactionReceipt")) != 0 {
+		t.Fatalf("not found failed over: %v", err)
+	}
+
+	// lagging endpoints are unhealthy, also after calls they answered
+	multi.options.MaxBlockLag = 8
+	if status := multi.Check(ctx); status[0].Healthy || !status[1].Healthy {
+		t.Fatalf("lag not detected: %+v", status)
+	}
+
+	backup.Enqueue("eth_blockNumber", mockrpc.Response{Status: 502})
+	if number, err := multi.BlockNumber(ctx); err != nil || number != 0x10 {
+		t.Fatalf("no failover to the lagging endpoint: %v %v", number, err)
+	}
+	if status := multi.Status(); status[0].Healthy {
+		t.Fatalf("lagging endpoint recovered by a call: %+v", status)
+	}
+
+	// transactions are sent once, without failover
+	backup.Enqueue("eth_sendRawTransaction", mockrpc.Response{Status: 502})
+	if err := multi.SendTransaction(ctx, types.NewTx(&types.LegacyTx{})); err == nil || len(flaky.Calls("eth_sendRawTransaction")) != 0 {
+		t.Fatalf("transaction sent twice: %v", err)
+	}
+
+	// round-robin spreads the calls over the healthy endpoints
+	flaky.Reset()
+	backup.Reset()
+	for _, server := range []*mockrpc.Server{flaky, backup} {
+		server.Respond("eth_blockNumber", "0x20")
+		server.Respond("eth_chainId", "0x1")
+	}
+	multi.options.MaxBlockLag = 0
+	multi.options.RoundRobin = true
+	multi.Check(ctx)
+
+	for i := 0; i < 4; i++ {
+		if _, err := multi.ChainID(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(flaky.Calls("eth_chainId")) != 2 || len(backup.Calls("eth_chainId")) != 2 {
+		t.Fatalf("calls not balanced: %v %v", len(flaky.Calls("eth_chainId")), len(backup.Calls("eth_chainId")))
+	}
+}
@@ -0,0 +1,391 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Marketplaces of the sales normalized by DecodeNFTSales.
+const (
+	MarketplaceSeaport   = "seaport"
+	MarketplaceLooksRare = "looksrare"
+)
+
+// abi_seaport holds the basic order methods and the order events of Seaport.
+const abi_seaport = `[{"inputs":[{"name":"parameters","type":"tuple","components":[{"name":"considerationToken","type":"address"},{"name":"considerationIdentifier","type":"uint256"},{"name":"considerationAmount","type":"uint256"},{"name":"offerer","type":"address"},{"name":"zone","type":"address"},{"name":"offerToken","type":"address"},{"name":"offerIdentifier","type":"uint256"},{"name":"offerAmount","type":"uint256"},{"name":"basicOrderType","type":"uint8"},{"name":"startTime","type":"uint256"},{"name":"endTime","type":"uint256"},{"name":"zoneHash","type":"bytes32"},{"name":"salt","type":"uint256"},{"name":"offererConduitKey","type":"bytes32"},{"name":"fulfillerConduitKey","type":"bytes32"},{"name":"totalOriginalAdditionalRecipients","type":"uint256"},{"name":"additionalRecipients","type":"tuple[]","components":[{"name":"amount","type":"uint256"},{"name":"recipient","type":"address"}]},{"name":"signature","type":"bytes"}]}],"name":"fulfillBasicOrder","outputs":[{"name":"fulfilled","type":"bool"}],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"parameters","type":"tuple","components":[{"name":"considerationToken","type":"address"},{"name":"considerationIdentifier","type":"uint256"},{"name":"considerationAmount","type":"uint256"},{"name":"offerer","type":"address"},{"name":"zone","type":"address"},{"name":"offerToken","type":"address"},{"name":"offerIdentifier","type":"uint256"},{"name":"offerAmount","type":"uint256"},{"name":"basicOrderType","type":"uint8"},{"name":"startTime","type":"uint256"},{"name":"endTime","type":"uint256"},{"name":"zoneHash","type":"bytes32"},{"name":"salt","type":"uint256"},{"name":"offererConduitKey","type":"bytes32"},{"name":"fulfillerConduitKey","type":"bytes32"},{"name":"totalOriginalAdditionalRecipients","type":"uint256"},{"name":"additionalRecipients","type":"tuple[]","components":[{"name":"amount","type":"uint256"},{"name":"recipient","type":"address"}]},{"name":"signature","type":"bytes"}]}],"name":"fulfillBasicOrder_efficient_6GL6yc","outputs":[{"name":"fulfilled","type":"bool"}],"stateMutability":"payable","type":"function"},{"anonymous":false,"inputs":[{"name":"orderHash","type":"bytes32","indexed":false},{"name":"offerer","type":"address","indexed":true},{"name":"zone","type":"address","indexed":true},{"name":"recipient","type":"address","indexed":false},{"name":"offer","type":"tuple[]","components":[{"name":"itemType","type":"uint8"},{"name":"token","type":"address"},{"name":"identifier","type":"uint256"},{"name":"amount","type":"uint256"}],"indexed":false},{"name":"consideration","type":"tuple[]","components":[{"name":"itemType","type":"uint8"},{"name":"token","type":"address"},{"name":"identifier","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"recipient","type":"address"}],"indexed":false}],"name":"OrderFulfilled","type":"event"},{"anonymous":false,"inputs":[{"name":"orderHash","type":"bytes32","indexed":false},{"name":"offerer","type":"address","indexed":true},{"name":"zone","type":"address","indexed":true}],"name":"OrderCancelled","type":"event"},{"anonymous":false,"inputs":[{"name":"newCounter","type":"uint256","indexed":false},{"name":"offerer","type":"address","indexed":true}],"name":"CounterIncremented","type":"event"}]`
+
+// abi_looksrare holds the TakerAsk and TakerBid events of the LooksRare exchange.
+const abi_looksrare = `[{"anonymous":false,"inputs":[{"name":"orderHash","type":"bytes32","indexed":false},{"name":"orderNonce","type":"uint256","indexed":false},{"name":"taker","type":"address","indexed":true},{"name":"maker","type":"address","indexed":true},{"name":"strategy","type":"address","indexed":true},{"name":"currency","type":"address","indexed":false},{"name":"collection","type":"address","indexed":false},{"name":"tokenId","type":"uint256","indexed":false},{"name":"amount","type":"uint256","indexed":false},{"name":"price","type":"uint256","indexed":false}],"name":"TakerAsk","type":"event"},{"anonymous":false,"inputs":[{"name":"orderHash","type":"bytes32","indexed":false},{"name":"orderNonce","type":"uint256","indexed":false},{"name":"taker","type":"address","indexed":true},{"name":"maker","type":"address","indexed":true},{"name":"strategy","type":"address","indexed":true},{"name":"currency","type":"address","indexed":false},{"name":"collection","type":"address","indexed":false},{"name":"tokenId","type":"uint256","indexed":false},{"name":"amount","type":"uint256","indexed":false},{"name":"price","type":"uint256","indexed":false}],"name":"TakerBid","type":"event"}]`
+
+var (
+	seaportAbi   = ParseABI(abi_seaport)
+	looksRareAbi = ParseABI(abi_looksrare)
+
+	orderFulfilledTopic = seaportAbi.Events["OrderFulfilled"].ID
+	takerAskTopic       = looksRareAbi.Events["TakerAsk"].ID
+	takerBidTopic       = looksRareAbi.Events["TakerBid"].ID
+)
+
+// Item types of Seaport offers and considerations.
+const (
+	seaportNative = iota
+	seaportERC20
+	seaportERC721
+	seaportERC1155
+	seaportERC721Criteria
+	seaportERC1155Criteria
+)
+
+// NFTSale is a sale of a single NFT on a marketplace. Sales of bundles are reported per NFT, each
+// with the price of the whole order.
+type NFTSale struct {
+	Marketplace     string   `json:"marketplace"`         // One of the Marketplace* constants.
+	Collection      string   `json:"collection"`          // Contract of the NFT.
+	TokenId         *big.Int `json:"tokenId"`             // Id of the NFT.
+	Amount          *big.Int `json:"amount"`              // Number of tokens sold, 1 for ERC721.
+	Standard        string   `json:"standard"`            // StandardERC721 or StandardERC1155.
+	Price           *big.Int `json:"price"`               // Price paid by the buyer, including fees.
+	Currency        string   `json:"currency"`            // Token paid, EtherAddress for the native currency.
+	Buyer           string   `json:"buyer"`               // Account receiving the NFT.
+	Seller          string   `json:"seller"`              // Account receiving the payment.
+	OrderHash       string   `json:"orderHash,omitempty"` // Hash of the fulfilled order.
+	TransactionHash string   `json:"transactionHash"`     // Transaction hash of the sale.
+	BlockNumber     uint64   `json:"blockNumber"`         // Block number of the sale.
+	LogIndex        uint     `json:"logIndex"`            // Index of the log of the sale.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the NFTSale object.
+func (data *NFTSale) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the NFTSale object.
+func (data *NFTSale) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// seaportItem is an offer or consideration item of an OrderFulfilled event.
+type seaportItem struct {
+	ItemType   uint8
+	Token      common.Address
+	Identifier *big.Int
+	Amount     *big.Int
+}
+
+// seaportReceivedItem is a consideration item of an OrderFulfilled event.
+type seaportReceivedItem struct {
+	ItemType   uint8
+	Token      common.Address
+	Identifier *big.Int
+	Amount     *big.Int
+	Recipient  common.Address
+}
+
+// basicOrder is the parameters tuple of fulfillBasicOrder.
+type basicOrder struct {
+	ConsiderationToken                common.Address
+	ConsiderationIdentifier           *big.Int
+	ConsiderationAmount               *big.Int
+	Offerer                           common.Address
+	Zone                              common.Address
+	OfferToken                        common.Address
+	OfferIdentifier                   *big.Int
+	OfferAmount                       *big.Int
+	BasicOrderType                    uint8
+	StartTime                         *big.Int
+	EndTime                           *big.Int
+	ZoneHash                          [32]byte
+	Salt                              *big.Int
+	OffererConduitKey                 [32]byte
+	FulfillerConduitKey               [32]byte
+	TotalOriginalAdditionalRecipients *big.Int
+	AdditionalRecipients              []struct {
+		Amount    *big.Int
+		Recipient common.Address
+	}
+	Signature []byte
+}
+
+// IsNFTSaleLog returns true if the log is a Seaport OrderFulfilled or a LooksRare TakerAsk or
+// TakerBid event.
+func IsNFTSaleLog(vLog *types.Log) bool {
+	if len(vLog.Topics) == 0 {
+		return false
+	}
+
+	switch vLog.Topics[0] {
+	case orderFulfilledTopic, takerAskTopic, takerBidTopic:
+		return true
+	}
+
+	return false
+}
+
+// DecodeNFTSales normalizes a sale log of Seaport or LooksRare into the NFTs sold. Seaport orders
+// offering NFTs are listings bought by the recipient of the order, orders offering a payment are
+// bids accepted by the recipient. Orders exchanging no NFT, e.g. the counter orders of
+// matchOrders, have no sales. It returns an error for logs of other events.
+func DecodeNFTSales(vLog *types.Log) ([]NFTSale, error) {
+	if !IsNFTSaleLog(vLog) {
+		return nil, fmt.Errorf("log %d is no nft sale", vLog.Index)
+	}
+
+	if vLog.Topics[0] == orderFulfilledTopic {
+		return decodeOrderFulfilled(vLog)
+	}
+
+	return decodeTakerOrder(vLog)
+}
+
+// ExtractNFTSales normalizes all sale logs, skipping other logs and sales that can not be
+// decoded.
+func ExtractNFTSales(vLogs []*types.Log) []NFTSale {
+	result := make([]NFTSale, 0)
+
+	for _, vLog := range vLogs {
+		if vLog.Removed || !IsNFTSaleLog(vLog) {
+			continue
+		}
+
+		if sales, err := DecodeNFTSales(vLog); err == nil {
+			result = append(result, sales...)
+		}
+	}
+
+	return result
+}
+
+// DecodeBasicOrder normalizes a Seaport fulfillBasicOrder call into the NFT sold. The sender of
+// the transaction fulfills the order, it is the buyer of listings and the seller of accepted
+// bids. Fees paid to additional recipients are part of the price.
+func DecodeBasicOrder(tx *types.Transaction) (*NFTSale, error) {
+	data := tx.Data()
+	if len(data) < 4 {
+		return nil, fmt.Errorf("transaction %s is no basic order", tx.Hash().Hex())
+	}
+
+	method, err := seaportAbi.MethodById(data[:4])
+	if err != nil || len(method.Inputs) != 1 {
+		return nil, fmt.Errorf("transaction %s is no basic order", tx.Hash().Hex())
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method.Name, err)
+	}
+	order := *abi.ConvertType(values[0], new(basicOrder)).(*basicOrder)
+
+	fulfiller, err := txSender(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NFTSale{
+		Marketplace:     MarketplaceSeaport,
+		TransactionHash: tx.Hash().Hex(),
+	}
+
+	// the route of the order is encoded in the basic order type, four types per route
+	switch route := order.BasicOrderType / 4; route {
+	case 0, 1, 2, 3:
+		// ETH or ERC20 paid for an offered ERC721 or ERC1155
+		result.Collection = order.OfferToken.Hex()
+		result.TokenId = order.OfferIdentifier
+		result.Amount = order.OfferAmount
+		result.Standard = StandardERC721
+		if route%2 == 1 {
+			result.Standard = StandardERC1155
+		}
+
+		result.Price = new(big.Int).Set(order.ConsiderationAmount)
+		for _, recipient := range order.AdditionalRecipients {
+			result.Price.Add(result.Price, recipient.Amount)
+		}
+
+		result.Currency = EtherAddress
+		if route >= 2 {
+			result.Currency = order.ConsiderationToken.Hex()
+		}
+		result.Buyer = fulfiller.Hex()
+		result.Seller = order.Offerer.Hex()
+
+	case 4, 5:
+		// ERC20 offered for an ERC721 or ERC1155
+		result.Collection = order.ConsiderationToken.Hex()
+		result.TokenId = order.ConsiderationIdentifier
+		result.Amount = order.ConsiderationAmount
+		result.Standard = StandardERC721
+		if route == 5 {
+			result.Standard = StandardERC1155
+		}
+
+		result.Price = order.OfferAmount
+		result.Currency = order.OfferToken.Hex()
+		result.Buyer = order.Offerer.Hex()
+		result.Seller = fulfiller.Hex()
+
+	default:
+		return nil, fmt.Errorf("invalid basic order type %d", order.BasicOrderType)
+	}
+
+	return &result, nil
+}
+
+// decodeOrderFulfilled normalizes a Seaport OrderFulfilled log.
+func decodeOrderFulfilled(vLog *types.Log) ([]NFTSale, error) {
+	if len(vLog.Topics) != 3 {
+		return nil, fmt.Errorf("invalid topics of log %d", vLog.Index)
+	}
+
+	values, err := seaportAbi.Events["OrderFulfilled"].Inputs.NonIndexed().Unpack(vLog.Data)
+	if err != nil || len(values) != 4 {
+		return nil, fmt.Errorf("invalid data of log %d: %v", vLog.Index, err)
+	}
+
+	orderHash := values[0].([32]byte)
+	recipient := values[1].(common.Address)
+	offer := *abi.ConvertType(values[2], new([]seaportItem)).(*[]seaportItem)
+	consideration := *abi.ConvertType(values[3], new([]seaportReceivedItem)).(*[]seaportReceivedItem)
+
+	received := make([]seaportItem, 0, len(consideration))
+	for _, item := range consideration {
+		received = append(received, seaportItem{item.ItemType, item.Token, item.Identifier, item.Amount})
+	}
+
+	offerer := topicAddress(vLog.Topics[1])
+	nfts, payments := offer, received
+	buyer, seller := recipient, offerer
+	if !hasNFT(offer) {
+		// a bid: the offerer pays for the NFTs of its consideration
+		nfts, payments = received, offer
+		buyer, seller = offerer, recipient
+	}
+
+	currency, price := seaportPayment(payments)
+	result := make([]NFTSale, 0)
+	for _, item := range nfts {
+		standard := seaportStandard(item.ItemType)
+		if standard == "" {
+			continue
+		}
+
+		result = append(result, NFTSale{
+			Marketplace:     MarketplaceSeaport,
+			Collection:      item.Token.Hex(),
+			TokenId:         item.Identifier,
+			Amount:          item.Amount,
+			Standard:        standard,
+			Price:           price,
+			Currency:        currency,
+			Buyer:           buyer.Hex(),
+			Seller:          seller.Hex(),
+			OrderHash:       common.Hash(orderHash).Hex(),
+			TransactionHash: vLog.TxHash.Hex(),
+			BlockNumber:     vLog.BlockNumber,
+			LogIndex:        vLog.Index,
+		})
+	}
+
+	return result, nil
+}
+
+// decodeTakerOrder normalizes a LooksRare TakerAsk or TakerBid log. The taker of a TakerAsk sells
+// to the maker of a bid, the taker of a TakerBid buys from the maker of a listing. The events do
+// not tell the token standard, sales of more than one token are taken for ERC1155.
+func decodeTakerOrder(vLog *types.Log) ([]NFTSale, error) {
+	if len(vLog.Topics) != 4 {
+		return nil, fmt.Errorf("invalid topics of log %d", vLog.Index)
+	}
+
+	values, err := looksRareAbi.Events["TakerAsk"].Inputs.NonIndexed().Unpack(vLog.Data)
+	if err != nil || len(values) != 7 {
+		return nil, fmt.Errorf("invalid data of log %d: %v", vLog.Index, err)
+	}
+
+	taker := topicAddress(vLog.Topics[1])
+	maker := topicAddress(vLog.Topics[2])
+	buyer, seller := maker, taker
+	if vLog.Topics[0] == takerBidTopic {
+		buyer, seller = taker, maker
+	}
+
+	amount := values[5].(*big.Int)
+	standard := StandardERC721
+	if amount.Cmp(big.NewInt(1)) > 0 {
+		standard = StandardERC1155
+	}
+
+	return []NFTSale{{
+		Marketplace:     MarketplaceLooksRare,
+		Collection:      values[3].(common.Address).Hex(),
+		TokenId:         values[4].(*big.Int),
+		Amount:          amount,
+		Standard:        standard,
+		Price:           values[6].(*big.Int),
+		Currency:        values[2].(common.Address).Hex(),
+		Buyer:           buyer.Hex(),
+		Seller:          seller.Hex(),
+		OrderHash:       common.Hash(values[0].([32]byte)).Hex(),
+		TransactionHash: vLog.TxHash.Hex(),
+		BlockNumber:     vLog.BlockNumber,
+		LogIndex:        vLog.Index,
+	}}, nil
+}
+
+// hasNFT returns true if one of the items is an ERC721 or ERC1155 token.
+func hasNFT(items []seaportItem) bool {
+	for _, item := range items {
+		if seaportStandard(item.ItemType) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// seaportStandard returns the token standard of an NFT item type, or an empty string for payments.
+func seaportStandard(itemType uint8) string {
+	switch itemType {
+	case seaportERC721, seaportERC721Criteria:
+		return StandardERC721
+	case seaportERC1155, seaportERC1155Criteria:
+		return StandardERC1155
+	}
+
+	return ""
+}
+
+// seaportPayment sums the payment items in the currency of the first payment item.
+func seaportPayment(items []seaportItem) (string, *big.Int) {
+	price := new(big.Int)
+	var currency *common.Address
+
+	for _, item := range items {
+		if item.ItemType != seaportNative && item.ItemType != seaportERC20 {
+			continue
+		}
+
+		token := item.Token
+		if item.ItemType == seaportNative {
+			token = common.Address{}
+		}
+		if currency == nil {
+			currency = &token
+		}
+		if token == *currency {
+			price.Add(price, item.Amount)
+		}
+	}
+
+	if currency == nil || *currency == (common.Address{}) {
+		return EtherAddress, price
+	}
+
+	return currency.Hex(), price
+}
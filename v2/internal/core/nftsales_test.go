@@ -0,0 +1,138 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDecodeNFTSales(t *testing.T) {
+	seller := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	buyer := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	fees := common.HexToAddress("0x0000000000000000000000000000000000000fee")
+	collection := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	zone := common.Hash{}
+
+	fulfilled := func(offerer common.Address, recipient common.Address, offer []seaportItem, consideration []seaportReceivedItem) *types.Log {
+		data, err := seaportAbi.Events["OrderFulfilled"].Inputs.NonIndexed().Pack([32]byte{1}, recipient, offer, consideration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &types.Log{Topics: []common.Hash{orderFulfilledTopic, offerer.Hash(), zone}, Data: data}
+	}
+
+	// a listing of an ERC721 bought for 100 wei, 10 of them paid as fees
+	listing := fulfilled(seller, buyer,
+		[]seaportItem{{seaportERC721, collection, big.NewInt(7), big.NewInt(1)}},
+		[]seaportReceivedItem{
+			{seaportNative, common.Address{}, big.NewInt(0), big.NewInt(90), seller},
+			{seaportNative, common.Address{}, big.NewInt(0), big.NewInt(10), fees},
+		},
+	)
+
+	// a bid of 50 WETH for two ERC1155 tokens accepted by the seller
+	bid := fulfilled(buyer, seller,
+		[]seaportItem{{seaportERC20, weth, big.NewInt(0), big.NewInt(50)}},
+		[]seaportReceivedItem{
+			{seaportERC1155Criteria, collection, big.NewInt(3), big.NewInt(2), buyer},
+			{seaportERC20, weth, big.NewInt(0), big.NewInt(1), fees},
+		},
+	)
+
+	// the counter order of matchOrders exchanges no NFT
+	counter := fulfilled(buyer, buyer,
+		[]seaportItem{{seaportNative, common.Address{}, big.NewInt(0), big.NewInt(100)}},
+		[]seaportReceivedItem{},
+	)
+
+	takerData, err := looksRareAbi.Events["TakerBid"].Inputs.NonIndexed().Pack([32]byte{2}, big.NewInt(0), weth, collection, big.NewInt(9), big.NewInt(1), big.NewInt(30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taker := &types.Log{Topics: []common.Hash{takerBidTopic, buyer.Hash(), seller.Hash(), common.Hash{}}, Data: takerData}
+
+	sales := ExtractNFTSales([]*types.Log{listing, bid, counter, {Topics: []common.Hash{transferTopic}}, taker})
+	expected := []struct {
+		standard string
+		tokenId  int64
+		amount   int64
+		price    int64
+		currency string
+		buyer    common.Address
+		seller   common.Address
+	}{
+		{StandardERC721, 7, 1, 100, EtherAddress, buyer, seller},
+		{StandardERC1155, 3, 2, 50, weth.Hex(), buyer, seller},
+		{StandardERC721, 9, 1, 30, weth.Hex(), buyer, seller},
+	}
+
+	if len(sales) != len(expected) {
+		t.Fatalf("unexpected sales: %d", len(sales))
+	}
+
+	for i, want := range expected {
+		got := sales[i]
+		if got.Collection != collection.Hex() || got.Standard != want.standard || got.TokenId.Int64() != want.tokenId || got.Amount.Int64() != want.amount {
+			t.Fatalf("unexpected nft of sale %d: %s", i, got.ToJSON())
+		}
+		if got.Price.Int64() != want.price || got.Currency != want.currency || got.Buyer != want.buyer.Hex() || got.Seller != want.seller.Hex() {
+			t.Fatalf("unexpected payment of sale %d: %s", i, got.ToJSON())
+		}
+	}
+
+	if sales[2].Marketplace != MarketplaceLooksRare {
+		t.Fatalf("unexpected marketplace: %s", sales[2].Marketplace)
+	}
+}
+
+func TestDecodeBasicOrder(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	fulfiller := crypto.PubkeyToAddress(key.PublicKey)
+	seller := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	collection := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	seaport := common.HexToAddress("0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC")
+
+	order := basicOrder{
+		ConsiderationAmount:     big.NewInt(95),
+		ConsiderationIdentifier: big.NewInt(0),
+		Offerer:                 seller,
+		OfferToken:              collection,
+		OfferIdentifier:         big.NewInt(42),
+		OfferAmount:             big.NewInt(1),
+		StartTime:               big.NewInt(0),
+		EndTime:                 big.NewInt(0),
+		Salt:                    big.NewInt(0),
+
+		TotalOriginalAdditionalRecipients: big.NewInt(1),
+	}
+	order.AdditionalRecipients = append(order.AdditionalRecipients, struct {
+		Amount    *big.Int
+		Recipient common.Address
+	}{big.NewInt(5), common.HexToAddress("0x0000000000000000000000000000000000000fee")})
+
+	data, err := seaportAbi.Pack("fulfillBasicOrder", order)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{ChainID: big.NewInt(1), To: &seaport, Value: big.NewInt(100), Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sale, err := DecodeBasicOrder(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sale.Collection != collection.Hex() || sale.TokenId.Int64() != 42 || sale.Standard != StandardERC721 || sale.Price.Int64() != 100 || sale.Currency != EtherAddress {
+		t.Fatalf("unexpected sale: %s", sale.ToJSON())
+	}
+	if sale.Buyer != fulfiller.Hex() || sale.Seller != seller.Hex() {
+		t.Fatalf("unexpected parties: %s", sale.ToJSON())
+	}
+}
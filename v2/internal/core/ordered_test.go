@@ -0,0 +1,46 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParamsOrdered(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+
+	from := common.HexToAddress(target_contract)
+	to := common.HexToAddress(target_erc721)
+	topics := []common.Hash{
+		decoder.Abi.Events["Transfer"].ID,
+		common.BytesToHash(from.Bytes()),
+		common.BytesToHash(to.Bytes()),
+	}
+
+	decoded := decoder.DecodeRawLog(topics, common.LeftPadBytes(big.NewInt(1000).Bytes(), 32))
+	if decoded == nil {
+		t.Fatal("raw log not decoded")
+	}
+
+	if ordered := decoded.ParamsOrdered; len(ordered) != 3 || ordered[0].Name != "from" || ordered[0].Value != from.Hex() || !ordered[0].Indexed || ordered[2].Value != "1000" {
+		t.Fatalf("invalid ordered params: %+v", ordered)
+	}
+
+	// the order of the signature is kept for methods, whose params are not indexed
+	data, _ := decoder.Abi.Pack("transferFrom", from, to, big.NewInt(5))
+	method, err := decoder.DecodeCalldata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ordered := method.ParamsOrdered
+	if len(ordered) != 3 || ordered[0].Value != from.Hex() || ordered[1].Value != to.Hex() || ordered[2].Value != "5" || ordered[0].Indexed {
+		t.Fatalf("invalid ordered method params: %+v", ordered)
+	}
+	for i, input := range decoder.Abi.Methods["transferFrom"].Inputs {
+		if ordered[i].Name != input.Name {
+			t.Fatalf("param %d out of order: %s, expected %s", i, ordered[i].Name, input.Name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// withABI returns a copy of the decoder using the given ABI, keeping all other options.
+func (decoder *AbiDecoder) withABI(contractAbi abi.ABI) *AbiDecoder {
+	override := *decoder
+	override.Abi = &contractAbi
+	return &override
+}
+
+// DecodeLogWithABI is like DecodeLog, using the given ABI instead of the decoder's for this call.
+func (decoder *AbiDecoder) DecodeLogWithABI(vLog *types.Log, contractAbi abi.ABI) *DecodedLog {
+	return decoder.withABI(contractAbi).DecodeLog(vLog)
+}
+
+// DecodeLogsWithABI is like DecodeLogs, using the given ABI instead of the decoder's for this call.
+func (decoder *AbiDecoder) DecodeLogsWithABI(vLogs []*types.Log, contractAbi abi.ABI) []*DecodedLog {
+	return decoder.withABI(contractAbi).DecodeLogs(vLogs)
+}
+
+// DecodeMethodWithABI is like DecodeMethod, using the given ABI instead of the decoder's for this
+// call.
+func (decoder *AbiDecoder) DecodeMethodWithABI(tx *types.Transaction, contractAbi abi.ABI) *DecodedMethod {
+	return decoder.withABI(contractAbi).DecodeMethod(tx)
+}
+
+// DecodeCalldataWithABI is like DecodeCalldata, using the given ABI instead of the decoder's for
+// this call.
+func (decoder *AbiDecoder) DecodeCalldataWithABI(data []byte, contractAbi abi.ABI) (*DecodedMethod, error) {
+	return decoder.withABI(contractAbi).DecodeCalldata(data)
+}
+
+// DecodeTransactionWithABI is like DecodeTransaction, using the given ABI instead of the
+// decoder's for this call.
+func (decoder *AbiDecoder) DecodeTransactionWithABI(transactionHash string, contractAbi abi.ABI) (*DecodedMethod, error) {
+	return decoder.withABI(contractAbi).DecodeTransaction(transactionHash)
+}
+
+// DecodeReceiptWithABI is like DecodeReceipt, using the given ABI instead of the decoder's for
+// this call.
+func (decoder *AbiDecoder) DecodeReceiptWithABI(transactionHash string, contractAbi abi.ABI) (*ScannedLogs, error) {
+	return decoder.withABI(contractAbi).DecodeReceipt(transactionHash)
+}
+
+// decoder returns a decoder for the given ABI with the options of the store.
+func (store *Storage) decoder(contractAbi abi.ABI) *AbiDecoder {
+	return &AbiDecoder{
+		Abi:           &contractAbi,
+		Anonymous:     store.Anonymous,
+		Compliance:    store.Compliance,
+		PreserveTypes: store.PreserveTypes,
+		KeepUnknown:   store.KeepUnknown,
+		Attestor:      store.Attestor,
+	}
+}
+
+// DecodeLogWithABI decodes the log with the given ABI only, applying the options of the store.
+// The ABI is not added to AbiList.
+func (store *Storage) DecodeLogWithABI(vLog *types.Log, contractAbi abi.ABI) *DecodedLog {
+	return store.decoder(contractAbi).DecodeLog(vLog)
+}
+
+// DecodeMethodWithABI decodes the transaction with the given ABI only, applying the options of
+// the store. The ABI is not added to AbiList.
+func (store *Storage) DecodeMethodWithABI(tx *types.Transaction, contractAbi abi.ABI) *DecodedMethod {
+	return store.decoder(contractAbi).DecodeMethod(tx)
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Standards of the permits decoded by DecodePermits.
+const (
+	PermitEIP2612 = "eip2612"
+	PermitPermit2 = "permit2"
+)
+
+// abi_permit holds the permit method of EIP-2612 tokens.
+const abi_permit = `[{"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"},{"name":"value","type":"uint256"},{"name":"deadline","type":"uint256"},{"name":"v","type":"uint8"},{"name":"r","type":"bytes32"},{"name":"s","type":"bytes32"}],"name":"permit","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+var (
+	permitAbi  = ParseABI(abi_permit)
+	permit2Abi = ParseABI(abi_permit2)
+)
+
+// PermitInfo is a signed token approval passed in calldata, see DecodePermits. Batch permits of
+// Permit2 are listed per token, sharing the signature.
+type PermitInfo struct {
+	Standard   string   `json:"standard"`             // PermitEIP2612 or PermitPermit2.
+	Owner      string   `json:"owner"`                // Account granting the allowance, the expected signer.
+	Spender    string   `json:"spender"`              // Account allowed to spend, empty if it is the caller.
+	Token      string   `json:"token"`                // Token of the allowance.
+	Amount     *big.Int `json:"amount"`               // Allowance granted, or transferred by permitTransferFrom.
+	Nonce      *big.Int `json:"nonce,omitempty"`      // Nonce of the signature, not part of EIP-2612 calldata.
+	Expiration *big.Int `json:"expiration,omitempty"` // Expiry of a Permit2 allowance.
+	Deadline   *big.Int `json:"deadline"`             // Expiry of the signature.
+	Recipient  string   `json:"recipient,omitempty"`  // Receiver of the tokens of permitTransferFrom.
+	Signature  string   `json:"signature"`            // Signature of the owner.
+	Signer     string   `json:"signer,omitempty"`     // Address recovered from the signature, see Verify.
+	Verified   bool     `json:"verified"`             // Whether the signer is the owner.
+
+	contract    string                    // verifying contract of the signature
+	primaryType string                    // EIP-712 type of the signed message, see TypedDataTypes
+	message     apitypes.TypedDataMessage // signed message, completed with Nonce or Spender by Verify
+}
+
+// permit2Details is the PermitDetails struct of Permit2.
+type permit2Details struct {
+	Token      common.Address
+	Amount     *big.Int
+	Expiration *big.Int
+	Nonce      *big.Int
+}
+
+// permit2Single is the PermitSingle struct of Permit2.
+type permit2Single struct {
+	Details     permit2Details
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// permit2Batch is the PermitBatch struct of Permit2.
+type permit2Batch struct {
+	Details     []permit2Details
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// permit2Transfer is the PermitTransferFrom struct of Permit2.
+type permit2Transfer struct {
+	Permitted struct {
+		Token  common.Address
+		Amount *big.Int
+	}
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// permit2TransferDetails is the SignatureTransferDetails struct of Permit2.
+type permit2TransferDetails struct {
+	To              common.Address
+	RequestedAmount *big.Int
+}
+
+// DecodePermits decodes the permits in calldata of the EIP-2612 permit method of a token, or of
+// the permit and permitTransferFrom methods of Permit2. It returns nil for other calldata. The
+// permits are not verified, see Verify and VerifyPermits.
+func DecodePermits(contract string, data []byte) []PermitInfo {
+	if len(data) < 4 {
+		return nil
+	}
+
+	if method, err := permitAbi.MethodById(data[:4]); err == nil {
+		return decodeEIP2612Permit(contract, method, data[4:])
+	}
+
+	method, err := permit2Abi.MethodById(data[:4])
+	if err != nil || (method.RawName != "permit" && method.RawName != "permitTransferFrom") {
+		return nil
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil
+	}
+
+	if method.RawName == "permitTransferFrom" {
+		// batch transfers are not decoded
+		if method.Inputs[1].Type.T != abi.TupleTy {
+			return nil
+		}
+		return decodePermitTransfer(contract, values)
+	}
+
+	owner := values[0].(common.Address)
+	signature := values[2].([]byte)
+	var spender common.Address
+	var deadline *big.Int
+	var details []permit2Details
+
+	primaryType := "PermitSingle"
+	if method.Inputs[1].Name == "permitBatch" {
+		primaryType = "PermitBatch"
+		batch := *abi.ConvertType(values[1], new(permit2Batch)).(*permit2Batch)
+		spender, deadline, details = batch.Spender, batch.SigDeadline, batch.Details
+	} else {
+		single := *abi.ConvertType(values[1], new(permit2Single)).(*permit2Single)
+		spender, deadline, details = single.Spender, single.SigDeadline, []permit2Details{single.Details}
+	}
+
+	messages := make([]interface{}, 0, len(details))
+	for _, detail := range details {
+		messages = append(messages, map[string]interface{}{
+			"token":      detail.Token.Hex(),
+			"amount":     detail.Amount.String(),
+			"expiration": detail.Expiration.String(),
+			"nonce":      detail.Nonce.String(),
+		})
+	}
+
+	message := apitypes.TypedDataMessage{
+		"details":     messages,
+		"spender":     spender.Hex(),
+		"sigDeadline": deadline.String(),
+	}
+	if primaryType == "PermitSingle" {
+		message["details"] = messages[0]
+	}
+
+	result := make([]PermitInfo, 0, len(details))
+	for _, detail := range details {
+		result = append(result, PermitInfo{
+			Standard:    PermitPermit2,
+			Owner:       owner.Hex(),
+			Spender:     spender.Hex(),
+			Token:       detail.Token.Hex(),
+			Amount:      detail.Amount,
+			Nonce:       detail.Nonce,
+			Expiration:  detail.Expiration,
+			Deadline:    deadline,
+			Signature:   hexutil.Encode(signature),
+			contract:    contract,
+			primaryType: primaryType,
+			message:     message,
+		})
+	}
+
+	return result
+}
+
+// decodeEIP2612Permit decodes the arguments of an EIP-2612 permit call of the token.
+func decodeEIP2612Permit(token string, method *abi.Method, data []byte) []PermitInfo {
+	values, err := method.Inputs.Unpack(data)
+	if err != nil {
+		return nil
+	}
+
+	owner := values[0].(common.Address)
+	spender := values[1].(common.Address)
+	value := values[2].(*big.Int)
+	deadline := values[3].(*big.Int)
+	v, r, s := values[4].(uint8), values[5].([32]byte), values[6].([32]byte)
+
+	return []PermitInfo{{
+		Standard:    PermitEIP2612,
+		Owner:       owner.Hex(),
+		Spender:     spender.Hex(),
+		Token:       common.HexToAddress(token).Hex(),
+		Amount:      value,
+		Deadline:    deadline,
+		Signature:   hexutil.Encode(append(append(r[:], s[:]...), v)),
+		contract:    token,
+		primaryType: "Permit",
+		message: apitypes.TypedDataMessage{
+			"owner":    owner.Hex(),
+			"spender":  spender.Hex(),
+			"value":    value.String(),
+			"deadline": deadline.String(),
+		},
+	}}
+}
+
+// decodePermitTransfer decodes the arguments of a Permit2 permitTransferFrom call. The spender of
+// the signature is the caller of Permit2, which is not part of the calldata.
+func decodePermitTransfer(contract string, values []interface{}) []PermitInfo {
+	permit := *abi.ConvertType(values[0], new(permit2Transfer)).(*permit2Transfer)
+	transfer := *abi.ConvertType(values[1], new(permit2TransferDetails)).(*permit2TransferDetails)
+
+	return []PermitInfo{{
+		Standard:    PermitPermit2,
+		Owner:       values[2].(common.Address).Hex(),
+		Token:       permit.Permitted.Token.Hex(),
+		Amount:      transfer.RequestedAmount,
+		Nonce:       permit.Nonce,
+		Deadline:    permit.Deadline,
+		Recipient:   transfer.To.Hex(),
+		Signature:   hexutil.Encode(values[3].([]byte)),
+		contract:    contract,
+		primaryType: "PermitTransferFrom",
+		message: apitypes.TypedDataMessage{
+			"permitted": map[string]interface{}{
+				"token":  permit.Permitted.Token.Hex(),
+				"amount": permit.Permitted.Amount.String(),
+			},
+			"nonce":    permit.Nonce.String(),
+			"deadline": permit.Deadline.String(),
+		},
+	}}
+}
+
+// Verify recovers the signer of the permit with the EIP-712 domain of the verifying contract and
+// sets Signer and Verified. EIP-2612 permits need the Nonce of the owner, and permitTransferFrom
+// permits the Spender, to be set before, as they are not part of the calldata.
+func (permit *PermitInfo) Verify(domain apitypes.TypedDataDomain) error {
+	if permit.primaryType == "" {
+		return fmt.Errorf("permit of %s was not decoded", permit.Owner)
+	}
+
+	message := make(apitypes.TypedDataMessage, len(permit.message)+1)
+	for key, value := range permit.message {
+		message[key] = value
+	}
+
+	switch permit.primaryType {
+	case "Permit":
+		if permit.Nonce == nil {
+			return fmt.Errorf("nonce of permit of %s not set", permit.Owner)
+		}
+		message["nonce"] = permit.Nonce.String()
+	case "PermitTransferFrom":
+		if permit.Spender == "" {
+			return fmt.Errorf("spender of permit of %s not set", permit.Owner)
+		}
+		message["spender"] = permit.Spender
+	}
+
+	signature, err := hexutil.Decode(permit.Signature)
+	if err != nil {
+		return err
+	}
+
+	typed := NewTypedData(TypedDataTypes[permit.primaryType], permit.primaryType, domain, message)
+	signer, err := RecoverTypedDataSigner(typed, signature)
+	if err != nil {
+		return err
+	}
+
+	permit.Signer = signer.Hex()
+	permit.Verified = signer.Hex() == permit.Owner
+	return nil
+}
+
+// VerifyPermits verifies the Permit2 permits of the decoded method and its nested calls with the
+// domain of Permit2 on the chain. EIP-2612 permits depend on the domain of the token and are left
+// to Verify.
+func VerifyPermits(decoded *DecodedMethod, chainId *big.Int) {
+	if decoded == nil || chainId == nil || chainId.Sign() <= 0 {
+		return
+	}
+
+	for i := range decoded.Permits {
+		permit := &decoded.Permits[i]
+		if permit.Standard != PermitPermit2 || permit.Spender == "" {
+			continue
+		}
+
+		permit.Verify(apitypes.TypedDataDomain{
+			Name:              "Permit2",
+			ChainId:           (*math.HexOrDecimal256)(chainId),
+			VerifyingContract: common.HexToAddress(permit.contract).Hex(),
+		})
+	}
+
+	for _, call := range decoded.Calls {
+		VerifyPermits(call, chainId)
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+func TestDecodePermits(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	permit2 := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	token := common.HexToAddress(target_erc20)
+	router := common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD")
+
+	sign := func(typed apitypes.TypedData) []byte {
+		digest, err := TypedDataDigest(typed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature, err := crypto.Sign(digest.Bytes(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature[crypto.RecoveryIDOffset] += 27
+		return signature
+	}
+
+	// Permit2 permit, verified with the chain id of the transaction
+	single := permit2Single{
+		Details:     permit2Details{token, big.NewInt(1000), big.NewInt(1700000000), big.NewInt(0)},
+		Spender:     router,
+		SigDeadline: big.NewInt(1700000001),
+	}
+	domain := apitypes.TypedDataDomain{Name: "Permit2", ChainId: math.NewHexOrDecimal256(1), VerifyingContract: permit2.Hex()}
+	signature := sign(NewTypedData(TypedDataTypes["PermitSingle"], "PermitSingle", domain, apitypes.TypedDataMessage{
+		"details":     map[string]interface{}{"token": token.Hex(), "amount": "1000", "expiration": "1700000000", "nonce": "0"},
+		"spender":     router.Hex(),
+		"sigDeadline": "1700000001",
+	}))
+
+	data, err := permit2Abi.Pack("permit", owner, single, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &Storage{}
+	if err := store.AddDefaultABIs("Permit2"); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{ChainID: big.NewInt(1), To: &permit2, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := store.DecodeMethod(tx)
+	if decoded == nil || len(decoded.Permits) != 1 {
+		t.Fatalf("permit not decoded: %+v", decoded)
+	}
+
+	permit := decoded.Permits[0]
+	if permit.Standard != PermitPermit2 || permit.Owner != owner.Hex() || permit.Spender != router.Hex() || permit.Token != token.Hex() || permit.Amount.Int64() != 1000 || permit.Deadline.Int64() != 1700000001 {
+		t.Fatalf("unexpected permit: %+v", permit)
+	}
+	if !permit.Verified || permit.Signer != owner.Hex() {
+		t.Fatalf("permit not verified: %+v", permit)
+	}
+
+	// EIP-2612 permit, the nonce is read from the token
+	tokenDomain := apitypes.TypedDataDomain{Name: "Token", Version: "1", ChainId: math.NewHexOrDecimal256(1), VerifyingContract: token.Hex()}
+	signature = sign(NewTypedData(TypedDataTypes["Permit"], "Permit", tokenDomain, apitypes.TypedDataMessage{
+		"owner":    owner.Hex(),
+		"spender":  router.Hex(),
+		"value":    "5",
+		"nonce":    "7",
+		"deadline": "1700000002",
+	}))
+
+	var r, s [32]byte
+	copy(r[:], signature[:32])
+	copy(s[:], signature[32:64])
+	data, err = permitAbi.Pack("permit", owner, router, big.NewInt(5), big.NewInt(1700000002), signature[64], r, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	permits := DecodePermits(token.Hex(), data)
+	if len(permits) != 1 || permits[0].Standard != PermitEIP2612 || permits[0].Amount.Int64() != 5 || permits[0].Token != token.Hex() {
+		t.Fatalf("unexpected permits: %+v", permits)
+	}
+
+	if err := permits[0].Verify(tokenDomain); err == nil {
+		t.Fatal("verified a permit without nonce")
+	}
+
+	permits[0].Nonce = big.NewInt(7)
+	if err := permits[0].Verify(tokenDomain); err != nil || !permits[0].Verified {
+		t.Fatalf("permit not verified: %v", err)
+	}
+
+	if DecodePermits(token.Hex(), []byte{1, 2, 3, 4}) != nil {
+		t.Fatal("decoded permits of other calldata")
+	}
+}
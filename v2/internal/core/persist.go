@@ -0,0 +1,357 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// storeSnapshotVersion is the version of the file format written by Storage.Save.
+const storeSnapshotVersion = 1
+
+// storeSnapshot is the file format of Storage.Save, holding the ABIs as JSON ABIs, so they can be
+// parsed back with abi.JSON.
+type storeSnapshot struct {
+	Version int                `json:"version"`
+	Abis    []snapshotABI      `json:"abis"`
+	Indexed []snapshotContract `json:"indexed"`
+}
+
+type snapshotABI struct {
+	Name string          `json:"name,omitempty"` // Source name of the ABI, see AddABI.
+	Abi  json.RawMessage `json:"abi"`
+}
+
+type snapshotContract struct {
+	Key      string            `json:"key"` // Key of the contract in Indexed.
+	Address  common.Address    `json:"address"`
+	Abi      json.RawMessage   `json:"abi"`
+	Bytecode *string           `json:"bytecode,omitempty"`
+	IsToken  bool              `json:"isToken"`
+	Verified bool              `json:"verified"`
+	IsERC721 *bool             `json:"isERC721,omitempty"`
+	Name     *string           `json:"name,omitempty"`
+	Pragma   *string           `json:"pragma,omitempty"`
+	Source   *string           `json:"source,omitempty"`
+	Guessed  bool              `json:"guessed,omitempty"`
+	Metadata *ContractMetadata `json:"metadata,omitempty"`
+	Versions []snapshotVersion `json:"versions,omitempty"`
+}
+
+type snapshotVersion struct {
+	Range BlockRange      `json:"range"`
+	Abi   json.RawMessage `json:"abi"`
+}
+
+// Save writes the ABIs of AbiList, with their source names, and all indexed contracts, with their
+// bytecode, flags and versions, to a JSON file at path, which is replaced atomically. Load reads
+// it back without parsing the original ABIs or fetching bytecode again.
+func (store *Storage) Save(path string) error {
+	snapshot := storeSnapshot{
+		Version: storeSnapshotVersion,
+		Abis:    make([]snapshotABI, 0, len(store.AbiList)),
+		Indexed: make([]snapshotContract, 0, len(store.Indexed)),
+	}
+
+	for i, contractAbi := range store.AbiList {
+		data, err := MarshalABI(contractAbi)
+		if err != nil {
+			return err
+		}
+
+		snapshot.Abis = append(snapshot.Abis, snapshotABI{Name: store.names[i], Abi: data})
+	}
+
+	keys := store.IndexedAddresses()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		contract, err := snapshotIndexed(key, store.Indexed[key])
+		if err != nil {
+			return err
+		}
+
+		snapshot.Indexed = append(snapshot.Indexed, *contract)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// Load replaces AbiList and Indexed with the ABIs and indexed contracts saved with Save at path.
+// Like AddABI, it must not be called concurrently with decoding.
+func (store *Storage) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var snapshot storeSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("invalid store file %s: %w", path, err)
+	}
+
+	if snapshot.Version != storeSnapshotVersion {
+		return fmt.Errorf("unsupported store file version %d in %s", snapshot.Version, path)
+	}
+
+	abis := make([]abi.ABI, 0, len(snapshot.Abis))
+	names := make(map[int]string)
+	for _, entry := range snapshot.Abis {
+		contractAbi, err := abi.JSON(bytes.NewReader(entry.Abi))
+		if err != nil {
+			return fmt.Errorf("invalid abi %q in store file %s: %w", entry.Name, path, err)
+		}
+
+		if entry.Name != "" {
+			names[len(abis)] = entry.Name
+		}
+		abis = append(abis, contractAbi)
+	}
+
+	indexed := make(map[string]*IndexedABI, len(snapshot.Indexed))
+	for _, entry := range snapshot.Indexed {
+		contract, err := restoreIndexed(entry)
+		if err != nil {
+			return fmt.Errorf("invalid contract %s in store file %s: %w", entry.Key, path, err)
+		}
+
+		contract.client = store.client
+		indexed[entry.Key] = contract
+	}
+
+	store.mu.Lock()
+	store.AbiList = abis
+	store.names = names
+	store.Indexed = indexed
+	store.mu.Unlock()
+	store.invalidateAmbiguities()
+	store.invalidateSignatures()
+
+	return nil
+}
+
+func snapshotIndexed(key string, data *IndexedABI) (*snapshotContract, error) {
+	contractAbi, err := MarshalABI(data.Abi)
+	if err != nil {
+		return nil, err
+	}
+
+	result := snapshotContract{
+		Key:      key,
+		Address:  data.Address,
+		Abi:      contractAbi,
+		Bytecode: data.Bytecode,
+		IsToken:  data.IsToken,
+		Verified: data.Verified,
+		IsERC721: data.IsERC721,
+		Name:     data.Name,
+		Pragma:   data.Pragma,
+		Source:   data.Source,
+		Guessed:  data.Guessed,
+		Metadata: data.Metadata,
+	}
+
+	for _, version := range data.Versions {
+		versionAbi, err := MarshalABI(version.Abi)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Versions = append(result.Versions, snapshotVersion{Range: version.Range, Abi: versionAbi})
+	}
+
+	return &result, nil
+}
+
+func restoreIndexed(data snapshotContract) (*IndexedABI, error) {
+	contractAbi, err := abi.JSON(bytes.NewReader(data.Abi))
+	if err != nil {
+		return nil, err
+	}
+
+	result := IndexedABI{
+		Address:  data.Address,
+		Abi:      contractAbi,
+		Bytecode: data.Bytecode,
+		IsToken:  data.IsToken,
+		Verified: data.Verified,
+		IsERC721: data.IsERC721,
+		Name:     data.Name,
+		Pragma:   data.Pragma,
+		Source:   data.Source,
+		Guessed:  data.Guessed,
+		Metadata: data.Metadata,
+	}
+
+	for _, version := range data.Versions {
+		versionAbi, err := abi.JSON(bytes.NewReader(version.Abi))
+		if err != nil {
+			return nil, err
+		}
+
+		result.Versions = append(result.Versions, AbiVersion{Range: version.Range, Abi: versionAbi})
+	}
+
+	return &result, nil
+}
+
+// abiEntryJSON is an entry of a JSON ABI.
+type abiEntryJSON struct {
+	Type            string            `json:"type"`
+	Name            string            `json:"name,omitempty"`
+	Inputs          []abiArgumentJSON `json:"inputs"`
+	Outputs         []abiArgumentJSON `json:"outputs,omitempty"`
+	StateMutability string            `json:"stateMutability,omitempty"`
+	Constant        bool              `json:"constant,omitempty"`
+	Payable         bool              `json:"payable,omitempty"`
+	Anonymous       bool              `json:"anonymous,omitempty"`
+}
+
+// abiArgumentJSON is a param of an entry of a JSON ABI.
+type abiArgumentJSON struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Indexed    bool              `json:"indexed,omitempty"`
+	Components []abiArgumentJSON `json:"components,omitempty"`
+}
+
+// MarshalABI encodes the ABI as JSON ABI, which abi.JSON and ParseABI parse back into an equal
+// ABI. Entries are sorted by kind and name, so the encoding is deterministic.
+func MarshalABI(contractAbi abi.ABI) ([]byte, error) {
+	entries := make([]abiEntryJSON, 0, len(contractAbi.Methods)+len(contractAbi.Events)+len(contractAbi.Errors)+3)
+
+	constructor := contractAbi.Constructor
+	if len(constructor.Inputs) > 0 || constructor.StateMutability != "" || constructor.Payable {
+		entries = append(entries, methodEntryJSON("constructor", constructor))
+	}
+
+	if contractAbi.HasFallback() {
+		entries = append(entries, methodEntryJSON("fallback", contractAbi.Fallback))
+	}
+
+	if contractAbi.HasReceive() {
+		entries = append(entries, methodEntryJSON("receive", contractAbi.Receive))
+	}
+
+	for _, name := range sortedNames(contractAbi.Methods) {
+		entries = append(entries, methodEntryJSON("function", contractAbi.Methods[name]))
+	}
+
+	for _, name := range sortedNames(contractAbi.Events) {
+		event := contractAbi.Events[name]
+		entries = append(entries, abiEntryJSON{
+			Type:      "event",
+			Name:      event.RawName,
+			Inputs:    argumentsJSON(event.Inputs),
+			Anonymous: event.Anonymous,
+		})
+	}
+
+	for _, name := range sortedNames(contractAbi.Errors) {
+		customError := contractAbi.Errors[name]
+		entries = append(entries, abiEntryJSON{
+			Type:   "error",
+			Name:   customError.Name,
+			Inputs: argumentsJSON(customError.Inputs),
+		})
+	}
+
+	return json.Marshal(entries)
+}
+
+func methodEntryJSON(kind string, method abi.Method) abiEntryJSON {
+	entry := abiEntryJSON{
+		Type:            kind,
+		Inputs:          argumentsJSON(method.Inputs),
+		StateMutability: method.StateMutability,
+	}
+
+	// ABIs of old compilers only carry the constant and payable flags.
+	if entry.StateMutability == "" {
+		entry.Constant = method.Constant
+		entry.Payable = method.Payable
+	}
+
+	if kind == "function" {
+		entry.Name = method.RawName
+		entry.Outputs = argumentsJSON(method.Outputs)
+	}
+
+	return entry
+}
+
+func argumentsJSON(arguments abi.Arguments) []abiArgumentJSON {
+	result := make([]abiArgumentJSON, 0, len(arguments))
+	for _, argument := range arguments {
+		param := typeJSON(argument.Name, argument.Type)
+		param.Indexed = argument.Indexed
+		result = append(result, param)
+	}
+
+	return result
+}
+
+// typeJSON returns the param of the type, with tuples as "tuple" types and their elements as
+// components, as abi.JSON expects them.
+func typeJSON(name string, t abi.Type) abiArgumentJSON {
+	switch t.T {
+	case abi.TupleTy:
+		param := abiArgumentJSON{Name: name, Type: "tuple"}
+		for i, elem := range t.TupleElems {
+			param.Components = append(param.Components, typeJSON(t.TupleRawNames[i], *elem))
+		}
+		return param
+	case abi.SliceTy:
+		param := typeJSON(name, *t.Elem)
+		param.Type += "[]"
+		return param
+	case abi.ArrayTy:
+		param := typeJSON(name, *t.Elem)
+		param.Type += fmt.Sprintf("[%d]", t.Size)
+		return param
+	}
+
+	return abiArgumentJSON{Name: name, Type: t.String()}
+}
+
+func sortedNames[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// writeFileAtomic writes the data to a temporary file next to path and renames it to path, so
+// readers never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), path)
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStorageSaveLoad(t *testing.T) {
+	structs := ParseABI(`[
+		{"inputs":[{"name":"owner","type":"address"}],"stateMutability":"nonpayable","type":"constructor"},
+		{"stateMutability":"payable","type":"receive"},
+		{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate","outputs":[{"name":"results","type":"bytes[2]"}],"stateMutability":"payable","type":"function"},
+		{"inputs":[{"name":"to","type":"address"}],"name":"mint","outputs":[],"stateMutability":"nonpayable","type":"function"},
+		{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"mint","outputs":[],"stateMutability":"nonpayable","type":"function"},
+		{"inputs":[{"name":"needed","type":"uint256"}],"name":"Insufficient","type":"error"},
+		{"anonymous":true,"inputs":[{"indexed":true,"name":"who","type":"address"}],"name":"Poked","type":"event"}
+	]`)
+
+	store := Storage{Indexed: make(map[string]*IndexedABI)}
+	store.AddABI("erc20", *ParseABI(abi_erc20))
+	store.AddABI("structs", *structs)
+
+	bytecode := "0x6080604052"
+	store.SetIndexed(target_erc20, *ParseABI(abi_erc20), true, true, &bytecode)
+	store.SetIndexed(target_erc20, *structs, false, false, nil, BlockRange{From: 100, To: 200})
+
+	path := filepath.Join(t.TempDir(), "store.json")
+	if err := store.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := Storage{}
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := loaded.Catalog().ToJSON(), store.Catalog().ToJSON(); got != want {
+		t.Fatalf("catalog mismatch:\n%s\n%s", got, want)
+	}
+
+	if loaded.AbiSetHash() != store.AbiSetHash() {
+		t.Fatal("abi set hash mismatch")
+	}
+
+	if len(loaded.AbiList[1].Constructor.Inputs) != 1 || !loaded.AbiList[1].HasReceive() {
+		t.Fatal("constructor or receive not restored")
+	}
+
+	if len(loaded.AbiList[1].Errors) != 1 || !loaded.AbiList[1].Events["Poked"].Anonymous {
+		t.Fatal("errors or anonymous events not restored")
+	}
+
+	indexed := loaded.GetIndexed(target_erc20)
+	if indexed == nil {
+		t.Fatal("indexed contract not restored")
+	}
+
+	if indexed.Bytecode == nil || *indexed.Bytecode != bytecode || !indexed.Verified {
+		t.Fatalf("unexpected indexed contract: %s", indexed.ToJSON())
+	}
+
+	if len(indexed.Versions) != 1 || indexed.Versions[0].Range.To != 200 {
+		t.Fatalf("unexpected versions: %+v", indexed.Versions)
+	}
+
+	if _, ok := indexed.AbiAt(150).Methods["aggregate"]; !ok {
+		t.Fatal("versioned abi not restored")
+	}
+}
+
+func TestStorageLoadMissing(t *testing.T) {
+	store := Storage{}
+	if err := store.Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
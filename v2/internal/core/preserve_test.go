@@ -0,0 +1,41 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPreserveTypes(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), PreserveTypes: true}
+
+	data, _ := decoder.Abi.Pack("transfer", common.HexToAddress(target_erc20), big.NewInt(42))
+	decoded, err := decoder.DecodeCalldata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, ok := decoded.Params["value"].(*big.Int); !ok || value.Int64() != 42 {
+		t.Fatalf("value not preserved as *big.Int: %T", decoded.Params["value"])
+	}
+
+	if to, ok := decoded.Params["to"].(common.Address); !ok || to != common.HexToAddress(target_erc20) {
+		t.Fatalf("to not preserved as common.Address: %T", decoded.Params["to"])
+	}
+
+	topics := []common.Hash{
+		decoder.Abi.Events["Transfer"].ID,
+		common.BytesToHash(common.HexToAddress(target_contract).Bytes()),
+		common.BytesToHash(common.HexToAddress(target_erc721).Bytes()),
+	}
+
+	log := decoder.DecodeRawLog(topics, common.LeftPadBytes(big.NewInt(7).Bytes(), 32))
+	if _, ok := log.Params["from"].(common.Address); !ok {
+		t.Fatalf("from not preserved as common.Address: %T", log.Params["from"])
+	}
+
+	if _, ok := log.Params["value"].(*big.Int); !ok {
+		t.Fatalf("log value not preserved as *big.Int: %T", log.Params["value"])
+	}
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ChainFeatures holds the capabilities of the chain and node of a backend, see ProbeChain.
+type ChainFeatures struct {
+	ChainID    *big.Int `json:"chainId"`              // Chain id, nil if the backend does not report it.
+	EIP1559    bool     `json:"eip1559"`              // Whether the latest block has a base fee.
+	Debug      bool     `json:"debug"`                // Whether the node serves the debug namespace, e.g. debug_traceTransaction.
+	Trace      bool     `json:"trace"`                // Whether the node serves the trace namespace of Erigon and Nethermind.
+	Archive    bool     `json:"archive"`              // Whether the node serves the state of old blocks.
+	Namespaces []string `json:"namespaces,omitempty"` // RPC namespaces reported by rpc_modules, if the node serves it.
+}
+
+// Signer returns the signer of transactions on the chain: the latest signer for chains with
+// EIP-1559, the EIP-155 signer for other chains, and the Homestead signer without chain id.
+func (features *ChainFeatures) Signer() types.Signer {
+	switch {
+	case features.ChainID == nil:
+		return types.HomesteadSigner{}
+	case features.EIP1559:
+		return types.LatestSignerForChainID(features.ChainID)
+	default:
+		return types.NewEIP155Signer(features.ChainID)
+	}
+}
+
+// ProbeChain detects the features of the chain and node of the global backend. The result is
+// cached on Ctx until the backend changes; it picks the signer of Ctx, and call traces fail
+// without a request on nodes without the debug namespace.
+func ProbeChain(ctx context.Context) (*ChainFeatures, error) {
+	if Ctx.features != nil {
+		return Ctx.features, nil
+	}
+
+	client, err := requireClient(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	features, err := probeChain(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	isLegacy := !features.EIP1559
+	Ctx.features, Ctx.isLegacy = features, &isLegacy
+	if features.ChainID != nil {
+		Ctx.chainId = features.ChainID
+	}
+	Ctx.signer = features.Signer()
+
+	return features, nil
+}
+
+func probeChain(ctx context.Context, client Backend) (*ChainFeatures, error) {
+	ctx, cancel := withTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	features := &ChainFeatures{EIP1559: head.BaseFee != nil}
+	if id, err := backendChainID(ctx, client); err == nil {
+		features.ChainID = id
+	}
+
+	if rpcClient(client) != nil {
+		var modules map[string]string
+		if err := callContext(ctx, client, &modules, "rpc_modules"); err == nil {
+			for namespace := range modules {
+				features.Namespaces = append(features.Namespaces, namespace)
+			}
+			sort.Strings(features.Namespaces)
+			_, features.Debug = modules["debug"]
+			_, features.Trace = modules["trace"]
+		} else {
+			// providers often hide rpc_modules, the namespaces are probed with unknown hashes
+			features.Debug = methodSupported(ctx, client, "debug_traceTransaction", common.Hash{}, map[string]interface{}{"tracer": "callTracer"})
+			features.Trace = methodSupported(ctx, client, "trace_transaction", common.Hash{})
+		}
+	}
+
+	// pruned nodes only keep the state of recent blocks
+	if head.Number != nil && head.Number.Sign() > 0 {
+		_, err := client.CodeAt(ctx, common.Address{}, common.Big1)
+		features.Archive = err == nil
+	}
+
+	return features, nil
+}
+
+// methodSupported reports whether the node serves the method. Calls failing with errors other than
+// an unknown method, e.g. as the transaction of the probe is not found, count as served.
+func methodSupported(ctx context.Context, client Backend, method string, args ...interface{}) bool {
+	var result interface{}
+	err := callContext(ctx, client, &result, method, args...)
+	if err == nil {
+		return true
+	}
+
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+
+	// method not found, and method not supported of EIP-1474
+	return rpcErr.ErrorCode() != -32601 && rpcErr.ErrorCode() != -32004
+}
+
+// probedFeatures returns the features probed with ProbeChain if the backend is the global one,
+// nil otherwise.
+func probedFeatures(backend Backend) *ChainFeatures {
+	if retry, ok := backend.(*retryBackend); ok {
+		backend = retry.Backend
+	}
+
+	if Ctx.features == nil || backend == nil || !reflect.TypeOf(backend).Comparable() || backend != Ctx.eth {
+		return nil
+	}
+
+	return Ctx.features
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestProbeChain(t *testing.T) {
+	head := *newTestChain(t, 1).headers[1]
+	head.BaseFee = big.NewInt(7)
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.Respond("eth_chainId", "0xa")
+	server.Respond("eth_getBlockByNumber", &head)
+	server.Respond("rpc_modules", map[string]string{"eth": "1.0", "debug": "1.0", "net": "1.0"})
+	server.Handle("eth_getCode", func(json.RawMessage) (interface{}, error) {
+		return nil, &mockrpc.Error{Code: -32000, Message: "missing trie node"}
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous, chainId := Ctx.eth, Ctx.chainId
+	defer func() { Ctx.chainId = chainId }()
+	SetClient(client)
+	defer SetBackend(previous)
+
+	if eip1559, err := IsEIP1559(client, context.Background()); err != nil || !*eip1559 {
+		t.Fatal("expected EIP-1559 to be detected from the base fee")
+	}
+
+	features, err := ProbeChain(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if features.ChainID.Int64() != 10 || !features.EIP1559 || !features.Debug || features.Trace || features.Archive {
+		t.Fatalf("unexpected features: %+v", features)
+	}
+	if len(features.Namespaces) != 3 || features.Namespaces[0] != "debug" {
+		t.Fatalf("unexpected namespaces: %v", features.Namespaces)
+	}
+	if Ctx.signer == nil || !Ctx.signer.Equal(types.LatestSignerForChainID(big.NewInt(10))) {
+		t.Fatal("expected the latest signer to be picked")
+	}
+
+	// the features are cached until the backend changes
+	if again, _ := ProbeChain(context.Background()); again != features || len(server.Calls("rpc_modules")) != 1 {
+		t.Fatal("expected the features to be cached")
+	}
+
+	// nodes without the debug namespace are not asked for traces
+	features.Debug = false
+	if _, err := Store.TraceTransaction(context.Background(), "0x01"); err == nil || len(server.Calls("debug_traceTransaction")) != 0 {
+		t.Fatalf("expected the trace to fail without request: %v", err)
+	}
+}
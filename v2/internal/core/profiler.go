@@ -0,0 +1,251 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProfilerConfig configures a CallProfiler.
+type ProfilerConfig struct {
+	WarmUp      int                 // Calls of a contract observed before anomalies are reported, defaults to 100.
+	RangeFactor float64             // Factor a value may exceed the historical range by, defaults to 1.
+	OnAnomaly   func(alert Anomaly) // Called for every anomaly, e.g. to feed an alerting system.
+}
+
+// Anomaly is a struct for holding a statistical anomaly of a decoded call.
+type Anomaly struct {
+	Kind            string    `json:"kind"`            // "new_selector" or "out_of_range".
+	Contract        string    `json:"contract"`        // Address of the called contract.
+	Signature       string    `json:"signature"`       // Signature of the called method.
+	Param           string    `json:"param,omitempty"` // Name of the param out of range.
+	Value           string    `json:"value,omitempty"` // Value out of range.
+	Detail          string    `json:"detail"`          // Human readable description.
+	TransactionHash string    `json:"transactionHash"` // Transaction of the call.
+	Time            time.Time `json:"time"`
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the Anomaly object.
+func (data *Anomaly) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the Anomaly object.
+func (data *Anomaly) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ContractProfile is a struct for holding the call statistics of a contract.
+type ContractProfile struct {
+	Contract  string                    `json:"contract"`
+	Calls     int                       `json:"calls"`     // Decoded calls observed.
+	FirstSeen time.Time                 `json:"firstSeen"` // Time of the first observed call.
+	LastSeen  time.Time                 `json:"lastSeen"`  // Time of the last observed call.
+	Methods   map[string]*MethodProfile `json:"methods"`   // Statistics keyed by selector.
+}
+
+// MethodProfile is a struct for holding the call statistics of a method.
+type MethodProfile struct {
+	Signature string                 `json:"signature"`
+	Calls     int                    `json:"calls"`
+	Params    map[string]*ParamStats `json:"params"` // Statistics of the numeric and address params.
+}
+
+// ParamStats is a struct for holding the distribution of a param. Numeric params track their
+// range, address params the frequency of every address.
+type ParamStats struct {
+	Type      string         `json:"type"`
+	Count     int            `json:"count"`
+	Min       *big.Int       `json:"min,omitempty"`
+	Max       *big.Int       `json:"max,omitempty"`
+	Addresses map[string]int `json:"addresses,omitempty"`
+}
+
+// CallProfiler profiles the params of decoded calls per contract and reports anomalies: selectors
+// not called before and numeric params outside of their historical range. Anomalies are only
+// reported once a contract has been observed WarmUp times.
+type CallProfiler struct {
+	config ProfilerConfig
+
+	mu        sync.Mutex
+	contracts map[string]*ContractProfile
+}
+
+// NewCallProfiler creates a profiler with the given configuration. An invalid configuration
+// fails with ValidationErrors.
+func NewCallProfiler(config ProfilerConfig) (*CallProfiler, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.WarmUp <= 0 {
+		config.WarmUp = 100
+	}
+
+	if config.RangeFactor < 1 {
+		config.RangeFactor = 1
+	}
+
+	return &CallProfiler{config: config, contracts: make(map[string]*ContractProfile)}, nil
+}
+
+// Observe adds the decoded call to the statistics of its contract and returns the anomalies
+// found, which are passed to OnAnomaly as well. Unknown calls are profiled by selector only.
+func (p *CallProfiler) Observe(decoded *DecodedMethod) []Anomaly {
+	if decoded == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := getClock().Now()
+	key := strings.ToLower(decoded.Contract)
+
+	contract, ok := p.contracts[key]
+	if !ok {
+		contract = &ContractProfile{Contract: decoded.Contract, FirstSeen: now, Methods: make(map[string]*MethodProfile)}
+		p.contracts[key] = contract
+	}
+
+	warm := contract.Calls >= p.config.WarmUp
+	contract.Calls++
+	contract.LastSeen = now
+
+	var anomalies []Anomaly
+	anomaly := func(kind string, param string, value string, detail string) {
+		anomalies = append(anomalies, Anomaly{
+			Kind:            kind,
+			Contract:        decoded.Contract,
+			Signature:       decoded.Signature,
+			Param:           param,
+			Value:           value,
+			Detail:          detail,
+			TransactionHash: decoded.TransactionHash,
+			Time:            now,
+		})
+	}
+
+	method, ok := contract.Methods[decoded.SigHash]
+	if !ok {
+		method = &MethodProfile{Signature: decoded.Signature, Params: make(map[string]*ParamStats)}
+		contract.Methods[decoded.SigHash] = method
+		if warm {
+			anomaly("new_selector", "", "", fmt.Sprintf("first call of %s %s after %d calls", decoded.SigHash, decoded.Signature, contract.Calls-1))
+		}
+	}
+	method.Calls++
+
+	for _, input := range decoded.Inputs {
+		value, ok := decoded.Params[input.Name]
+		if !ok {
+			continue
+		}
+
+		stats, ok := method.Params[input.Name]
+		if !ok {
+			stats = &ParamStats{Type: input.Type}
+			method.Params[input.Name] = stats
+		}
+
+		switch {
+		case input.Type == "address":
+			if stats.Addresses == nil {
+				stats.Addresses = make(map[string]int)
+			}
+			stats.Addresses[fmt.Sprint(value)]++
+			stats.Count++
+
+		case (strings.HasPrefix(input.Type, "uint") || strings.HasPrefix(input.Type, "int")) && !strings.HasSuffix(input.Type, "]"):
+			number, err := parseBigInt(value)
+			if err != nil {
+				continue
+			}
+
+			if warm && stats.Count > 0 && !p.inRange(stats, number) {
+				anomaly("out_of_range", input.Name, number.String(), fmt.Sprintf("%s %s outside of historical range [%s, %s]", input.Name, number, stats.Min, stats.Max))
+			}
+
+			if stats.Min == nil || number.Cmp(stats.Min) < 0 {
+				stats.Min = new(big.Int).Set(number)
+			}
+			if stats.Max == nil || number.Cmp(stats.Max) > 0 {
+				stats.Max = new(big.Int).Set(number)
+			}
+			stats.Count++
+		}
+	}
+
+	if p.config.OnAnomaly != nil {
+		for _, alert := range anomalies {
+			p.config.OnAnomaly(alert)
+		}
+	}
+
+	return anomalies
+}
+
+// inRange checks the value against the historical range of the param, widened by RangeFactor.
+func (p *CallProfiler) inRange(stats *ParamStats, value *big.Int) bool {
+	factor := big.NewFloat(p.config.RangeFactor)
+	number := new(big.Float).SetInt(value)
+
+	upper := new(big.Float).SetInt(stats.Max)
+	if stats.Max.Sign() >= 0 {
+		upper.Mul(upper, factor)
+	} else {
+		upper.Quo(upper, factor)
+	}
+
+	lower := new(big.Float).SetInt(stats.Min)
+	if stats.Min.Sign() >= 0 {
+		lower.Quo(lower, factor)
+	} else {
+		lower.Mul(lower, factor)
+	}
+
+	return number.Cmp(lower) >= 0 && number.Cmp(upper) <= 0
+}
+
+// Profile returns a copy of the statistics of the contract, or nil if it was not observed.
+func (p *CallProfiler) Profile(contract string) *ContractProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profile, ok := p.contracts[strings.ToLower(contract)]
+	if !ok {
+		return nil
+	}
+
+	// round trip through JSON for a deep copy, the statistics keep changing
+	var result ContractProfile
+	b, _ := json.Marshal(profile)
+	json.Unmarshal(b, &result)
+
+	return &result
+}
+
+// Contracts returns the addresses of all observed contracts.
+func (p *CallProfiler) Contracts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]string, 0, len(p.contracts))
+	for _, profile := range p.contracts {
+		result = append(result, profile.Contract)
+	}
+
+	return result
+}
+
+// Reset drops all statistics.
+func (p *CallProfiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.contracts = make(map[string]*ContractProfile)
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCallProfiler(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), ContractAddress: &target_erc20}
+	call := func(method string, args ...interface{}) *DecodedMethod {
+		data, _ := decoder.Abi.Pack(method, args...)
+		decoded, err := decoder.DecodeCalldata(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return decoded
+	}
+
+	var alerts []Anomaly
+	profiler, err := NewCallProfiler(ProfilerConfig{WarmUp: 3, OnAnomaly: func(alert Anomaly) { alerts = append(alerts, alert) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		if anomalies := profiler.Observe(call("transfer", common.HexToAddress(target_contract), big.NewInt(i*10))); len(anomalies) != 0 {
+			t.Fatalf("anomalies during warm up: %v", anomalies)
+		}
+	}
+
+	if anomalies := profiler.Observe(call("transfer", common.HexToAddress(target_contract), big.NewInt(20))); len(anomalies) != 0 {
+		t.Fatalf("value in range flagged: %v", anomalies)
+	}
+
+	anomalies := profiler.Observe(call("transfer", common.HexToAddress(target_contract), big.NewInt(1000)))
+	if len(anomalies) != 1 || anomalies[0].Kind != "out_of_range" || anomalies[0].Value != "1000" {
+		t.Fatalf("out of range value not flagged: %v", anomalies)
+	}
+
+	anomalies = profiler.Observe(call("approve", common.HexToAddress(target_contract), big.NewInt(1)))
+	if len(anomalies) != 1 || anomalies[0].Kind != "new_selector" || len(alerts) != 2 {
+		t.Fatalf("new selector not flagged: %v", anomalies)
+	}
+
+	profile := profiler.Profile(target_erc20)
+	stats := profile.Methods["0xa9059cbb"].Params["to"]
+	if profile.Calls != 6 || stats == nil || stats.Addresses[common.HexToAddress(target_contract).Hex()] != 5 {
+		t.Fatalf("invalid profile: %+v", profile.Methods["0xa9059cbb"])
+	}
+}
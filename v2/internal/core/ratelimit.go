@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the requests sent to a provider. It is an RPCInterceptor
+// holding back every outbound call until a token is free, calls of batch requests take a token
+// each. Connections created with ConnectOptions.RateLimit get their own limiter, a limiter added
+// to the Interceptors of several connections shares its quota between them.
+type RateLimiter struct {
+	rate  float64 // tokens added per second
+	burst float64 // size of the bucket
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perSecond requests per second on average and up to
+// burst requests at once. Burst defaults to one second worth of requests.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(perSecond)))
+	}
+
+	return &RateLimiter{rate: perSecond, burst: float64(burst), tokens: float64(burst)}
+}
+
+// reserve takes a token and returns how long the caller has to wait until it is due.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := getClock().Now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// Wait blocks until a request may be sent, or returns the error of the context if it is done
+// first. The token of a cancelled wait is returned to the bucket.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve()
+	if wait == 0 {
+		return nil
+	}
+
+	select {
+	case <-getClock().After(wait):
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Before waits for a token, see Wait.
+func (l *RateLimiter) Before(ctx context.Context, call *RPCCall) error {
+	return l.Wait(ctx)
+}
+
+// After implements RPCInterceptor.
+func (l *RateLimiter) After(ctx context.Context, call *RPCCall) {}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestRateLimiter(t *testing.T) {
+	manual := NewManualClock(time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(manual)
+	defer SetClock(nil)
+
+	limiter := NewRateLimiter(2, 0)
+	for i := 0; i < 2; i++ {
+		if wait := limiter.reserve(); wait != 0 {
+			t.Fatalf("burst not allowed: %v", wait)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- limiter.Wait(context.Background()) }()
+
+	select {
+	case <-done:
+		t.Fatal("quota exceeded")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	manual.Advance(500 * time.Millisecond)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("token not refilled")
+	}
+
+	// cancelled waits return their token
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Fatal("cancelled wait succeeded")
+	}
+
+	manual.Advance(500 * time.Millisecond)
+	if wait := limiter.reserve(); wait != 0 {
+		t.Fatalf("token of cancelled wait lost: %v", wait)
+	}
+}
+
+func TestConnectRateLimit(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+	server.Respond("eth_chainId", "0x1")
+
+	if err := (&ConnectOptions{RateLimit: -1}).Validate(); err == nil {
+		t.Fatal("negative rate limit not reported")
+	}
+
+	client, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{RateLimit: 20, RateBurst: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.ChainID(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("calls not limited: %v", elapsed)
+	}
+}
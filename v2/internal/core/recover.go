@@ -0,0 +1,82 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RecoverABI synthesizes a best-effort ABI for an unverified contract from the selectors pushed by
+// its bytecode, see ExtractSelectors. Every selector is resolved with the resolver, the embedded
+// selector database if nil, and the first signature hashing to it is kept. Params are named by
+// position. Events are left out, as the bytecode does not tell which of their params are indexed;
+// logs of the contract can still be decoded through the Resolver of the store.
+func RecoverABI(bytecode string, resolver SignatureResolver) abi.ABI {
+	if resolver == nil {
+		resolver = EmbeddedResolver{}
+	}
+
+	result := abi.ABI{
+		Methods: make(map[string]abi.Method),
+		Events:  make(map[string]abi.Event),
+		Errors:  make(map[string]abi.Error),
+	}
+
+	for _, selector := range ExtractSelectors(bytecode) {
+		id, _ := hexutil.Decode(selector)
+
+		ctx, cancel := withTimeout(context.Background(), ResolveTimeout)
+		signatures, err := resolver.ResolveFunction(ctx, selector)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		for _, signature := range signatures {
+			method, err := methodFromSignature(signature)
+			if err != nil || !bytes.Equal(method.ID, id) {
+				continue
+			}
+
+			// overloads share a name, keep them apart like abi.JSON does
+			name := method.Name
+			for i := 0; ; i++ {
+				if _, ok := result.Methods[name]; !ok {
+					break
+				}
+				name = fmt.Sprintf("%s%d", method.Name, i)
+			}
+			result.Methods[name] = method
+			break
+		}
+	}
+
+	return result
+}
+
+// IndexRecovered indexes the contract with the ABI recovered from its bytecode and flags it as
+// Guessed. Without bytecode it is fetched with the client of the store. It fails if no selector of the
+// bytecode could be resolved.
+func (store *Storage) IndexRecovered(address string, bytecode *string, resolver SignatureResolver) (*IndexedABI, error) {
+	if bytecode == nil {
+		bytecode = getBytecode(store.GetBackend(), common.HexToAddress(address))
+	}
+
+	if bytecode == nil || len(*bytecode) <= 2 {
+		return nil, fmt.Errorf("no bytecode of %s", address)
+	}
+
+	recovered := RecoverABI(*bytecode, resolver)
+	if len(recovered.Methods) == 0 {
+		return nil, fmt.Errorf("no known selector in bytecode of %s", address)
+	}
+
+	indexed := store.SetIndexed(address, recovered, false, false, bytecode)
+	indexed.Guessed = true
+
+	return indexed, nil
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// DefaultABIs is the registry of the built-in ABIs. It holds the ABIs of ALL_DEFAULT_ABIS and the
+// ABIs of widely deployed protocol contracts, and can be extended with Register.
+var DefaultABIs = newDefaultABIs()
+
+// ABIRegistry holds JSON ABIs by name, so that they can be selected individually instead of by
+// their index in ALL_DEFAULT_ABIS. Names are case insensitive. It is safe for concurrent use.
+type ABIRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]*registryEntry
+	names   []string
+}
+
+// registryEntry is a registered ABI, parsed once on registration.
+type registryEntry struct {
+	name   string
+	json   string
+	parsed *abi.ABI
+}
+
+// NewABIRegistry returns an empty ABIRegistry.
+func NewABIRegistry() *ABIRegistry {
+	return &ABIRegistry{entries: make(map[string]*registryEntry)}
+}
+
+func newDefaultABIs() *ABIRegistry {
+	registry := NewABIRegistry()
+
+	for _, entry := range []struct{ name, json string }{
+		{"ERC20", abi_erc20},
+		{"ERC721", abi_erc721},
+		{"ENS", abi_ens},
+		{"Proxy", abi_proxy},
+		{"UniswapV2Router", abi_uni_router},
+		{"RouterFactory", abi_router_factory},
+		{"UniswapV2Pair", abi_liquidity_token},
+		{"Staking", abi_staking_imp},
+		{"Depositable", abi_depositable},
+		{"ZapSwap", abi_zap_swap},
+		{"AirSwap", abi_air_swap},
+		{"Governor", abi_governance},
+		{"DAOToken", abi_dao_token},
+		{"Timelock", abi_timelock},
+		{"Safe", abi_safe},
+		{"WETH", abi_weth},
+		{"Multicall3", abi_multicall3},
+		{"Permit2", abi_permit2},
+		{"UniswapV3Pool", abi_uniswap_v3_pool},
+		{"UniswapV3Router", abi_uniswap_v3_router},
+		{"Seaport", abi_seaport},
+		{"LooksRare", abi_looksrare},
+		{"ERC4626", abi_erc4626},
+		{"Chainlink", abi_chainlink},
+	} {
+		if err := registry.Register(entry.name, entry.json); err != nil {
+			panic(err)
+		}
+	}
+
+	return registry
+}
+
+// Register parses the JSON ABI and registers it under the name, replacing an ABI registered
+// under the same name. It returns an error if the ABI can not be parsed.
+func (registry *ABIRegistry) Register(name string, abiJSON string) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return fmt.Errorf("error parsing abi %s: %w", name, err)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	key := strings.ToLower(name)
+	if _, ok := registry.entries[key]; !ok {
+		registry.names = append(registry.names, name)
+	}
+	registry.entries[key] = &registryEntry{name: name, json: abiJSON, parsed: &parsed}
+
+	return nil
+}
+
+// Get returns the ABI registered under the name, or nil if there is none. The ABI is shared by all
+// callers and must not be modified, see JSON for a private copy.
+func (registry *ABIRegistry) Get(name string) *abi.ABI {
+	if entry := registry.entry(name); entry != nil {
+		return entry.parsed
+	}
+
+	return nil
+}
+
+// JSON returns the JSON ABI registered under the name, or an empty string if there is none.
+func (registry *ABIRegistry) JSON(name string) string {
+	if entry := registry.entry(name); entry != nil {
+		return entry.json
+	}
+
+	return ""
+}
+
+// Names returns the names of the registered ABIs in order of registration.
+func (registry *ABIRegistry) Names() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return append([]string(nil), registry.names...)
+}
+
+// Merge merges the ABIs registered under the names, see MergeABIs. It returns an error if a name
+// is not registered.
+func (registry *ABIRegistry) Merge(names ...string) (*abi.ABI, error) {
+	jsonAbis := make([]string, 0, len(names))
+	for _, name := range names {
+		entry := registry.entry(name)
+		if entry == nil {
+			return nil, fmt.Errorf("abi not registered: %s", name)
+		}
+		jsonAbis = append(jsonAbis, entry.json)
+	}
+
+	return MergeABIs(jsonAbis...), nil
+}
+
+func (registry *ABIRegistry) entry(name string) *registryEntry {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registry.entries[strings.ToLower(name)]
+}
+
+// AddDefaultABIs adds the ABIs registered in DefaultABIs under the names to AbiList, named like
+// in the registry. It returns an error, without adding any ABI, if a name is not registered.
+func (store *Storage) AddDefaultABIs(names ...string) error {
+	entries := make([]*registryEntry, 0, len(names))
+	for _, name := range names {
+		entry := DefaultABIs.entry(name)
+		if entry == nil {
+			return fmt.Errorf("abi not registered: %s", name)
+		}
+		entries = append(entries, entry)
+	}
+
+	// the store removes entries of its ABIs, so every store gets its own copy
+	for _, entry := range entries {
+		store.AddABI(entry.name, *ParseABI(entry.json))
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestABIRegistry(t *testing.T) {
+	if erc20 := DefaultABIs.Get("erc20"); erc20 == nil || erc20.Events["Transfer"].ID.Hex() != TransferTopic {
+		t.Fatal("ERC20 not registered")
+	}
+
+	for _, name := range []string{"WETH", "Multicall3", "Permit2", "Safe", "UniswapV2Router", "UniswapV3Pool", "Governor"} {
+		if DefaultABIs.Get(name) == nil {
+			t.Fatalf("%s not registered", name)
+		}
+	}
+
+	if names := DefaultABIs.Names(); len(names) < len(ALL_DEFAULT_ABIS) || names[0] != "ERC20" {
+		t.Fatalf("unexpected names: %v", names)
+	}
+
+	registry := NewABIRegistry()
+	if err := registry.Register("Broken", "[{"); err == nil {
+		t.Fatal("expected an error for an invalid abi")
+	}
+	if err := registry.Register("Token", abi_erc20); err != nil {
+		t.Fatal(err)
+	}
+	if registry.Get("token") == nil || registry.Get("Broken") != nil || registry.JSON("Token") != abi_erc20 {
+		t.Fatal("unexpected registry entries")
+	}
+	if _, err := registry.Merge("Token", "Missing"); err == nil {
+		t.Fatal("expected an error for a missing abi")
+	}
+
+	store := &Storage{}
+	if err := store.AddDefaultABIs("WETH", "Missing"); err == nil || len(store.AbiList) != 0 {
+		t.Fatal("added abis despite a missing name")
+	}
+	if err := store.AddDefaultABIs("Permit2"); err != nil {
+		t.Fatal(err)
+	}
+
+	permit2 := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	token := common.HexToAddress(target_erc20)
+	data, err := DefaultABIs.Get("Permit2").Pack("approve", token, permit2, big.NewInt(1), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := store.DecodeMethod(types.NewTx(&types.LegacyTx{To: &permit2, Data: data}))
+	if decoded == nil || decoded.Name != "approve" || decoded.Params["token"] != token.Hex() {
+		t.Fatalf("unexpected permit2 call: %+v", decoded)
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// ReorgTracker keeps the block hashes and logs of the most recent blocks a scan or subscription
+// delivered logs of. Rewind compares them with the canonical chain, so logs of blocks that were
+// reorganized away can be reported as removed and the blocks decoded again.
+type ReorgTracker struct {
+	Depth uint64 // Number of blocks tracked below the newest one.
+
+	mu     sync.Mutex
+	blocks map[uint64]*trackedBlock
+	newest uint64
+}
+
+type trackedBlock struct {
+	hash string
+	logs []DecodedLog
+}
+
+// NewReorgTracker returns a tracker following the given number of blocks.
+func NewReorgTracker(depth uint64) *ReorgTracker {
+	return &ReorgTracker{Depth: depth, blocks: make(map[uint64]*trackedBlock)}
+}
+
+// Record tracks a delivered log. Logs of blocks older than Depth below the newest block are
+// dropped.
+func (tracker *ReorgTracker) Record(decoded *DecodedLog) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if tracker.blocks == nil {
+		tracker.blocks = make(map[uint64]*trackedBlock)
+	}
+
+	block := tracker.blocks[decoded.BlockNumber]
+	if block == nil || block.hash != decoded.BlockHash {
+		block = &trackedBlock{hash: decoded.BlockHash}
+		tracker.blocks[decoded.BlockNumber] = block
+	}
+	block.logs = append(block.logs, *decoded)
+
+	if decoded.BlockNumber > tracker.newest {
+		tracker.newest = decoded.BlockNumber
+		for number := range tracker.blocks {
+			if number+tracker.Depth < tracker.newest {
+				delete(tracker.blocks, number)
+			}
+		}
+	}
+}
+
+// Remove forgets a log the node reported as removed.
+func (tracker *ReorgTracker) Remove(decoded *DecodedLog) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	block := tracker.blocks[decoded.BlockNumber]
+	if block == nil {
+		return
+	}
+
+	for i := range block.logs {
+		if block.logs[i].LogIndex == decoded.LogIndex {
+			block.logs = append(block.logs[:i], block.logs[i+1:]...)
+			break
+		}
+	}
+
+	if len(block.logs) == 0 {
+		delete(tracker.blocks, decoded.BlockNumber)
+	}
+}
+
+// Rewind compares the hashes of the tracked blocks with the chain, newest first, until a block
+// still matches. The logs of all blocks that changed are returned marked as removed, newest first,
+// together with the oldest changed block the logs have to be fetched again from. ok is false if
+// no block changed.
+func (tracker *ReorgTracker) Rewind(ctx context.Context, client Backend) (fork uint64, removed []DecodedLog, ok bool, err error) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	numbers := make([]uint64, 0, len(tracker.blocks))
+	for number := range tracker.blocks {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] > numbers[j] })
+
+	// the headers of all tracked blocks are requested in batches
+	headers, err := fetchHeaders(ctx, client, numbers)
+	if err != nil {
+		return 0, nil, false, err
+	}
+
+	for i, number := range numbers {
+		if header := headers[i]; header != nil && header.Hash().Hex() == tracker.blocks[number].hash {
+			break
+		}
+		fork, ok = number, true
+	}
+
+	if !ok {
+		return 0, nil, false, nil
+	}
+
+	for _, number := range numbers {
+		if number < fork {
+			break
+		}
+
+		logs := tracker.blocks[number].logs
+		for i := len(logs) - 1; i >= 0; i-- {
+			log := logs[i]
+			log.Removed = true
+			removed = append(removed, log)
+		}
+		delete(tracker.blocks, number)
+	}
+
+	tracker.newest = 0
+	for number := range tracker.blocks {
+		if number > tracker.newest {
+			tracker.newest = number
+		}
+	}
+
+	return fork, removed, true, nil
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the context carrying the request or correlation id. The id is
+// attached to decoded results of the *Context decode functions, passed to RPC interceptors and
+// forwarded to the node if ConnectOptions.RequestIDHeader is set.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id attached with WithRequestID, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// DecodeMethodContext is like DecodeMethod, tagging the result with the request id of the context.
+func (store *Storage) DecodeMethodContext(ctx context.Context, tx *types.Transaction) *DecodedMethod {
+	decoded := store.DecodeMethod(tx)
+	if decoded != nil {
+		tagMethod(decoded, RequestIDFromContext(ctx))
+	}
+
+	return decoded
+}
+
+// DecodeLogContext is like DecodeLog, tagging the result with the request id of the context.
+func (store *Storage) DecodeLogContext(ctx context.Context, vLog *types.Log) *DecodedLog {
+	decoded := store.DecodeLog(vLog)
+	if decoded != nil {
+		decoded.RequestID = RequestIDFromContext(ctx)
+	}
+
+	return decoded
+}
+
+// DecodeLogsContext is like DecodeLogs, tagging the results with the request id of the context.
+func (store *Storage) DecodeLogsContext(ctx context.Context, vLogs []*types.Log) []*DecodedLog {
+	decodedLogs := store.DecodeLogs(vLogs)
+	id := RequestIDFromContext(ctx)
+	for _, decoded := range decodedLogs {
+		decoded.RequestID = id
+	}
+
+	return decodedLogs
+}
+
+// DecodeMethodContext is like DecodeMethod, tagging the result with the request id of the context.
+func (decoder *AbiDecoder) DecodeMethodContext(ctx context.Context, tx *types.Transaction) *DecodedMethod {
+	decoded := decoder.DecodeMethod(tx)
+	if decoded != nil {
+		tagMethod(decoded, RequestIDFromContext(ctx))
+	}
+
+	return decoded
+}
+
+// DecodeLogContext is like DecodeLog, tagging the result with the request id of the context.
+func (decoder *AbiDecoder) DecodeLogContext(ctx context.Context, vLog *types.Log) *DecodedLog {
+	decoded := decoder.DecodeLog(vLog)
+	if decoded != nil {
+		decoded.RequestID = RequestIDFromContext(ctx)
+	}
+
+	return decoded
+}
+
+// tagMethod sets the request id on the method and its nested calls.
+func tagMethod(decoded *DecodedMethod, id string) {
+	decoded.RequestID = id
+	for _, call := range decoded.Calls {
+		tagMethod(call, id)
+	}
+}
@@ -0,0 +1,385 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ResolveTimeout limits the duration of a single online signature lookup.
+var ResolveTimeout = 10 * time.Second
+
+// SignatureResolver looks up the text signatures of unknown function selectors and event topics,
+// e.g. "transfer(address,uint256)". Several signatures are returned on selector collisions, the
+// most likely first.
+type SignatureResolver interface {
+	ResolveFunction(ctx context.Context, selector string) ([]string, error)
+	ResolveEvent(ctx context.Context, topic string) ([]string, error)
+}
+
+// OpenchainResolver resolves signatures with the openchain.xyz signature database.
+type OpenchainResolver struct {
+	BaseURL string       // Defaults to https://api.openchain.xyz.
+	Client  *http.Client // Defaults to http.DefaultClient.
+}
+
+// ResolveFunction implements SignatureResolver.
+func (r *OpenchainResolver) ResolveFunction(ctx context.Context, selector string) ([]string, error) {
+	return r.lookup(ctx, "function", selector)
+}
+
+// ResolveEvent implements SignatureResolver.
+func (r *OpenchainResolver) ResolveEvent(ctx context.Context, topic string) ([]string, error) {
+	return r.lookup(ctx, "event", topic)
+}
+
+func (r *OpenchainResolver) lookup(ctx context.Context, kind string, hash string) ([]string, error) {
+	baseURL := r.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openchain.xyz"
+	}
+
+	var response struct {
+		Ok     bool `json:"ok"`
+		Result map[string]map[string][]struct {
+			Name string `json:"name"`
+		} `json:"result"`
+	}
+
+	query := url.Values{kind: {hash}, "filter": {"true"}}
+	if err := getJSON(ctx, r.Client, baseURL+"/signature-database/v1/lookup?"+query.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	if !response.Ok {
+		return nil, fmt.Errorf("openchain lookup failed: %s %s", kind, hash)
+	}
+
+	var result []string
+	for _, entry := range response.Result[kind][strings.ToLower(hash)] {
+		result = append(result, entry.Name)
+	}
+
+	return result, nil
+}
+
+// FourByteResolver resolves signatures with the 4byte.directory database. Older entries are
+// preferred on collisions, as later ones are commonly spam.
+type FourByteResolver struct {
+	BaseURL string       // Defaults to https://www.4byte.directory.
+	Client  *http.Client // Defaults to http.DefaultClient.
+}
+
+// ResolveFunction implements SignatureResolver.
+func (r *FourByteResolver) ResolveFunction(ctx context.Context, selector string) ([]string, error) {
+	return r.lookup(ctx, "signatures", selector)
+}
+
+// ResolveEvent implements SignatureResolver.
+func (r *FourByteResolver) ResolveEvent(ctx context.Context, topic string) ([]string, error) {
+	return r.lookup(ctx, "event-signatures", topic)
+}
+
+func (r *FourByteResolver) lookup(ctx context.Context, endpoint string, hash string) ([]string, error) {
+	baseURL := r.BaseURL
+	if baseURL == "" {
+		baseURL = "https://www.4byte.directory"
+	}
+
+	var response struct {
+		Results []struct {
+			ID            int    `json:"id"`
+			TextSignature string `json:"text_signature"`
+		} `json:"results"`
+	}
+
+	query := url.Values{"hex_signature": {hash}}
+	if err := getJSON(ctx, r.Client, baseURL+"/api/v1/"+endpoint+"/?"+query.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(response.Results, func(i, j int) bool {
+		return response.Results[i].ID < response.Results[j].ID
+	})
+
+	var result []string
+	for _, entry := range response.Results {
+		result = append(result, entry.TextSignature)
+	}
+
+	return result, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, result interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("signature lookup failed: %s", response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+// resolved caches the ABIs synthesized from resolved signatures, keyed by selector or topic.
+// Hashes without a matching signature are cached as empty ABIs.
+var resolved = struct {
+	sync.RWMutex
+	abis map[string]abi.ABI
+}{abis: make(map[string]abi.ABI)}
+
+// ResetResolvedSignatures clears the cache of resolved signatures. Signatures shared through the
+// Cache are kept.
+func ResetResolvedSignatures() {
+	resolved.Lock()
+	defer resolved.Unlock()
+	resolved.abis = make(map[string]abi.ABI)
+}
+
+// resolveMethod decodes calldata with the signatures returned by the resolver. Params are keyed
+// by position, as signatures do not carry argument names.
+func resolveMethod(resolver SignatureResolver, contract string, data []byte, preserve bool) *DecodedMethod {
+	if len(data) < 4 {
+		return nil
+	}
+
+	selector := hexutil.Encode(data[:4])
+	contractAbi := resolveABI("function:"+selector, func(ctx context.Context) ([]string, error) {
+		return resolver.ResolveFunction(ctx, selector)
+	}, func(signature string) (abi.ABI, bool) {
+		method, err := methodFromSignature(signature)
+		if err != nil || !bytes.Equal(method.ID, data[:4]) {
+			return abi.ABI{}, false
+		}
+
+		if _, err := method.Inputs.Unpack(data[4:]); err != nil {
+			return abi.ABI{}, false
+		}
+
+		return abi.ABI{Methods: map[string]abi.Method{method.Name: method}}, true
+	})
+
+	decoded, err := parseCalldata(contract, data, contractAbi, nil, preserve)
+	if err != nil {
+		return nil
+	}
+
+	return decoded
+}
+
+// resolveLog decodes a log with the signatures returned by the resolver. Signatures do not tell
+// which params are indexed, the first params are assumed to be indexed, one per topic.
+func resolveLog(resolver SignatureResolver, vLog *types.Log, preserve bool) *DecodedLog {
+	if len(vLog.Topics) == 0 {
+		return nil
+	}
+
+	topic := vLog.Topics[0].Hex()
+	key := fmt.Sprintf("event:%s:%d", topic, len(vLog.Topics))
+	contractAbi := resolveABI(key, func(ctx context.Context) ([]string, error) {
+		return resolver.ResolveEvent(ctx, topic)
+	}, func(signature string) (abi.ABI, bool) {
+		event, err := eventFromSignature(signature, len(vLog.Topics)-1)
+		if err != nil || event.ID != vLog.Topics[0] {
+			return abi.ABI{}, false
+		}
+
+		if _, err := event.Inputs.NonIndexed().Unpack(vLog.Data); err != nil {
+			return abi.ABI{}, false
+		}
+
+		return abi.ABI{Events: map[string]abi.Event{event.Name: event}}, true
+	})
+
+	if len(contractAbi.Events) == 0 {
+		return nil
+	}
+
+	return parseLog(vLog, contractAbi, nil, preserve)
+}
+
+// resolveABI returns the cached ABI for the key, or resolves the signatures and keeps the ABI of
+// the first signature accepted by build. Failed lookups are not cached, so they are retried.
+func resolveABI(key string, lookup func(ctx context.Context) ([]string, error), build func(signature string) (abi.ABI, bool)) abi.ABI {
+	resolved.RLock()
+	cached, ok := resolved.abis[key]
+	resolved.RUnlock()
+
+	if ok {
+		return cached
+	}
+
+	ctx, cancel := withTimeout(context.Background(), ResolveTimeout)
+	defer cancel()
+
+	// the signatures, not the ABIs built from them, are shared through the Cache
+	var signatures []string
+	if !cacheGet(ctx, "signatures:"+key, &signatures) {
+		var err error
+		if signatures, err = lookup(ctx); err != nil {
+			return abi.ABI{}
+		}
+		cacheSet(ctx, "signatures:"+key, signatures)
+	}
+
+	var result abi.ABI
+	for _, signature := range signatures {
+		if contractAbi, ok := build(signature); ok {
+			result = contractAbi
+			break
+		}
+	}
+
+	resolved.Lock()
+	resolved.abis[key] = result
+	resolved.Unlock()
+
+	return result
+}
+
+// methodFromSignature synthesizes a method from a text signature like "transfer(address,uint256)".
+func methodFromSignature(signature string) (abi.Method, error) {
+	name, inputs, err := parseSignature(signature)
+	if err != nil {
+		return abi.Method{}, err
+	}
+
+	return abi.NewMethod(name, name, abi.Function, "nonpayable", false, false, inputs, nil), nil
+}
+
+// eventFromSignature synthesizes an event from a text signature, marking the first params as
+// indexed.
+func eventFromSignature(signature string, indexed int) (abi.Event, error) {
+	name, inputs, err := parseSignature(signature)
+	if err != nil {
+		return abi.Event{}, err
+	}
+
+	if indexed > len(inputs) {
+		return abi.Event{}, fmt.Errorf("more topics than params in %s", signature)
+	}
+
+	for i := 0; i < indexed; i++ {
+		inputs[i].Indexed = true
+	}
+
+	return abi.NewEvent(name, name, false, inputs), nil
+}
+
+// parseSignature splits a text signature into its name and arguments. Arguments and tuple
+// components are named by position, as text signatures do not carry names.
+func parseSignature(signature string) (string, abi.Arguments, error) {
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return "", nil, fmt.Errorf("invalid signature: %s", signature)
+	}
+
+	types, err := splitTypes(signature[open+1 : len(signature)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid signature %s: %w", signature, err)
+	}
+
+	arguments := make(abi.Arguments, 0, len(types))
+	for i, raw := range types {
+		t, err := typeFromString(raw)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid signature %s: %w", signature, err)
+		}
+		arguments = append(arguments, abi.Argument{Name: fmt.Sprint(i), Type: t})
+	}
+
+	return signature[:open], arguments, nil
+}
+
+func typeFromString(raw string) (abi.Type, error) {
+	if !strings.HasPrefix(raw, "(") {
+		return abi.NewType(raw, "", nil)
+	}
+
+	end := strings.LastIndex(raw, ")")
+	components, err := tupleComponents(raw[1:end])
+	if err != nil {
+		return abi.Type{}, err
+	}
+
+	return abi.NewType("tuple"+raw[end+1:], "", components)
+}
+
+func tupleComponents(list string) ([]abi.ArgumentMarshaling, error) {
+	types, err := splitTypes(list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]abi.ArgumentMarshaling, 0, len(types))
+	for i, raw := range types {
+		component := abi.ArgumentMarshaling{Name: fmt.Sprintf("field%d", i), Type: raw}
+
+		if strings.HasPrefix(raw, "(") {
+			end := strings.LastIndex(raw, ")")
+			if component.Components, err = tupleComponents(raw[1:end]); err != nil {
+				return nil, err
+			}
+			component.Type = "tuple" + raw[end+1:]
+		}
+
+		result = append(result, component)
+	}
+
+	return result, nil
+}
+
+// splitTypes splits a comma separated type list at the top level, keeping tuples intact.
+func splitTypes(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	var result []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case ',':
+			if depth == 0 {
+				result = append(result, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+
+	return append(result, list[start:]), nil
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSignatureResolvers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/signatures/":
+			w.Write([]byte(`{"results":[{"id":2,"text_signature":"spam(uint256)"},{"id":1,"text_signature":"transfer(address,uint256)"}]}`))
+		case "/signature-database/v1/lookup":
+			w.Write([]byte(`{"ok":true,"result":{"event":{"` + r.URL.Query().Get("event") + `":[{"name":"Transfer(address,address,uint256)"}]}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	functions, err := (&FourByteResolver{BaseURL: server.URL}).ResolveFunction(context.Background(), "0xa9059cbb")
+	if err != nil || len(functions) != 2 || functions[0] != "transfer(address,uint256)" {
+		t.Fatalf("invalid 4byte result: %v %v", functions, err)
+	}
+
+	events, err := (&OpenchainResolver{BaseURL: server.URL}).ResolveEvent(context.Background(), TransferTopic)
+	if err != nil || len(events) != 1 || events[0] != "Transfer(address,address,uint256)" {
+		t.Fatalf("invalid openchain result: %v %v", events, err)
+	}
+}
+
+type staticResolver map[string][]string
+
+func (r staticResolver) ResolveFunction(ctx context.Context, selector string) ([]string, error) {
+	return r[selector], nil
+}
+
+func (r staticResolver) ResolveEvent(ctx context.Context, topic string) ([]string, error) {
+	return r[topic], nil
+}
+
+func TestStoreResolver(t *testing.T) {
+	defer ResetResolvedSignatures()
+
+	method, err := methodFromSignature("fill((address,uint256)[],bytes32)")
+	if err != nil || method.Sig != "fill((address,uint256)[],bytes32)" {
+		t.Fatalf("invalid tuple signature: %v %v", method.Sig, err)
+	}
+
+	erc20 := ParseABI(abi_erc20)
+	store := Storage{Resolver: staticResolver{
+		"0xa9059cbb":  {"transfer(address,uint256)"},
+		TransferTopic: {"Transfer(address,address,uint256)"},
+	}}
+
+	data, _ := erc20.Pack("transfer", common.HexToAddress(target_contract), big.NewInt(9))
+	to := common.HexToAddress(target_erc20)
+	decoded := store.DecodeMethod(types.NewTransaction(0, to, big.NewInt(0), 0, big.NewInt(0), data))
+	if decoded == nil || decoded.Signature != "transfer(address,uint256)" || decoded.Params["1"] != "9" {
+		t.Fatalf("invalid resolved method: %v", decoded)
+	}
+
+	log := store.DecodeLog(&types.Log{
+		Address: to,
+		Topics: []common.Hash{
+			common.HexToHash(TransferTopic),
+			common.BytesToHash(common.HexToAddress(target_contract).Bytes()),
+			common.BytesToHash(common.HexToAddress(target_erc721).Bytes()),
+		},
+		Data: common.LeftPadBytes(big.NewInt(3).Bytes(), 32),
+	})
+	if log == nil || log.Name != "Transfer" || log.Params["2"] != "3" || log.Params["0"] != common.HexToAddress(target_contract).Hex() {
+		t.Fatalf("invalid resolved log: %v", log)
+	}
+}
+
+func TestEmbeddedSelectorDB(t *testing.T) {
+	if len(selectorData) == 0 {
+		t.Skip("built without the selector database")
+	}
+
+	if signatures := LookupSelector("0xA9059CBB"); len(signatures) != 1 || signatures[0] != "transfer(address,uint256)" {
+		t.Fatalf("invalid selector lookup: %v", signatures)
+	}
+
+	if signatures := LookupTopic(TransferTopic); len(signatures) != 1 || signatures[0] != "Transfer(address,address,uint256)" {
+		t.Fatalf("invalid topic lookup: %v", signatures)
+	}
+
+	if signatures := LookupSelector("0xdeadbeef"); signatures != nil {
+		t.Fatalf("unknown selector resolved: %v", signatures)
+	}
+}
+
+func TestRecoverABI(t *testing.T) {
+	if len(selectorData) == 0 {
+		t.Skip("built without the selector database")
+	}
+
+	// dispatcher comparing transfer, balanceOf and an unknown selector
+	code := "0x63a9059cbb146370a08231146312345678146000"
+
+	recovered := RecoverABI(code, nil)
+	if len(recovered.Methods) != 2 || recovered.Methods["transfer"].Sig != "transfer(address,uint256)" {
+		t.Fatalf("invalid recovered abi: %v", recovered.Methods)
+	}
+
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	store := Storage{Indexed: make(map[string]*IndexedABI)}
+	indexed, err := store.IndexRecovered(target_erc20, &code, nil)
+	if err != nil || !indexed.Guessed || indexed.Verified {
+		t.Fatalf("invalid recovered contract: %v %v", indexed, err)
+	}
+
+	data, _ := ParseABI(abi_erc20).Pack("transfer", common.HexToAddress(target_erc20), big.NewInt(1))
+	decoder := indexed.GetDecoder()
+	if decoded, err := decoder.DecodeCalldata(data); err != nil || decoded == nil || decoded.Name != "transfer" {
+		t.Fatalf("recovered abi not decoding: %v %v", decoded, err)
+	}
+
+	if _, err := store.IndexRecovered(target_contract, new(string), nil); err == nil {
+		t.Fatal("contract without bytecode recovered")
+	}
+}
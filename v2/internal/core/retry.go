@@ -0,0 +1,345 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RetryPolicy configures the retries of failed RPC calls. Calls are attempted up to MaxAttempts
+// times, waiting InitialBackoff after the first failure and Multiplier times longer after every
+// further one, up to MaxBackoff. Transactions are sent once and subscriptions are not retried.
+type RetryPolicy struct {
+	MaxAttempts    int                  // Attempts of a call including the first one, values below 2 disable retries.
+	InitialBackoff time.Duration        // Wait after the first failure, defaults to 250 milliseconds.
+	MaxBackoff     time.Duration        // Upper bound of the wait, defaults to 10 seconds.
+	Multiplier     float64              // Growth of the wait per attempt, defaults to 2.
+	Jitter         float64              // Fraction the wait is randomized by, e.g. 0.2 for +-20%.
+	Retryable      func(err error) bool // Classifies errors worth retrying, defaults to IsRetryable.
+}
+
+// DefaultRetryPolicy is a policy suitable for public RPC endpoints.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+var retryPolicy = struct {
+	sync.RWMutex
+	policy *RetryPolicy
+}{}
+
+// SetRetryPolicy sets the retry policy of all backends, nil disables retries. Decoders and stores
+// with their own Retry policy use that instead. An invalid policy fails with ValidationErrors and
+// keeps the previous one.
+func SetRetryPolicy(policy *RetryPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	retryPolicy.Lock()
+	defer retryPolicy.Unlock()
+	retryPolicy.policy = policy
+	return nil
+}
+
+// GetRetryPolicy returns the global retry policy, nil if retries are disabled.
+func GetRetryPolicy() *RetryPolicy {
+	retryPolicy.RLock()
+	defer retryPolicy.RUnlock()
+	return retryPolicy.policy
+}
+
+// IsRetryable reports whether the error is transient: transport errors, timeouts, rate limits and
+// server errors of the endpoint. JSON-RPC errors of the node, e.g. reverts, ethereum.NotFound and
+// cancelled contexts are final.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// limit exceeded and rate limits of common providers
+		return rpcErr.ErrorCode() == -32005 || rpcErr.ErrorCode() == 429
+	}
+
+	var unsupported unsupportedError
+	if errors.As(err, &unsupported) {
+		return false
+	}
+
+	// transport errors and timeouts
+	return true
+}
+
+// backoff returns the wait after the given failed attempt, starting at 1.
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	initial, max, multiplier := policy.InitialBackoff, policy.MaxBackoff, policy.Multiplier
+	if initial <= 0 {
+		initial = 250 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 10 * time.Second
+	}
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	wait := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if policy.Jitter > 0 {
+		wait *= 1 + policy.Jitter*(2*rand.Float64()-1)
+	}
+
+	if wait > float64(max) {
+		return max
+	}
+
+	return time.Duration(wait)
+}
+
+// do runs the call until it succeeds, fails with an error that is not retryable or runs out of
+// attempts. The error of the last attempt is returned.
+func (policy *RetryPolicy) do(ctx context.Context, call func() error) error {
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-getClock().After(policy.backoff(attempt)):
+		}
+	}
+}
+
+// retryPolicyOf returns the retry policy the backend is wrapped with, a policy without retries for
+// other backends.
+func retryPolicyOf(backend Backend) *RetryPolicy {
+	if retry, ok := backend.(*retryBackend); ok {
+		return retry.policy
+	}
+
+	return &RetryPolicy{}
+}
+
+// callContext calls the method with the JSON-RPC client of the backend, retried with its retry
+// policy.
+func callContext(ctx context.Context, backend Backend, result interface{}, method string, args ...interface{}) error {
+	client := rpcClient(backend)
+	return retryPolicyOf(backend).do(ctx, func() error {
+		return client.CallContext(ctx, result, method, args...)
+	})
+}
+
+// retrying wraps the backend with the retry policy, the global one if policy is nil. Backends are
+// returned as is without a policy, backends already wrapped keep their policy unless one is given.
+func retrying(backend Backend, policy *RetryPolicy) Backend {
+	if backend == nil {
+		return nil
+	}
+
+	if wrapped, ok := backend.(*retryBackend); ok {
+		if policy == nil {
+			return backend
+		}
+		backend = wrapped.Backend
+	}
+
+	if policy == nil {
+		policy = GetRetryPolicy()
+	}
+	if policy == nil || policy.MaxAttempts < 2 {
+		return backend
+	}
+
+	return &retryBackend{Backend: backend, policy: policy}
+}
+
+// retryBackend retries the calls of its backend with the policy.
+type retryBackend struct {
+	Backend
+	policy *RetryPolicy
+}
+
+// Client returns the JSON-RPC client of the backend, see rpcClient.
+func (b *retryBackend) Client() *rpc.Client {
+	return rpcClient(b.Backend)
+}
+
+func (b *retryBackend) ChainID(ctx context.Context) (id *big.Int, err error) {
+	err = b.policy.do(ctx, func() error {
+		id, err = backendChainID(ctx, b.Backend)
+		return err
+	})
+	return id, err
+}
+
+func (b *retryBackend) BlockNumber(ctx context.Context) (number uint64, err error) {
+	err = b.policy.do(ctx, func() error {
+		number, err = latestBlockNumber(ctx, b.Backend)
+		return err
+	})
+	return number, err
+}
+
+func (b *retryBackend) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (sender common.Address, err error) {
+	err = b.policy.do(ctx, func() error {
+		sender, err = transactionSender(ctx, b.Backend, tx, block, index)
+		return err
+	})
+	return sender, err
+}
+
+func (b *retryBackend) BlockByHash(ctx context.Context, hash common.Hash) (block *types.Block, err error) {
+	err = b.policy.do(ctx, func() error {
+		block, err = b.Backend.BlockByHash(ctx, hash)
+		return err
+	})
+	return block, err
+}
+
+func (b *retryBackend) BlockByNumber(ctx context.Context, number *big.Int) (block *types.Block, err error) {
+	err = b.policy.do(ctx, func() error {
+		block, err = b.Backend.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+func (b *retryBackend) HeaderByHash(ctx context.Context, hash common.Hash) (header *types.Header, err error) {
+	err = b.policy.do(ctx, func() error {
+		header, err = b.Backend.HeaderByHash(ctx, hash)
+		return err
+	})
+	return header, err
+}
+
+func (b *retryBackend) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = b.policy.do(ctx, func() error {
+		header, err = b.Backend.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (b *retryBackend) TransactionCount(ctx context.Context, blockHash common.Hash) (count uint, err error) {
+	err = b.policy.do(ctx, func() error {
+		count, err = b.Backend.TransactionCount(ctx, blockHash)
+		return err
+	})
+	return count, err
+}
+
+func (b *retryBackend) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (tx *types.Transaction, err error) {
+	err = b.policy.do(ctx, func() error {
+		tx, err = b.Backend.TransactionInBlock(ctx, blockHash, index)
+		return err
+	})
+	return tx, err
+}
+
+func (b *retryBackend) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = b.policy.do(ctx, func() error {
+		tx, isPending, err = b.Backend.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+func (b *retryBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (receipt *types.Receipt, err error) {
+	err = b.policy.do(ctx, func() error {
+		receipt, err = b.Backend.TransactionReceipt(ctx, hash)
+		return err
+	})
+	return receipt, err
+}
+
+func (b *retryBackend) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = b.policy.do(ctx, func() error {
+		code, err = b.Backend.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+func (b *retryBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = b.policy.do(ctx, func() error {
+		result, err = b.Backend.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (b *retryBackend) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = b.policy.do(ctx, func() error {
+		code, err = b.Backend.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+func (b *retryBackend) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = b.policy.do(ctx, func() error {
+		nonce, err = b.Backend.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (b *retryBackend) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = b.policy.do(ctx, func() error {
+		price, err = b.Backend.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+func (b *retryBackend) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = b.policy.do(ctx, func() error {
+		tip, err = b.Backend.SuggestGasTipCap(ctx)
+		return err
+	})
+	return tip, err
+}
+
+func (b *retryBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	err = b.policy.do(ctx, func() error {
+		gas, err = b.Backend.EstimateGas(ctx, msg)
+		return err
+	})
+	return gas, err
+}
+
+func (b *retryBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = b.policy.do(ctx, func() error {
+		logs, err = b.Backend.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
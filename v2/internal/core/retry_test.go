@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+	server.Respond("eth_getCode", "0x6001")
+	server.Respond("eth_getBlockByNumber", nil)
+	server.HandleCall(func(common.Address, []byte) ([]byte, error) {
+		return nil, mockrpc.ErrReverted
+	})
+
+	client, err := DialWithOptions(context.Background(), server.URL, ConnectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	eth := Ctx.eth
+	Ctx.eth = client
+	defer func() { Ctx.eth = eth }()
+
+	SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	defer SetRetryPolicy(nil)
+
+	if GetClient() != client {
+		t.Fatal("client not unwrapped")
+	}
+
+	// transient failures are retried
+	server.Enqueue("eth_getCode", mockrpc.Response{Status: 503}, mockrpc.Response{Status: 429})
+	code, err := GetBackend().CodeAt(context.Background(), common.Address{}, nil)
+	if err != nil || len(code) != 2 || len(server.Calls("eth_getCode")) != 3 {
+		t.Fatalf("call not retried: %x %v %v", code, err, len(server.Calls("eth_getCode")))
+	}
+
+	// so are batches
+	server.Enqueue("eth_getBlockByNumber", mockrpc.Response{Status: 502})
+	if headers, err := fetchHeaders(context.Background(), GetBackend(), []uint64{1}); err != nil || headers[0] != nil {
+		t.Fatalf("batch not retried: %v %v", headers, err)
+	}
+
+	// node errors are final
+	if _, err := GetBackend().CallContract(context.Background(), ethereum.CallMsg{}, nil); err == nil || len(server.Calls("eth_call")) != 1 {
+		t.Fatalf("revert retried: %v %v", err, len(server.Calls("eth_call")))
+	}
+
+	// decoders override the global policy
+	server.Reset()
+	server.Respond("eth_getCode", "0x6001")
+	server.Enqueue("eth_getCode", mockrpc.Response{Status: 503})
+
+	decoder := &AbiDecoder{Retry: &RetryPolicy{MaxAttempts: 1}}
+	if _, err := decoder.GetBackend().CodeAt(context.Background(), common.Address{}, nil); err == nil || len(server.Calls("eth_getCode")) != 1 {
+		t.Fatalf("decoder policy not applied: %v", err)
+	}
+
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for attempt, expected := range []time.Duration{100, 200, 300, 300} {
+		if wait := policy.backoff(attempt + 1); wait != expected*time.Millisecond {
+			t.Fatalf("invalid backoff of attempt %d: %v", attempt+1, wait)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	errorSelector = common.FromHex("0x08c379a0") // Error(string)
+	panicSelector = common.FromHex("0x4e487b71") // Panic(uint256)
+)
+
+// panicReasons maps the solidity panic codes to their meaning.
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// DecodedError is a struct for holding decoded revert payloads. It implements error, so decode
+// functions can return it to surface the revert reason of a failed transaction.
+type DecodedError struct {
+	TransactionHash string `json:"transactionHash,omitempty"` // Transaction hash of the failed transaction.
+	SigHash         string `json:"sigHash"`                   // Error selector of the revert payload.
+	Signature       string `json:"signature"`                 // Error signature, e.g. Error(string).
+	Reason          string `json:"reason"`                    // Human readable revert reason.
+	Params          Params `json:"params"`                    // Decoded error parameters.
+	Raw             string `json:"raw"`                       // Raw revert payload.
+}
+
+// Error returns the revert reason.
+func (data *DecodedError) Error() string {
+	return "execution reverted: " + data.Reason
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedError object.
+func (data *DecodedError) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedError object.
+func (data *DecodedError) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// DecodeRevert decodes a revert payload. Builtin Error(string) and Panic(uint256) payloads are
+// always supported, custom errors are resolved against the errors of the loaded ABI.
+func (decoder *AbiDecoder) DecodeRevert(data []byte) (*DecodedError, error) {
+	var abis []abi.ABI
+	if decoder.Abi != nil {
+		abis = append(abis, *decoder.Abi)
+	}
+
+	return parseRevert(data, abis...)
+}
+
+// DecodeRevert decodes a revert payload against the builtin errors and the custom errors of all
+// ABIs in Store.AbiList.
+func (store *Storage) DecodeRevert(data []byte) (*DecodedError, error) {
+	return parseRevert(data, store.AbiList...)
+}
+
+// parseRevert decodes the revert payload using the builtin errors first and the custom errors of
+// the given ABIs afterwards.
+func parseRevert(data []byte, abis ...abi.ABI) (*DecodedError, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("revert payload too short: %s", hexutil.Encode(data))
+	}
+
+	selector := data[:4]
+	result := DecodedError{
+		SigHash: hexutil.Encode(selector),
+		Raw:     hexutil.Encode(data),
+		Params:  Params{},
+	}
+
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Signature = "Error(string)"
+		result.Reason = reason
+		result.Params["reason"] = reason
+		return &result, nil
+
+	case bytes.Equal(selector, panicSelector):
+		if len(data) != 36 {
+			return nil, fmt.Errorf("invalid panic payload: %s", result.Raw)
+		}
+
+		code := new(big.Int).SetBytes(data[4:])
+		reason, ok := panicReasons[code.Uint64()]
+		if !ok || !code.IsUint64() {
+			reason = "unknown panic code"
+		}
+
+		result.Signature = "Panic(uint256)"
+		result.Reason = fmt.Sprintf("panic 0x%x: %s", code, reason)
+		result.Params["code"] = code.String()
+		return &result, nil
+	}
+
+	var id [4]byte
+	copy(id[:], selector)
+
+	for _, contractAbi := range abis {
+		customError, err := contractAbi.ErrorByID(id)
+		if err != nil {
+			continue
+		}
+
+		params, err := unpackArguments(customError.Inputs, data[4:])
+		if err != nil {
+			continue
+		}
+
+		result.Signature = customError.Sig
+		result.Params = formatParameters(params, nil, customError.Inputs...)
+
+		// render the custom error like solidity, e.g. InsufficientBalance(10, 20)
+		args, _ := json.Marshal(&result.Params)
+		result.Reason = fmt.Sprintf("%s %s", customError.Name, string(args))
+		return &result, nil
+	}
+
+	return nil, fmt.Errorf("unknown error selector: %s", result.SigHash)
+}
+
+// fetchRevertData replays a failed transaction with eth_call on top of its parent block and
+// returns the revert payload reported by the node.
+func fetchRevertData(ctx context.Context, client Backend, tx *types.Transaction, receipt *types.Receipt) ([]byte, error) {
+	if tx.To() == nil {
+		return nil, fmt.Errorf("can not replay contract creation: %s", tx.Hash().Hex())
+	}
+
+	sender, err := txSender(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var block *big.Int
+	if receipt.BlockNumber != nil && receipt.BlockNumber.Sign() > 0 {
+		block = new(big.Int).Sub(receipt.BlockNumber, common.Big1)
+	}
+
+	_, err = client.CallContract(ctx, ethereum.CallMsg{
+		From:  sender,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, block)
+
+	if err == nil {
+		return nil, fmt.Errorf("replayed transaction did not revert: %s", tx.Hash().Hex())
+	}
+
+	dataErr, ok := err.(interface{ ErrorData() interface{} })
+	if !ok {
+		return nil, err
+	}
+
+	payload, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, err
+	}
+
+	return hexutil.Decode(payload)
+}
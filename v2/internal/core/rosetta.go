@@ -0,0 +1,180 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Rosetta operation types and statuses emitted by ToRosetta.
+const (
+	RosettaOpCall   = "CALL"
+	RosettaOpFee    = "FEE"
+	RosettaOpERC20  = "ERC20_TRANSFER"
+	RosettaOpERC721 = "ERC721_TRANSFER"
+	RosettaSuccess  = "SUCCESS"
+	RosettaFailure  = "FAILURE"
+)
+
+// RosettaNativeCurrency is the currency used for value transfers and fees of the connected chain.
+var RosettaNativeCurrency = RosettaCurrency{Symbol: "ETH", Decimals: 18}
+
+// RosettaTransaction is a transaction in the Rosetta Data API format.
+type RosettaTransaction struct {
+	TransactionIdentifier RosettaTransactionIdentifier `json:"transaction_identifier"`
+	Operations            []RosettaOperation           `json:"operations"`
+	Metadata              map[string]interface{}       `json:"metadata,omitempty"`
+}
+
+// RosettaTransactionIdentifier uniquely identifies a transaction.
+type RosettaTransactionIdentifier struct {
+	Hash string `json:"hash"`
+}
+
+// RosettaOperationIdentifier identifies an operation within a transaction.
+type RosettaOperationIdentifier struct {
+	Index int64 `json:"index"`
+}
+
+// RosettaOperation is a single balance changing operation of a transaction.
+type RosettaOperation struct {
+	OperationIdentifier RosettaOperationIdentifier   `json:"operation_identifier"`
+	RelatedOperations   []RosettaOperationIdentifier `json:"related_operations,omitempty"`
+	Type                string                       `json:"type"`
+	Status              string                       `json:"status"`
+	Account             RosettaAccount               `json:"account"`
+	Amount              *RosettaAmount               `json:"amount,omitempty"`
+	Metadata            map[string]interface{}       `json:"metadata,omitempty"`
+}
+
+// RosettaAccount is the account affected by an operation.
+type RosettaAccount struct {
+	Address string `json:"address"`
+}
+
+// RosettaAmount is a signed amount in the smallest unit of its currency.
+type RosettaAmount struct {
+	Value    string          `json:"value"`
+	Currency RosettaCurrency `json:"currency"`
+}
+
+// RosettaCurrency describes the asset of an amount. Token contracts are kept in the metadata.
+type RosettaCurrency struct {
+	Symbol   string                 `json:"symbol"`
+	Decimals uint8                  `json:"decimals"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the RosettaTransaction object.
+func (data *RosettaTransaction) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the RosettaTransaction object.
+func (data *RosettaTransaction) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ToRosetta converts a transaction, its optional receipt and its decoded logs into Rosetta
+// operations, based on the movements of SummarizeBalanceChanges. Native value transfers become a
+// CALL debit/credit pair, the paid fee becomes a FEE debit of the sender and every decoded Transfer
+// log becomes an ERC20 or ERC721 debit/credit pair. Failed transactions only carry the fee and
+// are marked with a FAILURE status in the transaction metadata.
+func ToRosetta(tx *types.Transaction, receipt *types.Receipt, logs []*DecodedLog) (*RosettaTransaction, error) {
+	summary, err := SummarizeBalanceChanges(tx, receipt, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := RosettaTransaction{
+		TransactionIdentifier: RosettaTransactionIdentifier{Hash: summary.TransactionHash},
+		Operations:            make([]RosettaOperation, 0),
+	}
+
+	if summary.Failed {
+		result.Metadata = map[string]interface{}{"status": RosettaFailure}
+	}
+
+	for _, movement := range summary.Movements {
+		switch movement.Kind {
+		case MovementFee:
+			result.add(RosettaOperation{
+				Type:    RosettaOpFee,
+				Status:  RosettaSuccess,
+				Account: RosettaAccount{Address: movement.From},
+				Amount:  &RosettaAmount{Value: negateAmount(movement.Amount.String()), Currency: RosettaNativeCurrency},
+			})
+
+		case MovementNative:
+			result.addPair(RosettaOpCall, movement.From, movement.To, movement.Amount.String(), RosettaNativeCurrency, nil)
+
+		case MovementERC20:
+			metadata := map[string]interface{}{"logIndex": *movement.LogIndex}
+			currency := rosettaTokenCurrency(movement.Asset)
+			result.addPair(RosettaOpERC20, movement.From, movement.To, movement.Amount.String(), currency, metadata)
+
+		case MovementERC721:
+			metadata := map[string]interface{}{"logIndex": *movement.LogIndex, "tokenId": movement.TokenId.String()}
+			currency := rosettaTokenCurrency(movement.Asset)
+			currency.Decimals = 0
+			result.addPair(RosettaOpERC721, movement.From, movement.To, movement.Amount.String(), currency, metadata)
+		}
+	}
+
+	return &result, nil
+}
+
+// add appends an operation and assigns its index.
+func (data *RosettaTransaction) add(op RosettaOperation) int64 {
+	op.OperationIdentifier.Index = int64(len(data.Operations))
+	data.Operations = append(data.Operations, op)
+	return op.OperationIdentifier.Index
+}
+
+// addPair appends a debit of from and a related credit of to.
+func (data *RosettaTransaction) addPair(opType, from, to, value string, currency RosettaCurrency, metadata map[string]interface{}) {
+	debit := data.add(RosettaOperation{
+		Type:     opType,
+		Status:   RosettaSuccess,
+		Account:  RosettaAccount{Address: common.HexToAddress(from).Hex()},
+		Amount:   &RosettaAmount{Value: negateAmount(value), Currency: currency},
+		Metadata: metadata,
+	})
+
+	data.add(RosettaOperation{
+		RelatedOperations: []RosettaOperationIdentifier{{Index: debit}},
+		Type:              opType,
+		Status:            RosettaSuccess,
+		Account:           RosettaAccount{Address: common.HexToAddress(to).Hex()},
+		Amount:            &RosettaAmount{Value: value, Currency: currency},
+		Metadata:          metadata,
+	})
+}
+
+// rosettaTokenCurrency builds the currency of a token contract from TknStore without querying the
+// chain. Unknown tokens use the contract address as symbol.
+func rosettaTokenCurrency(contract string) RosettaCurrency {
+	address := common.HexToAddress(contract)
+	currency := RosettaCurrency{
+		Symbol:   address.Hex(),
+		Metadata: map[string]interface{}{"contract": address.Hex()},
+	}
+
+	if info := TknStore.Cached(address); info != nil {
+		currency.Symbol = info.Symbol
+		currency.Decimals = info.Decimals
+	}
+
+	return currency
+}
+
+func negateAmount(value string) string {
+	if value == "0" || strings.HasPrefix(value, "-") {
+		return value
+	}
+
+	return "-" + value
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestToRosetta(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress(target_contract)
+	token, nft := common.HexToAddress("0x5e"), common.HexToAddress(target_erc721)
+
+	TknStore.Set(&ITknInfo{Address: token, IsERC20: true, Symbol: "TKN", Decimals: 6})
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{
+		To:       &to,
+		Value:    big.NewInt(500),
+		Gas:      21000,
+		GasPrice: big.NewInt(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(2),
+	}
+
+	logs := []*DecodedLog{{
+		Contract: token.Hex(),
+		Topic:    TransferTopic,
+		LogIndex: 1,
+		Params:   Params{"from": sender.Hex(), "to": to.Hex(), "value": "1000"},
+	}, {
+		Contract: nft.Hex(),
+		Topic:    TransferTopic,
+		LogIndex: 2,
+		Params:   Params{"from": to.Hex(), "to": sender.Hex(), "tokenId": "7"},
+	}}
+
+	rosetta, err := ToRosetta(tx, receipt, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ops := rosetta.Operations
+	if rosetta.TransactionIdentifier.Hash != tx.Hash().Hex() || len(ops) != 7 || rosetta.Metadata != nil {
+		t.Fatalf("invalid rosetta transaction: %s", rosetta.ToJSON())
+	}
+
+	// native value, fee, token and nft transfers, debits followed by their related credits
+	for i, expected := range []struct {
+		opType, account, value string
+	}{
+		{RosettaOpCall, sender.Hex(), "-500"},
+		{RosettaOpCall, to.Hex(), "500"},
+		{RosettaOpFee, sender.Hex(), "-42000"},
+		{RosettaOpERC20, sender.Hex(), "-1000"},
+		{RosettaOpERC20, to.Hex(), "1000"},
+		{RosettaOpERC721, to.Hex(), "-1"},
+		{RosettaOpERC721, sender.Hex(), "1"},
+	} {
+		op := ops[i]
+		if op.OperationIdentifier.Index != int64(i) || op.Type != expected.opType || op.Account.Address != expected.account || op.Amount.Value != expected.value {
+			t.Fatalf("invalid operation %d: %s", i, rosetta.ToJSON())
+		}
+	}
+
+	if ops[4].RelatedOperations[0].Index != 3 || ops[2].RelatedOperations != nil {
+		t.Fatalf("invalid related operations: %s", rosetta.ToJSON())
+	}
+	if currency := ops[3].Amount.Currency; currency.Symbol != "TKN" || currency.Decimals != 6 || currency.Metadata["contract"] != token.Hex() {
+		t.Fatalf("invalid token currency: %+v", currency)
+	}
+	if currency := ops[5].Amount.Currency; currency.Symbol != nft.Hex() || currency.Decimals != 0 || ops[5].Metadata["tokenId"] != "7" {
+		t.Fatalf("invalid nft currency: %s", rosetta.ToJSON())
+	}
+
+	// failed transactions only pay the fee
+	receipt.Status = types.ReceiptStatusFailed
+	failed, err := ToRosetta(tx, receipt, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failed.Operations) != 1 || failed.Operations[0].Type != RosettaOpFee || failed.Metadata["status"] != RosettaFailure {
+		t.Fatalf("invalid failed transaction: %s", failed.ToJSON())
+	}
+}
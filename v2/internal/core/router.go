@@ -0,0 +1,259 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Routers of the calls decoded by DecodeRouterCall.
+const (
+	RouterUniversal  = "uniswap-universal-router"
+	RouterAggregator = "1inch"
+)
+
+// abi_routers holds the execute methods of the Uniswap Universal Router and the swap methods of the
+// 1inch aggregation router.
+const abi_routers = `[{"inputs":[{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"},{"name":"deadline","type":"uint256"}],"name":"execute","outputs":[],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"}],"name":"execute","outputs":[],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"executor","type":"address"},{"components":[{"name":"srcToken","type":"address"},{"name":"dstToken","type":"address"},{"name":"srcReceiver","type":"address"},{"name":"dstReceiver","type":"address"},{"name":"amount","type":"uint256"},{"name":"minReturnAmount","type":"uint256"},{"name":"flags","type":"uint256"}],"name":"desc","type":"tuple"},{"name":"permit","type":"bytes"},{"name":"data","type":"bytes"}],"name":"swap","outputs":[{"name":"returnAmount","type":"uint256"},{"name":"spentAmount","type":"uint256"}],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"srcToken","type":"address"},{"name":"amount","type":"uint256"},{"name":"minReturn","type":"uint256"},{"name":"pools","type":"uint256[]"}],"name":"unoswap","outputs":[{"name":"returnAmount","type":"uint256"}],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"recipient","type":"address"},{"name":"srcToken","type":"address"},{"name":"amount","type":"uint256"},{"name":"minReturn","type":"uint256"},{"name":"pools","type":"uint256[]"}],"name":"unoswapTo","outputs":[{"name":"returnAmount","type":"uint256"}],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"amount","type":"uint256"},{"name":"minReturn","type":"uint256"},{"name":"pools","type":"uint256[]"}],"name":"uniswapV3Swap","outputs":[{"name":"returnAmount","type":"uint256"}],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"},{"name":"minReturn","type":"uint256"},{"name":"pools","type":"uint256[]"}],"name":"uniswapV3SwapTo","outputs":[{"name":"returnAmount","type":"uint256"}],"stateMutability":"payable","type":"function"}]`
+
+// abi_router_commands holds the inputs of the Universal Router commands, each declared as a
+// function named like the command.
+const abi_router_commands = `[{"inputs":[{"name":"recipient","type":"address"},{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"bytes"},{"name":"payerIsUser","type":"bool"}],"name":"V3_SWAP_EXACT_IN","type":"function"},{"inputs":[{"name":"recipient","type":"address"},{"name":"amountOut","type":"uint256"},{"name":"amountInMax","type":"uint256"},{"name":"path","type":"bytes"},{"name":"payerIsUser","type":"bool"}],"name":"V3_SWAP_EXACT_OUT","type":"function"},{"inputs":[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"amount","type":"uint160"}],"name":"PERMIT2_TRANSFER_FROM","type":"function"},{"inputs":[{"components":[{"components":[{"name":"token","type":"address"},{"name":"amount","type":"uint160"},{"name":"expiration","type":"uint48"},{"name":"nonce","type":"uint48"}],"name":"details","type":"tuple[]"},{"name":"spender","type":"address"},{"name":"sigDeadline","type":"uint256"}],"name":"permitBatch","type":"tuple"},{"name":"signature","type":"bytes"}],"name":"PERMIT2_PERMIT_BATCH","type":"function"},{"inputs":[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"amountMin","type":"uint256"}],"name":"SWEEP","type":"function"},{"inputs":[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"value","type":"uint256"}],"name":"TRANSFER","type":"function"},{"inputs":[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"bips","type":"uint256"}],"name":"PAY_PORTION","type":"function"},{"inputs":[{"name":"recipient","type":"address"},{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"payerIsUser","type":"bool"}],"name":"V2_SWAP_EXACT_IN","type":"function"},{"inputs":[{"name":"recipient","type":"address"},{"name":"amountOut","type":"uint256"},{"name":"amountInMax","type":"uint256"},{"name":"path","type":"address[]"},{"name":"payerIsUser","type":"bool"}],"name":"V2_SWAP_EXACT_OUT","type":"function"},{"inputs":[{"components":[{"components":[{"name":"token","type":"address"},{"name":"amount","type":"uint160"},{"name":"expiration","type":"uint48"},{"name":"nonce","type":"uint48"}],"name":"details","type":"tuple"},{"name":"spender","type":"address"},{"name":"sigDeadline","type":"uint256"}],"name":"permitSingle","type":"tuple"},{"name":"signature","type":"bytes"}],"name":"PERMIT2_PERMIT","type":"function"},{"inputs":[{"name":"recipient","type":"address"},{"name":"amountMin","type":"uint256"}],"name":"WRAP_ETH","type":"function"},{"inputs":[{"name":"recipient","type":"address"},{"name":"amountMin","type":"uint256"}],"name":"UNWRAP_WETH","type":"function"},{"inputs":[{"components":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint160"},{"name":"token","type":"address"}],"name":"batchDetails","type":"tuple[]"}],"name":"PERMIT2_TRANSFER_FROM_BATCH","type":"function"},{"inputs":[{"name":"owner","type":"address"},{"name":"token","type":"address"},{"name":"minBalance","type":"uint256"}],"name":"BALANCE_CHECK_ERC20","type":"function"}]`
+
+var (
+	routersAbi        = ParseABI(abi_routers)
+	routerCommandsAbi = ParseABI(abi_router_commands)
+)
+
+// RouterCommands maps the command types of the Universal Router to their names in
+// abi_router_commands. Commands missing here, e.g. of NFT marketplaces, are listed without
+// decoded params.
+var RouterCommands = map[byte]string{
+	0x00: "V3_SWAP_EXACT_IN",
+	0x01: "V3_SWAP_EXACT_OUT",
+	0x02: "PERMIT2_TRANSFER_FROM",
+	0x03: "PERMIT2_PERMIT_BATCH",
+	0x04: "SWEEP",
+	0x05: "TRANSFER",
+	0x06: "PAY_PORTION",
+	0x08: "V2_SWAP_EXACT_IN",
+	0x09: "V2_SWAP_EXACT_OUT",
+	0x0a: "PERMIT2_PERMIT",
+	0x0b: "WRAP_ETH",
+	0x0c: "UNWRAP_WETH",
+	0x0d: "PERMIT2_TRANSFER_FROM_BATCH",
+	0x0e: "BALANCE_CHECK_ERC20",
+}
+
+const (
+	// routerCommandMask selects the command type of a Universal Router command byte.
+	routerCommandMask = 0x3f
+	// routerAllowRevert is set on commands whose failure does not revert the execution.
+	routerAllowRevert = 0x80
+)
+
+const (
+	// poolReverseBit of the 1inch pools reverses the direction of the swap.
+	poolReverseBit = 255
+	// poolUnwrapV2Bit of unoswap pools and poolUnwrapV3Bit of uniswapV3Swap pools unwrap WETH
+	// after the swap.
+	poolUnwrapV2Bit = 254
+	poolUnwrapV3Bit = 253
+)
+
+var poolAddressMask = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+// swapDescription is the desc param of the 1inch swap method.
+type swapDescription struct {
+	SrcToken        common.Address
+	DstToken        common.Address
+	SrcReceiver     common.Address
+	DstReceiver     common.Address
+	Amount          *big.Int
+	MinReturnAmount *big.Int
+	Flags           *big.Int
+}
+
+// RouterOperation is a single step of a router call: a command of a Universal Router execute call
+// or a pool swap of an aggregator call.
+type RouterOperation struct {
+	Command     string   `json:"command,omitempty"`     // Hex encoded command byte, Universal Router only.
+	Name        string   `json:"name"`                  // Name of the command, UNKNOWN if it is not decoded.
+	AllowRevert bool     `json:"allowRevert,omitempty"` // Whether a failure of the command is ignored.
+	Params      Params   `json:"params,omitempty"`      // Decoded params of the operation.
+	Path        []string `json:"path,omitempty"`        // Tokens of a swap path, in swap order.
+	Fees        []uint32 `json:"fees,omitempty"`        // Pool fees between the tokens of a V3 path.
+	Input       string   `json:"input,omitempty"`       // Raw input of the command.
+}
+
+// RouterCall is a router call expanded into its operations, see DecodeRouterCall.
+type RouterCall struct {
+	Router     string            `json:"router"`     // One of the Router* constants.
+	Method     *DecodedMethod    `json:"method"`     // Decoded call of the router.
+	Operations []RouterOperation `json:"operations"` // Operations of the call, in execution order.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the RouterCall object.
+func (data *RouterCall) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the RouterCall object.
+func (data *RouterCall) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// IsRouterCall returns true if the calldata calls a method of the Universal Router or of the 1inch
+// aggregation router that DecodeRouterCall expands.
+func IsRouterCall(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+
+	method, err := routersAbi.MethodById(data[:4])
+	return err == nil && method != nil
+}
+
+// DecodeRouterCall decodes a call of the Uniswap Universal Router or of the 1inch aggregation
+// router and expands it into its operations. The packed commands of execute are split into one
+// operation per command with the params of its input, the pools of unoswap and uniswapV3Swap
+// into one operation per pool swap. It returns an error for calldata of other methods.
+func DecodeRouterCall(contract string, data []byte) (*RouterCall, error) {
+	if !IsRouterCall(data) {
+		return nil, fmt.Errorf("calldata of %s is no router call", contract)
+	}
+
+	method, _ := routersAbi.MethodById(data[:4])
+	params, err := unpackArguments(method.Inputs, data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method.Name, err)
+	}
+
+	decoded, err := parseCalldata(contract, data, *routersAbi, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := RouterCall{Router: RouterAggregator, Method: decoded, Operations: make([]RouterOperation, 0)}
+
+	switch method.RawName {
+	case "execute":
+		commands, _ := params["commands"].([]byte)
+		inputs, _ := params["inputs"].([][]byte)
+		if len(commands) != len(inputs) {
+			return nil, fmt.Errorf("execute: %d commands for %d inputs", len(commands), len(inputs))
+		}
+
+		result.Router = RouterUniversal
+		for i, command := range commands {
+			result.Operations = append(result.Operations, routerOperation(command, inputs[i]))
+		}
+
+	case "swap":
+		desc := *abi.ConvertType(params["desc"], new(swapDescription)).(*swapDescription)
+		data, _ := params["data"].([]byte)
+		result.Operations = append(result.Operations, RouterOperation{
+			Name: "SWAP",
+			Params: formatParameters(Params{
+				"executor":        params["executor"],
+				"srcToken":        desc.SrcToken,
+				"dstToken":        desc.DstToken,
+				"dstReceiver":     desc.DstReceiver,
+				"amount":          desc.Amount,
+				"minReturnAmount": desc.MinReturnAmount,
+			}, nil),
+			Path:  []string{desc.SrcToken.Hex(), desc.DstToken.Hex()},
+			Input: hexutil.Encode(data),
+		})
+
+	case "unoswap", "unoswapTo":
+		pools, _ := params["pools"].([]*big.Int)
+		result.Operations = append(result.Operations, poolOperations("V2_POOL_SWAP", pools, poolUnwrapV2Bit)...)
+
+	case "uniswapV3Swap", "uniswapV3SwapTo":
+		pools, _ := params["pools"].([]*big.Int)
+		result.Operations = append(result.Operations, poolOperations("V3_POOL_SWAP", pools, poolUnwrapV3Bit)...)
+	}
+
+	return &result, nil
+}
+
+// routerOperation decodes a single Universal Router command and its input. Inputs that do not
+// match the command are kept raw.
+func routerOperation(command byte, input []byte) RouterOperation {
+	result := RouterOperation{
+		Command:     hexutil.Encode([]byte{command}),
+		Name:        "UNKNOWN",
+		AllowRevert: command&routerAllowRevert != 0,
+		Input:       hexutil.Encode(input),
+	}
+
+	name, ok := RouterCommands[command&routerCommandMask]
+	if !ok {
+		return result
+	}
+
+	result.Name = name
+	arguments := routerCommandsAbi.Methods[name].Inputs
+	params, err := unpackArguments(arguments, input)
+	if err != nil {
+		return result
+	}
+
+	switch path := params["path"].(type) {
+	case []byte:
+		result.Path, result.Fees = unpackV3Path(path)
+	case []common.Address:
+		for _, token := range path {
+			result.Path = append(result.Path, token.Hex())
+		}
+	}
+
+	result.Params = formatParameters(params, nil, arguments...)
+	return result
+}
+
+// unpackV3Path splits a packed Uniswap V3 path into its tokens and the fees of the pools between
+// them. A path is encoded as token (20 bytes), followed by fee (3 bytes) and token for every pool.
+func unpackV3Path(path []byte) ([]string, []uint32) {
+	if len(path) < 20 || (len(path)-20)%23 != 0 {
+		return nil, nil
+	}
+
+	tokens := []string{common.BytesToAddress(path[:20]).Hex()}
+	fees := make([]uint32, 0)
+	for offset := 20; offset < len(path); offset += 23 {
+		fee := uint32(path[offset])<<16 | uint32(path[offset+1])<<8 | uint32(path[offset+2])
+		fees = append(fees, fee)
+		tokens = append(tokens, common.BytesToAddress(path[offset+3:offset+23]).Hex())
+	}
+
+	return tokens, fees
+}
+
+// poolOperations expands the packed pools of a 1inch unoswap or uniswapV3Swap call. Every pool
+// holds the pool address in its low 160 bits and flags for the direction and unwrapping in its
+// high bits.
+func poolOperations(name string, pools []*big.Int, unwrapBit int) []RouterOperation {
+	result := make([]RouterOperation, 0, len(pools))
+
+	for _, pool := range pools {
+		address := common.BigToAddress(new(big.Int).And(pool, poolAddressMask))
+		result = append(result, RouterOperation{
+			Name: name,
+			Params: Params{
+				"pool":       address.Hex(),
+				"zeroForOne": pool.Bit(poolReverseBit) == 0,
+				"unwrapWeth": pool.Bit(unwrapBit) == 1,
+			},
+			Input: hexutil.EncodeBig(pool),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDecodeRouterCall(t *testing.T) {
+	router := "0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+	usdc := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	weth := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	pack := func(name string, args ...interface{}) []byte {
+		input, err := routerCommandsAbi.Methods[name].Inputs.Pack(args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return input
+	}
+
+	// weth (20 bytes), fee 500 (3 bytes), usdc (20 bytes)
+	path := append(append(weth.Bytes(), 0x00, 0x01, 0xf4), usdc.Bytes()...)
+	commands := []byte{0x0b, 0x00, 0x84, 0x1f}
+	inputs := [][]byte{
+		pack("WRAP_ETH", recipient, big.NewInt(2)),
+		pack("V3_SWAP_EXACT_IN", recipient, big.NewInt(2), big.NewInt(3000), path, false),
+		pack("SWEEP", usdc, recipient, big.NewInt(3000)),
+		{0x01, 0x02},
+	}
+
+	data, err := routersAbi.Pack("execute", commands, inputs, big.NewInt(1700000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call, err := DecodeRouterCall(router, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if call.Router != RouterUniversal || call.Method.Name != "execute" || len(call.Operations) != 4 {
+		t.Fatalf("unexpected router call: %s", call.ToJSON())
+	}
+
+	swap := call.Operations[1]
+	if swap.Name != "V3_SWAP_EXACT_IN" || swap.Params["amountOutMin"] != "3000" || swap.Params["recipient"] != recipient.Hex() {
+		t.Fatalf("unexpected swap: %+v", swap)
+	}
+	if len(swap.Path) != 2 || swap.Path[0] != weth.Hex() || swap.Path[1] != usdc.Hex() || len(swap.Fees) != 1 || swap.Fees[0] != 500 {
+		t.Fatalf("unexpected swap path: %v %v", swap.Path, swap.Fees)
+	}
+
+	if sweep := call.Operations[2]; sweep.Name != "SWEEP" || !sweep.AllowRevert || sweep.Params["token"] != usdc.Hex() {
+		t.Fatalf("unexpected sweep: %+v", sweep)
+	}
+	if unknown := call.Operations[3]; unknown.Name != "UNKNOWN" || unknown.Command != "0x1f" || unknown.Input != "0x0102" {
+		t.Fatalf("unexpected unknown command: %+v", unknown)
+	}
+
+	// 1inch: the first pool swaps token1 for token0 and unwraps WETH
+	poolAddress := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	pool := new(big.Int).SetBytes(poolAddress.Bytes())
+	reversed := new(big.Int).Set(pool)
+	reversed.SetBit(reversed, poolReverseBit, 1).SetBit(reversed, poolUnwrapV3Bit, 1)
+
+	data, err = routersAbi.Pack("uniswapV3Swap", big.NewInt(1000), big.NewInt(1), []*big.Int{reversed, pool})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call, err = DecodeRouterCall(router, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if call.Router != RouterAggregator || len(call.Operations) != 2 {
+		t.Fatalf("unexpected aggregator call: %s", call.ToJSON())
+	}
+
+	first, second := call.Operations[0].Params, call.Operations[1].Params
+	if first["pool"] != poolAddress.Hex() || first["zeroForOne"] != false || first["unwrapWeth"] != true {
+		t.Fatalf("unexpected first pool: %v", first)
+	}
+	if second["zeroForOne"] != true || second["unwrapWeth"] != false {
+		t.Fatalf("unexpected second pool: %v", second)
+	}
+
+	if _, err := DecodeRouterCall(router, []byte{1, 2, 3, 4}); err == nil {
+		t.Fatal("expected an error for other calldata")
+	}
+}
@@ -0,0 +1,369 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ScanChunkSize is the number of blocks requested with a single eth_getLogs call by ScanLogs and
+// ScanEvents.
+var ScanChunkSize uint64 = 2000
+
+// ScanOptions configures ScanLogs. A nil ScanOptions uses the defaults.
+type ScanOptions struct {
+	ChunkSize    uint64 // Blocks per eth_getLogs call, defaults to ScanChunkSize.
+	MinChunkSize uint64 // Smallest window a rejected range is split into, defaults to 1.
+	Concurrency  int    // Workers requesting chunks in parallel, defaults to 1.
+}
+
+// rangeLimitErrors are parts of the errors providers return for queries with too many results or
+// too large block ranges.
+var rangeLimitErrors = []string{
+	"more than",
+	"too many",
+	"limit exceeded",
+	"range too large",
+	"range is too large",
+	"block range",
+	"response size",
+	"query timeout",
+	"-32005",
+}
+
+// isRangeLimit returns true if the error is a provider limit of the size of log queries.
+func isRangeLimit(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, part := range rangeLimitErrors {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScanEvents is Store.ScanEvents, using the ABIs of the global store.
+func ScanEvents(ctx context.Context, eventName string, fromBlock uint64, toBlock uint64) (*ScannedLogs, error) {
+	return Store.ScanEvents(ctx, eventName, fromBlock, toBlock)
+}
+
+// ScanEvents fetches and decodes the logs of the event with the given name or signature of any
+// contract in the inclusive block range, see BuildFilter. A toBlock of 0 scans up to the latest
+// block. The range is requested in chunks of ScanChunkSize blocks, see ScanLogs.
+func (store *Storage) ScanEvents(ctx context.Context, eventName string, fromBlock uint64, toBlock uint64) (*ScannedLogs, error) {
+	filter, err := store.BuildFilter(eventName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectLogs(ctx, filter, fromBlock, toBlock, store.ScanLogs)
+}
+
+// ScanEvents is like Storage.ScanEvents with the ABI of the decoder, restricted to its
+// ContractAddress if set.
+func (decoder *AbiDecoder) ScanEvents(ctx context.Context, eventName string, fromBlock uint64, toBlock uint64) (*ScannedLogs, error) {
+	filter, err := decoder.BuildFilter(eventName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectLogs(ctx, filter, fromBlock, toBlock, decoder.ScanLogs)
+}
+
+func collectLogs(ctx context.Context, filter ethereum.FilterQuery, fromBlock uint64, toBlock uint64, scan func(context.Context, ethereum.FilterQuery, *ScanOptions, func(*DecodedLog) error) error) (*ScannedLogs, error) {
+	filter.FromBlock = new(big.Int).SetUint64(fromBlock)
+	if toBlock > 0 {
+		filter.ToBlock = new(big.Int).SetUint64(toBlock)
+	}
+
+	events := make(ScannedLogs, 0)
+	err := scan(ctx, filter, nil, func(decoded *DecodedLog) error {
+		events = append(events, *decoded)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &events, nil
+}
+
+// FilterLogEventsWithOptions is like FilterLogEvents, fetching the logs in chunks with the given
+// options, see ScanLogs. Unlike FilterLogEvents a nil FromBlock starts at block 0.
+func (decoder *AbiDecoder) FilterLogEventsWithOptions(ctx context.Context, filter ethereum.FilterQuery, options *ScanOptions) (*ScannedLogs, error) {
+	events := make(ScannedLogs, 0)
+	err := decoder.ScanLogs(ctx, filter, options, func(decoded *DecodedLog) error {
+		events = append(events, *decoded)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &events, nil
+}
+
+// ScanLogs fetches the logs matching the filter in chunks and streams the decoded logs to handle,
+// in block order. A nil FromBlock starts at block 0, a nil ToBlock ends at the latest block.
+// Chunks rejected by the provider for too many results are split in halves and retried, and
+// later chunks keep the smaller size. With a Concurrency above 1 the chunks are fetched by a pool
+// of workers and merged back into block and log index order; handle is still called from a single
+// goroutine. Scanning stops at the first error of handle.
+func (store *Storage) ScanLogs(ctx context.Context, filter ethereum.FilterQuery, options *ScanOptions, handle func(*DecodedLog) error) error {
+	client, err := store.requireClient()
+	if err != nil {
+		return err
+	}
+
+	return scanLogs(ctx, client, filter, options, func(vLog *types.Log) *DecodedLog {
+		return store.DecodeLogContext(ctx, vLog)
+	}, handle)
+}
+
+// ScanLogs is like Storage.ScanLogs with the ABI of the decoder.
+func (decoder *AbiDecoder) ScanLogs(ctx context.Context, filter ethereum.FilterQuery, options *ScanOptions, handle func(*DecodedLog) error) error {
+	client := decoder.GetBackend()
+	if client == nil {
+		return fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", decoder.ContractAddress)
+	}
+
+	return scanLogs(ctx, client, filter, options, func(vLog *types.Log) *DecodedLog {
+		return decoder.DecodeLogContext(ctx, vLog)
+	}, handle)
+}
+
+// logScanner holds the state of a running scan, the chunk size shrinks once the provider
+// rejected a range and applies to all chunks taken afterwards.
+type logScanner struct {
+	client Backend
+	filter ethereum.FilterQuery
+	min    uint64
+
+	mu   sync.Mutex
+	size uint64 // current chunk size
+	next uint64 // first block of the next chunk
+	to   uint64 // last block of the scan
+	seq  int    // sequence number of the next chunk
+	done bool
+}
+
+func scanLogs(ctx context.Context, client Backend, filter ethereum.FilterQuery, options *ScanOptions, decode func(*types.Log) *DecodedLog, handle func(*DecodedLog) error) error {
+	return scanRawLogs(ctx, client, filter, options, func(vLog *types.Log) error {
+		decoded := decode(vLog)
+		if decoded == nil {
+			return nil
+		}
+
+		return handle(decoded)
+	})
+}
+
+// scanRawLogs is scanLogs without decoding, handle is called with the logs of the node.
+func scanRawLogs(ctx context.Context, client Backend, filter ethereum.FilterQuery, options *ScanOptions, handle func(*types.Log) error) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if options == nil {
+		options = &ScanOptions{}
+	}
+
+	scanner := &logScanner{client: client, filter: filter, min: options.MinChunkSize, size: options.ChunkSize}
+	if scanner.size == 0 {
+		scanner.size = ScanChunkSize
+	}
+	if scanner.size == 0 {
+		scanner.size = 2000
+	}
+	if scanner.min == 0 {
+		scanner.min = 1
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var fromBlock, toBlock uint64
+	if filter.FromBlock != nil {
+		fromBlock = filter.FromBlock.Uint64()
+	}
+
+	if filter.ToBlock != nil {
+		toBlock = filter.ToBlock.Uint64()
+	} else {
+		latest, err := latestBlockNumber(ctx, client)
+		if err != nil {
+			return fmt.Errorf("error getting block number: %w", err)
+		}
+		toBlock = latest
+	}
+
+	if fromBlock > toBlock {
+		return fmt.Errorf("invalid block range %d - %d", fromBlock, toBlock)
+	}
+
+	scanner.next, scanner.to = fromBlock, toBlock
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// workers take the next chunk once they are free, holding one of the slots until its logs were
+	// handed out, so fast workers run at most 2 * concurrency chunks ahead of the slowest one
+	slots := make(chan struct{}, 2*concurrency)
+	results := make(chan scanResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+
+				seq, from, to, ok := scanner.take()
+				if !ok {
+					<-slots
+					return
+				}
+
+				logs, err := scanner.fetch(ctx, from, to)
+				select {
+				case results <- scanResult{seq: seq, logs: logs, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// chunks finish in any order, they are buffered until all earlier chunks were handed out
+	pending := make(map[int]scanResult)
+	expected := 0
+	for result := range results {
+		pending[result.seq] = result
+
+		for {
+			next, ok := pending[expected]
+			if !ok {
+				break
+			}
+			delete(pending, expected)
+
+			if next.err != nil {
+				return next.err
+			}
+
+			sort.SliceStable(next.logs, func(i, j int) bool {
+				if next.logs[i].BlockNumber != next.logs[j].BlockNumber {
+					return next.logs[i].BlockNumber < next.logs[j].BlockNumber
+				}
+				return next.logs[i].Index < next.logs[j].Index
+			})
+
+			for j := range next.logs {
+				if err := handle(&next.logs[j]); err != nil {
+					return err
+				}
+			}
+
+			<-slots
+			expected++
+		}
+	}
+
+	return parent.Err()
+}
+
+// scanResult holds the logs of the chunk with the given sequence number.
+type scanResult struct {
+	seq  int
+	logs []types.Log
+	err  error
+}
+
+// take returns the next chunk at the current chunk size, false once the range is exhausted.
+func (s *logScanner) take() (seq int, from uint64, to uint64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return 0, 0, 0, false
+	}
+
+	from = s.next
+	to = from + s.size - 1
+	if to > s.to || to < from {
+		to = s.to
+	}
+
+	if to == s.to {
+		s.done = true
+	} else {
+		s.next = to + 1
+	}
+
+	seq = s.seq
+	s.seq++
+	return seq, from, to, true
+}
+
+// fetch requests the logs of the range, splitting it in halves while the provider rejects it.
+func (s *logScanner) fetch(ctx context.Context, from uint64, to uint64) ([]types.Log, error) {
+	filter := s.filter
+	filter.FromBlock = new(big.Int).SetUint64(from)
+	filter.ToBlock = new(big.Int).SetUint64(to)
+
+	logs, err := s.client.FilterLogs(ctx, filter)
+	if err == nil {
+		return logs, nil
+	}
+
+	size := to - from + 1
+	if !isRangeLimit(err) || size <= s.min || ctx.Err() != nil {
+		return nil, fmt.Errorf("error scanning blocks %d - %d: %w", from, to, err)
+	}
+
+	half := size / 2
+	if half < s.min {
+		half = s.min
+	}
+
+	s.mu.Lock()
+	if half < s.size {
+		s.size = half
+	}
+	s.mu.Unlock()
+
+	first, err := s.fetch(ctx, from, from+half-1)
+	if err != nil {
+		return nil, err
+	}
+
+	if from+half > to {
+		return first, nil
+	}
+
+	second, err := s.fetch(ctx, from+half, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(first, second...), nil
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestScanEvents(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	// every chunk returns a single transfer in its first block
+	server.Handle("eth_getLogs", func(params json.RawMessage) (interface{}, error) {
+		var query []struct {
+			FromBlock string `json:"fromBlock"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+
+		block, _ := hexutil.DecodeUint64(query[0].FromBlock)
+		return []types.Log{{
+			Address:     common.HexToAddress(target_erc20),
+			Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+			Data:        common.LeftPadBytes([]byte{1}, 32),
+			BlockNumber: block,
+		}}, nil
+	})
+	server.Respond("eth_blockNumber", "0x1f")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunkSize := ScanChunkSize
+	ScanChunkSize = 10
+	defer func() { ScanChunkSize = chunkSize }()
+
+	contract := target_erc20
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20), ContractAddress: &contract}
+	decoder.SetClient(client)
+
+	events, err := decoder.ScanEvents(context.Background(), "Transfer", 5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// blocks 5 - 31 are requested in the chunks 5, 15 and 25
+	if len(*events) != 3 || (*events)[2].BlockNumber != 25 || (*events)[0].Params["value"] != "1" {
+		t.Fatalf("invalid events: %s", events.ToJSON())
+	}
+
+	if calls := server.Calls("eth_getLogs"); len(calls) != 3 {
+		t.Fatalf("invalid chunks: %d", len(calls))
+	}
+
+	if _, err := decoder.ScanEvents(context.Background(), "Unknown", 0, 1); err == nil {
+		t.Fatal("unknown event scanned")
+	}
+}
+
+func TestScanLogsSplitsRanges(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	// the provider rejects ranges of more than 4 blocks and returns one transfer per block
+	server.Handle("eth_getLogs", func(params json.RawMessage) (interface{}, error) {
+		var query []struct {
+			FromBlock string `json:"fromBlock"`
+			ToBlock   string `json:"toBlock"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+
+		from, _ := hexutil.DecodeUint64(query[0].FromBlock)
+		to, _ := hexutil.DecodeUint64(query[0].ToBlock)
+		if to-from+1 > 4 {
+			return nil, fmt.Errorf("query returned more than 10000 results")
+		}
+
+		logs := make([]types.Log, 0)
+		for block := from; block <= to; block++ {
+			logs = append(logs, types.Log{
+				Address:     common.HexToAddress(target_erc20),
+				Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+				Data:        common.LeftPadBytes([]byte{1}, 32),
+				BlockNumber: block,
+			})
+		}
+		return logs, nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoder.SetClient(client)
+
+	filter := ethereum.FilterQuery{FromBlock: big.NewInt(0), ToBlock: big.NewInt(39)}
+	var blocks []uint64
+	err = decoder.ScanLogs(context.Background(), filter, &ScanOptions{ChunkSize: 16}, func(decoded *DecodedLog) error {
+		blocks = append(blocks, decoded.BlockNumber)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, block := range blocks {
+		if uint64(i) != block {
+			t.Fatalf("logs not streamed in order: %v", blocks)
+		}
+	}
+
+	if len(blocks) != 40 {
+		t.Fatalf("invalid number of logs: %d", len(blocks))
+	}
+
+	// the first chunk of 16 blocks is split into 4 blocks, the rest is requested in 4 block chunks
+	if calls := len(server.Calls("eth_getLogs")); calls != 1+2+4+6 {
+		t.Fatalf("chunk size not adapted, %d calls", calls)
+	}
+
+	err = decoder.ScanLogs(context.Background(), filter, &ScanOptions{ChunkSize: 16, MinChunkSize: 8}, func(*DecodedLog) error { return nil })
+	if err == nil {
+		t.Fatal("range limit below the minimum chunk size not reported")
+	}
+}
+
+func TestScanLogsWorkerPool(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	// earlier chunks take longer and every chunk returns its logs in reverse order
+	server.Handle("eth_getLogs", func(params json.RawMessage) (interface{}, error) {
+		var query []struct {
+			FromBlock string `json:"fromBlock"`
+			ToBlock   string `json:"toBlock"`
+		}
+		if err := json.Unmarshal(params, &query); err != nil {
+			return nil, err
+		}
+
+		from, _ := hexutil.DecodeUint64(query[0].FromBlock)
+		to, _ := hexutil.DecodeUint64(query[0].ToBlock)
+		time.Sleep(time.Duration(100-from) * time.Millisecond / 10)
+
+		logs := make([]types.Log, 0)
+		for block := to + 1; block > from; block-- {
+			for index := uint(2); index > 0; index-- {
+				logs = append(logs, types.Log{
+					Address:     common.HexToAddress(target_erc20),
+					Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+					Data:        common.LeftPadBytes([]byte{1}, 32),
+					BlockNumber: block - 1,
+					Index:       index - 1,
+				})
+			}
+		}
+		return logs, nil
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoder.SetClient(client)
+
+	filter := ethereum.FilterQuery{FromBlock: big.NewInt(0), ToBlock: big.NewInt(99)}
+	events, err := decoder.FilterLogEventsWithOptions(context.Background(), filter, &ScanOptions{ChunkSize: 5, Concurrency: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*events) != 200 {
+		t.Fatalf("invalid number of logs: %d", len(*events))
+	}
+
+	for i, event := range *events {
+		if event.BlockNumber != uint64(i/2) || event.LogIndex != uint(i%2) {
+			t.Fatalf("logs not merged in order at %d: block %d, index %d", i, event.BlockNumber, event.LogIndex)
+		}
+	}
+
+	if calls := len(server.Calls("eth_getLogs")); calls != 20 {
+		t.Fatalf("invalid number of chunks: %d", calls)
+	}
+
+	// an error of handle stops all workers
+	stop := fmt.Errorf("stop")
+	err = decoder.ScanLogs(context.Background(), filter, &ScanOptions{ChunkSize: 5, Concurrency: 4}, func(*DecodedLog) error { return stop })
+	if err != stop {
+		t.Fatalf("handle error not returned: %v", err)
+	}
+}
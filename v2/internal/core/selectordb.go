@@ -0,0 +1,84 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// selectorDB holds the embedded signatures keyed by lowercase selector or topic hex. It is
+// decompressed and hashed on first use.
+var selectorDB = struct {
+	once      sync.Once
+	functions map[string][]string
+	events    map[string][]string
+}{}
+
+// LookupSelector returns the signatures of the embedded database matching the 4 byte function
+// selector, e.g. "0xa9059cbb". It returns nil if the selector is unknown or the package was built
+// with the noselectordb tag.
+func LookupSelector(sigHash string) []string {
+	loadSelectorDB()
+	return selectorDB.functions[strings.ToLower(sigHash)]
+}
+
+// LookupTopic returns the event signatures of the embedded database matching the topic hash. It
+// returns nil if the topic is unknown or the package was built with the noselectordb tag.
+func LookupTopic(topic string) []string {
+	loadSelectorDB()
+	return selectorDB.events[strings.ToLower(topic)]
+}
+
+// EmbeddedResolver is a SignatureResolver backed by the embedded selector database, identifying
+// common methods and events fully offline.
+type EmbeddedResolver struct{}
+
+// ResolveFunction implements SignatureResolver.
+func (EmbeddedResolver) ResolveFunction(ctx context.Context, selector string) ([]string, error) {
+	return LookupSelector(selector), nil
+}
+
+// ResolveEvent implements SignatureResolver.
+func (EmbeddedResolver) ResolveEvent(ctx context.Context, topic string) ([]string, error) {
+	return LookupTopic(topic), nil
+}
+
+func loadSelectorDB() {
+	selectorDB.once.Do(func() {
+		selectorDB.functions = make(map[string][]string)
+		selectorDB.events = make(map[string][]string)
+
+		if len(selectorData) == 0 {
+			return
+		}
+
+		reader, err := gzip.NewReader(bytes.NewReader(selectorData))
+		if err != nil {
+			return
+		}
+		defer reader.Close()
+
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			kind, signature, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+			if !ok || strings.HasPrefix(kind, "#") {
+				continue
+			}
+
+			hash := keccak256([]byte(signature))
+			switch kind {
+			case "function":
+				key := hexutil.Encode(hash[:4])
+				selectorDB.functions[key] = append(selectorDB.functions[key], signature)
+			case "event":
+				key := hexutil.Encode(hash)
+				selectorDB.events[key] = append(selectorDB.events[key], signature)
+			}
+		}
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !noselectordb
+
+package core
+
+import _ "embed"
+
+// selectorData is the gzip compressed signature list, one "function sig" or "event sig" per line.
+//
+//go:embed selectordb/signatures.txt.gz
+var selectorData []byte
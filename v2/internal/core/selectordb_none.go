@@ -0,0 +1,6 @@
+//go:build noselectordb
+
+package core
+
+// selectorData is empty when built with the noselectordb tag, leaving the lookups without results.
+var selectorData []byte
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SourcifyConfig configures the Sourcify server used by FetchSourcify.
+type SourcifyConfig struct {
+	BaseURL       string        // Server endpoint, defaults to https://sourcify.dev/server.
+	Client        *http.Client  // Defaults to http.DefaultClient.
+	Timeout       time.Duration // Timeout of a single request, defaults to 10 seconds.
+	FullMatchOnly bool          // Whether partial matches, whose metadata differs from the deployed one, are rejected.
+}
+
+// sourcifyFiles is the response of the files/any endpoint.
+type sourcifyFiles struct {
+	Status string `json:"status"` // "full" or "partial"
+	Files  []struct {
+		Name    string `json:"name"`
+		Path    string `json:"path"`
+		Content string `json:"content"`
+	} `json:"files"`
+}
+
+// sourcifyMetadata holds the fields used of the Solidity metadata.json.
+type sourcifyMetadata struct {
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+	Output struct {
+		Abi json.RawMessage `json:"abi"`
+	} `json:"output"`
+	Settings struct {
+		CompilationTarget map[string]string `json:"compilationTarget"`
+	} `json:"settings"`
+}
+
+// FetchSourcify returns the indexed contract of the address, resolving its ABI and metadata from
+// Sourcify if it is not indexed yet. Name, Pragma (the compiler version) and Source (the source of
+// the compilation target) are taken from the metadata. Only full matches are flagged as Verified.
+// Contracts unknown to Sourcify fail with ErrNotVerified. A nil config uses the defaults, an
+// invalid one fails with ValidationErrors.
+func (store *Storage) FetchSourcify(address string, chainId *big.Int, config *SourcifyConfig) (*IndexedABI, error) {
+	if indexed := store.GetIndexed(address); indexed != nil {
+		return indexed, nil
+	}
+
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid address: %s", address)
+	}
+
+	if chainId == nil {
+		return nil, fmt.Errorf("chain id required for sourcify lookup of %s", address)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &SourcifyConfig{}
+	}
+
+	files, err := config.fetchFiles(address, chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.FullMatchOnly && files.Status != "full" {
+		return nil, fmt.Errorf("%s: only a %s match: %w", address, files.Status, ErrNotVerified)
+	}
+
+	var metadata sourcifyMetadata
+	sources := make(map[string]string)
+	for _, file := range files.Files {
+		if file.Name == "metadata.json" {
+			if err := json.Unmarshal([]byte(file.Content), &metadata); err != nil {
+				return nil, fmt.Errorf("invalid sourcify metadata of %s: %w", address, err)
+			}
+			continue
+		}
+		sources[file.Path] = file.Content
+	}
+
+	if len(metadata.Output.Abi) == 0 {
+		return nil, fmt.Errorf("no abi in sourcify metadata of %s", address)
+	}
+
+	contractAbi, err := abi.JSON(strings.NewReader(string(metadata.Output.Abi)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi of %s: %w", address, err)
+	}
+
+	indexed := store.SetIndexed(address, contractAbi, files.Status == "full", false, nil)
+
+	if version := metadata.Compiler.Version; version != "" {
+		pragma := strings.SplitN(version, "+", 2)[0]
+		indexed.Pragma = &pragma
+	}
+
+	for path, name := range metadata.Settings.CompilationTarget {
+		contractName := name
+		indexed.Name = &contractName
+
+		// sourcify stores the sources below a sources/ folder, keeping the compiler path
+		for sourcePath, content := range sources {
+			if strings.HasSuffix(sourcePath, "/"+path) {
+				source := content
+				indexed.Source = &source
+				break
+			}
+		}
+	}
+
+	return indexed, nil
+}
+
+func (config *SourcifyConfig) fetchFiles(address string, chainId *big.Int) (*sourcifyFiles, error) {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://sourcify.dev/server"
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ctx, cancel := withTimeout(context.Background(), timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/files/any/%s/%s", baseURL, chainId, address), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("sourcify request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", address, ErrNotVerified)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sourcify request failed: %s", response.Status)
+	}
+
+	var files sourcifyFiles
+	if err := json.NewDecoder(response.Body).Decode(&files); err != nil {
+		return nil, fmt.Errorf("invalid sourcify response: %w", err)
+	}
+
+	return &files, nil
+}
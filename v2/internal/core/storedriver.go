@@ -0,0 +1,371 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StorageDriver persists the ABIs and indexed contracts of a Storage and the token infos of an
+// ITknStore, see Storage.SaveTo, Storage.LoadFrom, ITknStore.SaveTo and ITknStore.LoadFrom.
+// Other backends plug in by implementing the methods, SQLStorageDriver is the reference driver
+// for SQLite and Postgres.
+type StorageDriver interface {
+	// SaveStorage replaces all stored ABIs, in order, and all indexed contracts with the given
+	// ones at once, so contracts removed from the store are removed from the driver too.
+	SaveStorage(ctx context.Context, abis []NamedABI, indexed map[string]*IndexedABI) error
+	// LoadABIs returns the stored ABIs in the order they were saved.
+	LoadABIs(ctx context.Context) ([]NamedABI, error)
+	// LoadIndexed returns all stored indexed contracts by key.
+	LoadIndexed(ctx context.Context) (map[string]*IndexedABI, error)
+	// SaveToken inserts or updates the token info of its address.
+	SaveToken(ctx context.Context, info *ITknInfo) error
+	// LoadTokens returns all stored token infos.
+	LoadTokens(ctx context.Context) ([]*ITknInfo, error)
+}
+
+// NamedABI is an ABI of AbiList together with its source name, see AddABI.
+type NamedABI struct {
+	Name string
+	Abi  abi.ABI
+}
+
+// SaveTo replaces the ABIs and indexed contracts saved with the driver by the ABIs of AbiList and
+// the indexed contracts of the store. They are snapshotted under the lock of the store, so SaveTo
+// may run concurrently with AddABI and SetIndexed.
+func (store *Storage) SaveTo(ctx context.Context, driver StorageDriver) error {
+	store.mu.RLock()
+	abis := make([]NamedABI, 0, len(store.AbiList))
+	for i, contractAbi := range store.AbiList {
+		abis = append(abis, NamedABI{Name: store.names[i], Abi: contractAbi})
+	}
+
+	indexed := make(map[string]*IndexedABI, len(store.Indexed))
+	for key, contract := range store.Indexed {
+		indexed[key] = contract
+	}
+	store.mu.RUnlock()
+
+	return driver.SaveStorage(ctx, abis, indexed)
+}
+
+// LoadFrom replaces AbiList and Indexed with the ABIs and indexed contracts of the driver. Like
+// AddABI, it must not be called concurrently with decoding.
+func (store *Storage) LoadFrom(ctx context.Context, driver StorageDriver) error {
+	abis, err := driver.LoadABIs(ctx)
+	if err != nil {
+		return err
+	}
+
+	indexed, err := driver.LoadIndexed(ctx)
+	if err != nil {
+		return err
+	}
+
+	list := make([]abi.ABI, 0, len(abis))
+	names := make(map[int]string)
+	for _, entry := range abis {
+		if entry.Name != "" {
+			names[len(list)] = entry.Name
+		}
+		list = append(list, entry.Abi)
+	}
+
+	for _, contract := range indexed {
+		contract.client = store.client
+	}
+
+	store.mu.Lock()
+	store.AbiList = list
+	store.names = names
+	store.Indexed = indexed
+	store.mu.Unlock()
+	store.invalidateAmbiguities()
+	store.invalidateSignatures()
+
+	return nil
+}
+
+// SaveTo saves all cached token infos with the driver.
+func (store *ITknStore) SaveTo(ctx context.Context, driver StorageDriver) error {
+	store.mu.RLock()
+	tokens := make([]*ITknInfo, 0, len(store.data))
+	for _, info := range store.data {
+		tokens = append(tokens, info)
+	}
+	store.mu.RUnlock()
+
+	for _, info := range tokens {
+		if err := driver.SaveToken(ctx, info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadFrom adds the token infos of the driver to the cache, so they are not queried again.
+func (store *ITknStore) LoadFrom(ctx context.Context, driver StorageDriver) error {
+	tokens, err := driver.LoadTokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range tokens {
+		store.Set(info)
+	}
+
+	return nil
+}
+
+// SQLStorageDriver keeps ABIs, indexed contracts and token infos in three tables, see Schema.
+// SQLite stores ABIs and contracts as JSON text and upserts with an update followed by an insert.
+// Postgres stores them as JSONB, upserts with ON CONFLICT and uses $1 style placeholders.
+//
+// The driver is up to the caller, see NewSQLiteDriver and NewPostgresDriver.
+type SQLStorageDriver struct {
+	DB       *sql.DB
+	Prefix   string // Prefix of the table names, defaults to abi_.
+	Postgres bool   // Whether the database is Postgres, otherwise SQLite.
+}
+
+// NewSQLiteDriver returns a driver for a SQLite database.
+func NewSQLiteDriver(db *sql.DB) *SQLStorageDriver {
+	return &SQLStorageDriver{DB: db}
+}
+
+// NewPostgresDriver returns a driver for a Postgres database.
+func NewPostgresDriver(db *sql.DB) *SQLStorageDriver {
+	return &SQLStorageDriver{DB: db, Postgres: true}
+}
+
+// Schema returns the statements creating the tables of the driver, if they do not exist.
+func (driver *SQLStorageDriver) Schema() []string {
+	if driver.Postgres {
+		return []string{
+			fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (position INTEGER PRIMARY KEY, name TEXT NOT NULL, abi JSONB NOT NULL)", driver.table("abis")),
+			fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, address TEXT NOT NULL, contract JSONB NOT NULL)", driver.table("indexed")),
+			fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (address TEXT PRIMARY KEY, is_erc20 BOOLEAN NOT NULL, is_erc721 BOOLEAN NOT NULL, is_erc1155 BOOLEAN NOT NULL, name TEXT NOT NULL, symbol TEXT NOT NULL, decimals SMALLINT NOT NULL, meta TEXT NOT NULL)", driver.table("tokens")),
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (position INTEGER PRIMARY KEY, name TEXT NOT NULL, abi TEXT NOT NULL)", driver.table("abis")),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, address TEXT NOT NULL, contract TEXT NOT NULL)", driver.table("indexed")),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (address TEXT PRIMARY KEY, is_erc20 BOOLEAN NOT NULL, is_erc721 BOOLEAN NOT NULL, is_erc1155 BOOLEAN NOT NULL, name TEXT NOT NULL, symbol TEXT NOT NULL, decimals INTEGER NOT NULL, meta TEXT NOT NULL)", driver.table("tokens")),
+	}
+}
+
+// Migrate creates the tables of the driver, if they do not exist.
+func (driver *SQLStorageDriver) Migrate(ctx context.Context) error {
+	for _, statement := range driver.Schema() {
+		if _, err := driver.DB.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveStorage replaces the stored ABIs and indexed contracts in one transaction.
+func (driver *SQLStorageDriver) SaveStorage(ctx context.Context, abis []NamedABI, indexed map[string]*IndexedABI) error {
+	tx, err := driver.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"abis", "indexed"} {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", driver.table(table))); err != nil {
+			return err
+		}
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (position, name, abi) VALUES (%s, %s, %s)", driver.table("abis"), driver.placeholder(1), driver.placeholder(2), driver.placeholder(3))
+	for i, entry := range abis {
+		data, err := MarshalABI(entry.Abi)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, insert, i, entry.Name, string(data)); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, 0, len(indexed))
+	for key := range indexed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	insert = fmt.Sprintf("INSERT INTO %s (key, address, contract) VALUES (%s, %s, %s)", driver.table("indexed"), driver.placeholder(1), driver.placeholder(2), driver.placeholder(3))
+	for _, key := range keys {
+		snapshot, err := snapshotIndexed(key, indexed[key])
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, insert, key, indexed[key].Address.Hex(), string(data)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (driver *SQLStorageDriver) LoadABIs(ctx context.Context) ([]NamedABI, error) {
+	rows, err := driver.DB.QueryContext(ctx, fmt.Sprintf("SELECT name, abi FROM %s ORDER BY position", driver.table("abis")))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]NamedABI, 0)
+	for rows.Next() {
+		var name, data string
+		if err := rows.Scan(&name, &data); err != nil {
+			return nil, err
+		}
+
+		contractAbi, err := abi.JSON(bytes.NewReader([]byte(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored abi %q: %w", name, err)
+		}
+
+		result = append(result, NamedABI{Name: name, Abi: contractAbi})
+	}
+
+	return result, rows.Err()
+}
+
+func (driver *SQLStorageDriver) LoadIndexed(ctx context.Context) (map[string]*IndexedABI, error) {
+	rows, err := driver.DB.QueryContext(ctx, fmt.Sprintf("SELECT key, contract FROM %s", driver.table("indexed")))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]*IndexedABI)
+	for rows.Next() {
+		var key, data string
+		if err := rows.Scan(&key, &data); err != nil {
+			return nil, err
+		}
+
+		var snapshot snapshotContract
+		if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+			return nil, fmt.Errorf("invalid stored contract %s: %w", key, err)
+		}
+
+		contract, err := restoreIndexed(snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored contract %s: %w", key, err)
+		}
+
+		result[key] = contract
+	}
+
+	return result, rows.Err()
+}
+
+// SaveToken updates the token info, inserting it if the address has none yet.
+func (driver *SQLStorageDriver) SaveToken(ctx context.Context, info *ITknInfo) error {
+	if driver.Postgres {
+		_, err := driver.DB.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (is_erc20, is_erc721, is_erc1155, name, symbol, decimals, meta, address) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) "+
+			"ON CONFLICT (address) DO UPDATE SET is_erc20 = EXCLUDED.is_erc20, is_erc721 = EXCLUDED.is_erc721, is_erc1155 = EXCLUDED.is_erc1155, "+
+			"name = EXCLUDED.name, symbol = EXCLUDED.symbol, decimals = EXCLUDED.decimals, meta = EXCLUDED.meta", driver.table("tokens")),
+			info.IsERC20, info.IsERC721, info.IsERC1155, info.Name, info.Symbol, int64(info.Decimals), info.Meta, info.Address.Hex(),
+		)
+		return err
+	}
+
+	columns := "is_erc20 = %s, is_erc721 = %s, is_erc1155 = %s, name = %s, symbol = %s, decimals = %s, meta = %s"
+	update := fmt.Sprintf("UPDATE %s SET "+columns+" WHERE address = %s", driver.table("tokens"),
+		driver.placeholder(1), driver.placeholder(2), driver.placeholder(3), driver.placeholder(4),
+		driver.placeholder(5), driver.placeholder(6), driver.placeholder(7), driver.placeholder(8))
+	insert := fmt.Sprintf("INSERT INTO %s (is_erc20, is_erc721, is_erc1155, name, symbol, decimals, meta, address) VALUES (%s, %s, %s, %s, %s, %s, %s, %s)", driver.table("tokens"),
+		driver.placeholder(1), driver.placeholder(2), driver.placeholder(3), driver.placeholder(4),
+		driver.placeholder(5), driver.placeholder(6), driver.placeholder(7), driver.placeholder(8))
+
+	return driver.upsert(ctx, update, insert,
+		info.IsERC20, info.IsERC721, info.IsERC1155, info.Name, info.Symbol, int64(info.Decimals), info.Meta, info.Address.Hex(),
+	)
+}
+
+func (driver *SQLStorageDriver) LoadTokens(ctx context.Context) ([]*ITknInfo, error) {
+	query := fmt.Sprintf("SELECT address, is_erc20, is_erc721, is_erc1155, name, symbol, decimals, meta FROM %s", driver.table("tokens"))
+	rows, err := driver.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*ITknInfo, 0)
+	for rows.Next() {
+		var info ITknInfo
+		var address string
+		var decimals int64
+		if err := rows.Scan(&address, &info.IsERC20, &info.IsERC721, &info.IsERC1155, &info.Name, &info.Symbol, &decimals, &info.Meta); err != nil {
+			return nil, err
+		}
+
+		info.Address = common.HexToAddress(address)
+		info.Decimals = uint8(decimals)
+		result = append(result, &info)
+	}
+
+	return result, rows.Err()
+}
+
+// upsert runs the update and, if it affected no rows, the insert in one transaction, for SQLite
+// versions without ON CONFLICT. Both take the same args.
+func (driver *SQLStorageDriver) upsert(ctx context.Context, update string, insert string, args ...interface{}) error {
+	tx, err := driver.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, update, args...)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		if _, err := tx.ExecContext(ctx, insert, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (driver *SQLStorageDriver) table(name string) string {
+	if driver.Prefix == "" {
+		return "abi_" + name
+	}
+
+	return driver.Prefix + name
+}
+
+func (driver *SQLStorageDriver) placeholder(i int) string {
+	if driver.Postgres {
+		return fmt.Sprintf("$%d", i)
+	}
+
+	return "?"
+}
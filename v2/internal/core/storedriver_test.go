@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// memoryDriver is a StorageDriver keeping everything in maps.
+type memoryDriver struct {
+	abis    []NamedABI
+	indexed map[string]*IndexedABI
+	tokens  map[common.Address]*ITknInfo
+}
+
+func (driver *memoryDriver) SaveStorage(ctx context.Context, abis []NamedABI, indexed map[string]*IndexedABI) error {
+	driver.abis = abis
+	driver.indexed = make(map[string]*IndexedABI)
+	for key, contract := range indexed {
+		copied := *contract
+		driver.indexed[key] = &copied
+	}
+	return nil
+}
+
+func (driver *memoryDriver) LoadABIs(ctx context.Context) ([]NamedABI, error) {
+	return driver.abis, nil
+}
+
+func (driver *memoryDriver) LoadIndexed(ctx context.Context) (map[string]*IndexedABI, error) {
+	return driver.indexed, nil
+}
+
+func (driver *memoryDriver) SaveToken(ctx context.Context, info *ITknInfo) error {
+	if driver.tokens == nil {
+		driver.tokens = make(map[common.Address]*ITknInfo)
+	}
+	driver.tokens[info.Address] = info
+	return nil
+}
+
+func (driver *memoryDriver) LoadTokens(ctx context.Context) ([]*ITknInfo, error) {
+	result := make([]*ITknInfo, 0, len(driver.tokens))
+	for _, info := range driver.tokens {
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+func TestStorageSaveToLoadFrom(t *testing.T) {
+	ctx := context.Background()
+	driver := &memoryDriver{}
+
+	store := Storage{Indexed: make(map[string]*IndexedABI)}
+	store.AddABI("erc20", *ParseABI(abi_erc20))
+	bytecode := "0x6080604052"
+	store.SetIndexed(target_erc20, *ParseABI(abi_erc20), true, true, &bytecode)
+
+	if err := store.SaveTo(ctx, driver); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := Storage{}
+	if err := loaded.LoadFrom(ctx, driver); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.AbiList) != 1 || loaded.abiName(0) != "erc20" {
+		t.Fatalf("unexpected abis: %d %s", len(loaded.AbiList), loaded.abiName(0))
+	}
+
+	if !loaded.IsIndexed(target_erc20) {
+		t.Fatal("indexed contract not loaded")
+	}
+
+	// contracts removed from the store are removed from the driver
+	store.RemoveIndexed(target_erc20)
+	if err := store.SaveTo(ctx, driver); err != nil {
+		t.Fatal(err)
+	}
+	if len(driver.indexed) != 0 {
+		t.Fatalf("removed contract still saved: %v", driver.indexed)
+	}
+
+	tokens := ITknStore{data: make(map[common.Address]*ITknInfo)}
+	tokens.Set(&ITknInfo{Address: common.HexToAddress(target_erc20), IsERC20: true, Symbol: "TKN", Decimals: 18})
+	if err := tokens.SaveTo(ctx, driver); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := ITknStore{data: make(map[common.Address]*ITknInfo)}
+	if err := restored.LoadFrom(ctx, driver); err != nil {
+		t.Fatal(err)
+	}
+
+	if !restored.Has(common.HexToAddress(target_erc20)) {
+		t.Fatal("token info not loaded")
+	}
+}
+
+func TestSQLStorageDriverSchema(t *testing.T) {
+	driver := NewPostgresDriver(nil)
+	if driver.placeholder(2) != "$2" || driver.table("abis") != "abi_abis" {
+		t.Fatalf("unexpected postgres dialect: %s %s", driver.placeholder(2), driver.table("abis"))
+	}
+
+	if schema := driver.Schema(); len(schema) != 3 || !strings.Contains(schema[0], "abi JSONB") || !strings.Contains(schema[1], "contract JSONB") {
+		t.Fatalf("unexpected postgres schema: %v", schema)
+	}
+
+	if schema := NewSQLiteDriver(nil).Schema(); len(schema) != 3 || strings.Contains(schema[0], "JSONB") {
+		t.Fatalf("unexpected sqlite schema: %v", schema)
+	}
+}
@@ -0,0 +1,361 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// SubscribeOptions configures SubscribeLogs. A nil SubscribeOptions uses the defaults.
+type SubscribeOptions struct {
+	// Dial connects to a node supporting subscriptions, e.g. via websocket. It defaults to
+	// redialing the url of the global connection, or reusing the global client if there is none.
+	Dial func(ctx context.Context) (*ethclient.Client, error)
+
+	Buffer            int           // Logs buffered between the subscription and the channel, defaults to 1024.
+	ReconnectDelay    time.Duration // First delay between reconnects, doubled per failure, defaults to 1s.
+	MaxReconnectDelay time.Duration // Upper bound of the reconnect delay, defaults to 30s.
+	OnError           func(error)   // Called with connection errors before reconnecting.
+
+	// ReorgDepth enables tracking the hashes of that many recent blocks, so reorgs that happened
+	// while disconnected are reported as removed logs after reconnecting. 0 disables tracking.
+	ReorgDepth uint64
+
+	// Backfill configures the chunks the missed logs are fetched in, like ScanLogs. A nil Backfill
+	// uses the defaults of ScanOptions.
+	Backfill *ScanOptions
+}
+
+// SubscribeLogs is Store.SubscribeLogs with the default options.
+func SubscribeLogs(ctx context.Context, filter ethereum.FilterQuery) (<-chan DecodedLog, error) {
+	return Store.SubscribeLogs(ctx, filter, nil)
+}
+
+// SubscribeLogs subscribes to new logs matching the filter and returns their decoded logs on a
+// channel, which is closed once the context is done. A set FromBlock fetches the logs since that
+// block first.
+//
+// Lost connections are redialed with an increasing delay. The logs since the last delivered log
+// are fetched with eth_getLogs after resubscribing, so no log is skipped and none is delivered
+// twice. A consumer falling far behind makes the node drop the subscription, which is handled the
+// same way, so memory stays bounded.
+//
+// Logs the node reports as removed by a reorg are delivered again with Removed set, followed by
+// the logs of the new chain. Reorgs missed while disconnected are only detected with ReorgDepth.
+// Invalid options fail with ValidationErrors.
+func (store *Storage) SubscribeLogs(ctx context.Context, filter ethereum.FilterQuery, options *SubscribeOptions) (<-chan DecodedLog, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := &logSubscription{store: store, filter: filter, options: options.withDefaults()}
+	if s.options.ReorgDepth > 0 {
+		s.reorgs = NewReorgTracker(s.options.ReorgDepth)
+	}
+
+	if filter.FromBlock != nil {
+		s.resume = &logPosition{block: filter.FromBlock.Uint64()}
+	}
+	s.filter.FromBlock, s.filter.ToBlock = nil, nil
+
+	// the first subscription fails synchronously, later ones are retried
+	client, sub, logs, err := s.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan DecodedLog, s.options.Buffer)
+	go s.run(ctx, client, sub, logs, out)
+
+	return out, nil
+}
+
+// withDefaults returns a copy of the options with the defaults filled in.
+func (options *SubscribeOptions) withDefaults() SubscribeOptions {
+	result := SubscribeOptions{}
+	if options != nil {
+		result = *options
+	}
+
+	if result.Dial == nil {
+		result.Dial = dialGlobal
+	}
+	if result.Buffer <= 0 {
+		result.Buffer = 1024
+	}
+	if result.ReconnectDelay <= 0 {
+		result.ReconnectDelay = time.Second
+	}
+	if result.MaxReconnectDelay <= 0 {
+		result.MaxReconnectDelay = 30 * time.Second
+	}
+
+	return result
+}
+
+// reconnect reports the error of the lost subscription and retries subscribe with an increasing
+// delay until it succeeds. It returns false once the context is done.
+func (options *SubscribeOptions) reconnect(ctx context.Context, err error, subscribe func() error) bool {
+	options.report(err)
+
+	delay := options.ReconnectDelay
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-getClock().After(delay):
+		}
+
+		err := subscribe()
+		if err == nil {
+			return true
+		}
+		options.report(err)
+
+		delay *= 2
+		if delay > options.MaxReconnectDelay {
+			delay = options.MaxReconnectDelay
+		}
+	}
+}
+
+func (options *SubscribeOptions) report(err error) {
+	if err != nil && options.OnError != nil {
+		options.OnError(err)
+	}
+}
+
+// dialGlobal redials the global connection, or returns the global client if it was not connected
+// by url.
+func dialGlobal(ctx context.Context) (*ethclient.Client, error) {
+	if Ctx.connection != nil {
+		options := ConnectOptions{}
+		if Ctx.options != nil {
+			options = *Ctx.options
+		}
+		return DialWithOptions(ctx, *Ctx.connection, options)
+	}
+
+	if err := clientRequired(); err != nil {
+		return nil, err
+	}
+
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("global backend does not support subscriptions, set a Dial function")
+	}
+
+	return client, nil
+}
+
+// logPosition orders logs by block and log index.
+type logPosition struct {
+	block uint64
+	index uint
+}
+
+func (p *logPosition) after(vLog *types.Log) bool {
+	return p == nil || vLog.BlockNumber > p.block || (vLog.BlockNumber == p.block && vLog.Index > p.index)
+}
+
+// less reports whether p is before other, nil is before all positions.
+func (p *logPosition) less(other *logPosition) bool {
+	return other != nil && (p == nil || p.block < other.block || (p.block == other.block && p.index < other.index))
+}
+
+// positionBefore returns the position right before the log index of the block.
+func positionBefore(block uint64, index uint) *logPosition {
+	if index > 0 {
+		return &logPosition{block: block, index: index - 1}
+	}
+
+	if block > 0 {
+		return &logPosition{block: block - 1, index: ^uint(0)}
+	}
+
+	return nil
+}
+
+type logSubscription struct {
+	store   *Storage
+	filter  ethereum.FilterQuery
+	options SubscribeOptions
+
+	last   *logPosition // last delivered log
+	resume *logPosition // first block to backfill from, set until the first log was delivered
+	reorgs *ReorgTracker
+
+	// removed logs rewind last once the logs of the new chain arrive
+	rewinding bool
+	rewind    *logPosition
+}
+
+func (s *logSubscription) subscribe(ctx context.Context) (*ethclient.Client, ethereum.Subscription, chan types.Log, error) {
+	client, err := s.options.Dial(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error connecting for log subscription: %w", err)
+	}
+
+	logs := make(chan types.Log, s.options.Buffer)
+	sub, err := client.SubscribeFilterLogs(ctx, s.filter, logs)
+	if err != nil {
+		closeDialed(client)
+		return nil, nil, nil, fmt.Errorf("error subscribing to logs: %w", err)
+	}
+
+	return client, sub, logs, nil
+}
+
+func (s *logSubscription) run(ctx context.Context, client *ethclient.Client, sub ethereum.Subscription, logs chan types.Log, out chan<- DecodedLog) {
+	defer close(out)
+
+	for {
+		err := s.pump(ctx, client, sub, logs, out)
+		sub.Unsubscribe()
+		closeDialed(client)
+		if ctx.Err() != nil {
+			return
+		}
+
+		reconnected := s.options.reconnect(ctx, err, func() (err error) {
+			client, sub, logs, err = s.subscribe(ctx)
+			return err
+		})
+		if !reconnected {
+			return
+		}
+	}
+}
+
+// closeDialed closes clients dialed for the subscription, the global client is left open.
+func closeDialed(client *ethclient.Client) {
+	if client != Ctx.eth {
+		client.Close()
+	}
+}
+
+// pump backfills the logs missed since the last delivered log and then delivers the logs of the
+// subscription until it fails.
+func (s *logSubscription) pump(ctx context.Context, client *ethclient.Client, sub ethereum.Subscription, logs chan types.Log, out chan<- DecodedLog) error {
+	s.applyRewind()
+
+	if s.reorgs != nil && s.last != nil {
+		fork, removed, ok, err := s.reorgs.Rewind(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		for i := range removed {
+			if !s.send(ctx, &removed[i], out) {
+				return nil
+			}
+		}
+
+		if ok {
+			s.last = positionBefore(fork, 0)
+		}
+	}
+
+	from := s.resume
+	if s.last != nil {
+		from = s.last
+	}
+
+	if from != nil {
+		filter := s.filter
+		filter.FromBlock = new(big.Int).SetUint64(from.block)
+
+		// missed logs are fetched in chunks, split further if the provider rejects the range
+		err := scanRawLogs(ctx, client, filter, s.options.Backfill, func(vLog *types.Log) error {
+			if !s.deliver(ctx, vLog, out) {
+				return ctx.Err()
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error fetching missed logs: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			if err == nil {
+				err = fmt.Errorf("log subscription closed")
+			}
+			return err
+		case vLog := <-logs:
+			if !s.deliver(ctx, &vLog, out) {
+				return nil
+			}
+		}
+	}
+}
+
+// deliver decodes and sends the log unless it was delivered already, false once the context is
+// done. Removed logs are only sent if they were delivered before.
+func (s *logSubscription) deliver(ctx context.Context, vLog *types.Log, out chan<- DecodedLog) bool {
+	if vLog.Removed {
+		if s.last.after(vLog) {
+			return true
+		}
+
+		if position := positionBefore(vLog.BlockNumber, vLog.Index); !s.rewinding || position.less(s.rewind) {
+			s.rewinding, s.rewind = true, position
+		}
+
+		decoded := s.store.DecodeLogContext(ctx, vLog)
+		if decoded == nil {
+			return true
+		}
+
+		if s.reorgs != nil {
+			s.reorgs.Remove(decoded)
+		}
+		return s.send(ctx, decoded, out)
+	}
+
+	s.applyRewind()
+	if !s.last.after(vLog) {
+		return true
+	}
+
+	s.last = &logPosition{block: vLog.BlockNumber, index: vLog.Index}
+	s.resume = nil
+
+	decoded := s.store.DecodeLogContext(ctx, vLog)
+	if decoded == nil {
+		return true
+	}
+
+	if s.reorgs != nil {
+		s.reorgs.Record(decoded)
+	}
+	return s.send(ctx, decoded, out)
+}
+
+// applyRewind moves the last delivered log before the logs removed since.
+func (s *logSubscription) applyRewind() {
+	if s.rewinding {
+		s.last, s.rewinding, s.rewind = s.rewind, false, nil
+	}
+}
+
+func (s *logSubscription) send(ctx context.Context, decoded *DecodedLog, out chan<- DecodedLog) bool {
+	select {
+	case out <- *decoded:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// logService serves eth_subscribe("logs"), eth_getLogs and eth_blockNumber from a list of mined
+// logs.
+type logService struct {
+	mu         sync.Mutex
+	mined      []types.Log
+	feeds      []chan types.Log
+	subscribed chan struct{}
+	queries    [][2]uint64 // block ranges of eth_getLogs
+}
+
+func (s *logService) Logs(ctx context.Context, crit map[string]interface{}) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+
+	feed := make(chan types.Log, 16)
+	s.mu.Lock()
+	s.feeds = append(s.feeds, feed)
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case vLog := <-feed:
+				notifier.Notify(sub.ID, vLog)
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	s.subscribed <- struct{}{}
+	return sub, nil
+}
+
+func (s *logService) GetLogs(ctx context.Context, crit map[string]interface{}) ([]types.Log, error) {
+	from, _ := hexutil.DecodeUint64(crit["fromBlock"].(string))
+	to, _ := hexutil.DecodeUint64(crit["toBlock"].(string))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queries = append(s.queries, [2]uint64{from, to})
+	logs := make([]types.Log, 0)
+	for _, vLog := range s.mined {
+		if vLog.BlockNumber >= from && vLog.BlockNumber <= to {
+			logs = append(logs, vLog)
+		}
+	}
+	return logs, nil
+}
+
+func (s *logService) BlockNumber() hexutil.Uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest uint64
+	for _, vLog := range s.mined {
+		if vLog.BlockNumber > latest {
+			latest = vLog.BlockNumber
+		}
+	}
+	return hexutil.Uint64(latest)
+}
+
+// push sends a log to the latest subscription.
+func (s *logService) push(vLog types.Log) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.feeds[len(s.feeds)-1] <- vLog
+}
+
+// mine adds a transfer log and pushes it to the latest subscription if notify is set.
+func (s *logService) mine(block uint64, notify bool) {
+	vLog := types.Log{
+		Address:     common.HexToAddress(target_erc20),
+		Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+		Data:        common.LeftPadBytes([]byte{byte(block)}, 32),
+		BlockNumber: block,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mined = append(s.mined, vLog)
+	if notify {
+		s.feeds[len(s.feeds)-1] <- vLog
+	}
+}
+
+func TestSubscribeLogs(t *testing.T) {
+	service := &logService{subscribed: make(chan struct{}, 4)}
+
+	var mu sync.Mutex
+	newServer := func() *rpc.Server {
+		server := rpc.NewServer()
+		if err := server.RegisterName("eth", service); err != nil {
+			t.Fatal(err)
+		}
+		return server
+	}
+	current := newServer()
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		server := current
+		mu.Unlock()
+		server.WebsocketHandler([]string{"*"}).ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	store := &Storage{}
+	store.ParseAndAddABIs(abi_erc20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logs, err := store.SubscribeLogs(ctx, ethereum.FilterQuery{}, &SubscribeOptions{
+		Dial: func(ctx context.Context) (*ethclient.Client, error) {
+			return ethclient.DialContext(ctx, url)
+		},
+		ReconnectDelay: 10 * time.Millisecond,
+		Backfill:       &ScanOptions{ChunkSize: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receive := func() DecodedLog {
+		select {
+		case decoded := <-logs:
+			return decoded
+		case <-time.After(5 * time.Second):
+			t.Fatal("no log received")
+		}
+		return DecodedLog{}
+	}
+
+	<-service.subscribed
+	service.mine(1, true)
+	service.mine(2, true)
+	if first, second := receive(), receive(); first.BlockNumber != 1 || second.BlockNumber != 2 {
+		t.Fatalf("invalid logs: %d, %d", first.BlockNumber, second.BlockNumber)
+	}
+
+	// the connection drops and blocks 3 and 4 are mined meanwhile
+	mu.Lock()
+	current.Stop()
+	current = newServer()
+	mu.Unlock()
+	service.mine(3, false)
+	service.mine(4, false)
+
+	<-service.subscribed
+	service.mine(5, true)
+
+	for _, block := range []uint64{3, 4, 5} {
+		if decoded := receive(); decoded.BlockNumber != block || decoded.Name != "Transfer" {
+			t.Fatalf("invalid log after reconnect: block %d, expected %d", decoded.BlockNumber, block)
+		}
+	}
+
+	// the missed logs were fetched in chunks of one block
+	service.mu.Lock()
+	queries := service.queries
+	service.mu.Unlock()
+	if len(queries) < 3 || queries[0] != [2]uint64{2, 2} {
+		t.Fatalf("backfill not chunked: %v", queries)
+	}
+	for _, query := range queries {
+		if query[0] != query[1] {
+			t.Fatalf("backfill not chunked: %v", queries)
+		}
+	}
+
+	// a reorg replaces block 5, the node reports the removed log first
+	removed := types.Log{
+		Address:     common.HexToAddress(target_erc20),
+		Topics:      []common.Hash{common.HexToHash(TransferTopic), {}, {}},
+		Data:        common.LeftPadBytes([]byte{5}, 32),
+		BlockNumber: 5,
+		Removed:     true,
+	}
+	service.push(removed)
+	service.mine(5, true)
+
+	if decoded := receive(); decoded.BlockNumber != 5 || !decoded.Removed {
+		t.Fatalf("removal not delivered: block %d, removed %v", decoded.BlockNumber, decoded.Removed)
+	}
+
+	if decoded := receive(); decoded.BlockNumber != 5 || decoded.Removed {
+		t.Fatalf("replacement not delivered: block %d, removed %v", decoded.BlockNumber, decoded.Removed)
+	}
+
+	cancel()
+	for range logs {
+	}
+}
+
+func TestSubscribeReconnectClock(t *testing.T) {
+	manual := NewManualClock(time.Unix(0, 0))
+	SetClock(manual)
+	defer SetClock(nil)
+
+	options := (&SubscribeOptions{ReconnectDelay: time.Minute}).withDefaults()
+	done := make(chan bool)
+	go func() {
+		done <- options.reconnect(context.Background(), errors.New("lost"), func() error { return nil })
+	}()
+
+	// the delay passes on the clock, not in real time
+	deadline := time.After(2 * time.Second)
+	for {
+		manual.Advance(time.Minute)
+		select {
+		case ok := <-done:
+			if !ok {
+				t.Fatal("not reconnected")
+			}
+			return
+		case <-deadline:
+			t.Fatal("reconnect delay not taken from the clock")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Protocols of the swaps normalized by DecodeSwap.
+const (
+	ProtocolUniswapV2 = "uniswap-v2"
+	ProtocolUniswapV3 = "uniswap-v3"
+)
+
+var (
+	swapV2Topic = crypto.Keccak256Hash([]byte("Swap(address,uint256,uint256,uint256,uint256,address)"))
+	swapV3Topic = crypto.Keccak256Hash([]byte("Swap(address,address,int256,int256,uint160,uint128,int24)"))
+	// PancakeSwap V3 appends the protocol fees to the Swap event of Uniswap V3.
+	swapPancakeV3Topic = crypto.Keccak256Hash([]byte("Swap(address,address,int256,int256,uint160,uint128,int24,uint128,uint128)"))
+)
+
+// SwapInfo is a swap of a Uniswap V2 or V3 style pool, normalized to the tokens and amounts going
+// in and out of the pool. Symbols and decimals are resolved through the token store, they are
+// empty if the token does not implement them.
+type SwapInfo struct {
+	Protocol        string   `json:"protocol"`               // One of the Protocol* constants.
+	Pool            string   `json:"pool"`                   // Pool contract that emitted the Swap.
+	TokenIn         string   `json:"tokenIn"`                // Token paid into the pool.
+	TokenOut        string   `json:"tokenOut"`               // Token paid out of the pool.
+	AmountIn        *big.Int `json:"amountIn"`               // Amount of TokenIn in its smallest unit.
+	AmountOut       *big.Int `json:"amountOut"`              // Amount of TokenOut in its smallest unit.
+	SymbolIn        string   `json:"symbolIn,omitempty"`     // Symbol of TokenIn.
+	SymbolOut       string   `json:"symbolOut,omitempty"`    // Symbol of TokenOut.
+	DecimalsIn      uint8    `json:"decimalsIn"`             // Decimals of TokenIn.
+	DecimalsOut     uint8    `json:"decimalsOut"`            // Decimals of TokenOut.
+	Sender          string   `json:"sender"`                 // Account that called the pool, usually a router.
+	Recipient       string   `json:"recipient"`              // Account receiving TokenOut.
+	TransactionHash string   `json:"transactionHash"`        // Transaction hash of the log.
+	BlockNumber     uint64   `json:"blockNumber"`            // Block number of the log.
+	LogIndex        uint     `json:"logIndex"`               // Index of the Swap log.
+	SqrtPriceX96    *big.Int `json:"sqrtPriceX96,omitempty"` // Pool price after the swap, V3 only.
+	Tick            *big.Int `json:"tick,omitempty"`         // Pool tick after the swap, V3 only.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the SwapInfo object.
+func (data *SwapInfo) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the SwapInfo object.
+func (data *SwapInfo) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// IsSwapLog returns true if the log is a Swap event of a Uniswap V2 or V3 style pool.
+func IsSwapLog(vLog *types.Log) bool {
+	if len(vLog.Topics) != 3 {
+		return false
+	}
+
+	switch vLog.Topics[0] {
+	case swapV2Topic, swapV3Topic, swapPancakeV3Topic:
+		return true
+	}
+
+	return false
+}
+
+// DecodeSwap normalizes the Swap log of a pool with the global TknStore, see
+// ITknStore.DecodeSwap.
+func DecodeSwap(ctx context.Context, vLog *types.Log) (*SwapInfo, error) {
+	return TknStore.DecodeSwap(ctx, vLog)
+}
+
+// DecodeSwap normalizes the Swap log of a Uniswap V2 or V3 style pool, including the forks
+// emitting the same events. The tokens of the pool are read with token0 and token1 from the
+// backend of the store, their metadata is resolved through the store. It returns an error for
+// logs of other events.
+func (store *ITknStore) DecodeSwap(ctx context.Context, vLog *types.Log) (*SwapInfo, error) {
+	if !IsSwapLog(vLog) {
+		return nil, fmt.Errorf("log %d is no swap", vLog.Index)
+	}
+
+	result := SwapInfo{
+		Pool:            vLog.Address.Hex(),
+		Sender:          topicAddress(vLog.Topics[1]).Hex(),
+		TransactionHash: vLog.TxHash.Hex(),
+		BlockNumber:     vLog.BlockNumber,
+		LogIndex:        vLog.Index,
+	}
+
+	var zeroForOne bool
+	if vLog.Topics[0] == swapV2Topic {
+		if len(vLog.Data) < 128 {
+			return nil, fmt.Errorf("invalid swap data of log %d", vLog.Index)
+		}
+
+		amount0In := new(big.Int).SetBytes(vLog.Data[0:32])
+		amount1In := new(big.Int).SetBytes(vLog.Data[32:64])
+		amount0Out := new(big.Int).SetBytes(vLog.Data[64:96])
+		amount1Out := new(big.Int).SetBytes(vLog.Data[96:128])
+
+		result.Protocol = ProtocolUniswapV2
+		result.Recipient = topicAddress(vLog.Topics[2]).Hex()
+		zeroForOne = amount0In.Sign() > 0
+		if zeroForOne {
+			result.AmountIn, result.AmountOut = amount0In, amount1Out
+		} else {
+			result.AmountIn, result.AmountOut = amount1In, amount0Out
+		}
+	} else {
+		if len(vLog.Data) < 160 {
+			return nil, fmt.Errorf("invalid swap data of log %d", vLog.Index)
+		}
+
+		// the amounts are deltas of the pool balances, positive for tokens paid in
+		amount0 := math.S256(new(big.Int).SetBytes(vLog.Data[0:32]))
+		amount1 := math.S256(new(big.Int).SetBytes(vLog.Data[32:64]))
+
+		result.Protocol = ProtocolUniswapV3
+		result.Recipient = topicAddress(vLog.Topics[2]).Hex()
+		result.SqrtPriceX96 = new(big.Int).SetBytes(vLog.Data[64:96])
+		result.Tick = math.S256(new(big.Int).SetBytes(vLog.Data[128:160]))
+		zeroForOne = amount0.Sign() > 0
+		if zeroForOne {
+			result.AmountIn, result.AmountOut = amount0, new(big.Int).Neg(amount1)
+		} else {
+			result.AmountIn, result.AmountOut = amount1, new(big.Int).Neg(amount0)
+		}
+	}
+
+	token0, token1, err := store.poolTokens(ctx, vLog.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenIn, tokenOut := token1, token0
+	if zeroForOne {
+		tokenIn, tokenOut = token0, token1
+	}
+
+	result.TokenIn = tokenIn.Hex()
+	result.TokenOut = tokenOut.Hex()
+	result.SymbolIn, result.DecimalsIn = store.swapToken(tokenIn)
+	result.SymbolOut, result.DecimalsOut = store.swapToken(tokenOut)
+
+	return &result, nil
+}
+
+// DecodeSwaps normalizes all Swap logs, skipping other logs and swaps whose pool tokens can not be
+// read.
+func (store *ITknStore) DecodeSwaps(ctx context.Context, vLogs []*types.Log) []SwapInfo {
+	result := make([]SwapInfo, 0)
+
+	for _, vLog := range vLogs {
+		if !IsSwapLog(vLog) {
+			continue
+		}
+
+		if swap, err := store.DecodeSwap(ctx, vLog); err == nil {
+			result = append(result, *swap)
+		}
+	}
+
+	return result
+}
+
+// poolTokens returns token0 and token1 of the pool, which never change and are cached like the
+// other contract lookups.
+func (store *ITknStore) poolTokens(ctx context.Context, pool common.Address) (common.Address, common.Address, error) {
+	client, err := requireClient(store.client)
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+
+	tokens := make([]common.Address, 2)
+	for i, selector := range []string{"0dfe1681", "d21220a7"} {
+		kind := fmt.Sprintf("token%d", i)
+		token := cachedLookup(ctx, client, pool, kind, func() *common.Address {
+			msg := ethereum.CallMsg{To: &pool, Data: common.Hex2Bytes(selector)}
+			output, err := client.CallContract(ctx, msg, nil)
+			if err != nil || len(output) < 32 {
+				return nil
+			}
+
+			token := common.BytesToAddress(output[:32])
+			return &token
+		})
+
+		if token == nil {
+			return common.Address{}, common.Address{}, fmt.Errorf("can not read %s of pool %s", kind, pool.Hex())
+		}
+		tokens[i] = *token
+	}
+
+	return tokens[0], tokens[1], nil
+}
+
+// swapToken returns the symbol and decimals of a token of a swap from the store.
+func (store *ITknStore) swapToken(token common.Address) (string, uint8) {
+	info, err := store.Get(token)
+	if err != nil || info == nil {
+		return "", 0
+	}
+
+	return info.Symbol, info.Decimals
+}
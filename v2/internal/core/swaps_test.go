@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestDecodeSwap(t *testing.T) {
+	pool := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	usdc := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	weth := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	router := common.HexToAddress("0x00000000000000000000000000000000000000dd")
+	trader := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	stringType, _ := abi.NewType("string", "", nil)
+	uintType, _ := abi.NewType("uint8", "", nil)
+	server.HandleCall(func(to common.Address, data []byte) ([]byte, error) {
+		var output []byte
+		switch hexutil.Encode(data) {
+		case "0x0dfe1681":
+			output = usdc.Hash().Bytes()
+		case "0xd21220a7":
+			output = weth.Hash().Bytes()
+		case "0x95d89b41":
+			symbol := "USDC"
+			if to == weth {
+				symbol = "WETH"
+			}
+			output, _ = abi.Arguments{{Type: stringType}}.Pack(symbol)
+		case "0x313ce567":
+			decimals := uint8(6)
+			if to == weth {
+				decimals = 18
+			}
+			output, _ = abi.Arguments{{Type: uintType}}.Pack(decimals)
+		default:
+			output, _ = abi.Arguments{{Type: stringType}}.Pack("Token")
+		}
+		return output, nil
+	})
+	server.Respond("eth_getCode", "0x")
+	server.Respond("eth_chainId", "0x1")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &ITknStore{data: make(map[common.Address]*ITknInfo)}
+	store.SetClient(client)
+
+	word := func(n *big.Int) []byte { return math.U256Bytes(new(big.Int).Set(n)) }
+
+	// V2: 1000 USDC in for 1 WETH out
+	v2 := &types.Log{
+		Address: pool,
+		Topics:  []common.Hash{swapV2Topic, router.Hash(), trader.Hash()},
+		Data:    append(append(word(big.NewInt(1000)), word(big.NewInt(0))...), append(word(big.NewInt(0)), word(big.NewInt(1))...)...),
+	}
+
+	swap, err := store.DecodeSwap(context.Background(), v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if swap.Protocol != ProtocolUniswapV2 || swap.TokenIn != usdc.Hex() || swap.TokenOut != weth.Hex() || swap.AmountIn.Int64() != 1000 || swap.AmountOut.Int64() != 1 {
+		t.Fatalf("unexpected v2 swap: %s", swap.ToJSON())
+	}
+
+	if swap.SymbolIn != "USDC" || swap.DecimalsIn != 6 || swap.SymbolOut != "WETH" || swap.DecimalsOut != 18 || swap.Recipient != trader.Hex() {
+		t.Fatalf("unexpected v2 swap metadata: %s", swap.ToJSON())
+	}
+
+	// V3: 2 WETH in for 3000 USDC out, the pool pays out a negative delta
+	v3 := &types.Log{
+		Address: pool,
+		Topics:  []common.Hash{swapV3Topic, router.Hash(), trader.Hash()},
+		Data: append(append(word(big.NewInt(-3000)), word(big.NewInt(2))...),
+			append(append(word(big.NewInt(1)), word(big.NewInt(1))...), word(big.NewInt(-5))...)...),
+	}
+
+	swap, err = store.DecodeSwap(context.Background(), v3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if swap.Protocol != ProtocolUniswapV3 || swap.TokenIn != weth.Hex() || swap.AmountIn.Int64() != 2 || swap.TokenOut != usdc.Hex() || swap.AmountOut.Int64() != 3000 || swap.Tick.Int64() != -5 {
+		t.Fatalf("unexpected v3 swap: %s", swap.ToJSON())
+	}
+
+	// other logs are skipped
+	if swaps := store.DecodeSwaps(context.Background(), []*types.Log{v2, {Topics: []common.Hash{transferTopic}}, v3}); len(swaps) != 2 {
+		t.Fatalf("unexpected swaps: %d", len(swaps))
+	}
+}
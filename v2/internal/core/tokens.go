@@ -0,0 +1,241 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ITknInfo represents the structure of the 'token_info' table.
+type ITknInfo struct {
+	Address   common.Address
+	IsERC20   bool
+	IsERC721  bool
+	IsERC1155 bool
+	Name      string
+	Symbol    string
+	Decimals  uint8
+	Meta      string
+}
+
+type ITknStore struct {
+	mu     sync.RWMutex
+	data   map[common.Address]*ITknInfo
+	abis   map[common.Address]*abi.ABI
+	client Backend // see SetClient, nil for the global backend
+}
+
+var TknStore = ITknStore{
+	data: make(map[common.Address]*ITknInfo),
+}
+
+// GetClient returns the client of the store, the global client if none is set. It is nil if the
+// backend is no *ethclient.Client, see GetBackend.
+func (store *ITknStore) GetClient() *ethclient.Client {
+	return asClient(store.GetBackend())
+}
+
+// GetBackend returns the backend of the store, the global backend if none is set. It is wrapped with
+// the global retry policy.
+func (store *ITknStore) GetBackend() Backend {
+	if store.client != nil {
+		return retrying(store.client, nil)
+	}
+
+	return GetBackend()
+}
+
+// SetClient sets the client the store queries tokens with. Setting the client of the global
+// TknStore sets the global client, as before.
+func (store *ITknStore) SetClient(client *ethclient.Client) {
+	store.SetBackend(asBackend(client))
+}
+
+// SetBackend sets the backend of the store, like SetClient for backends other than
+// *ethclient.Client.
+func (store *ITknStore) SetBackend(backend Backend) {
+	if store == &TknStore {
+		SetBackend(backend)
+		return
+	}
+
+	store.client = backend
+}
+
+func (store *ITknStore) Connect(nodeUrl string) {
+	Connect(nodeUrl)
+}
+
+func (store *ITknStore) HasAbi(address common.Address) bool {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.data[address] != nil
+}
+
+func (store *ITknStore) SetAbi(tkn common.Address, abis abi.ABI) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.abis == nil {
+		store.abis = make(map[common.Address]*abi.ABI)
+	}
+	store.abis[tkn] = &abis
+}
+
+func (store *ITknStore) GetAbi(addr common.Address) *abi.ABI {
+	var result *abi.ABI
+
+	if store.HasAbi(addr) {
+		store.mu.RLock()
+		result = store.abis[addr]
+		store.mu.RUnlock()
+	} else if tkn, err := store.Get(addr); err == nil {
+		if tkn.IsERC20 {
+			result = ParseABI(DefaultABIs.JSON("ERC20"))
+		} else if tkn.IsERC721 {
+			result = ParseABI(DefaultABIs.JSON("ERC721"))
+		}
+	}
+
+	if result == nil {
+		result = MergeABIs(DefaultABIs.JSON("ERC20"), DefaultABIs.JSON("ERC721"))
+	}
+
+	return result
+}
+
+func (store *ITknStore) Has(address common.Address) bool {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.data[address] != nil
+}
+
+func (store *ITknStore) Set(nfo *ITknInfo) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.data[nfo.Address] = nfo
+}
+
+// Len returns the number of cached token infos and token ABIs.
+func (store *ITknStore) Len() (tokens int, abis int) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return len(store.data), len(store.abis)
+}
+
+// Trim drops all cached token infos and token ABIs, they are queried again on demand.
+func (store *ITknStore) Trim() {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.data = make(map[common.Address]*ITknInfo)
+	store.abis = make(map[common.Address]*abi.ABI)
+}
+
+// Cached returns the cached info of the token without querying it, nil if it is not cached.
+func (store *ITknStore) Cached(address common.Address) *ITknInfo {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return store.data[address]
+}
+
+func (store *ITknStore) Get(address common.Address) (*ITknInfo, error) {
+	var result ITknInfo
+
+	if info := store.Cached(address); info != nil {
+		return info, nil
+	} else {
+		// Create a context with a timeout
+		ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// token infos are shared through the Cache by chain, if the backend reports its chain id
+		var key string
+		if backend := store.GetBackend(); backend != nil {
+			if chainId := lookupChainID(ctx, backend); chainId != nil {
+				key = contractCacheKey("token", chainId, address)
+			}
+		}
+
+		if key != "" && cacheGet(ctx, key, &result) {
+			return &result, nil
+		}
+
+		result = queryTokenInfo(ctx, store.client, address)
+		if key != "" {
+			cacheSet(ctx, key, result)
+		}
+	}
+
+	return &result, nil
+}
+
+func (store *ITknStore) BalanceOf(tkn common.Address, addr common.Address) (uint64, error) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return getERC20Balance(ctx, store.client, addr, tkn)
+}
+
+func (store *ITknStore) GetDecoder(contract common.Address) (*AbiDecoder, error) {
+	if !store.Has(contract) {
+		return nil, fmt.Errorf("can not create decoder, token not in store: %s", contract.Hex())
+	}
+
+	info, err := store.Get(contract)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := info.CreateDecoder()
+	return &decoder, err
+}
+
+func (tkn *ITknInfo) CreateDecoder() AbiDecoder {
+	var contractAddress string
+
+	if tkn.Address.Hex() != EtherAddress {
+		contractAddress = tkn.Address.Hex()
+	}
+
+	abi := TknStore.GetAbi(tkn.Address)
+
+	fmt.Println("abi loaded", abi.Methods)
+
+	return AbiDecoder{
+		ContractAddress: &contractAddress,
+		client:          TknStore.GetBackend(),
+		Abi:             abi,
+	}
+}
+
+func (tkn *ITknInfo) Query() (*ITknInfo, error) {
+	return TknStore.Get(tkn.Address)
+}
+
+func (tkn *ITknInfo) GetName() *string {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return getName(ctx, TknStore.client, tkn.Address)
+}
+
+func (tkn *ITknInfo) GetSymbol() *string {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return getSymbol(ctx, TknStore.client, tkn.Address)
+}
+
+func (tkn *ITknInfo) GetDecimals() *uint8 {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return getDecimals(ctx, TknStore.client, tkn.Address)
+}
+
+func (tkn *ITknInfo) BalanceOf(addr common.Address) (uint64, error) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return getERC20Balance(ctx, TknStore.client, addr, tkn.Address)
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestTokenStore(t *testing.T) {
+	addr := common.HexToAddress(EtherAddress)
+	TknStore.Set(&ITknInfo{
+		Address:   addr,
+		IsERC20:   false,
+		IsERC721:  false,
+		IsERC1155: false,
+		Name:      "empty-decoder",
+		Symbol:    "EPTY",
+		Decimals:  18,
+		Meta:      "{}",
+	})
+
+	tkn, err := TknStore.Get(addr)
+
+	if err != nil {
+		t.Fatal("error getting token info from store", err)
+	}
+
+	t.Log("Token loaded from TknStore", tkn.Name)
+
+	dec := tkn.CreateDecoder()
+	if dec.Abi == nil {
+		t.Fatal("error get decoder - no abi loaded")
+	}
+
+	if dec.ContractAddress == nil {
+		t.Fatal("error getting token info from store - no contract address")
+	}
+
+	t.Log(dec.GetSigHashes())
+}
+
+func TestWatchdogTrim(t *testing.T) {
+	TknStore.Set(&ITknInfo{Address: common.HexToAddress("0x1"), Symbol: "ONE"})
+	TknStore.Set(&ITknInfo{Address: common.HexToAddress("0x2"), Symbol: "TWO"})
+
+	var alerts []WatchdogAlert
+	watchdog, err := NewWatchdog(WatchdogConfig{
+		MaxCacheEntries: 1,
+		TrimCaches:      true,
+		OnAlert:         func(alert WatchdogAlert) { alerts = append(alerts, alert) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := watchdog.Check()
+	if stats.TokenCache < 2 || stats.Goroutines == 0 || stats.HeapAlloc == 0 {
+		t.Fatalf("invalid watchdog stats: %+v", stats)
+	}
+
+	if len(alerts) != 1 || !alerts[0].Trimmed {
+		t.Fatalf("cache limit not alerted: %+v", alerts)
+	}
+
+	if tokens, _ := TknStore.Len(); tokens != 0 {
+		t.Fatalf("token cache not trimmed: %d", tokens)
+	}
+
+	watchdog.Check()
+	if len(alerts) != 1 {
+		t.Fatalf("unexpected alert after trim: %+v", alerts)
+	}
+}
+
+func TestWatchdogConcurrentReads(t *testing.T) {
+	token, code := common.HexToAddress("0x3"), "0x"
+	store := &Storage{}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			TknStore.Set(&ITknInfo{Address: token, Symbol: "THREE"})
+			TknStore.Trim()
+			store.ParseAndAddABIs(abi_erc20)
+			store.SetIndexed(token.Hex(), abi.ABI{}, false, false, &code)
+		}
+	}()
+
+	// the readers of the watchdog, the ledger and the rosetta adapter run while the caches are trimmed
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+
+		if info := TknStore.Cached(token); info != nil && info.Symbol != "THREE" {
+			t.Fatalf("unexpected token info: %+v", info)
+		}
+		rosettaTokenCurrency(token.Hex())
+		store.Len()
+	}
+
+	if abis, indexed := store.Len(); abis != 100 || indexed != 1 {
+		t.Fatalf("unexpected store size: %d abis, %d contracts", abis, indexed)
+	}
+}
+
+func TestTokenStoreClient(t *testing.T) {
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	stringType, _ := abi.NewType("string", "", nil)
+	uintType, _ := abi.NewType("uint8", "", nil)
+	server.HandleCall(func(to common.Address, data []byte) ([]byte, error) {
+		var output []byte
+		switch hexutil.Encode(data) {
+		case "0x313ce567":
+			output, _ = abi.Arguments{{Type: uintType}}.Pack(uint8(18))
+		case "0x95d89b41":
+			output, _ = abi.Arguments{{Type: stringType}}.Pack("TKN")
+		default:
+			output, _ = abi.Arguments{{Type: stringType}}.Pack("Token")
+		}
+		return output, nil
+	})
+	server.Respond("eth_getCode", "0x")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the global client is not used by stores with their own client
+	eth := Ctx.eth
+	Ctx.eth = nil
+	defer func() { Ctx.eth = eth }()
+
+	store := &ITknStore{data: make(map[common.Address]*ITknInfo)}
+	store.SetClient(client)
+	if store.GetClient() != client || TknStore.GetClient() != nil {
+		t.Fatal("client not scoped to the store")
+	}
+
+	info, err := store.Get(common.BigToAddress(big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Name != "Token" || info.Symbol != "TKN" || info.Decimals != 18 {
+		t.Fatalf("token not queried with the store client: %+v", info)
+	}
+}
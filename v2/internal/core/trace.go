@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// callFrame is a single frame of the callTracer output of debug_traceTransaction.
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	Value   *hexutil.Big    `json:"value"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output"`
+	Error   string          `json:"error"`
+	Calls   []callFrame     `json:"calls"`
+}
+
+// DecodedCallTree is a struct for holding a decoded internal call and all of its sub calls.
+type DecodedCallTree struct {
+	Type      string             `json:"type"`              // Call type, e.g. CALL, DELEGATECALL, STATICCALL, CREATE.
+	From      string             `json:"from"`              // Caller of the frame.
+	To        string             `json:"to"`                // Called contract of the frame.
+	Value     string             `json:"value"`             // Native value sent with the call.
+	GasUsed   uint64             `json:"gasUsed"`           // Gas used by the frame.
+	SigHash   string             `json:"sigHash,omitempty"` // Function selector of the call input.
+	Signature string             `json:"signature"`         // Function signature, empty if the selector is unknown.
+	Params    Params             `json:"params"`            // Decoded call input.
+	Output    Params             `json:"output"`            // Decoded return data.
+	Error     string             `json:"error,omitempty"`   // Error of reverted frames.
+	Revert    *DecodedError      `json:"revert,omitempty"`  // Decoded revert payload of reverted frames.
+	Calls     []*DecodedCallTree `json:"calls,omitempty"`   // Sub calls of the frame.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedCallTree object.
+func (data *DecodedCallTree) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedCallTree object.
+func (data *DecodedCallTree) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// TraceTransaction fetches the call tree of the given transaction with debug_traceTransaction
+// (callTracer) and decodes the input and output of every internal call with the decoder's ABI
+// and the ABIs of Store.AbiList. The connected node must expose the debug namespace.
+func (decoder *AbiDecoder) TraceTransaction(transactionHash string) (*DecodedCallTree, error) {
+	if decoder.client == nil && Ctx.eth == nil {
+		return nil, fmt.Errorf("no provider set for decoder nor set in CTX - contract: %v", decoder.ContractAddress)
+	}
+
+	abis := Store.AbiList
+	if decoder.Abi != nil {
+		abis = append([]abi.ABI{*decoder.Abi}, abis...)
+	}
+
+	return traceTransaction(context.Background(), decoder.GetBackend(), common.HexToHash(transactionHash), abis)
+}
+
+// TraceTransaction fetches the call tree of the given transaction with debug_traceTransaction
+// (callTracer) and decodes every internal call with the ABIs of Store.AbiList.
+func (store *Storage) TraceTransaction(ctx context.Context, transactionHash string) (*DecodedCallTree, error) {
+	client, err := store.requireClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return traceTransaction(ctx, client, common.HexToHash(transactionHash), store.AbiList)
+}
+
+func traceTransaction(ctx context.Context, client Backend, hash common.Hash, abis []abi.ABI) (*DecodedCallTree, error) {
+	var root callFrame
+	tracer := map[string]interface{}{"tracer": "callTracer"}
+
+	if rpcClient(client) == nil {
+		return nil, unsupportedError("backend does not support debug_traceTransaction")
+	}
+	if features := probedFeatures(client); features != nil && !features.Debug {
+		return nil, unsupportedError("node does not serve the debug namespace")
+	}
+
+	if err := callContext(ctx, client, &root, "debug_traceTransaction", hash, tracer); err != nil {
+		return nil, err
+	}
+
+	return decodeCallFrame(root, abis), nil
+}
+
+// decodeCallFrame decodes the frame with the first ABI knowing its selector and walks all sub calls.
+func decodeCallFrame(frame callFrame, abis []abi.ABI) *DecodedCallTree {
+	result := DecodedCallTree{
+		Type:    frame.Type,
+		From:    frame.From.Hex(),
+		To:      EtherAddress,
+		Value:   "0",
+		GasUsed: uint64(frame.GasUsed),
+		Params:  Params{},
+		Output:  Params{},
+		Error:   frame.Error,
+	}
+
+	if frame.To != nil {
+		result.To = frame.To.Hex()
+	}
+
+	if frame.Value != nil {
+		result.Value = frame.Value.ToInt().String()
+	}
+
+	if len(frame.Input) >= 4 && frame.Type != "CREATE" && frame.Type != "CREATE2" {
+		result.SigHash = hexutil.Encode(frame.Input[:4])
+
+		for _, contractAbi := range abis {
+			method, err := contractAbi.MethodById(frame.Input[:4])
+			if err != nil {
+				continue
+			}
+
+			params, err := unpackArguments(method.Inputs, frame.Input[4:])
+			if err != nil {
+				continue
+			}
+
+			result.Signature = method.Sig
+			result.Params = formatParameters(params, nil, method.Inputs...)
+
+			if frame.Error == "" && len(frame.Output) > 0 {
+				if output, err := unpackArguments(method.Outputs, frame.Output); err == nil {
+					result.Output = formatParameters(output, nil, method.Outputs...)
+				}
+			}
+			break
+		}
+	}
+
+	if frame.Error != "" && len(frame.Output) >= 4 {
+		result.Revert, _ = parseRevert(frame.Output, abis...)
+	}
+
+	for _, call := range frame.Calls {
+		result.Calls = append(result.Calls, decodeCallFrame(call, abis))
+	}
+
+	return &result
+}
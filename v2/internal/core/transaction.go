@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DecodedTransaction is a struct for holding a fully decoded transaction: its decoded method and
+// logs, the token transfers of the receipt, the revert reason if it failed, and the block it was
+// included in.
+type DecodedTransaction struct {
+	DecodedBlockTransaction
+
+	BlockNumber       uint64          `json:"blockNumber"`       // Block number the transaction was included in.
+	BlockHash         string          `json:"blockHash"`         // Hash of the block.
+	Timestamp         uint64          `json:"timestamp"`         // Block timestamp in seconds, 0 if the block is unknown.
+	EffectiveGasPrice *big.Int        `json:"effectiveGasPrice"` // Price per gas actually paid.
+	Transfers         []TokenTransfer `json:"transfers"`         // Token transfers of the receipt, see ExtractReceiptTransfers.
+	Revert            *DecodedError   `json:"revert,omitempty"`  // Decoded revert reason of failed transactions.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedTransaction object.
+func (data *DecodedTransaction) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedTransaction object.
+func (data *DecodedTransaction) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// DecodeTransactionFull is Store.DecodeTransactionFull.
+func DecodeTransactionFull(ctx context.Context, transactionHash string) (*DecodedTransaction, error) {
+	return Store.DecodeTransactionFull(ctx, transactionHash)
+}
+
+// DecodeTransactionFull fetches the transaction and its receipt in a single batch request, then
+// the header of its block, and decodes the method, logs and token transfers against the store.
+// The revert reason of failed transactions is decoded from a replay of the call, it is left
+// empty if the node can not replay it.
+func (store *Storage) DecodeTransactionFull(ctx context.Context, transactionHash string) (*DecodedTransaction, error) {
+	client, err := store.requireClient()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, receipt, err := fetchTransactionAndReceipt(ctx, client, common.HexToHash(transactionHash))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DecodedTransaction{
+		DecodedBlockTransaction: store.decodeBlockTransaction(ctx, tx, receipt.TransactionIndex, receipt),
+		BlockHash:               receipt.BlockHash.Hex(),
+		EffectiveGasPrice:       receipt.EffectiveGasPrice,
+		Transfers:               ExtractReceiptTransfers(receipt),
+	}
+
+	if result.From == "" {
+		if from, err := transactionSender(ctx, client, tx, receipt.BlockHash, receipt.TransactionIndex); err == nil {
+			result.From = from.Hex()
+		}
+	}
+
+	// nodes predating London report no effective gas price, legacy transactions pay their price
+	if result.EffectiveGasPrice == nil && tx.Type() == types.LegacyTxType {
+		result.EffectiveGasPrice = tx.GasPrice()
+	}
+
+	if receipt.BlockNumber != nil {
+		result.BlockNumber = receipt.BlockNumber.Uint64()
+
+		headers, err := fetchHeaders(ctx, client, []uint64{result.BlockNumber})
+		if err != nil {
+			return nil, err
+		}
+		if headers[0] != nil {
+			result.Timestamp = headers[0].Time
+		}
+	}
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		if payload, err := fetchRevertData(ctx, client, tx, receipt); err == nil {
+			if revert, err := store.DecodeRevert(payload); err == nil {
+				revert.TransactionHash = receipt.TxHash.Hex()
+				result.Revert = revert
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// fetchTransactionAndReceipt returns the transaction of the hash and its receipt, requested in a
+// single batch. Backends without a JSON-RPC client are asked one by one.
+func fetchTransactionAndReceipt(ctx context.Context, client Backend, hash common.Hash) (*types.Transaction, *types.Receipt, error) {
+	if rpcClient(client) == nil {
+		tx, _, err := client.TransactionByHash(ctx, hash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting transaction %s: %w", hash.Hex(), err)
+		}
+
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting receipt of %s: %w", hash.Hex(), err)
+		}
+
+		return tx, receipt, nil
+	}
+
+	var raw json.RawMessage
+	var receipt *types.Receipt
+	batch := []rpc.BatchElem{
+		{Method: "eth_getTransactionByHash", Args: []interface{}{hash}, Result: &raw},
+		{Method: "eth_getTransactionReceipt", Args: []interface{}{hash}, Result: &receipt},
+	}
+
+	if err := batchCall(ctx, client, batch); err != nil {
+		return nil, nil, fmt.Errorf("error getting transaction %s: %w", hash.Hex(), err)
+	}
+
+	if batch[0].Error != nil {
+		return nil, nil, fmt.Errorf("error getting transaction %s: %w", hash.Hex(), batch[0].Error)
+	}
+
+	tx, err := unmarshalTransaction(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding transaction %s: %w", hash.Hex(), err)
+	}
+	if tx == nil {
+		return nil, nil, fmt.Errorf("transaction %s not found", hash.Hex())
+	}
+
+	if batch[1].Error != nil {
+		return nil, nil, fmt.Errorf("error getting receipt of %s: %w", hash.Hex(), batch[1].Error)
+	}
+	if receipt == nil {
+		// pending transactions have no receipt yet
+		return nil, nil, fmt.Errorf("receipt of %s not found", hash.Hex())
+	}
+
+	return tx, receipt, nil
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestDecodeTransactionFull(t *testing.T) {
+	chain := newTestChain(t, 2)
+
+	txs := make(map[common.Hash]*types.Transaction)
+	for number := uint64(1); number <= 2; number++ {
+		tx := chain.blocks[number]["transactions"].([]*types.Transaction)[0]
+		txs[tx.Hash()] = tx
+		chain.receipts[tx.Hash()].EffectiveGasPrice = big.NewInt(1)
+	}
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	lookup := func(found func(common.Hash) interface{}) mockrpc.Handler {
+		return func(params json.RawMessage) (interface{}, error) {
+			var args []common.Hash
+			if err := json.Unmarshal(params, &args); err != nil {
+				return nil, err
+			}
+			return found(args[0]), nil
+		}
+	}
+	server.Handle("eth_getTransactionByHash", lookup(func(hash common.Hash) interface{} { return txs[hash] }))
+	server.Handle("eth_getTransactionReceipt", lookup(func(hash common.Hash) interface{} { return chain.receipts[hash] }))
+	server.Handle("eth_getBlockByNumber", func(params json.RawMessage) (interface{}, error) {
+		var args []interface{}
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		number, _ := hexutil.DecodeUint64(args[0].(string))
+		return chain.blocks[number], nil
+	})
+	server.HandleCall(func(common.Address, []byte) ([]byte, error) {
+		return nil, mockrpc.ErrReverted
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &Storage{}
+	store.SetClient(client)
+	store.ParseAndAddABIs(abi_erc20)
+
+	tx := chain.blocks[2]["transactions"].([]*types.Transaction)[0]
+	decoded, err := store.DecodeTransactionFull(context.Background(), tx.Hash().Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.From != crypto.PubkeyToAddress(chain.key.PublicKey).Hex() || decoded.Method == nil || decoded.Method.Params["value"] != "1000" {
+		t.Fatalf("invalid method: %s", decoded.ToJSON())
+	}
+	if len(decoded.Logs) != 1 || len(decoded.Transfers) != 1 || decoded.Transfers[0].Amount.Int64() != 1000 {
+		t.Fatalf("invalid logs: %s", decoded.ToJSON())
+	}
+	if decoded.Status != types.ReceiptStatusSuccessful || decoded.GasUsed != 51000 || decoded.EffectiveGasPrice.Int64() != 1 {
+		t.Fatalf("invalid status: %s", decoded.ToJSON())
+	}
+	if decoded.BlockNumber != 2 || decoded.BlockHash != chain.headers[2].Hash().Hex() || decoded.Timestamp != 1002 {
+		t.Fatalf("invalid block: %s", decoded.ToJSON())
+	}
+
+	// failed transactions have no transfers, the revert reason is left empty if it can not be
+	// decoded
+	failed := chain.blocks[1]["transactions"].([]*types.Transaction)[0]
+	chain.receipts[failed.Hash()].Status = types.ReceiptStatusFailed
+
+	decoded, err = store.DecodeTransactionFull(context.Background(), failed.Hash().Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Status != types.ReceiptStatusFailed || len(decoded.Transfers) != 0 || decoded.Revert != nil || len(server.Calls("eth_call")) != 1 {
+		t.Fatalf("invalid failed transaction: %s", decoded.ToJSON())
+	}
+
+	// an unknown transaction is reported
+	if _, err := store.DecodeTransactionFull(context.Background(), common.Hash{}.Hex()); err == nil {
+		t.Fatal("missing transaction not reported")
+	}
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Standards of the token transfers extracted by ExtractTransfers.
+const (
+	StandardERC20   = "erc20"
+	StandardERC721  = "erc721"
+	StandardERC1155 = "erc1155"
+	StandardWrapped = "wrapped" // Deposit and Withdrawal of wrapped native tokens, e.g. WETH.
+)
+
+var (
+	transferTopic       = common.HexToHash(TransferTopic)
+	transferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	transferBatchTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+	depositTopic        = crypto.Keccak256Hash([]byte("Deposit(address,uint256)"))
+	withdrawalTopic     = crypto.Keccak256Hash([]byte("Withdrawal(address,uint256)"))
+)
+
+// WrappedNativeTokens are the contracts whose Deposit and Withdrawal events are extracted as
+// transfers from and to the zero address, as other contracts emit events of the same signature.
+// It holds the wrapped native tokens of Ethereum, OP-stack chains, Arbitrum, Polygon and BNB Chain.
+var WrappedNativeTokens = map[common.Address]bool{
+	common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"): true, // WETH
+	common.HexToAddress("0x4200000000000000000000000000000000000006"): true, // WETH, OP-stack
+	common.HexToAddress("0x82aF49447D8a07e3bd95BD0d56f35241523fBab1"): true, // WETH, Arbitrum
+	common.HexToAddress("0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"): true, // WMATIC
+	common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"): true, // WBNB
+}
+
+// TokenTransfer is a single token transfer of any standard. Mints and wrapping deposits are
+// transfers from the zero address, burns and withdrawals transfers to it.
+type TokenTransfer struct {
+	Standard        string   `json:"standard"`             // One of the Standard* constants.
+	Token           string   `json:"token"`                // Contract of the token.
+	From            string   `json:"from"`                 // Account the tokens are taken from.
+	To              string   `json:"to"`                   // Account the tokens are credited to.
+	Amount          *big.Int `json:"amount"`               // Amount in the smallest unit, 1 for ERC721.
+	TokenId         *big.Int `json:"tokenId,omitempty"`    // Token id of ERC721 and ERC1155 transfers.
+	Operator        string   `json:"operator,omitempty"`   // Account that executed an ERC1155 transfer.
+	TransactionHash string   `json:"transactionHash"`      // Transaction hash of the log.
+	BlockNumber     uint64   `json:"blockNumber"`          // Block number of the log.
+	LogIndex        uint     `json:"logIndex"`             // Index of the log the transfer was extracted from.
+	BatchIndex      int      `json:"batchIndex,omitempty"` // Position of the id in an ERC1155 TransferBatch.
+	ValueUSD        string   `json:"valueUsd,omitempty"`   // USD value of the amount, set by ITknStore.AnnotateUSD.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the TokenTransfer object.
+func (data *TokenTransfer) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the TokenTransfer object.
+func (data *TokenTransfer) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ExtractTransfers converts the ERC20 and ERC721 Transfer, ERC1155 TransferSingle and
+// TransferBatch, and wrapped native token Deposit and Withdrawal logs into token transfers, in log
+// order. The raw topics and data of the logs are used, so the transfers do not depend on the
+// param names of the ABIs the logs were decoded with. Logs removed by a reorganization are skipped.
+func ExtractTransfers(logs []*DecodedLog) []TokenTransfer {
+	result := make([]TokenTransfer, 0)
+
+	for _, decoded := range logs {
+		if decoded == nil || decoded.Removed {
+			continue
+		}
+
+		topics := make([]common.Hash, len(decoded.Topics))
+		for i, topic := range decoded.Topics {
+			topics[i] = common.HexToHash(topic)
+		}
+
+		data, err := hexutil.Decode(decoded.Data)
+		if err != nil && decoded.Data != "" {
+			continue
+		}
+
+		vLog := types.Log{
+			Address:     common.HexToAddress(decoded.Contract),
+			Topics:      topics,
+			Data:        data,
+			BlockNumber: decoded.BlockNumber,
+			TxHash:      common.HexToHash(decoded.TransactionHash),
+			Index:       decoded.LogIndex,
+		}
+		result = append(result, logTransfers(&vLog)...)
+	}
+
+	return result
+}
+
+// ExtractReceiptTransfers is like ExtractTransfers for the raw logs of a receipt, which need not
+// be decoded first. Failed transactions do not transfer any tokens.
+func ExtractReceiptTransfers(receipt *types.Receipt) []TokenTransfer {
+	result := make([]TokenTransfer, 0)
+	if receipt == nil || receipt.Status == types.ReceiptStatusFailed {
+		return result
+	}
+
+	for _, vLog := range receipt.Logs {
+		if vLog != nil && !vLog.Removed {
+			result = append(result, logTransfers(vLog)...)
+		}
+	}
+
+	return result
+}
+
+// logTransfers returns the transfers of a single log, none for logs of other events.
+func logTransfers(vLog *types.Log) []TokenTransfer {
+	if len(vLog.Topics) == 0 {
+		return nil
+	}
+
+	transfer := TokenTransfer{
+		Token:           vLog.Address.Hex(),
+		TransactionHash: vLog.TxHash.Hex(),
+		BlockNumber:     vLog.BlockNumber,
+		LogIndex:        vLog.Index,
+	}
+
+	switch vLog.Topics[0] {
+	case transferTopic:
+		// ERC721 indexes the token id, ERC20 keeps the amount in the data
+		if len(vLog.Topics) == 4 {
+			transfer.Standard = StandardERC721
+			transfer.Amount = big.NewInt(1)
+			transfer.TokenId = vLog.Topics[3].Big()
+		} else if len(vLog.Topics) == 3 && len(vLog.Data) >= 32 {
+			transfer.Standard = StandardERC20
+			transfer.Amount = new(big.Int).SetBytes(vLog.Data[:32])
+		} else {
+			return nil
+		}
+
+		transfer.From = topicAddress(vLog.Topics[1]).Hex()
+		transfer.To = topicAddress(vLog.Topics[2]).Hex()
+		return []TokenTransfer{transfer}
+
+	case transferSingleTopic:
+		if len(vLog.Topics) != 4 || len(vLog.Data) < 64 {
+			return nil
+		}
+
+		transfer.Standard = StandardERC1155
+		transfer.Operator = topicAddress(vLog.Topics[1]).Hex()
+		transfer.From = topicAddress(vLog.Topics[2]).Hex()
+		transfer.To = topicAddress(vLog.Topics[3]).Hex()
+		transfer.TokenId = new(big.Int).SetBytes(vLog.Data[:32])
+		transfer.Amount = new(big.Int).SetBytes(vLog.Data[32:64])
+		return []TokenTransfer{transfer}
+
+	case transferBatchTopic:
+		if len(vLog.Topics) != 4 {
+			return nil
+		}
+
+		ids, values, ok := unpackBatch(vLog.Data)
+		if !ok {
+			return nil
+		}
+
+		transfer.Standard = StandardERC1155
+		transfer.Operator = topicAddress(vLog.Topics[1]).Hex()
+		transfer.From = topicAddress(vLog.Topics[2]).Hex()
+		transfer.To = topicAddress(vLog.Topics[3]).Hex()
+
+		result := make([]TokenTransfer, 0, len(ids))
+		for i := range ids {
+			item := transfer
+			item.TokenId = ids[i]
+			item.Amount = values[i]
+			item.BatchIndex = i
+			result = append(result, item)
+		}
+		return result
+
+	case depositTopic, withdrawalTopic:
+		if !WrappedNativeTokens[vLog.Address] || len(vLog.Topics) != 2 || len(vLog.Data) < 32 {
+			return nil
+		}
+
+		transfer.Standard = StandardWrapped
+		transfer.Amount = new(big.Int).SetBytes(vLog.Data[:32])
+		if vLog.Topics[0] == depositTopic {
+			transfer.From = EtherAddress
+			transfer.To = topicAddress(vLog.Topics[1]).Hex()
+		} else {
+			transfer.From = topicAddress(vLog.Topics[1]).Hex()
+			transfer.To = EtherAddress
+		}
+		return []TokenTransfer{transfer}
+	}
+
+	return nil
+}
+
+// topicAddress returns the address stored in an indexed topic.
+func topicAddress(topic common.Hash) common.Address {
+	return common.BytesToAddress(topic.Bytes())
+}
+
+// unpackBatch unpacks the ids and values of a TransferBatch log, which must be of equal length.
+func unpackBatch(data []byte) ([]*big.Int, []*big.Int, bool) {
+	uintArray, _ := abi.NewType("uint256[]", "", nil)
+	unpacked, err := abi.Arguments{{Type: uintArray}, {Type: uintArray}}.Unpack(data)
+	if err != nil || len(unpacked) != 2 {
+		return nil, nil, false
+	}
+
+	ids, okIds := unpacked[0].([]*big.Int)
+	values, okValues := unpacked[1].([]*big.Int)
+	if !okIds || !okValues || len(ids) != len(values) {
+		return nil, nil, false
+	}
+
+	return ids, values, true
+}
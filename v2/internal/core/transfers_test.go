@@ -0,0 +1,90 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestExtractTransfers(t *testing.T) {
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	token := common.HexToAddress(target_erc20)
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+
+	uintArray, _ := abi.NewType("uint256[]", "", nil)
+	batch, err := abi.Arguments{{Type: uintArray}, {Type: uintArray}}.Pack(
+		[]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(10), big.NewInt(20)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	word := func(n int64) []byte { return common.LeftPadBytes(big.NewInt(n).Bytes(), 32) }
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{
+		{Address: token, Topics: []common.Hash{transferTopic, alice.Hash(), bob.Hash()}, Data: word(5), Index: 0},
+		{Address: token, Topics: []common.Hash{transferTopic, alice.Hash(), bob.Hash(), common.BigToHash(big.NewInt(7))}, Index: 1},
+		{Address: token, Topics: []common.Hash{transferSingleTopic, alice.Hash(), alice.Hash(), bob.Hash()}, Data: append(word(3), word(4)...), Index: 2},
+		{Address: token, Topics: []common.Hash{transferBatchTopic, alice.Hash(), alice.Hash(), bob.Hash()}, Data: batch, Index: 3},
+		{Address: weth, Topics: []common.Hash{depositTopic, alice.Hash()}, Data: word(9), Index: 4},
+		{Address: weth, Topics: []common.Hash{withdrawalTopic, bob.Hash()}, Data: word(8), Index: 5},
+		// deposits of other contracts are no wrapping
+		{Address: token, Topics: []common.Hash{depositTopic, alice.Hash()}, Data: word(9), Index: 6},
+	}}
+
+	transfers := ExtractReceiptTransfers(receipt)
+	expected := []struct {
+		standard string
+		from     common.Address
+		to       common.Address
+		amount   int64
+		tokenId  int64
+	}{
+		{StandardERC20, alice, bob, 5, -1},
+		{StandardERC721, alice, bob, 1, 7},
+		{StandardERC1155, alice, bob, 4, 3},
+		{StandardERC1155, alice, bob, 10, 1},
+		{StandardERC1155, alice, bob, 20, 2},
+		{StandardWrapped, common.Address{}, alice, 9, -1},
+		{StandardWrapped, bob, common.Address{}, 8, -1},
+	}
+
+	if len(transfers) != len(expected) {
+		t.Fatalf("unexpected transfers: %d", len(transfers))
+	}
+
+	for i, want := range expected {
+		got := transfers[i]
+		if got.Standard != want.standard || got.From != want.from.Hex() || got.To != want.to.Hex() || got.Amount.Int64() != want.amount {
+			t.Fatalf("unexpected transfer %d: %s", i, got.ToJSON())
+		}
+
+		if (want.tokenId < 0) != (got.TokenId == nil) || (got.TokenId != nil && got.TokenId.Int64() != want.tokenId) {
+			t.Fatalf("unexpected token id %d: %s", i, got.ToJSON())
+		}
+	}
+
+	if transfers[4].BatchIndex != 1 || transfers[2].Operator != alice.Hex() {
+		t.Fatalf("unexpected batch transfer: %s", transfers[4].ToJSON())
+	}
+
+	// decoded logs carry their raw topics and data
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+	decoded := decoder.DecodeLog(receipt.Logs[0])
+	if decoded == nil {
+		t.Fatal("transfer not decoded")
+	}
+
+	fromDecoded := ExtractTransfers([]*DecodedLog{decoded})
+	if len(fromDecoded) != 1 || fromDecoded[0].Amount.Int64() != 5 || fromDecoded[0].To != bob.Hex() {
+		t.Fatalf("unexpected transfers of decoded logs: %+v", fromDecoded)
+	}
+
+	receipt.Status = types.ReceiptStatusFailed
+	if len(ExtractReceiptTransfers(receipt)) != 0 {
+		t.Fatal("failed transaction transferred tokens")
+	}
+}
@@ -0,0 +1,241 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Labels of the transactions classified by ClassifyTransaction.
+const (
+	LabelSwap     = "swap"
+	LabelTransfer = "transfer"
+	LabelApproval = "approval"
+	LabelMint     = "mint"
+	LabelBurn     = "burn"
+	LabelBridge   = "bridge"
+	LabelDeploy   = "contract-deploy"
+	LabelUnknown  = "unknown"
+)
+
+// BridgeMethods are the names of methods sending assets to another chain, e.g. of the canonical
+// bridges of rollups. Calls of these methods are classified as LabelBridge.
+var BridgeMethods = map[string]bool{
+	"bridgeETH":                     true,
+	"bridgeETHTo":                   true,
+	"bridgeERC20":                   true,
+	"bridgeERC20To":                 true,
+	"depositETH":                    true,
+	"depositETHTo":                  true,
+	"depositERC20":                  true,
+	"depositERC20To":                true,
+	"depositTransaction":            true,
+	"outboundTransfer":              true,
+	"outboundTransferCustomRefund":  true,
+	"sendToL2":                      true,
+	"withdrawTo":                    true,
+	"finalizeWithdrawal":            true,
+	"proveWithdrawalTransaction":    true,
+	"finalizeWithdrawalTransaction": true,
+	"sendMessage":                   true,
+	"relayMessage":                  true,
+}
+
+// BridgeEvents are the names of events emitted when assets are sent to or received from another
+// chain. Transactions emitting them are classified as LabelBridge.
+var BridgeEvents = map[string]bool{
+	"ETHDepositInitiated":   true,
+	"ERC20DepositInitiated": true,
+	"ETHBridgeInitiated":    true,
+	"ERC20BridgeInitiated":  true,
+	"DepositInitiated":      true,
+	"WithdrawalInitiated":   true,
+	"WithdrawalFinalized":   true,
+	"TransactionDeposited":  true,
+	"TransferSentToL2":      true,
+	"SentMessage":           true,
+	"MessageDelivered":      true,
+	"InboxMessageDelivered": true,
+}
+
+// Classification labels a transaction, see ClassifyTransaction.
+type Classification struct {
+	Label      string          `json:"label"`            // One of the Label* constants.
+	Confidence float64         `json:"confidence"`       // Likelihood of the label, 1 when the method tells it.
+	Assets     []string        `json:"assets"`           // Tokens moved, EtherAddress for the native currency.
+	Reason     string          `json:"reason,omitempty"` // What the label was derived from.
+	Transfers  []TokenTransfer `json:"transfers"`        // Token transfers of the logs, see ExtractTransfers.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the Classification object.
+func (data *Classification) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the Classification object.
+func (data *Classification) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// ClassifyTransaction labels a transaction by its decoded method, its decoded logs and the token
+// transfers of the logs. Contract creations are labeled first, then approvals, bridge transfers,
+// swaps, mints, burns and plain transfers. Rules matching the method are certain, rules matching
+// logs or the transfer set have a lower confidence. The method, and the transaction of calls
+// decoded without one, may be nil.
+func ClassifyTransaction(tx *types.Transaction, method *DecodedMethod, logs []*DecodedLog) *Classification {
+	transfers := ExtractTransfers(logs)
+	result := Classification{Label: LabelUnknown, Assets: transferAssets(tx, transfers), Transfers: transfers}
+
+	name := ""
+	if method != nil && !method.Unknown {
+		name = method.Name
+	}
+
+	events := make(map[string]bool)
+	for _, decoded := range logs {
+		if decoded != nil {
+			events[decoded.Name] = true
+		}
+	}
+
+	label := func(label string, confidence float64, reason string) *Classification {
+		result.Label = label
+		result.Confidence = confidence
+		result.Reason = reason
+		return &result
+	}
+
+	if tx != nil && tx.To() == nil {
+		return label(LabelDeploy, 1, "contract creation")
+	}
+
+	switch name {
+	case "approve", "increaseAllowance", "decreaseAllowance", "setApprovalForAll", "permit":
+		return label(LabelApproval, 1, "method "+name)
+	}
+
+	if BridgeMethods[name] {
+		return label(LabelBridge, 1, "method "+name)
+	}
+	for _, decoded := range logs {
+		if decoded != nil && BridgeEvents[decoded.Name] {
+			return label(LabelBridge, 0.8, "event "+decoded.Name)
+		}
+	}
+
+	if isSwapMethod(name) {
+		return label(LabelSwap, 1, "method "+name)
+	}
+	if events["Swap"] {
+		return label(LabelSwap, 0.9, "event Swap")
+	}
+	if tx != nil && swapsAssets(tx, transfers) {
+		return label(LabelSwap, 0.7, "sender exchanged assets")
+	}
+
+	switch name {
+	case "mint", "safeMint", "mintTo", "batchMint":
+		return label(LabelMint, 1, "method "+name)
+	case "burn", "burnFrom", "batchBurn":
+		return label(LabelBurn, 1, "method "+name)
+	case "transfer", "transferFrom", "safeTransferFrom", "safeBatchTransferFrom":
+		return label(LabelTransfer, 1, "method "+name)
+	}
+
+	if len(transfers) > 0 {
+		mints, burns := 0, 0
+		for _, transfer := range transfers {
+			if transfer.Standard == StandardWrapped {
+				continue
+			}
+			if transfer.From == EtherAddress {
+				mints++
+			} else if transfer.To == EtherAddress {
+				burns++
+			}
+		}
+
+		if mints == len(transfers) {
+			return label(LabelMint, 0.9, "transfers from the zero address")
+		}
+		if burns == len(transfers) {
+			return label(LabelBurn, 0.9, "transfers to the zero address")
+		}
+		return label(LabelTransfer, 0.8, "token transfers")
+	}
+
+	if (events["Approval"] || events["ApprovalForAll"]) && len(events) <= 2 {
+		return label(LabelApproval, 0.8, "approval events")
+	}
+
+	if tx != nil && tx.Value() != nil && tx.Value().Sign() > 0 && len(tx.Data()) == 0 {
+		return label(LabelTransfer, 1, "native value transfer")
+	}
+
+	return &result
+}
+
+// isSwapMethod returns true for the swap methods of Uniswap style routers and aggregators.
+func isSwapMethod(name string) bool {
+	if strings.HasPrefix(name, "swap") || strings.HasPrefix(name, "exactInput") || strings.HasPrefix(name, "exactOutput") {
+		return true
+	}
+
+	return name == "unoswap" || name == "uniswapV3Swap" || name == "fillOrder"
+}
+
+// swapsAssets returns true if the sender of the transaction sent one asset and received another,
+// including the native currency it paid.
+func swapsAssets(tx *types.Transaction, transfers []TokenTransfer) bool {
+	sender, err := txSender(tx)
+	if err != nil {
+		return false
+	}
+
+	sent := make(map[string]bool)
+	received := make(map[string]bool)
+	if tx.Value() != nil && tx.Value().Sign() > 0 {
+		sent[EtherAddress] = true
+	}
+
+	for _, transfer := range transfers {
+		if transfer.From == sender.Hex() {
+			sent[transfer.Token] = true
+		}
+		if transfer.To == sender.Hex() {
+			received[transfer.Token] = true
+		}
+	}
+
+	for in := range sent {
+		for out := range received {
+			if in != out {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// transferAssets returns the assets moved by the transaction, in order of appearance.
+func transferAssets(tx *types.Transaction, transfers []TokenTransfer) []string {
+	result := make([]string, 0)
+	seen := make(map[string]bool)
+
+	if tx != nil && tx.Value() != nil && tx.Value().Sign() > 0 {
+		result = append(result, EtherAddress)
+		seen[EtherAddress] = true
+	}
+
+	for _, transfer := range transfers {
+		if !seen[transfer.Token] {
+			seen[transfer.Token] = true
+			result = append(result, transfer.Token)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestClassifyTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	token := common.HexToAddress(target_erc20)
+	other := common.HexToAddress("0x0000000000000000000000000000000000000123")
+
+	sign := func(to *common.Address, value int64, data []byte) *types.Transaction {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), To: to, Value: big.NewInt(value), Data: data})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	transfer := func(token common.Address, from common.Address, to common.Address) *DecodedLog {
+		return &DecodedLog{
+			Contract: token.Hex(),
+			Name:     "Transfer",
+			Topics:   []string{TransferTopic, from.Hash().Hex(), to.Hash().Hex()},
+			Data:     hexutil.Encode(common.LeftPadBytes([]byte{1}, 32)),
+		}
+	}
+
+	cases := []struct {
+		name   string
+		tx     *types.Transaction
+		method *DecodedMethod
+		logs   []*DecodedLog
+		label  string
+	}{
+		{"deploy", sign(nil, 0, []byte{0x60}), nil, nil, LabelDeploy},
+		{"approve", sign(&token, 0, nil), &DecodedMethod{Name: "approve"}, nil, LabelApproval},
+		{"bridge", sign(&other, 1, nil), &DecodedMethod{Name: "depositETH"}, nil, LabelBridge},
+		{"swap event", sign(&other, 0, nil), nil, []*DecodedLog{{Name: "Swap"}}, LabelSwap},
+		{"exchanged", sign(&other, 1, nil), nil, []*DecodedLog{transfer(token, other, sender)}, LabelSwap},
+		{"mint", sign(&token, 0, nil), nil, []*DecodedLog{transfer(token, common.Address{}, other)}, LabelMint},
+		{"burn", sign(&token, 0, nil), nil, []*DecodedLog{transfer(token, sender, common.Address{})}, LabelBurn},
+		{"transfer", sign(&token, 0, nil), nil, []*DecodedLog{transfer(token, sender, other)}, LabelTransfer},
+		{"native", sign(&other, 1, nil), nil, nil, LabelTransfer},
+		{"unknown", sign(&other, 0, []byte{1, 2, 3, 4}), &DecodedMethod{Unknown: true}, nil, LabelUnknown},
+	}
+
+	for _, c := range cases {
+		result := ClassifyTransaction(c.tx, c.method, c.logs)
+		if result.Label != c.label {
+			t.Fatalf("%s: unexpected classification: %s", c.name, result.ToJSON())
+		}
+	}
+
+	exchanged := ClassifyTransaction(cases[4].tx, nil, cases[4].logs)
+	if len(exchanged.Assets) != 2 || exchanged.Assets[0] != EtherAddress || exchanged.Assets[1] != token.Hex() {
+		t.Fatalf("unexpected assets: %v", exchanged.Assets)
+	}
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type Params map[string]interface{}
+
+func (m *Params) MarshalJSON() ([]byte, error) {
+	regex := regexp.MustCompile(`^"0x[0-9a-fA-F]{40}"$`)
+	result := "{"
+	var parts []string
+
+	// sort the keys, so the encoding is deterministic, e.g. for attestations
+	keys := make([]string, 0, len(*m))
+	for k := range *m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := (*m)[k]
+		part, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if regex.MatchString(string(part)) {
+			addr := common.HexToAddress(strings.ReplaceAll(string(part), "\"", ""))
+			parts = append(parts, fmt.Sprintf(`"%s":"%s"`, k, addr.Hex()))
+		} else {
+			parts = append(parts, fmt.Sprintf(`"%s":%s`, k, string(part)))
+		}
+	}
+
+	result += strings.Join(parts, ",")
+	result += "}"
+
+	return []byte(result), nil
+}
+
+// AddressNames maps checksummed addresses to human-readable names, e.g. their ENS names.
+type AddressNames map[string]string
+
+// ParamInfo is a struct for holding the ABI metadata of a decoded param.
+type ParamInfo struct {
+	Name    string `json:"name"`              // Key of the param in Params, the position for unnamed params.
+	Type    string `json:"type"`              // Canonical ABI type, e.g. uint256 or (address,bytes)[].
+	Indexed bool   `json:"indexed,omitempty"` // Whether the event param is stored in a topic.
+}
+
+// DecodedParam is a struct for holding a decoded param together with its ABI metadata.
+type DecodedParam struct {
+	Name    string      `json:"name"`              // Key of the param in Params.
+	Type    string      `json:"type"`              // Canonical ABI type.
+	Value   interface{} `json:"value"`             // Formatted value, the same as in Params.
+	Indexed bool        `json:"indexed,omitempty"` // Whether the event param is stored in a topic.
+}
+
+type ScannedLogs []DecodedLog
+
+func (l *ScannedLogs) ToJSONBytes() []byte {
+	res, err := json.Marshal(l)
+	if err != nil {
+		panic(err)
+	}
+
+	return res
+}
+
+func (l *ScannedLogs) ToJSON() string {
+	return string(l.ToJSONBytes())
+}
+
+// DecodedLog is a struct for holding decoded Ethereum logs.
+type DecodedLog struct {
+	Contract        string         `json:"contract"`              // Contract address of the decoded log.
+	Topic           string         `json:"topic"`                 // Event topic hash of the decoded log.
+	Signature       string         `json:"signature"`             // Event signature of the decoded log.
+	Name            string         `json:"name"`                  // Event name of the decoded log.
+	Params          Params         `json:"params"`                // Parameters of the decoded log.
+	Inputs          []ParamInfo    `json:"inputs"`                // Ordered ABI metadata of the parameters.
+	ParamsOrdered   []DecodedParam `json:"paramsOrdered"`         // Parameters in signature order.
+	Topics          []string       `json:"topics"`                // Raw topics of the log.
+	Data            string         `json:"data"`                  // Raw data of the log.
+	TransactionHash string         `json:"transactionHash"`       // Transaction hash of the decoded log.
+	LogIndex        uint           `json:"logIndex"`              // Index of the decoded log
+	BlockNumber     uint64         `json:"blockNumber"`           // blockNumber of given decoded log
+	BlockHash       string         `json:"blockHash"`             // Hash of the block containing the log.
+	Removed         bool           `json:"removed,omitempty"`     // Whether the log was removed by a chain reorganization.
+	IdempotencyKey  string         `json:"idempotencyKey"`        // Deduplication key, see LogIdempotencyKey.
+	Anonymous       bool           `json:"anonymous,omitempty"`   // Whether the log was decoded against an anonymous event.
+	Confidence      float64        `json:"confidence,omitempty"`  // Likelihood of an anonymous match, 1 when only one candidate fits.
+	Flagged         []string       `json:"flagged,omitempty"`     // Addresses flagged by the compliance hook.
+	ENSNames        AddressNames   `json:"ensNames,omitempty"`    // ENS names of the address params, if ResolveNames is set.
+	Labels          AddressNames   `json:"labels,omitempty"`      // Labels of the contract and address params, if a LabelStore is set.
+	RequestID       string         `json:"requestId,omitempty"`   // Request id of the context the log was decoded in.
+	Attestation     *Attestation   `json:"attestation,omitempty"` // Signature of the result, if an Attestor is configured.
+
+	pending *pendingLog // set by ClassifyLog until Hydrate decodes the params
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedLog object.
+func (data *DecodedLog) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedLog object.
+func (data *DecodedLog) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// GetParamsJSON returns the JSON-encoded string of the parameters of the DecodedLog object.
+func (data *DecodedLog) GetParamsJSON() string {
+	if data == nil {
+		return "{}"
+	}
+
+	b, _ := json.Marshal(data.Params)
+
+	return string(b)
+}
+
+// GetSig returns the signature of the DecodedLog object.
+func (data *DecodedLog) GetSig() string {
+	if data == nil {
+		return ""
+	}
+
+	return data.Signature
+}
+
+// GetSigHash returns the signature hash of the DecodedLog object.
+func (data *DecodedLog) GetSigHash() string {
+	if data == nil {
+		return ""
+	}
+
+	return data.Topic
+}
+
+// DecodedMethod is a struct for holding decoded Ethereum methods.
+type DecodedMethod struct {
+	TransactionHash string           `json:"transactionHash"`          // Transaction hash of the decoded method.
+	Contract        string           `json:"contract"`                 // Contract address of the decoded method.
+	SigHash         string           `json:"sigHash"`                  // Function selector hash of the decoded method.
+	Signature       string           `json:"signature"`                // Function signature of the decoded method.
+	Name            string           `json:"name"`                     // Function name of the decoded method.
+	Params          Params           `json:"params"`                   // Parameters of the decoded method.
+	Inputs          []ParamInfo      `json:"inputs"`                   // Ordered ABI metadata of the parameters.
+	ParamsOrdered   []DecodedParam   `json:"paramsOrdered"`            // Parameters in signature order.
+	Data            string           `json:"data"`                     // Raw calldata of the decoded method.
+	Flagged         []string         `json:"flagged,omitempty"`        // Addresses flagged by the compliance hook.
+	Unknown         bool             `json:"unknown,omitempty"`        // Whether no ABI knew the selector, only SigHash and Data are set.
+	Calls           []*DecodedMethod `json:"calls,omitempty"`          // Decoded calls nested in the params, e.g. of multicalls.
+	Permits         []PermitInfo     `json:"permits,omitempty"`        // Signed approvals passed as params, see DecodePermits.
+	Tx              *TxInfo          `json:"tx,omitempty"`             // Type, sender, value, nonce and gas of the transaction, if IncludeTx is set.
+	ENSNames        AddressNames     `json:"ensNames,omitempty"`       // ENS names of the address params, if ResolveNames is set.
+	Labels          AddressNames     `json:"labels,omitempty"`         // Labels of the contract and address params, if a LabelStore is set.
+	RequestID       string           `json:"requestId,omitempty"`      // Request id of the context the method was decoded in.
+	IdempotencyKey  string           `json:"idempotencyKey,omitempty"` // Deduplication key, see MethodIdempotencyKey.
+	Attestation     *Attestation     `json:"attestation,omitempty"`    // Signature of the result, if an Attestor is configured.
+
+	nested []nestedCall // raw calldata nested in the params, decoded into Calls
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedMethod object.
+func (data *DecodedMethod) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedMethod object.
+func (data *DecodedMethod) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// GetParamsJSON returns the JSON-encoded string of the parameters of the DecodedMethod object.
+func (data *DecodedMethod) GetParamsJSON() string {
+	if data == nil {
+		return "{}"
+	}
+
+	b, _ := json.Marshal(data.Params)
+	return string(b)
+}
+
+// GetSig returns the signature of the DecodedMethod object.
+func (data *DecodedMethod) GetSig() string {
+	if data == nil {
+		return ""
+	}
+
+	return data.Signature
+}
+
+// GetSigHash returns the signature hash of the DecodedMethod object.
+func (data *DecodedMethod) GetSigHash() string {
+	if data == nil {
+		return ""
+	}
+
+	return "0x" + data.SigHash
+}
+
+// TxInfo holds the fields of the transaction a method was decoded from.
+type TxInfo struct {
+	Type       uint8            `json:"type"`                           // EIP-2718 transaction type, see TxTypeName.
+	TypeName   string           `json:"typeName"`                       // Name of the type, e.g. dynamicFee.
+	From       string           `json:"from"`                           // Recovered sender, empty if the signature is invalid.
+	Value      string           `json:"value"`                          // Transferred value in wei.
+	Nonce      uint64           `json:"nonce"`                          // Nonce of the sender.
+	Gas        uint64           `json:"gas"`                            // Gas limit.
+	GasPrice   string           `json:"gasPrice,omitempty"`             // Gas price of legacy and access list transactions.
+	GasFeeCap  string           `json:"maxFeePerGas,omitempty"`         // Max fee per gas of dynamic fee and blob transactions.
+	GasTipCap  string           `json:"maxPriorityFeePerGas,omitempty"` // Max priority fee per gas of dynamic fee and blob transactions.
+	BlobFeeCap string           `json:"maxFeePerBlobGas,omitempty"`     // Max fee per blob gas of blob transactions.
+	BlobGas    uint64           `json:"blobGas,omitempty"`              // Blob gas used by the blobs of blob transactions.
+	BlobHashes []common.Hash    `json:"blobVersionedHashes,omitempty"`  // Versioned hashes of the blobs of blob transactions.
+	AccessList types.AccessList `json:"accessList,omitempty"`           // Access list of EIP-2930 and later transactions.
+	ChainId    *uint64          `json:"chainId,omitempty"`              // Chain id of replay protected transactions.
+}
+
+// DecodedDeployment is a struct for holding decoded contract-creation transactions.
+type DecodedDeployment struct {
+	TransactionHash string `json:"transactionHash"`          // Transaction hash of the deployment.
+	Creator         string `json:"creator"`                  // Address that sent the deployment.
+	Contract        string `json:"contract"`                 // Address of the created contract.
+	Bytecode        string `json:"bytecode"`                 // Init code without the constructor arguments.
+	Arguments       string `json:"arguments"`                // Raw ABI-encoded constructor arguments.
+	Signature       string `json:"signature"`                // Constructor signature of the decoded deployment.
+	Params          Params `json:"params"`                   // Decoded constructor parameters.
+	Implementation  string `json:"implementation,omitempty"` // Target of an EIP-1167 minimal proxy deployment.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedDeployment object.
+func (data *DecodedDeployment) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedDeployment object.
+func (data *DecodedDeployment) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// DecodedReceipt is a struct for holding a decoded transaction receipt including its fees.
+type DecodedReceipt struct {
+	TransactionHash string        `json:"transactionHash"`  // Transaction hash of the receipt.
+	BlockNumber     uint64        `json:"blockNumber"`      // Block number the transaction was included in.
+	Status          uint64        `json:"status"`           // Execution status, 1 for success.
+	GasUsed         uint64        `json:"gasUsed"`          // Gas used by the transaction.
+	Logs            ScannedLogs   `json:"logs"`             // Decoded logs of the receipt.
+	Revert          *DecodedError `json:"revert,omitempty"` // Decoded revert reason of failed transactions.
+	Fees            *FeeBreakdown `json:"fees,omitempty"`   // Fee attribution of the transaction.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the DecodedReceipt object.
+func (data *DecodedReceipt) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the DecodedReceipt object.
+func (data *DecodedReceipt) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
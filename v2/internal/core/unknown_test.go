@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestKeepUnknown(t *testing.T) {
+	decoder := AbiDecoder{Abi: ParseABI(abi_erc20)}
+
+	tx := types.NewTransaction(0, common.HexToAddress(target_erc20), common.Big0, 0, common.Big0, common.FromHex("0xdeadbeef0001"))
+	if decoded := decoder.DecodeMethod(tx); decoded != nil {
+		t.Fatalf("unknown call decoded without KeepUnknown: %v", decoded.ToJSON())
+	}
+
+	decoder.KeepUnknown = true
+	unknown, err := decoder.DecodeCalldata(common.FromHex("0xdeadbeef0001"))
+	if err != nil || !unknown.Unknown || unknown.SigHash != "0xdeadbeef" || unknown.Data != "0xdeadbeef0001" || unknown.Signature != "" {
+		t.Fatalf("invalid unknown result: %v %v", unknown, err)
+	}
+
+	if decoded := decoder.DecodeMethod(tx); decoded == nil || !decoded.Unknown || decoded.SigHash != "0xdeadbeef" {
+		t.Fatalf("unknown call not kept: %v", decoded)
+	}
+
+	// known selectors are decoded as usual
+	data, _ := decoder.Abi.Pack("approve", common.HexToAddress(target_contract), common.Big1)
+	if decoded, err := decoder.DecodeCalldata(data); err != nil || decoded.Unknown || decoded.Name != "approve" {
+		t.Fatalf("known call flagged as unknown: %v %v", decoded, err)
+	}
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	EtherAddress  = "0x0000000000000000000000000000000000000000"
+	Zero32Bytes   = "0x0000000000000000000000000000000000000000000000000000000000000000"
+	TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+func ParseABI(input string) *abi.ABI {
+	contractAbi, err := abi.JSON(strings.NewReader(input))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return &contractAbi
+}
+
+func ToAscii(input []byte) string {
+	if len(input) < 64 {
+		return ""
+	}
+
+	var out = make([]byte, 0)
+	for _, b := range input[64:] {
+		if b > 0 {
+			// fmt.Printf("%#U starts at byte position %d\n", b, i)
+			out = append(out, b)
+		}
+	}
+
+	return string(out)
+}
+
+func MergeABIs(jsonAbis ...string) *abi.ABI {
+	mergedABI := abi.ABI{
+		Methods: make(map[string]abi.Method),
+		Events:  make(map[string]abi.Event),
+		Errors:  make(map[string]abi.Error),
+	}
+
+	for _, jsonStr := range jsonAbis {
+		contractAbi, err := abi.JSON(bytes.NewReader([]byte(jsonStr)))
+		if err != nil {
+			log.Fatal("decoder.MergeABIs: error parsing ABI: ", err)
+		}
+
+		// Merge Methods
+		for name, method := range contractAbi.Methods {
+			mergedABI.Methods[name] = method
+		}
+
+		// Merge Events
+		for name, event := range contractAbi.Events {
+			mergedABI.Events[name] = event
+		}
+
+		// Merge Errors
+		for name, customError := range contractAbi.Errors {
+			mergedABI.Errors[name] = customError
+		}
+	}
+
+	return &mergedABI
+}
+
+// IsEIP1559 reports whether the latest block of the backend has a base fee.
+//
+// Deprecated: use ProbeChain, which detects further features of the chain and node and is cached.
+func IsEIP1559(client Backend, ctx_ context.Context) (*bool, error) {
+	head, err := client.HeaderByNumber(ctx_, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := head.BaseFee != nil
+	return &result, nil
+}
+
+// ToSHA3 returns the 0x prefixed keccak256 hash of the string. Hashes are memoized, as the same
+// signatures are hashed over and over when indexing contracts, see SetKeccak.
+func ToSHA3(data string) string {
+	return sha3Hex(data)
+}
+
+// IsToken checks whether the bytecode implements any of the known token standards.
+func IsToken(bytecode string) bool {
+	return AnalyzeBytecode(bytecode).Standard != "UNKNOWN"
+}
+
+func IsERC1155(bytecode string) bool {
+	report := AnalyzeBytecode(bytecode)
+	return report.Supports("ERC1155")
+}
+
+func IsERC721(bytecode string) bool {
+	report := AnalyzeBytecode(bytecode)
+	return report.Supports("ERC721")
+}
+
+func IsERC20(bytecode string) bool {
+	report := AnalyzeBytecode(bytecode)
+	return report.Supports("ERC20")
+}
+
+// helper function to detect token standard.
+// results: ERC20, ERC721, ERC1155, ERC4626, ERC777, UNKNOWN
+func DetectTokenStandard(bytecode string) string {
+	return AnalyzeBytecode(bytecode).Standard
+}
+
+// DetectBytecodes checks if a given bytecode contains a set of signatures.
+//
+// 4 byte selectors are looked up in the PUSH1 to PUSH4 operands and 32 byte topics in the PUSH32
+// operands of the bytecode, see AnalyzeBytecode. Signatures of other lengths are searched in the raw
+// bytecode.
+//
+// Parameters:
+// - bytecode: The bytecode string to search for signatures.
+// - signatures: A list of hex signatures to check for within the bytecode.
+//
+// Returns:
+//   - true if all signatures are found, otherwise false.
+//
+// Example Usage:
+//
+//	bytecode := "0x63a9059cbb14"
+//	signatures := []string{"0xa9059cbb"}
+//	result := DetectBytecodes(bytecode, signatures)
+//	// result will be true as the selector is pushed by the dispatcher.
+func DetectBytecodes(bytecode string, signatures []string) bool {
+	selectors, topics := pushOperands(bytecode)
+	short := shortSelectors(bytecode)
+	raw := strings.ToLower(bytecode)
+
+	for _, code := range signatures {
+		code = strings.ToLower(strings.TrimPrefix(code, "0x")) // Remove "0x" prefix if it exists
+
+		switch len(code) {
+		case 8:
+			if !selectors[code] && !short[code] {
+				return false
+			}
+		case 64:
+			if !topics[code] {
+				return false
+			}
+		default:
+			if !strings.Contains(raw, code) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func GetMinerAndNonce(block *types.Block) (miner string, nonce string) {
+	bytes, err := block.Header().MarshalJSON()
+	if err != nil {
+		log.Fatal(`error marshalling block json`)
+		return EtherAddress, "0x"
+	}
+
+	var data map[string]interface{}
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		log.Fatal(`error marshalling block json`)
+		return EtherAddress, "0x"
+	}
+
+	minerAddress := common.HexToAddress(data["miner"].(string)).Hex()
+
+	return minerAddress, data["nonce"].(string)
+}
@@ -0,0 +1,279 @@
+package core
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	test_bytecode = "0x608060405260043610610078576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff1680638d8f2adb1461007d578063babd701214610094578063c0ee0b8a146100bf578063d01cf41914610152578063e8742a401461017d578063fc0c546a146101e9575b600080fd5b34801561008957600080fd5b50610092610240565b005b3480156100a057600080fd5b506100a9610436565b6040518082815260200191505060405180910390f35b3480156100cb57600080fd5b50610150600480360381019080803573ffffffffffffffffffffffffffffffffffffffff16906020019092919080359060200190929190803590602001908201803590602001908080601f016020809104026020016040519081016040528093929190818152602001838380828437820191505050505050919291929050505061043c565b005b34801561015e57600080fd5b50610167610714565b6040518082815260200191505060405180910390f35b34801561018957600080fd5b5061019261071a565b6040518080602001828103825283818151815260200191508051906020019060200280838360005b838110156101d55780820151818401526020810190506101ba565b505050509050019250505060405180910390f35b3480156101f557600080fd5b506101fe6107a8565b604051808273ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200191505060405180910390f35b6000600360003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054905060008111151561029057fe5b600160008154809291906001900391905055506102b8816002546107cd90919063ffffffff16565b6002819055506000809054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1663a9059cbb33836040518363ffffffff167c0100000000000000000000000000000000000000000000000000000000028152600401808373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200182815260200192505050602060405180830381600087803b15801561038257600080fd5b505af1158015610396573d6000803e3d6000fd5b505050506040513d60208110156103ac57600080fd5b810190808051906020019092919050505015156103c857600080fd5b7f884edad9ce6fa2440d8a54cc123490eb96d2768479d49ff9c7366125a94243643382604051808373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020018281526020019250505060405180910390a150565b60025481565b6000809054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff1614151561049457fe5b6000821115156104a057fe5b6104f282600360008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546107e690919063ffffffff16565b600360008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000208190555061054a826002546107e690919063ffffffff16565b600281905550600015156105e48460048054806020026020016040519081016040528092919081815260200182805480156105da57602002820191906000526020600020905b8160009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019060010190808311610590575b5050505050610804565b151514156106655760048390806001815401808255809150509060018203906000526020600020016000909192909190916101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550506001600081548092919060010191905055505b7f52ff2ed8f8a732b560956b48a0b78ef36b87044aeec29675bfe2468fa50e50f183600360008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054604051808373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020018281526020019250505060405180910390a1505050565b60015481565b6060600480548060200260200160405190810160405280929190818152602001828054801561079e57602002820191906000526020600020905b8160009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019060010190808311610754575b5050505050905090565b6000809054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b60008282111515156107db57fe5b818303905092915050565b60008082840190508381101515156107fa57fe5b8091505092915050565b600080600090505b8251811015610876578373ffffffffffffffffffffffffffffffffffffffff16838281518110151561083a57fe5b9060200190602002015173ffffffffffffffffffffffffffffffffffffffff161415610869576001915061087b565b808060010191505061080c565b600091505b50929150505600a165627a7a72305820531ccf0a409e40bb271574acc6c76a53ef7a32e2860326f95a24e74ccf651c8b0029"
+	test_abi      = "[\r\n    {\r\n        \"constant\": false,\r\n        \"inputs\": [],\r\n        \"name\": \"withdrawTokens\",\r\n        \"outputs\": [],\r\n        \"payable\": false,\r\n        \"stateMutability\": \"nonpayable\",\r\n        \"type\": \"function\"\r\n    },\r\n    {\r\n        \"constant\": true,\r\n        \"inputs\": [],\r\n        \"name\": \"activeSupply\",\r\n        \"outputs\": [\r\n            {\r\n                \"name\": \"\",\r\n                \"type\": \"uint256\"\r\n            }\r\n        ],\r\n        \"payable\": false,\r\n        \"stateMutability\": \"view\",\r\n        \"type\": \"function\"\r\n    },\r\n    {\r\n        \"constant\": false,\r\n        \"inputs\": [\r\n            {\r\n                \"name\": \"from\",\r\n                \"type\": \"address\"\r\n            },\r\n            {\r\n                \"name\": \"value\",\r\n                \"type\": \"uint256\"\r\n            },\r\n            {\r\n                \"name\": \"data\",\r\n                \"type\": \"bytes\"\r\n            }\r\n        ],\r\n        \"name\": \"tokenFallback\",\r\n        \"outputs\": [],\r\n        \"payable\": false,\r\n        \"stateMutability\": \"nonpayable\",\r\n        \"type\": \"function\"\r\n    },\r\n    {\r\n        \"constant\": true,\r\n        \"inputs\": [],\r\n        \"name\": \"daoCount\",\r\n        \"outputs\": [\r\n            {\r\n                \"name\": \"\",\r\n                \"type\": \"uint256\"\r\n            }\r\n        ],\r\n        \"payable\": false,\r\n        \"stateMutability\": \"view\",\r\n        \"type\": \"function\"\r\n    },\r\n    {\r\n        \"constant\": true,\r\n        \"inputs\": [],\r\n        \"name\": \"showActiveWallets\",\r\n        \"outputs\": [\r\n            {\r\n                \"name\": \"\",\r\n                \"type\": \"address[]\"\r\n            }\r\n        ],\r\n        \"payable\": false,\r\n        \"stateMutability\": \"view\",\r\n        \"type\": \"function\"\r\n    },\r\n    {\r\n        \"constant\": true,\r\n        \"inputs\": [],\r\n        \"name\": \"token\",\r\n        \"outputs\": [\r\n            {\r\n                \"name\": \"\",\r\n                \"type\": \"address\"\r\n            }\r\n        ],\r\n        \"payable\": false,\r\n        \"stateMutability\": \"view\",\r\n        \"type\": \"function\"\r\n    },\r\n    {\r\n        \"payable\": true,\r\n        \"stateMutability\": \"payable\",\r\n        \"type\": \"fallback\"\r\n    },\r\n    {\r\n        \"anonymous\": false,\r\n        \"inputs\": [\r\n            {\r\n                \"indexed\": false,\r\n                \"name\": \"voter\",\r\n                \"type\": \"address\"\r\n            },\r\n            {\r\n                \"indexed\": false,\r\n                \"name\": \"balance\",\r\n                \"type\": \"uint256\"\r\n            }\r\n        ],\r\n        \"name\": \"DaoVote\",\r\n        \"type\": \"event\"\r\n    },\r\n    {\r\n        \"anonymous\": false,\r\n        \"inputs\": [\r\n            {\r\n                \"indexed\": false,\r\n                \"name\": \"voter\",\r\n                \"type\": \"address\"\r\n            },\r\n            {\r\n                \"indexed\": false,\r\n                \"name\": \"balance\",\r\n                \"type\": \"uint256\"\r\n            }\r\n        ],\r\n        \"name\": \"Withdraw\",\r\n        \"type\": \"event\"\r\n    }\r\n]"
+)
+
+func TestDetectBytecodes(t *testing.T) {
+	idx := IndexedABI{
+		Abi: *MergeABIs(test_abi),
+	}
+
+	signatures := append(idx.GetSigHashes(), idx.GetTopics()...)
+	t.Log("Validated Bytecode", signatures)
+
+	valid := DetectBytecodes(test_bytecode, signatures)
+	t.Log("Validated Bytecode", valid)
+	if !valid {
+		t.Fatal("signatures of the abi not detected")
+	}
+
+	// the selector of transfer is pushed by the code, the one of balanceOf only appears in data
+	if !DetectBytecodes("0x63a9059cbb14", []string{"0xa9059cbb"}) || DetectBytecodes("0x6270a0823114", []string{"70a08231"}) {
+		t.Fatal("selectors detected outside of PUSH4 operands")
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	// PUSH1 0x80, PUSH4 transfer, EQ, PUSH32 Transfer, LOG0 and a truncated PUSH2
+	code := "0x608063a9059cbb147f" + TransferTopic[2:] + "a061ff"
+
+	instructions, err := Disassemble(code)
+	if err != nil || len(instructions) != 6 {
+		t.Fatalf("invalid instructions: %v %v", instructions, err)
+	}
+
+	if instructions[1].String() != "0x0002 PUSH4 0xa9059cbb" || instructions[4].Name != "LOG0" || len(instructions[5].Operand) != 1 {
+		t.Fatalf("invalid instructions: %v", instructions)
+	}
+
+	if selectors := ExtractSelectors(code); len(selectors) != 1 || selectors[0] != "0xa9059cbb" {
+		t.Fatalf("invalid selectors: %v", selectors)
+	}
+
+	if topics := ExtractTopics(code); len(topics) != 1 || topics[0] != TransferTopic {
+		t.Fatalf("invalid topics: %v", topics)
+	}
+
+	if _, err := Disassemble("0xzz"); err == nil {
+		t.Fatal("invalid bytecode disassembled")
+	}
+}
+
+func TestAnalyzeBytecode(t *testing.T) {
+	erc20 := []string{"totalSupply()", "balanceOf(address)", "transfer(address,uint256)", "transferFrom(address,address,uint256)", "approve(address,uint256)", "allowance(address,address)"}
+
+	code := "0x"
+	for _, signature := range erc20 {
+		code += "63" + hex.EncodeToString(crypto.Keccak256([]byte(signature))[:4]) + "14"
+	}
+	code += "7f" + TransferTopic[2:] + "7f" + hex.EncodeToString(crypto.Keccak256([]byte("Approval(address,address,uint256)")))
+
+	report := AnalyzeBytecode(code)
+	if report.Standard != "ERC20" || !report.Supports("ERC20") || report.Supports("ERC721") {
+		t.Fatalf("invalid report: %s", report.ToJSON())
+	}
+
+	if len(report.Selectors) != len(erc20) || len(report.Topics) != 2 || report.Interfaces[0].Name != "ERC20" {
+		t.Fatalf("invalid operands: %s", report.ToJSON())
+	}
+
+	if DetectTokenStandard(test_bytecode) != "UNKNOWN" || IsToken(test_bytecode) {
+		t.Fatal("token standard detected for a non token")
+	}
+
+	// solc pushes selectors starting with zero bytes with shorter PUSH, e.g. balanceOf of ERC1155
+	erc1155 := "0x"
+	for _, signature := range []string{"balanceOfBatch(address[],uint256[])", "setApprovalForAll(address,bool)", "isApprovedForAll(address,address)", "safeTransferFrom(address,address,uint256,uint256,bytes)", "safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)"} {
+		erc1155 += "63" + hex.EncodeToString(crypto.Keccak256([]byte(signature))[:4]) + "14"
+	}
+	erc1155 += "62fdd58e14" + "6020" + "61ffff"
+	for _, signature := range []string{"TransferSingle(address,address,address,uint256,uint256)", "TransferBatch(address,address,address,uint256[],uint256[])", "ApprovalForAll(address,address,bool)"} {
+		erc1155 += "7f" + hex.EncodeToString(crypto.Keccak256([]byte(signature)))
+	}
+
+	report = AnalyzeBytecode(erc1155)
+	if report.Standard != "ERC1155" || !report.Supports("ERC1155") || len(report.Selectors) != 6 {
+		t.Fatalf("invalid ERC1155 report: %s", report.ToJSON())
+	}
+	if !DetectBytecodes(erc1155, []string{"0x00fdd58e"}) || DetectBytecodes(erc1155, []string{"0x00fdd58f"}) {
+		t.Fatal("selector pushed with PUSH3 not detected")
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		amount   int64
+		decimals uint8
+		format   AmountFormat
+		expected string
+	}{
+		{1500000, 6, AmountFormat{Precision: -1}, "1.5"},
+		{-1500000, 6, AmountFormat{Precision: -1}, "-1.5"},
+		{125, 2, AmountFormat{Precision: 1, Rounding: RoundHalfEven}, "1.2"},
+		{135, 2, AmountFormat{Precision: 1, Rounding: RoundHalfEven}, "1.4"},
+		{125, 2, AmountFormat{Precision: 1, Rounding: RoundHalfUp}, "1.3"},
+		{129, 2, AmountFormat{Precision: 1, Rounding: RoundTruncate}, "1.2"},
+		{-4, 2, AmountFormat{Precision: 1, Rounding: RoundHalfEven}, "0.0"},
+		{5, 3, AmountFormat{Precision: 5}, "0.00500"},
+		{42, 0, AmountFormat{Precision: -1}, "42"},
+	}
+
+	for _, c := range cases {
+		if result := c.format.Format(big.NewInt(c.amount), c.decimals); result != c.expected {
+			t.Fatalf("format %v with %v decimals: expected %v, got %v", c.amount, c.decimals, c.expected, result)
+		}
+	}
+}
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	if err := CheckSchemaCompatibility(SchemaVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckSchemaCompatibility(SchemaVersion + 1); err == nil {
+		t.Fatal("newer schema accepted")
+	}
+
+	if err := CheckSchemaCompatibility(MinSchemaVersion - 1); err == nil {
+		t.Fatal("outdated schema accepted")
+	}
+
+	if info := GetBuildInfo(); info.Version == "" || info.SchemaVersion != SchemaVersion {
+		t.Fatalf("invalid build info: %+v", info)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	store := Storage{}
+	store.AddABI("erc20", *ParseABI(abi_erc20))
+	store.ParseAndAddABIs(abi_erc721)
+
+	events := store.AllEvents()
+	if len(events) == 0 || events[0].Signature > events[len(events)-1].Signature {
+		t.Fatalf("invalid events: %v", events)
+	}
+
+	for _, event := range events {
+		if event.Name == "Approval" && event.Source != "erc20" {
+			t.Fatalf("duplicate event not attributed to the first abi: %+v", event)
+		}
+	}
+
+	var transfer *MethodEntry
+	methods := store.AllMethods()
+	for i := range methods {
+		if methods[i].Signature == "transfer(address,uint256)" {
+			transfer = &methods[i]
+		}
+	}
+
+	if transfer == nil || transfer.Selector != "0xa9059cbb" || transfer.Source != "erc20" {
+		t.Fatalf("invalid transfer entry: %+v", transfer)
+	}
+
+	markdown := store.Catalog().Markdown()
+	if !strings.Contains(markdown, "| `transfer(address,uint256)` | `0xa9059cbb` | address to, uint256 value | bool 0 | nonpayable | erc20 |") {
+		t.Fatalf("invalid markdown: %s", markdown)
+	}
+
+	match, err := store.MethodBySelector("0xa9059cbb")
+	if err != nil || match.Source != "erc20" || match.Method.Sig != "transfer(address,uint256)" {
+		t.Fatalf("invalid method lookup: %+v %v", match, err)
+	}
+
+	if _, err := match.Abi.Pack(match.Method.Name, common.Address{}, big.NewInt(1)); err != nil {
+		t.Fatalf("matched abi can not encode: %v", err)
+	}
+
+	event, err := store.EventByTopic(TransferTopic)
+	if err != nil || event.Event.Name != "Transfer" || event.Index != 0 {
+		t.Fatalf("invalid event lookup: %+v %v", event, err)
+	}
+
+	if _, err := store.MethodBySelector("0xdeadbeef"); err == nil {
+		t.Fatal("unknown selector found")
+	}
+}
+
+func TestTrimABIs(t *testing.T) {
+	erc20 := ParseABI(abi_erc20)
+	store := Storage{}
+	store.AddABI("erc20", *erc20)
+	store.AddABI("erc721", *ParseABI(abi_erc721))
+	store.AddABI("diamond", *ParseABI(abi_diamond))
+
+	if removed := store.RemoveABIBySelector("0xa9059cbb"); removed != 1 {
+		t.Fatalf("invalid removed methods: %d", removed)
+	}
+
+	if _, ok := erc20.Methods["transfer"]; !ok {
+		t.Fatal("shared abi modified")
+	}
+
+	// Transfer is part of both token abis
+	if removed := store.RemoveABIByTopic(TransferTopic); removed != 2 {
+		t.Fatalf("invalid removed events: %d", removed)
+	}
+
+	if _, err := store.MethodBySelector("0xa9059cbb"); err == nil {
+		t.Fatal("removed selector still known")
+	}
+
+	removed := store.Retain(func(source string, contractAbi abi.ABI) bool {
+		return source != "erc721"
+	})
+	if removed != 1 || len(store.AbiList) != 2 || store.abiName(1) != "diamond" {
+		t.Fatalf("invalid retained abis: %d %v", removed, store.names)
+	}
+}
+
+func TestExtractMetadata(t *testing.T) {
+	metadata, err := ExtractMetadata(test_bytecode)
+	if err != nil || metadata.Bzzr0 != "0x531ccf0a409e40bb271574acc6c76a53ef7a32e2860326f95a24e74ccf651c8b" || metadata.Solc != "" {
+		t.Fatalf("invalid bzzr0 metadata: %v %v", metadata, err)
+	}
+
+	// {"ipfs": <multihash>, "solc": 0.8.19}
+	code := "0x6080a2646970667358221220" + strings.Repeat("ab", 32) + "64736f6c63430008130033"
+	metadata, err = ExtractMetadata(code)
+	if err != nil || metadata.Solc != "0.8.19" || !strings.HasPrefix(metadata.IPFS, "Qm") || len(metadata.IPFS) != 46 {
+		t.Fatalf("invalid ipfs metadata: %v %v", metadata, err)
+	}
+
+	if _, err := ExtractMetadata("0x6080600052"); err == nil {
+		t.Fatal("metadata extracted from bytecode without metadata")
+	}
+}
+
+func TestSetKeccak(t *testing.T) {
+	defer SetKeccak(nil)
+
+	if ToSHA3("Transfer(address,address,uint256)") != TransferTopic {
+		t.Fatal("invalid default keccak")
+	}
+
+	calls := 0
+	SetKeccak(func(data []byte) []byte {
+		calls++
+		return crypto.Keccak256(data)
+	})
+
+	for i := 0; i < 3; i++ {
+		if ToSHA3("transfer(address,uint256)")[:10] != "0xa9059cbb" {
+			t.Fatal("invalid custom keccak")
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("hash not memoized, %d calls", calls)
+	}
+
+	ResetSHA3Cache()
+	ToSHA3("transfer(address,uint256)")
+	if calls != 2 {
+		t.Fatalf("cache not reset, %d calls", calls)
+	}
+}
@@ -0,0 +1,376 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FieldError is a single invalid field of a configuration struct.
+type FieldError struct {
+	Field   string // Path of the field, e.g. "ConnectOptions.BasicAuth.Username".
+	Message string // Description of the problem.
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors aggregates all invalid fields found by a Validate method.
+type ValidationErrors []FieldError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(messages, "; "))
+}
+
+// add records an invalid field below the given path.
+func (errs *ValidationErrors) add(path string, field string, format string, args ...interface{}) {
+	*errs = append(*errs, FieldError{Field: path + "." + field, Message: fmt.Sprintf(format, args...)})
+}
+
+// nest records the invalid fields of a nested configuration below the given path, e.g. the
+// ExplorerConfig.BaseURL of ChainConfig.Explorer as ChainConfig.Explorer.BaseURL.
+func (errs *ValidationErrors) nest(path string, err error) {
+	var nested ValidationErrors
+	if !errors.As(err, &nested) {
+		if err != nil {
+			errs.add(path, "", "%v", err)
+		}
+		return
+	}
+
+	for _, fieldErr := range nested {
+		field := fieldErr.Field[strings.Index(fieldErr.Field, ".")+1:]
+		errs.add(path, field, "%s", fieldErr.Message)
+	}
+}
+
+// durationField is a duration checked by nonNegative.
+type durationField struct {
+	field string
+	value time.Duration
+}
+
+// nonNegative records every negative duration below the given path.
+func (errs *ValidationErrors) nonNegative(path string, durations ...durationField) {
+	for _, duration := range durations {
+		if duration.value < 0 {
+			errs.add(path, duration.field, "must not be negative")
+		}
+	}
+}
+
+// httpURL records an invalid field if the value is not an absolute http or https url.
+func (errs *ValidationErrors) httpURL(path string, field string, value string) {
+	parsed, err := url.Parse(value)
+	switch {
+	case err != nil:
+		errs.add(path, field, "%v", err)
+	case parsed.Scheme != "http" && parsed.Scheme != "https":
+		errs.add(path, field, "unsupported scheme %q", parsed.Scheme)
+	case parsed.Host == "":
+		errs.add(path, field, "missing host")
+	}
+}
+
+// err returns nil if no field was invalid, so callers can return it directly.
+func (errs ValidationErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Validate checks the connection options and returns all invalid fields as ValidationErrors.
+func (options *ConnectOptions) Validate() error {
+	const path = "ConnectOptions"
+	var errs ValidationErrors
+
+	for key := range options.Headers {
+		if strings.TrimSpace(key) == "" {
+			errs.add(path, "Headers", "empty header name")
+		}
+	}
+
+	if options.BasicAuth != nil && options.BasicAuth.Username == "" {
+		errs.add(path, "BasicAuth.Username", "must not be empty")
+	}
+
+	authSchemes := 0
+	for _, set := range []bool{options.BasicAuth != nil, options.BearerToken != "", options.JWTSecret != ""} {
+		if set {
+			authSchemes++
+		}
+	}
+	if authSchemes > 1 {
+		errs.add(path, "BasicAuth", "only one of BasicAuth, BearerToken and JWTSecret can be set")
+	}
+
+	if options.JWTSecret != "" {
+		if secret := common.FromHex(options.JWTSecret); len(secret) != 32 {
+			errs.add(path, "JWTSecret", "expected 32 bytes, got %v", len(secret))
+		}
+	}
+
+	if options.ProxyURL != "" {
+		proxy, err := url.Parse(options.ProxyURL)
+		switch {
+		case err != nil:
+			errs.add(path, "ProxyURL", "%v", err)
+		case proxy.Host == "":
+			errs.add(path, "ProxyURL", "missing host")
+		case proxy.Scheme != "http" && proxy.Scheme != "https" && proxy.Scheme != "socks5" && proxy.Scheme != "socks5h":
+			errs.add(path, "ProxyURL", "unsupported scheme %q", proxy.Scheme)
+		}
+	}
+
+	errs.nonNegative(path,
+		durationField{"DialTimeout", options.DialTimeout},
+		durationField{"TLSHandshakeTimeout", options.TLSHandshakeTimeout},
+		durationField{"KeepAlive", options.KeepAlive},
+		durationField{"IdleConnTimeout", options.IdleConnTimeout},
+		durationField{"RequestTimeout", options.RequestTimeout},
+	)
+
+	if options.HTTPClient != nil && options.hasDialer() {
+		errs.add(path, "HTTPClient", "cannot be combined with ProxyURL, TLSConfig and the dialer timeouts")
+	}
+
+	if options.RateLimit < 0 {
+		errs.add(path, "RateLimit", "must not be negative")
+	}
+	if options.RateBurst < 0 {
+		errs.add(path, "RateBurst", "must not be negative")
+	}
+
+	for i, interceptor := range options.Interceptors {
+		if interceptor == nil {
+			errs.add(path, fmt.Sprintf("Interceptors[%d]", i), "must not be nil")
+		}
+	}
+
+	return errs.err()
+}
+
+// Validate checks the amount format and returns all invalid fields as ValidationErrors.
+func (format AmountFormat) Validate() error {
+	const path = "AmountFormat"
+	var errs ValidationErrors
+
+	if format.Rounding < RoundHalfEven || format.Rounding > RoundTruncate {
+		errs.add(path, "Rounding", "unknown rounding mode %d", format.Rounding)
+	}
+
+	return errs.err()
+}
+
+// Validate checks the options of a MultiClient, including the nested connection options, and
+// returns all invalid fields as ValidationErrors.
+func (options *MultiClientOptions) Validate() error {
+	const path = "MultiClientOptions"
+	var errs ValidationErrors
+
+	errs.nest(path+".Connect", options.Connect.Validate())
+
+	if options.MaxFailures < 0 {
+		errs.add(path, "MaxFailures", "must not be negative")
+	}
+
+	errs.nonNegative(path,
+		durationField{"HealthInterval", options.HealthInterval},
+		durationField{"HealthTimeout", options.HealthTimeout},
+	)
+
+	return errs.err()
+}
+
+// Validate checks the retry policy and returns all invalid fields as ValidationErrors. A nil
+// policy, which disables retries, is valid.
+func (policy *RetryPolicy) Validate() error {
+	if policy == nil {
+		return nil
+	}
+
+	const path = "RetryPolicy"
+	var errs ValidationErrors
+
+	if policy.MaxAttempts < 0 {
+		errs.add(path, "MaxAttempts", "must not be negative")
+	}
+
+	errs.nonNegative(path,
+		durationField{"InitialBackoff", policy.InitialBackoff},
+		durationField{"MaxBackoff", policy.MaxBackoff},
+	)
+	if policy.InitialBackoff > 0 && policy.MaxBackoff > 0 && policy.MaxBackoff < policy.InitialBackoff {
+		errs.add(path, "MaxBackoff", "must not be below InitialBackoff %v", policy.InitialBackoff)
+	}
+
+	if policy.Multiplier < 0 || (policy.Multiplier > 0 && policy.Multiplier < 1) {
+		errs.add(path, "Multiplier", "must be 0 for the default or at least 1, got %v", policy.Multiplier)
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		errs.add(path, "Jitter", "must be between 0 and 1, got %v", policy.Jitter)
+	}
+
+	return errs.err()
+}
+
+// Validate checks the scan options and returns all invalid fields as ValidationErrors. Nil
+// options, which use the defaults, are valid.
+func (options *ScanOptions) Validate() error {
+	if options == nil {
+		return nil
+	}
+
+	const path = "ScanOptions"
+	var errs ValidationErrors
+
+	chunkSize := options.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = ScanChunkSize
+	}
+	if chunkSize > 0 && options.MinChunkSize > chunkSize {
+		errs.add(path, "MinChunkSize", "must not exceed the chunk size %v", chunkSize)
+	}
+
+	if options.Concurrency < 0 {
+		errs.add(path, "Concurrency", "must not be negative")
+	}
+
+	return errs.err()
+}
+
+// Validate checks the subscription options, including the Backfill scan options, and returns
+// all invalid fields as ValidationErrors. Nil options, which use the defaults, are valid.
+func (options *SubscribeOptions) Validate() error {
+	if options == nil {
+		return nil
+	}
+
+	const path = "SubscribeOptions"
+	var errs ValidationErrors
+
+	if options.Buffer < 0 {
+		errs.add(path, "Buffer", "must not be negative")
+	}
+
+	errs.nonNegative(path,
+		durationField{"ReconnectDelay", options.ReconnectDelay},
+		durationField{"MaxReconnectDelay", options.MaxReconnectDelay},
+	)
+	if options.ReconnectDelay > 0 && options.MaxReconnectDelay > 0 && options.MaxReconnectDelay < options.ReconnectDelay {
+		errs.add(path, "MaxReconnectDelay", "must not be below ReconnectDelay %v", options.ReconnectDelay)
+	}
+
+	errs.nest(path+".Backfill", options.Backfill.Validate())
+
+	return errs.err()
+}
+
+// Validate checks the explorer config and returns all invalid fields as ValidationErrors.
+func (config *ExplorerConfig) Validate() error {
+	const path = "ExplorerConfig"
+	var errs ValidationErrors
+
+	if config.BaseURL == "" {
+		errs.add(path, "BaseURL", "must not be empty")
+	} else {
+		errs.httpURL(path, "BaseURL", config.BaseURL)
+	}
+
+	errs.nonNegative(path,
+		durationField{"Timeout", config.Timeout},
+		durationField{"RateLimit", config.RateLimit},
+		durationField{"NotVerifiedTTL", config.NotVerifiedTTL},
+	)
+
+	return errs.err()
+}
+
+// Validate checks the Sourcify config and returns all invalid fields as ValidationErrors. A nil
+// config, which uses the defaults, is valid.
+func (config *SourcifyConfig) Validate() error {
+	if config == nil {
+		return nil
+	}
+
+	const path = "SourcifyConfig"
+	var errs ValidationErrors
+
+	if config.BaseURL != "" {
+		errs.httpURL(path, "BaseURL", config.BaseURL)
+	}
+
+	errs.nonNegative(path, durationField{"Timeout", config.Timeout})
+
+	return errs.err()
+}
+
+// Validate checks the chain config, including its explorer, and returns all invalid fields as
+// ValidationErrors.
+func (config *ChainConfig) Validate() error {
+	const path = "ChainConfig"
+	var errs ValidationErrors
+
+	if config.ID == 0 {
+		errs.add(path, "ID", "must not be 0")
+	}
+	if strings.TrimSpace(config.Name) == "" {
+		errs.add(path, "Name", "must not be empty")
+	}
+
+	if config.Explorer != nil {
+		errs.nest(path+".Explorer", config.Explorer.Validate())
+	}
+
+	for address, name := range config.SystemContracts {
+		if strings.TrimSpace(name) == "" {
+			errs.add(path, fmt.Sprintf("SystemContracts[%s]", address.Hex()), "empty name")
+		}
+	}
+
+	return errs.err()
+}
+
+// Validate checks the watchdog config and returns all invalid fields as ValidationErrors.
+func (config WatchdogConfig) Validate() error {
+	const path = "WatchdogConfig"
+	var errs ValidationErrors
+
+	errs.nonNegative(path, durationField{"Interval", config.Interval})
+
+	if config.MaxGoroutines < 0 {
+		errs.add(path, "MaxGoroutines", "must not be negative")
+	}
+	if config.MaxCacheEntries < 0 {
+		errs.add(path, "MaxCacheEntries", "must not be negative")
+	}
+
+	return errs.err()
+}
+
+// Validate checks the profiler config and returns all invalid fields as ValidationErrors.
+func (config ProfilerConfig) Validate() error {
+	const path = "ProfilerConfig"
+	var errs ValidationErrors
+
+	if config.WarmUp < 0 {
+		errs.add(path, "WarmUp", "must not be negative")
+	}
+	if config.RangeFactor < 0 || (config.RangeFactor > 0 && config.RangeFactor < 1) {
+		errs.add(path, "RangeFactor", "must be 0 for the default or at least 1, got %v", config.RangeFactor)
+	}
+
+	return errs.err()
+}
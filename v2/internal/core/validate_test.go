@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fields returns the paths of the invalid fields of a Validate error.
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	paths := make([]string, 0, len(errs))
+	for _, fieldErr := range errs {
+		paths = append(paths, fieldErr.Field)
+	}
+
+	return paths
+}
+
+func expectFields(t *testing.T, err error, expected ...string) {
+	t.Helper()
+
+	paths := fields(t, err)
+	if len(paths) != len(expected) {
+		t.Fatalf("expected fields %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Fatalf("expected fields %v, got %v", expected, paths)
+		}
+	}
+}
+
+func TestValidateConfigs(t *testing.T) {
+	expectFields(t, (&MultiClientOptions{
+		Connect:       ConnectOptions{RateLimit: -1},
+		MaxFailures:   -1,
+		HealthTimeout: -time.Second,
+	}).Validate(), "MultiClientOptions.Connect.RateLimit", "MultiClientOptions.MaxFailures", "MultiClientOptions.HealthTimeout")
+
+	expectFields(t, (&RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Millisecond,
+		Multiplier:     0.5,
+		Jitter:         2,
+	}).Validate(), "RetryPolicy.MaxBackoff", "RetryPolicy.Multiplier", "RetryPolicy.Jitter")
+
+	expectFields(t, (&ScanOptions{ChunkSize: 10, MinChunkSize: 20, Concurrency: -1}).Validate(), "ScanOptions.MinChunkSize", "ScanOptions.Concurrency")
+
+	expectFields(t, (&SubscribeOptions{
+		Buffer:            -1,
+		ReconnectDelay:    time.Minute,
+		MaxReconnectDelay: time.Second,
+		Backfill:          &ScanOptions{MinChunkSize: ScanChunkSize + 1},
+	}).Validate(), "SubscribeOptions.Buffer", "SubscribeOptions.MaxReconnectDelay", "SubscribeOptions.Backfill.MinChunkSize")
+
+	expectFields(t, (&ExplorerConfig{RateLimit: -time.Second}).Validate(), "ExplorerConfig.BaseURL", "ExplorerConfig.RateLimit")
+	expectFields(t, (&ExplorerConfig{BaseURL: "api.etherscan.io/api"}).Validate(), "ExplorerConfig.BaseURL")
+	expectFields(t, (&SourcifyConfig{BaseURL: "ftp://sourcify.dev", Timeout: -time.Second}).Validate(), "SourcifyConfig.BaseURL", "SourcifyConfig.Timeout")
+
+	expectFields(t, (&ChainConfig{
+		Explorer:        &ExplorerConfig{BaseURL: "https://"},
+		SystemContracts: map[common.Address]string{{}: ""},
+	}).Validate(), "ChainConfig.ID", "ChainConfig.Name", "ChainConfig.Explorer.BaseURL", "ChainConfig.SystemContracts[0x0000000000000000000000000000000000000000]")
+
+	expectFields(t, WatchdogConfig{Interval: -time.Second, MaxCacheEntries: -1}.Validate(), "WatchdogConfig.Interval", "WatchdogConfig.MaxCacheEntries")
+	expectFields(t, ProfilerConfig{WarmUp: -1, RangeFactor: 0.5}.Validate(), "ProfilerConfig.WarmUp", "ProfilerConfig.RangeFactor")
+
+	// nil and zero configs use the defaults
+	valid := []interface{ Validate() error }{
+		&MultiClientOptions{}, (*RetryPolicy)(nil), &RetryPolicy{}, (*ScanOptions)(nil), &ScanOptions{},
+		(*SubscribeOptions)(nil), &SubscribeOptions{}, (*SourcifyConfig)(nil), WatchdogConfig{}, ProfilerConfig{},
+	}
+	for _, config := range valid {
+		if err := config.Validate(); err != nil {
+			t.Fatalf("unexpected error of %T: %v", config, err)
+		}
+	}
+
+	for _, id := range Chains.IDs() {
+		if err := Chains.Get(id).Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestValidateConstructors(t *testing.T) {
+	if _, err := DialMulti(context.Background(), []string{"http://127.0.0.1:8545"}, MultiClientOptions{MaxFailures: -1}); err == nil {
+		t.Fatal("expected invalid multi client options to fail")
+	}
+	if _, err := DialMulti(context.Background(), []string{""}, MultiClientOptions{}); err == nil {
+		t.Fatal("expected an empty endpoint url to fail")
+	}
+
+	if err := Chains.Register(&ChainConfig{ID: 12345}); err == nil || Chains.Get(12345) != nil {
+		t.Fatal("expected a chain without name not to be registered")
+	}
+
+	if err := SetRetryPolicy(&RetryPolicy{Jitter: -1}); err == nil || GetRetryPolicy() != nil {
+		t.Fatal("expected an invalid retry policy to be rejected")
+	}
+
+	if _, err := NewWatchdog(WatchdogConfig{MaxGoroutines: -1}); err == nil {
+		t.Fatal("expected an invalid watchdog config to fail")
+	}
+	if _, err := NewCallProfiler(ProfilerConfig{RangeFactor: -1}); err == nil {
+		t.Fatal("expected an invalid profiler config to fail")
+	}
+
+	storage := &Storage{}
+	if _, err := storage.FetchABI(target_contract, &ExplorerConfig{}); err == nil {
+		t.Fatal("expected an explorer config without base url to fail")
+	}
+	if _, err := storage.FetchSourcify(target_contract, common.Big1, &SourcifyConfig{Timeout: -1}); err == nil {
+		t.Fatal("expected an invalid sourcify config to fail")
+	}
+
+	storage.SetBackend(newFixtureBackend(t, 1))
+	if err := storage.ScanLogs(context.Background(), ethereum.FilterQuery{}, &ScanOptions{Concurrency: -1}, func(*DecodedLog) error { return nil }); err == nil {
+		t.Fatal("expected invalid scan options to fail")
+	}
+	if _, err := storage.SubscribeLogs(context.Background(), ethereum.FilterQuery{}, &SubscribeOptions{Buffer: -1}); err == nil {
+		t.Fatal("expected invalid subscribe options to fail")
+	}
+	if _, err := storage.SubscribeBlocks(context.Background(), &SubscribeOptions{ReconnectDelay: -1}); err == nil {
+		t.Fatal("expected invalid subscribe options to fail")
+	}
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Kinds of the vault events normalized by DecodeVaultEvent.
+const (
+	VaultDeposit  = "deposit"
+	VaultWithdraw = "withdraw"
+)
+
+var (
+	vaultAbi = ParseABI(abi_erc4626)
+
+	vaultDepositTopic  = vaultAbi.Events["Deposit"].ID
+	vaultWithdrawTopic = vaultAbi.Events["Withdraw"].ID
+
+	// ERC4626InterfaceID is the ERC-165 interface id of ERC-4626, the xor of the selectors of its
+	// methods. Few vaults report it, IsVault also detects vaults by their selectors.
+	ERC4626InterfaceID = vaultInterfaceID()
+)
+
+// VaultEvent is a Deposit or Withdraw event of an ERC-4626 vault.
+type VaultEvent struct {
+	Kind            string   `json:"kind"`            // VaultDeposit or VaultWithdraw.
+	Vault           string   `json:"vault"`           // Vault contract that emitted the event.
+	Sender          string   `json:"sender"`          // Account that called the vault.
+	Owner           string   `json:"owner"`           // Owner of the shares minted or burned.
+	Receiver        string   `json:"receiver"`        // Account receiving the shares of a deposit or the assets of a withdrawal.
+	Assets          *big.Int `json:"assets"`          // Amount of the underlying asset.
+	Shares          *big.Int `json:"shares"`          // Amount of vault shares.
+	TransactionHash string   `json:"transactionHash"` // Transaction hash of the log.
+	BlockNumber     uint64   `json:"blockNumber"`     // Block number of the log.
+	LogIndex        uint     `json:"logIndex"`        // Index of the log.
+}
+
+// ToJSONBytes returns the JSON-encoded byte array of the VaultEvent object.
+func (data *VaultEvent) ToJSONBytes() []byte {
+	b, _ := json.Marshal(data)
+	return b
+}
+
+// ToJSON returns the JSON-encoded string of the VaultEvent object.
+func (data *VaultEvent) ToJSON() string {
+	return string(data.ToJSONBytes())
+}
+
+// IsVaultLog returns true if the log is a Deposit or Withdraw event of an ERC-4626 vault.
+func IsVaultLog(vLog *types.Log) bool {
+	if len(vLog.Topics) == 0 {
+		return false
+	}
+
+	return (vLog.Topics[0] == vaultDepositTopic && len(vLog.Topics) == 3) ||
+		(vLog.Topics[0] == vaultWithdrawTopic && len(vLog.Topics) == 4)
+}
+
+// DecodeVaultEvent normalizes a Deposit or Withdraw log of an ERC-4626 vault. It returns an
+// error for logs of other events.
+func DecodeVaultEvent(vLog *types.Log) (*VaultEvent, error) {
+	if !IsVaultLog(vLog) {
+		return nil, fmt.Errorf("log %d is no vault event", vLog.Index)
+	}
+
+	if len(vLog.Data) < 64 {
+		return nil, fmt.Errorf("invalid vault data of log %d", vLog.Index)
+	}
+
+	result := VaultEvent{
+		Vault:           vLog.Address.Hex(),
+		Sender:          topicAddress(vLog.Topics[1]).Hex(),
+		Assets:          new(big.Int).SetBytes(vLog.Data[0:32]),
+		Shares:          new(big.Int).SetBytes(vLog.Data[32:64]),
+		TransactionHash: vLog.TxHash.Hex(),
+		BlockNumber:     vLog.BlockNumber,
+		LogIndex:        vLog.Index,
+	}
+
+	if vLog.Topics[0] == vaultDepositTopic {
+		result.Kind = VaultDeposit
+		result.Owner = topicAddress(vLog.Topics[2]).Hex()
+		result.Receiver = result.Owner
+	} else {
+		result.Kind = VaultWithdraw
+		result.Receiver = topicAddress(vLog.Topics[2]).Hex()
+		result.Owner = topicAddress(vLog.Topics[3]).Hex()
+	}
+
+	return &result, nil
+}
+
+// ExtractVaultEvents normalizes all vault events, skipping other logs.
+func ExtractVaultEvents(vLogs []*types.Log) []VaultEvent {
+	result := make([]VaultEvent, 0)
+
+	for _, vLog := range vLogs {
+		if vLog.Removed || !IsVaultLog(vLog) {
+			continue
+		}
+
+		if event, err := DecodeVaultEvent(vLog); err == nil {
+			result = append(result, *event)
+		}
+	}
+
+	return result
+}
+
+// IsVault returns true if the token is an ERC-4626 vault. Vaults are detected by the selectors of
+// their bytecode, by ERC-165 or, for proxies, by answering asset() and totalAssets().
+func (tkn *ITknInfo) IsVault() bool {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := requireClient(TknStore.client)
+	if err != nil {
+		return false
+	}
+
+	if code := getBytecode(client, tkn.Address); code != nil {
+		if report := AnalyzeBytecode(*code); report.Supports("ERC4626") {
+			return true
+		}
+	}
+
+	if supportsInterface(ctx, client, tkn.Address, ERC4626InterfaceID) {
+		return true
+	}
+
+	if getVaultAsset(ctx, client, tkn.Address) == nil {
+		return false
+	}
+
+	_, err = callVault(ctx, client, tkn.Address, "totalAssets")
+	return err == nil
+}
+
+// Asset returns the underlying asset of the vault.
+func (tkn *ITknInfo) Asset() (*common.Address, error) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := requireClient(TknStore.client)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := getVaultAsset(ctx, client, tkn.Address)
+	if asset == nil {
+		return nil, fmt.Errorf("can not read asset of vault %s", tkn.Address.Hex())
+	}
+
+	return asset, nil
+}
+
+// TotalAssets returns the amount of the underlying asset managed by the vault.
+func (tkn *ITknInfo) TotalAssets() (*big.Int, error) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := requireClient(TknStore.client)
+	if err != nil {
+		return nil, err
+	}
+
+	return callVault(ctx, client, tkn.Address, "totalAssets")
+}
+
+// ConvertToAssets returns the amount of the underlying asset the shares of the vault are worth,
+// see Asset for the token the amount is in.
+func (tkn *ITknInfo) ConvertToAssets(shares *big.Int) (*big.Int, error) {
+	ctx, cancel := withTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := requireClient(TknStore.client)
+	if err != nil {
+		return nil, err
+	}
+
+	return callVault(ctx, client, tkn.Address, "convertToAssets", shares)
+}
+
+// getVaultAsset returns the underlying asset of the vault, which never changes and is cached like
+// the other contract lookups.
+func getVaultAsset(ctx context.Context, client Backend, vault common.Address) *common.Address {
+	return cachedLookup(ctx, client, vault, "asset", func() *common.Address {
+		msg := ethereum.CallMsg{To: &vault, Data: vaultAbi.Methods["asset"].ID}
+		output, err := client.CallContract(ctx, msg, nil)
+		if err != nil || len(output) < 32 {
+			return nil
+		}
+
+		asset := common.BytesToAddress(output[:32])
+		return &asset
+	})
+}
+
+// callVault calls a view method of the vault returning a single uint256.
+func callVault(ctx context.Context, client Backend, vault common.Address, method string, args ...interface{}) (*big.Int, error) {
+	data, err := vaultAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &vault, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error calling %s of %s: %w", method, vault.Hex(), err)
+	}
+
+	values, err := vaultAbi.Unpack(method, output)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("error unpack %s of %s: %v", method, vault.Hex(), err)
+	}
+
+	return values[0].(*big.Int), nil
+}
+
+// supportsInterface asks the contract whether it supports the interface with ERC-165. Contracts
+// without supportsInterface do not.
+func supportsInterface(ctx context.Context, client Backend, contract common.Address, id [4]byte) bool {
+	data := append(common.FromHex("0x01ffc9a7"), common.RightPadBytes(id[:], 32)...)
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil || len(output) < 32 {
+		return false
+	}
+
+	return new(big.Int).SetBytes(output[:32]).Cmp(common.Big1) == 0
+}
+
+// vaultInterfaceID xors the selectors of the methods of ERC-4626, which do not include the
+// methods of ERC-20 it extends.
+func vaultInterfaceID() [4]byte {
+	var result [4]byte
+	for _, method := range vaultAbi.Methods {
+		for i := range result {
+			result[i] ^= method.ID[i]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/mockrpc"
+)
+
+func TestDecodeVaultEvent(t *testing.T) {
+	vault := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000dd")
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+	receiver := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+
+	data := append(math.U256Bytes(big.NewInt(1000)), math.U256Bytes(big.NewInt(900))...)
+	deposit := &types.Log{Address: vault, Topics: []common.Hash{vaultDepositTopic, sender.Hash(), owner.Hash()}, Data: data}
+	withdraw := &types.Log{Address: vault, Topics: []common.Hash{vaultWithdrawTopic, sender.Hash(), receiver.Hash(), owner.Hash()}, Data: data, Index: 1}
+	other := &types.Log{Address: vault, Topics: []common.Hash{common.HexToHash(TransferTopic), sender.Hash(), owner.Hash()}, Data: data, Index: 2}
+
+	event, err := DecodeVaultEvent(deposit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Kind != VaultDeposit || event.Owner != owner.Hex() || event.Receiver != owner.Hex() || event.Assets.Int64() != 1000 || event.Shares.Int64() != 900 {
+		t.Fatalf("unexpected deposit: %s", event.ToJSON())
+	}
+
+	event, err = DecodeVaultEvent(withdraw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Kind != VaultWithdraw || event.Sender != sender.Hex() || event.Owner != owner.Hex() || event.Receiver != receiver.Hex() {
+		t.Fatalf("unexpected withdraw: %s", event.ToJSON())
+	}
+
+	if _, err := DecodeVaultEvent(other); err == nil {
+		t.Fatal("expected an error for other logs")
+	}
+
+	if events := ExtractVaultEvents([]*types.Log{deposit, other, withdraw}); len(events) != 2 {
+		t.Fatalf("expected 2 vault events, got %d", len(events))
+	}
+}
+
+func TestVaultReads(t *testing.T) {
+	vault := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	asset := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+
+	server := mockrpc.NewServer()
+	defer server.Close()
+
+	server.HandleCall(func(to common.Address, data []byte) ([]byte, error) {
+		switch method, _ := vaultAbi.MethodById(data[:4]); {
+		case method == nil:
+			return nil, nil
+		case method.RawName == "asset":
+			return asset.Hash().Bytes(), nil
+		case method.RawName == "totalAssets":
+			return math.U256Bytes(big.NewInt(5000)), nil
+		case method.RawName == "convertToAssets":
+			// 2 assets per share
+			shares := new(big.Int).SetBytes(data[4:])
+			return math.U256Bytes(shares.Mul(shares, big.NewInt(2))), nil
+		}
+		return nil, nil
+	})
+	server.Respond("eth_getCode", "0x")
+	server.Respond("eth_chainId", "0x1")
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the read helpers of ITknInfo use the global backend
+	previous := Ctx.eth
+	SetClient(client)
+	defer SetBackend(previous)
+
+	tkn := &ITknInfo{Address: vault}
+	if !tkn.IsVault() {
+		t.Fatal("expected a vault")
+	}
+
+	if result, err := tkn.Asset(); err != nil || *result != asset {
+		t.Fatalf("unexpected asset: %v %v", result, err)
+	}
+	if total, err := tkn.TotalAssets(); err != nil || total.Int64() != 5000 {
+		t.Fatalf("unexpected total assets: %v %v", total, err)
+	}
+	if assets, err := tkn.ConvertToAssets(big.NewInt(21)); err != nil || assets.Int64() != 42 {
+		t.Fatalf("unexpected assets: %v %v", assets, err)
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+const modulePath = "github.com/w2496/go-abi-decoder/v2"
+
+// SchemaVersion is the version of the JSON output schema of decoded results (DecodedMethod,
+// DecodedLog, ...). It is increased whenever fields are renamed, removed or change their type.
+const SchemaVersion = 1
+
+// MinSchemaVersion is the oldest schema version whose persisted data can still be read by this
+// version of the package without migration.
+const MinSchemaVersion = 1
+
+// BuildInfo is a struct for holding the version information of the decoder.
+type BuildInfo struct {
+	Version       string `json:"version"`       // Module version, "(devel)" for local builds.
+	SchemaVersion int    `json:"schemaVersion"` // Version of the decoded JSON output schema.
+	GoVersion     string `json:"goVersion"`     // Go version the binary was built with.
+}
+
+// Version returns the version of the decoder module as recorded in the build info of the
+// running binary, or "(devel)" if it is built from a local checkout.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+
+		return dep.Version
+	}
+
+	return "(devel)"
+}
+
+// GetBuildInfo returns the module, schema and go version of the decoder.
+func GetBuildInfo() BuildInfo {
+	return BuildInfo{
+		Version:       Version(),
+		SchemaVersion: SchemaVersion,
+		GoVersion:     runtime.Version(),
+	}
+}
+
+// CheckSchemaCompatibility returns an error if decoded data persisted with the given schema
+// version can not be read by this version of the package. Sinks call it before reading stored
+// results, to trigger a migration instead of misinterpreting old or newer data.
+func CheckSchemaCompatibility(version int) error {
+	if version > SchemaVersion {
+		return fmt.Errorf("decoded data has schema version %d, newer than supported version %d - upgrade the decoder", version, SchemaVersion)
+	}
+
+	if version < MinSchemaVersion {
+		return fmt.Errorf("decoded data has schema version %d, older than minimum version %d - migration required", version, MinSchemaVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// WatchdogConfig configures the limits of a Watchdog. Zero limits are not checked.
+type WatchdogConfig struct {
+	Interval        time.Duration // How often the watchdog samples, defaults to one minute.
+	MaxGoroutines   int           // Alert when more goroutines are running.
+	MaxHeapBytes    uint64        // Alert when the allocated heap grows beyond this size.
+	MaxCacheEntries int           // Alert when the token caches hold more entries.
+	TrimCaches      bool          // Whether the token and bytecode caches are trimmed when a heap or cache limit is exceeded.
+	OnAlert         func(alert WatchdogAlert)
+}
+
+// WatchdogStats is a struct for holding a single sample of the watchdog.
+type WatchdogStats struct {
+	Time             time.Time `json:"time"`
+	Goroutines       int       `json:"goroutines"`       // Number of running goroutines.
+	HeapAlloc        uint64    `json:"heapAlloc"`        // Bytes of allocated heap objects.
+	TokenCache       int       `json:"tokenCache"`       // Token infos cached in TknStore.
+	TokenAbiCache    int       `json:"tokenAbiCache"`    // Token ABIs cached in TknStore.
+	AbiList          int       `json:"abiList"`          // ABIs loaded into Store.
+	IndexedContracts int       `json:"indexedContracts"` // Contracts indexed in Store.
+}
+
+// WatchdogAlert is passed to OnAlert when a sample exceeds one of the configured limits.
+type WatchdogAlert struct {
+	Stats   WatchdogStats `json:"stats"`
+	Reasons []string      `json:"reasons"` // Human readable description of every exceeded limit.
+	Trimmed bool          `json:"trimmed"` // Whether the caches were trimmed in response.
+}
+
+// Watchdog monitors goroutine counts, heap usage and cache sizes of long-running decoder
+// processes, alerts when limits are exceeded and optionally trims the caches to protect the
+// process. Without OnAlert, alerts are logged.
+type Watchdog struct {
+	config WatchdogConfig
+
+	mu   sync.Mutex
+	last WatchdogStats
+}
+
+// NewWatchdog creates a watchdog with the given configuration. An invalid configuration fails
+// with ValidationErrors.
+func NewWatchdog(config WatchdogConfig) (*Watchdog, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+
+	return &Watchdog{config: config}, nil
+}
+
+// Start samples in the configured interval until the context is cancelled.
+func (w *Watchdog) Start(ctx context.Context) {
+	ticker := getClock().NewTicker(w.config.Interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				w.Check()
+			}
+		}
+	}()
+}
+
+// Stats returns the last sample taken.
+func (w *Watchdog) Stats() WatchdogStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.last
+}
+
+// Check takes a sample, compares it with the limits and raises an alert if any is exceeded.
+func (w *Watchdog) Check() WatchdogStats {
+	stats := sampleStats()
+
+	w.mu.Lock()
+	w.last = stats
+	w.mu.Unlock()
+
+	var reasons []string
+	trim := false
+
+	if w.config.MaxGoroutines > 0 && stats.Goroutines > w.config.MaxGoroutines {
+		reasons = append(reasons, fmt.Sprintf("goroutines %d exceed limit %d", stats.Goroutines, w.config.MaxGoroutines))
+	}
+
+	if w.config.MaxHeapBytes > 0 && stats.HeapAlloc > w.config.MaxHeapBytes {
+		reasons = append(reasons, fmt.Sprintf("heap %d bytes exceeds limit %d", stats.HeapAlloc, w.config.MaxHeapBytes))
+		trim = true
+	}
+
+	if cached := stats.TokenCache + stats.TokenAbiCache; w.config.MaxCacheEntries > 0 && cached > w.config.MaxCacheEntries {
+		reasons = append(reasons, fmt.Sprintf("token cache entries %d exceed limit %d", cached, w.config.MaxCacheEntries))
+		trim = true
+	}
+
+	if len(reasons) == 0 {
+		return stats
+	}
+
+	alert := WatchdogAlert{Stats: stats, Reasons: reasons}
+	if trim && w.config.TrimCaches {
+		TknStore.Trim()
+		ResetCodeCache()
+		ResetSHA3Cache()
+		debug.FreeOSMemory()
+		alert.Trimmed = true
+	}
+
+	if w.config.OnAlert != nil {
+		w.config.OnAlert(alert)
+	} else {
+		log.Println("decoder watchdog:", reasons, "trimmed:", alert.Trimmed)
+	}
+
+	return stats
+}
+
+func sampleStats() WatchdogStats {
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+
+	tokens, abis := TknStore.Len()
+	abiList, indexed := Store.Len()
+
+	return WatchdogStats{
+		Time:             getClock().Now(),
+		Goroutines:       runtime.NumGoroutine(),
+		HeapAlloc:        memory.HeapAlloc,
+		TokenCache:       tokens,
+		TokenAbiCache:    abis,
+		AbiList:          abiList,
+		IndexedContracts: indexed,
+	}
+}
@@ -0,0 +1,261 @@
+// Package mockrpc provides a scriptable JSON-RPC server for integration tests of code built on
+// the decoder package. Responses are scripted per method from Go tests, including JSON-RPC
+// errors, HTTP failures and latency, so retries and failover can be tested without a node.
+package mockrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Error is a JSON-RPC error returned by the server. Handlers returning an Error respond with its
+// code, all other errors are returned with code -32000.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// ErrReverted is the error nodes return for calls that revert without reason.
+var ErrReverted = &Error{Code: 3, Message: "execution reverted"}
+
+// Response is a scripted response for a single call.
+type Response struct {
+	Result  interface{}   // Result encoded as JSON, e.g. []types.Log for eth_getLogs.
+	Error   error         // JSON-RPC error returned instead of the result.
+	Status  int           // HTTP status, values other than 0 and 200 fail the whole request.
+	Latency time.Duration // Delay before the response is sent.
+}
+
+// Handler computes the result of a call from its raw params.
+type Handler func(params json.RawMessage) (interface{}, error)
+
+// CallHandler computes the return data of an eth_call from the called contract and its calldata.
+type CallHandler func(to common.Address, data []byte) ([]byte, error)
+
+// Server is a JSON-RPC server over HTTP. Every method is answered by its scripted responses in
+// order first, then by its handler. Unknown methods fail with code -32601.
+type Server struct {
+	URL string // Base URL of the server, passed to Connect or DialWithOptions.
+
+	server   *httptest.Server
+	mu       sync.Mutex
+	handlers map[string]Handler
+	queues   map[string][]Response
+	latency  time.Duration
+	calls    map[string][]json.RawMessage
+}
+
+// NewServer starts a new server. It has to be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]Handler),
+		queues:   make(map[string][]Response),
+		calls:    make(map[string][]json.RawMessage),
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Handle sets the handler answering the method once its scripted responses are consumed.
+func (s *Server) Handle(method string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Respond answers every call of the method with the given result.
+func (s *Server) Respond(method string, result interface{}) {
+	s.Handle(method, func(json.RawMessage) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// HandleCall answers eth_call with the handler, which gets the decoded target and calldata of the
+// call instead of the raw params. The returned data is sent hex encoded.
+func (s *Server) HandleCall(handler CallHandler) {
+	s.Handle("eth_call", func(params json.RawMessage) (interface{}, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(params, &args); err != nil || len(args) == 0 {
+			return nil, &Error{Code: -32602, Message: fmt.Sprintf("invalid eth_call params: %s", params)}
+		}
+
+		var call struct {
+			To    common.Address `json:"to"`
+			Data  hexutil.Bytes  `json:"data"`
+			Input hexutil.Bytes  `json:"input"`
+		}
+		if err := json.Unmarshal(args[0], &call); err != nil {
+			return nil, &Error{Code: -32602, Message: err.Error()}
+		}
+
+		data := call.Input
+		if len(data) == 0 {
+			data = call.Data
+		}
+
+		output, err := handler(call.To, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return hexutil.Encode(output), nil
+	})
+}
+
+// Enqueue scripts the next responses of the method. They are consumed in order before the
+// handler of the method is used, e.g. to fail the first two calls and succeed afterwards.
+func (s *Server) Enqueue(method string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues[method] = append(s.queues[method], responses...)
+}
+
+// SetLatency delays every request by the given duration.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// Calls returns the params of all calls of the method received so far.
+func (s *Server) Calls(method string) []json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]json.RawMessage{}, s.calls[method]...)
+}
+
+// Reset removes all handlers, scripted responses and recorded calls.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = make(map[string]Handler)
+	s.queues = make(map[string][]Response)
+	s.calls = make(map[string][]json.RawMessage)
+	s.latency = 0
+}
+
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	batch := len(body) > 0 && body[0] == '['
+	var requests []request
+	if batch {
+		if err := json.Unmarshal(body, &requests); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		var single request
+		if err := json.Unmarshal(body, &single); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, single)
+	}
+
+	status := http.StatusOK
+	responses := make([]response, 0, len(requests))
+	for _, req := range requests {
+		scripted := s.next(req.Method, req.Params)
+		time.Sleep(scripted.Latency)
+
+		if scripted.Status != 0 && scripted.Status != http.StatusOK {
+			status = scripted.Status
+		}
+
+		responses = append(responses, encodeResponse(req.ID, scripted))
+	}
+
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		json.NewEncoder(w).Encode(responses)
+	} else {
+		json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+// next records the call and returns its scripted response, with the global latency applied.
+func (s *Server) next(method string, params json.RawMessage) Response {
+	s.mu.Lock()
+	s.calls[method] = append(s.calls[method], params)
+
+	var result Response
+	if queue := s.queues[method]; len(queue) > 0 {
+		result = queue[0]
+		s.queues[method] = queue[1:]
+	} else if handler, ok := s.handlers[method]; ok {
+		s.mu.Unlock()
+		result.Result, result.Error = handler(params)
+		s.mu.Lock()
+	} else {
+		result.Error = &Error{Code: -32601, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+	}
+
+	result.Latency += s.latency
+	s.mu.Unlock()
+
+	return result
+}
+
+func encodeResponse(id json.RawMessage, scripted Response) response {
+	result := response{JSONRPC: "2.0", ID: id}
+
+	if scripted.Error != nil {
+		if rpcErr, ok := scripted.Error.(*Error); ok {
+			result.Error = rpcErr
+		} else {
+			result.Error = &Error{Code: -32000, Message: scripted.Error.Error()}
+		}
+		return result
+	}
+
+	encoded, err := json.Marshal(scripted.Result)
+	if err != nil {
+		result.Error = &Error{Code: -32603, Message: err.Error()}
+		return result
+	}
+
+	result.Result = encoded
+	return result
+}
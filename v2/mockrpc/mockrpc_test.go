@@ -0,0 +1,104 @@
+package mockrpc
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixture := types.Log{
+		Address:   common.HexToAddress("0x1"),
+		Topics:    []common.Hash{common.HexToHash("0x2")},
+		Data:      []byte{},
+		TxHash:    common.HexToHash("0x3"),
+		BlockHash: common.HexToHash("0x4"),
+	}
+
+	server.Respond("eth_getLogs", []types.Log{fixture})
+	server.Enqueue("eth_getLogs",
+		Response{Error: &Error{Code: -32005, Message: "query returned more than 10000 results"}},
+		Response{Status: http.StatusTooManyRequests},
+	)
+
+	query := ethereum.FilterQuery{FromBlock: big.NewInt(1), ToBlock: big.NewInt(2)}
+	if _, err := client.FilterLogs(context.Background(), query); err == nil {
+		t.Fatal("expected scripted rpc error")
+	}
+
+	if _, err := client.FilterLogs(context.Background(), query); err == nil {
+		t.Fatal("expected scripted http error")
+	}
+
+	logs, err := client.FilterLogs(context.Background(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 1 || logs[0].TxHash != fixture.TxHash {
+		t.Fatalf("invalid logs: %v", logs)
+	}
+
+	if calls := server.Calls("eth_getLogs"); len(calls) != 3 {
+		t.Fatalf("invalid recorded calls: %v", len(calls))
+	}
+
+	if _, err := client.ChainID(context.Background()); err == nil {
+		t.Fatal("expected error for unknown method")
+	}
+
+	server.Respond("eth_chainId", "0x1")
+	server.SetLatency(50 * time.Millisecond)
+
+	start := time.Now()
+	if chainID, err := client.ChainID(context.Background()); err != nil || chainID.Int64() != 1 {
+		t.Fatalf("invalid chain id: %v %v", chainID, err)
+	}
+
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatal("latency not applied")
+	}
+}
+
+func TestHandleCall(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := common.HexToAddress("0x1")
+	server.HandleCall(func(to common.Address, data []byte) ([]byte, error) {
+		if to != token {
+			return nil, ErrReverted
+		}
+		return append([]byte{0xff}, data...), nil
+	})
+
+	output, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: []byte{1, 2}}, nil)
+	if err != nil || common.Bytes2Hex(output) != "ff0102" {
+		t.Fatalf("invalid output: %x %v", output, err)
+	}
+
+	other := common.HexToAddress("0x2")
+	if _, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &other}, nil); err == nil || err.Error() != "execution reverted" {
+		t.Fatalf("expected revert: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package protocols
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// PriceFeeds maps tokens to the Chainlink USD price feed their amounts are valued with, see
+// ITknStore.ValueUSD. EtherAddress stands for the native currency. It holds the feeds of Ethereum
+// mainnet, feeds of other chains can be added.
+var PriceFeeds = core.PriceFeeds
+
+// USDAmountFormat is used to format the USD values of ValueUSD.
+var USDAmountFormat = &core.USDAmountFormat
+
+// PriceRound is a round of a Chainlink price feed as returned by latestRoundData.
+type PriceRound = core.PriceRound
+
+// PriceUpdate is an AnswerUpdated event of a Chainlink aggregator. The event is emitted by the
+// aggregator behind the feed, not by the proxy contract read by LatestRoundData.
+type PriceUpdate = core.PriceUpdate
+
+// IsAnswerUpdatedLog returns true if the log is an AnswerUpdated event of a Chainlink aggregator.
+func IsAnswerUpdatedLog(vLog *types.Log) bool {
+	return core.IsAnswerUpdatedLog(vLog)
+}
+
+// DecodeAnswerUpdated decodes the AnswerUpdated log of a Chainlink aggregator. It returns an error
+// for logs of other events.
+func DecodeAnswerUpdated(vLog *types.Log) (*PriceUpdate, error) {
+	return core.DecodeAnswerUpdated(vLog)
+}
+
+// LatestRoundData reads the latest round of the price feed with the global TknStore, see
+// ITknStore.LatestRoundData.
+func LatestRoundData(ctx context.Context, feed common.Address) (*PriceRound, error) {
+	return core.LatestRoundData(ctx, feed)
+}
@@ -0,0 +1,5 @@
+// Package protocols decodes the events and calls of well-known protocols.
+//
+// USDAmountFormat and ENSRegistry point to settings shared by all packages of the module, changed
+// through the pointers.
+package protocols
@@ -0,0 +1,24 @@
+package protocols
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ENSRegistry is the ENS registry, deployed at the same address on Ethereum mainnet and its
+// testnets.
+var ENSRegistry = &core.ENSRegistry
+
+// ResolveENS returns the address the ENS name resolves to with the global backend. Names are only
+// lowercased, not fully normalized.
+func ResolveENS(name string) (common.Address, error) {
+	return core.ResolveENS(name)
+}
+
+// LookupENS returns the primary ENS name of the address with the global backend. Reverse records
+// are only returned if the name resolves back to the address, as anyone can claim any name in
+// their reverse record. Names, and the absence of a name, are cached like the other contract
+// lookups.
+func LookupENS(address common.Address) (string, error) {
+	return core.LookupENS(address)
+}
@@ -0,0 +1,32 @@
+package protocols
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Transaction types of L2 chains. They have no signature, the sender is reported by the node, and
+// go-ethereum can not represent them as types.Transaction.
+const (
+	DepositTxType                 = core.DepositTxType                 // OP-stack deposit, submitted on L1 or inserted by the protocol.
+	ArbitrumDepositTxType         = core.ArbitrumDepositTxType         // ETH deposited from L1.
+	ArbitrumUnsignedTxType        = core.ArbitrumUnsignedTxType        // Call of an L1 account without signature.
+	ArbitrumContractTxType        = core.ArbitrumContractTxType        // Call of an L1 contract.
+	ArbitrumRetryTxType           = core.ArbitrumRetryTxType           // Redeem attempt of a retryable ticket.
+	ArbitrumSubmitRetryableTxType = core.ArbitrumSubmitRetryableTxType // Retryable ticket submitted on L1.
+	ArbitrumInternalTxType        = core.ArbitrumInternalTxType        // Internal transaction of ArbOS, e.g. the L1 block info.
+	ArbitrumLegacyTxType          = core.ArbitrumLegacyTxType          // Transaction of the classic, pre-Nitro chain.
+)
+
+var (
+	// L1AttributesDepositor sends the L1 attributes deposit starting every OP-stack block.
+	L1AttributesDepositor = core.L1AttributesDepositor
+
+	// ArbOSAddress sends the internal transactions of Arbitrum.
+	ArbOSAddress = core.ArbOSAddress
+)
+
+// IsL2TxType reports whether the transaction type is one of the L2 types of OP-stack chains or
+// Arbitrum.
+func IsL2TxType(txType uint8) bool {
+	return core.IsL2TxType(txType)
+}
@@ -0,0 +1,43 @@
+package protocols
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Marketplaces of the sales normalized by DecodeNFTSales.
+const (
+	MarketplaceSeaport   = core.MarketplaceSeaport
+	MarketplaceLooksRare = core.MarketplaceLooksRare
+)
+
+// NFTSale is a sale of a single NFT on a marketplace. Sales of bundles are reported per NFT, each
+// with the price of the whole order.
+type NFTSale = core.NFTSale
+
+// IsNFTSaleLog returns true if the log is a Seaport OrderFulfilled or a LooksRare TakerAsk or
+// TakerBid event.
+func IsNFTSaleLog(vLog *types.Log) bool {
+	return core.IsNFTSaleLog(vLog)
+}
+
+// DecodeNFTSales normalizes a sale log of Seaport or LooksRare into the NFTs sold. Seaport orders
+// offering NFTs are listings bought by the recipient of the order, orders offering a payment are
+// bids accepted by the recipient. Orders exchanging no NFT, e.g. the counter orders of
+// matchOrders, have no sales. It returns an error for logs of other events.
+func DecodeNFTSales(vLog *types.Log) ([]NFTSale, error) {
+	return core.DecodeNFTSales(vLog)
+}
+
+// ExtractNFTSales normalizes all sale logs, skipping other logs and sales that can not be
+// decoded.
+func ExtractNFTSales(vLogs []*types.Log) []NFTSale {
+	return core.ExtractNFTSales(vLogs)
+}
+
+// DecodeBasicOrder normalizes a Seaport fulfillBasicOrder call into the NFT sold. The sender of
+// the transaction fulfills the order, it is the buyer of listings and the seller of accepted
+// bids. Fees paid to additional recipients are part of the price.
+func DecodeBasicOrder(tx *types.Transaction) (*NFTSale, error) {
+	return core.DecodeBasicOrder(tx)
+}
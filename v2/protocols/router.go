@@ -0,0 +1,37 @@
+package protocols
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Routers of the calls decoded by DecodeRouterCall.
+const (
+	RouterUniversal  = core.RouterUniversal
+	RouterAggregator = core.RouterAggregator
+)
+
+// RouterCommands maps the command types of the Universal Router to their names in
+// abi_router_commands. Commands missing here, e.g. of NFT marketplaces, are listed without
+// decoded params.
+var RouterCommands = core.RouterCommands
+
+// RouterOperation is a single step of a router call: a command of a Universal Router execute call
+// or a pool swap of an aggregator call.
+type RouterOperation = core.RouterOperation
+
+// RouterCall is a router call expanded into its operations, see DecodeRouterCall.
+type RouterCall = core.RouterCall
+
+// IsRouterCall returns true if the calldata calls a method of the Universal Router or of the 1inch
+// aggregation router that DecodeRouterCall expands.
+func IsRouterCall(data []byte) bool {
+	return core.IsRouterCall(data)
+}
+
+// DecodeRouterCall decodes a call of the Uniswap Universal Router or of the 1inch aggregation
+// router and expands it into its operations. The packed commands of execute are split into one
+// operation per command with the params of its input, the pools of unoswap and uniswapV3Swap
+// into one operation per pool swap. It returns an error for calldata of other methods.
+func DecodeRouterCall(contract string, data []byte) (*RouterCall, error) {
+	return core.DecodeRouterCall(contract, data)
+}
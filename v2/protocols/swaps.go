@@ -0,0 +1,30 @@
+package protocols
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Protocols of the swaps normalized by DecodeSwap.
+const (
+	ProtocolUniswapV2 = core.ProtocolUniswapV2
+	ProtocolUniswapV3 = core.ProtocolUniswapV3
+)
+
+// SwapInfo is a swap of a Uniswap V2 or V3 style pool, normalized to the tokens and amounts going
+// in and out of the pool. Symbols and decimals are resolved through the token store, they are
+// empty if the token does not implement them.
+type SwapInfo = core.SwapInfo
+
+// IsSwapLog returns true if the log is a Swap event of a Uniswap V2 or V3 style pool.
+func IsSwapLog(vLog *types.Log) bool {
+	return core.IsSwapLog(vLog)
+}
+
+// DecodeSwap normalizes the Swap log of a pool with the global TknStore, see
+// ITknStore.DecodeSwap.
+func DecodeSwap(ctx context.Context, vLog *types.Log) (*SwapInfo, error) {
+	return core.DecodeSwap(ctx, vLog)
+}
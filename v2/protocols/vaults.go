@@ -0,0 +1,35 @@
+package protocols
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Kinds of the vault events normalized by DecodeVaultEvent.
+const (
+	VaultDeposit  = core.VaultDeposit
+	VaultWithdraw = core.VaultWithdraw
+)
+
+// ERC4626InterfaceID is the ERC-165 interface id of ERC-4626, the xor of the selectors of its
+// methods. Few vaults report it, IsVault also detects vaults by their selectors.
+var ERC4626InterfaceID = core.ERC4626InterfaceID
+
+// VaultEvent is a Deposit or Withdraw event of an ERC-4626 vault.
+type VaultEvent = core.VaultEvent
+
+// IsVaultLog returns true if the log is a Deposit or Withdraw event of an ERC-4626 vault.
+func IsVaultLog(vLog *types.Log) bool {
+	return core.IsVaultLog(vLog)
+}
+
+// DecodeVaultEvent normalizes a Deposit or Withdraw log of an ERC-4626 vault. It returns an
+// error for logs of other events.
+func DecodeVaultEvent(vLog *types.Log) (*VaultEvent, error) {
+	return core.DecodeVaultEvent(vLog)
+}
+
+// ExtractVaultEvents normalizes all vault events, skipping other logs.
+func ExtractVaultEvents(vLogs []*types.Log) []VaultEvent {
+	return core.ExtractVaultEvents(vLogs)
+}
@@ -0,0 +1,12 @@
+package rpc
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Backend is the chain access of decoders and stores. *ethclient.Client implements it, as do the
+// simulated backend of go-ethereum, recorded fixtures or custom RPC wrappers. Backends exposing
+// the JSON-RPC client with Client() *rpc.Client, like *ethclient.Client, get batched lookups,
+// call traces and the L1 fees of OP-stack receipts; the lookups fall back to single calls for
+// other backends.
+type Backend = core.Backend
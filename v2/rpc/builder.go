@@ -0,0 +1,22 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// BuildTx builds a transaction of the sender calling the method of the contract with the global
+// Store, see Storage.BuildTx.
+func BuildTx(ctx context.Context, from, contract common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	return core.BuildTx(ctx, from, contract, method, args...)
+}
+
+// ResetNonce drops the nonces of the sender tracked by BuildTx on the chain, e.g. after a built
+// transaction was not sent. The next transaction takes the pending nonce of the node again.
+func ResetNonce(chainId *big.Int, from common.Address) {
+	core.ResetNonce(chainId, from)
+}
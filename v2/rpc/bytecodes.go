@@ -0,0 +1,24 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// CodeBatchSize is the number of eth_getCode calls sent in a single batch request by GetCodes.
+var CodeBatchSize = &core.CodeBatchSize
+
+// ResetCodeCache clears the shared bytecode cache.
+func ResetCodeCache() {
+	core.ResetCodeCache()
+}
+
+// GetCodes returns the bytecode of all addresses at the given block, nil for the latest block.
+// Addresses missing in the shared cache are fetched with batched eth_getCode calls of
+// CodeBatchSize each. Addresses without code are returned with empty code.
+func GetCodes(ctx context.Context, addresses []common.Address, block *big.Int) (map[common.Address][]byte, error) {
+	return core.GetCodes(ctx, addresses, block)
+}
@@ -0,0 +1,22 @@
+package rpc
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ChainConfig describes an EVM chain: its name, the explorer its ABIs are fetched from and its
+// known system contracts.
+type ChainConfig = core.ChainConfig
+
+// ChainRegistry holds the configs of chains by chain id. It is safe for concurrent use.
+type ChainRegistry = core.ChainRegistry
+
+// NewChainRegistry returns a registry holding the configs. It panics if a config is invalid, as
+// it is meant for static tables like Chains; use Register to handle invalid configs.
+func NewChainRegistry(configs ...*ChainConfig) *ChainRegistry {
+	return core.NewChainRegistry(configs...)
+}
+
+// Chains is the global chain registry. It holds Ethereum, Sepolia and major L2s and sidechains,
+// their explorers are the public Blockscout instances, which need no API key.
+var Chains = core.Chains
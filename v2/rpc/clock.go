@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Clock abstracts the time source of the package, so timeouts, tickers and timestamps can be
+// controlled in tests. The package uses SystemClock unless SetClock is called.
+type Clock = core.Clock
+
+// Ticker delivers ticks of a Clock in an interval.
+type Ticker = core.Ticker
+
+// SystemClock is the Clock backed by the time package.
+var SystemClock = &core.SystemClock
+
+// SetClock replaces the clock used by the package, nil restores SystemClock.
+func SetClock(c Clock) {
+	core.SetClock(c)
+}
+
+// ManualClock is a Clock for tests that only moves when Advance is called. Timers and tickers
+// fire synchronously during Advance.
+type ManualClock = core.ManualClock
+
+// NewManualClock creates a ManualClock starting at the given time.
+func NewManualClock(now time.Time) *ManualClock {
+	return core.NewManualClock(now)
+}
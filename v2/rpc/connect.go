@@ -0,0 +1,27 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ConnectOptions configures the rpc connection created by ConnectWithOptions and DialWithOptions.
+// It covers the authentication schemes of common enterprise and private RPC providers.
+type ConnectOptions = core.ConnectOptions
+
+// BasicAuth holds the credentials for HTTP basic authentication.
+type BasicAuth = core.BasicAuth
+
+// DialWithOptions validates the connection options and creates a new client for the given node
+// url without touching the global Ctx.
+func DialWithOptions(ctx context.Context, nodeUrl string, options ConnectOptions) (*ethclient.Client, error) {
+	return core.DialWithOptions(ctx, nodeUrl, options)
+}
+
+// ConnectWithOptions connects to the given node url using the connection options and sets the
+// client as global client like Connect. The options are kept for reconnects.
+func ConnectWithOptions(nodeUrl string, options ConnectOptions) (*ethclient.Client, error) {
+	return core.ConnectWithOptions(nodeUrl, options)
+}
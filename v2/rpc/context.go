@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Ctx holds the global client, used by the package-level functions and by decoders and stores
+// without a client of their own.
+//
+// Deprecated: set the client of each AbiDecoder, Storage, IndexedABI or ITknStore with SetClient
+// instead of relying on the global client.
+var Ctx = &core.Ctx
+
+// NewCtx is an initializer function for TxnSign.
+var NewCtx = core.NewCtx
+
+func SetClient(client *ethclient.Client) *ethclient.Client {
+	return core.SetClient(client)
+}
+
+// SetBackend sets the global backend, like SetClient for backends other than *ethclient.Client.
+// The features of the previous backend, see ProbeChain, are dropped.
+func SetBackend(backend Backend) Backend {
+	return core.SetBackend(backend)
+}
+
+// GetClient returns the global client, nil if the global backend is no *ethclient.Client.
+func GetClient() *ethclient.Client {
+	return core.GetClient()
+}
+
+// GetBackend returns the global backend, wrapped with the global retry policy if one is set.
+func GetBackend() Backend {
+	return core.GetBackend()
+}
+
+func Connect(nodeUrl string) *ethclient.Client {
+	return core.Connect(nodeUrl)
+}
@@ -0,0 +1,6 @@
+// Package rpc connects the decoder to nodes, with the global client used by the package-level
+// functions of all packages.
+//
+// Ctx points to the global client of SetClient. CodeBatchSize, SystemClock and DefaultRetryPolicy
+// point to settings shared by all packages of the module, changed through the pointers.
+package rpc
@@ -0,0 +1,13 @@
+package rpc
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ErrNotVerified is returned by FetchABI for contracts without verified source on the explorer.
+var ErrNotVerified = core.ErrNotVerified
+
+// ExplorerConfig configures the Etherscan-compatible API used by FetchABI, e.g. Etherscan and its
+// forks or Blockscout. The config keeps the rate limiter and the cache of unverified contracts,
+// so the same pointer should be reused for all fetches against one explorer.
+type ExplorerConfig = core.ExplorerConfig
@@ -0,0 +1,15 @@
+package rpc
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// RPCCall describes a single outbound JSON-RPC call seen by an RPCInterceptor. Duration and Err
+// are only set when it is passed to After.
+type RPCCall = core.RPCCall
+
+// RPCInterceptor hooks into every outbound RPC call of connections created with ConnectOptions,
+// e.g. for logging, quota accounting or chaos testing. Returning an error from Before aborts the
+// request with that error. Batch requests invoke the hooks once per call. Interceptors apply to
+// HTTP connections only.
+type RPCInterceptor = core.RPCInterceptor
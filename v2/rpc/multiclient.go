@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// MultiClientOptions configures the endpoints of a MultiClient.
+type MultiClientOptions = core.MultiClientOptions
+
+// EndpointStatus is a struct for holding the health of a single MultiClient endpoint.
+type EndpointStatus = core.EndpointStatus
+
+// MultiClient is a Backend spreading its calls over several RPC endpoints. Calls failing with
+// transport errors, HTTP errors or rate limits are retried on the next endpoint, endpoints failing
+// MaxFailures times in a row are skipped until a health check or call succeeds again. JSON-RPC
+// errors of the node, e.g. reverts, and ethereum.NotFound are returned as is. Lagging endpoints
+// stay unhealthy until a health check finds them caught up. Set it with SetBackend or the
+// SetBackend methods of decoders, stores and indexed contracts.
+//
+// Transactions are sent once, to the preferred endpoint, as a failed send may still have reached
+// the node. Batched lookups, call traces and subscriptions use the endpoint preferred when they
+// start and do not fail over.
+type MultiClient = core.MultiClient
+
+// DialMulti creates a MultiClient for the node urls, dialed with the connection options of
+// options. All endpoints start healthy. Invalid options fail with ValidationErrors.
+func DialMulti(ctx context.Context, urls []string, options MultiClientOptions) (*MultiClient, error) {
+	return core.DialMulti(ctx, urls, options)
+}
@@ -0,0 +1,17 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ChainFeatures holds the capabilities of the chain and node of a backend, see ProbeChain.
+type ChainFeatures = core.ChainFeatures
+
+// ProbeChain detects the features of the chain and node of the global backend. The result is
+// cached on Ctx until the backend changes; it picks the signer of Ctx, and call traces fail
+// without a request on nodes without the debug namespace.
+func ProbeChain(ctx context.Context) (*ChainFeatures, error) {
+	return core.ProbeChain(ctx)
+}
@@ -0,0 +1,17 @@
+package rpc
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// RateLimiter is a token bucket limiting the requests sent to a provider. It is an RPCInterceptor
+// holding back every outbound call until a token is free, calls of batch requests take a token
+// each. Connections created with ConnectOptions.RateLimit get their own limiter, a limiter added
+// to the Interceptors of several connections shares its quota between them.
+type RateLimiter = core.RateLimiter
+
+// NewRateLimiter creates a limiter allowing perSecond requests per second on average and up to
+// burst requests at once. Burst defaults to one second worth of requests.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return core.NewRateLimiter(perSecond, burst)
+}
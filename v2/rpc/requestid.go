@@ -0,0 +1,19 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// WithRequestID returns a copy of the context carrying the request or correlation id. The id is
+// attached to decoded results of the *Context decode functions, passed to RPC interceptors and
+// forwarded to the node if ConnectOptions.RequestIDHeader is set.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return core.WithRequestID(ctx, id)
+}
+
+// RequestIDFromContext returns the request id attached with WithRequestID, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	return core.RequestIDFromContext(ctx)
+}
@@ -0,0 +1,32 @@
+package rpc
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// RetryPolicy configures the retries of failed RPC calls. Calls are attempted up to MaxAttempts
+// times, waiting InitialBackoff after the first failure and Multiplier times longer after every
+// further one, up to MaxBackoff. Transactions are sent once and subscriptions are not retried.
+type RetryPolicy = core.RetryPolicy
+
+// DefaultRetryPolicy is a policy suitable for public RPC endpoints.
+var DefaultRetryPolicy = &core.DefaultRetryPolicy
+
+// SetRetryPolicy sets the retry policy of all backends, nil disables retries. Decoders and stores
+// with their own Retry policy use that instead. An invalid policy fails with ValidationErrors and
+// keeps the previous one.
+func SetRetryPolicy(policy *RetryPolicy) error {
+	return core.SetRetryPolicy(policy)
+}
+
+// GetRetryPolicy returns the global retry policy, nil if retries are disabled.
+func GetRetryPolicy() *RetryPolicy {
+	return core.GetRetryPolicy()
+}
+
+// IsRetryable reports whether the error is transient: transport errors, timeouts, rate limits and
+// server errors of the endpoint. JSON-RPC errors of the node, e.g. reverts, ethereum.NotFound and
+// cancelled contexts are final.
+func IsRetryable(err error) bool {
+	return core.IsRetryable(err)
+}
@@ -0,0 +1,8 @@
+package rpc
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// SourcifyConfig configures the Sourcify server used by FetchSourcify.
+type SourcifyConfig = core.SourcifyConfig
@@ -0,0 +1,10 @@
+package scan
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// BatchSize is the number of transaction, receipt and header lookups sent in a single batch
+// request by DecodeBlock, SubscribeBlocks, DecodeTransactions, DecodeReceipts and the reorg checks
+// of scan sessions.
+var BatchSize = &core.BatchSize
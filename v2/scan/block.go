@@ -0,0 +1,19 @@
+package scan
+
+import (
+	"context"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// DecodedBlock is a struct for holding the decoded transactions of a block.
+type DecodedBlock = core.DecodedBlock
+
+// DecodedBlockTransaction is a struct for holding a transaction of a DecodedBlock with its
+// decoded method and logs.
+type DecodedBlockTransaction = core.DecodedBlockTransaction
+
+// SubscribeBlocks is Store.SubscribeBlocks with the default options.
+func SubscribeBlocks(ctx context.Context) (<-chan DecodedBlock, error) {
+	return core.SubscribeBlocks(ctx)
+}
@@ -0,0 +1,16 @@
+package scan
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+	"github.com/w2496/go-abi-decoder/v2/store"
+)
+
+// BloomMatches returns true if the logs bloom of a block or receipt may contain logs the store can
+// decode: the topic of an event of the ABIs in AbiList or the address of an indexed contract. A
+// false result is definite, a true one may be a false positive of the bloom filter. Stores
+// decoding anonymous events or resolving unknown topics online can decode any log, so the bloom
+// always matches for them.
+func BloomMatches(bloom types.Bloom, storage *store.Storage) bool {
+	return core.BloomMatches(bloom, storage)
+}
@@ -0,0 +1,37 @@
+package scan
+
+import (
+	"github.com/ethereum/go-ethereum"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// CheckpointStore persists the last fully processed block of scan sessions by key. Other
+// backends, e.g. Redis, plug in by implementing the two methods.
+type CheckpointStore = core.CheckpointStore
+
+// ScanFunc is the signature of Storage.ScanLogs and AbiDecoder.ScanLogs.
+type ScanFunc = core.ScanFunc
+
+// ScanSession is a resumable scan: the last fully processed block is saved to the checkpoint
+// store while scanning, and a later Run with the same key continues after it. Logs are processed
+// at least once, the logs of a block interrupted by a crash or an error of handle are handed out
+// again by the next run.
+type ScanSession = core.ScanSession
+
+// NewScanSession returns a session scanning the filter with the global store.
+func NewScanSession(key string, checkpoint CheckpointStore, filter ethereum.FilterQuery) *ScanSession {
+	return core.NewScanSession(key, checkpoint, filter)
+}
+
+// FileCheckpointStore keeps the checkpoints of all keys in a JSON file, which is replaced
+// atomically on every save.
+type FileCheckpointStore = core.FileCheckpointStore
+
+// SQLCheckpointStore keeps the checkpoints in a table with the columns key (text, primary key)
+// and block (bigint), e.g.
+//
+//	CREATE TABLE scan_checkpoints (key TEXT PRIMARY KEY, block BIGINT NOT NULL)
+//
+// The driver is up to the caller. Numbered enables $1 style placeholders as used by Postgres,
+// otherwise ? is used.
+type SQLCheckpointStore = core.SQLCheckpointStore
@@ -0,0 +1,5 @@
+// Package scan fetches and decodes the logs of block ranges and blocks.
+//
+// BatchSize and ScanChunkSize point to settings shared by all packages of the module, changed
+// through the pointers.
+package scan
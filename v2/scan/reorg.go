@@ -0,0 +1,15 @@
+package scan
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ReorgTracker keeps the block hashes and logs of the most recent blocks a scan or subscription
+// delivered logs of. Rewind compares them with the canonical chain, so logs of blocks that were
+// reorganized away can be reported as removed and the blocks decoded again.
+type ReorgTracker = core.ReorgTracker
+
+// NewReorgTracker returns a tracker following the given number of blocks.
+func NewReorgTracker(depth uint64) *ReorgTracker {
+	return core.NewReorgTracker(depth)
+}
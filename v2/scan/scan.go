@@ -0,0 +1,20 @@
+package scan
+
+import (
+	"context"
+
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ScanChunkSize is the number of blocks requested with a single eth_getLogs call by ScanLogs and
+// ScanEvents.
+var ScanChunkSize = &core.ScanChunkSize
+
+// ScanOptions configures ScanLogs. A nil ScanOptions uses the defaults.
+type ScanOptions = core.ScanOptions
+
+// ScanEvents is Store.ScanEvents, using the ABIs of the global store.
+func ScanEvents(ctx context.Context, eventName string, fromBlock uint64, toBlock uint64) (*decode.ScannedLogs, error) {
+	return core.ScanEvents(ctx, eventName, fromBlock, toBlock)
+}
@@ -0,0 +1,17 @@
+package scan
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// SubscribeOptions configures SubscribeLogs. A nil SubscribeOptions uses the defaults.
+type SubscribeOptions = core.SubscribeOptions
+
+// SubscribeLogs is Store.SubscribeLogs with the default options.
+func SubscribeLogs(ctx context.Context, filter ethereum.FilterQuery) (<-chan decode.DecodedLog, error) {
+	return core.SubscribeLogs(ctx, filter)
+}
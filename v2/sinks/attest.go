@@ -0,0 +1,33 @@
+package sinks
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Attestation is a struct for holding the signature of a decoded result, attesting which signer
+// and ABI set produced it.
+type Attestation = core.Attestation
+
+// Attestor signs the canonical JSON of decoded results with a secp256k1 key, so downstream
+// consumers can verify their provenance and integrity with VerifyMethod and VerifyLog. The
+// Attestation and RequestID fields are not part of the signed payload.
+type Attestor = core.Attestor
+
+// NewAttestor creates an attestor for the hex encoded private key.
+func NewAttestor(hexKey string, abiSet string) (*Attestor, error) {
+	return core.NewAttestor(hexKey, abiSet)
+}
+
+// VerifyMethod checks the attestation of the decoded method. If trusted signers are given, the
+// signer has to be one of them.
+func VerifyMethod(decoded *decode.DecodedMethod, trusted ...common.Address) error {
+	return core.VerifyMethod(decoded, trusted...)
+}
+
+// VerifyLog checks the attestation of the decoded log. If trusted signers are given, the signer
+// has to be one of them.
+func VerifyLog(decoded *decode.DecodedLog, trusted ...common.Address) error {
+	return core.VerifyLog(decoded, trusted...)
+}
@@ -0,0 +1,3 @@
+// Package sinks supports the consumers of decoded results, like the schema version of the JSON
+// output.
+package sinks
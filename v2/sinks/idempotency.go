@@ -0,0 +1,20 @@
+package sinks
+
+import (
+	"math/big"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// LogIdempotencyKey returns the deterministic deduplication key of a log, formatted as
+// chainId:blockHash:txHash:logIndex. The block hash keeps logs of reorged blocks apart. A nil
+// chain id is formatted as 0.
+func LogIdempotencyKey(chainId *big.Int, blockHash string, txHash string, logIndex uint) string {
+	return core.LogIdempotencyKey(chainId, blockHash, txHash, logIndex)
+}
+
+// MethodIdempotencyKey returns the deterministic deduplication key of a decoded transaction,
+// its lowercase hash.
+func MethodIdempotencyKey(txHash string) string {
+	return core.MethodIdempotencyKey(txHash)
+}
@@ -0,0 +1,33 @@
+package sinks
+
+import (
+	"database/sql"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// StorageDriver persists the ABIs and indexed contracts of a Storage and the token infos of an
+// ITknStore, see Storage.SaveTo, Storage.LoadFrom, ITknStore.SaveTo and ITknStore.LoadFrom.
+// Other backends plug in by implementing the methods, SQLStorageDriver is the reference driver
+// for SQLite and Postgres.
+type StorageDriver = core.StorageDriver
+
+// NamedABI is an ABI of AbiList together with its source name, see AddABI.
+type NamedABI = core.NamedABI
+
+// SQLStorageDriver keeps ABIs, indexed contracts and token infos in three tables, see Schema.
+// SQLite stores ABIs and contracts as JSON text and upserts with an update followed by an insert.
+// Postgres stores them as JSONB, upserts with ON CONFLICT and uses $1 style placeholders.
+//
+// The driver is up to the caller, see NewSQLiteDriver and NewPostgresDriver.
+type SQLStorageDriver = core.SQLStorageDriver
+
+// NewSQLiteDriver returns a driver for a SQLite database.
+func NewSQLiteDriver(db *sql.DB) *SQLStorageDriver {
+	return core.NewSQLiteDriver(db)
+}
+
+// NewPostgresDriver returns a driver for a Postgres database.
+func NewPostgresDriver(db *sql.DB) *SQLStorageDriver {
+	return core.NewPostgresDriver(db)
+}
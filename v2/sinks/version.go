@@ -0,0 +1,34 @@
+package sinks
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// SchemaVersion is the version of the JSON output schema of decoded results (DecodedMethod,
+// DecodedLog, ...). It is increased whenever fields are renamed, removed or change their type.
+const SchemaVersion = core.SchemaVersion
+
+// MinSchemaVersion is the oldest schema version whose persisted data can still be read by this
+// version of the package without migration.
+const MinSchemaVersion = core.MinSchemaVersion
+
+// BuildInfo is a struct for holding the version information of the decoder.
+type BuildInfo = core.BuildInfo
+
+// Version returns the version of the decoder module as recorded in the build info of the
+// running binary, or "(devel)" if it is built from a local checkout.
+func Version() string {
+	return core.Version()
+}
+
+// GetBuildInfo returns the module, schema and go version of the decoder.
+func GetBuildInfo() BuildInfo {
+	return core.GetBuildInfo()
+}
+
+// CheckSchemaCompatibility returns an error if decoded data persisted with the given schema
+// version can not be read by this version of the package. Sinks call it before reading stored
+// results, to trigger a migration instead of misinterpreting old or newer data.
+func CheckSchemaCompatibility(version int) error {
+	return core.CheckSchemaCompatibility(version)
+}
@@ -0,0 +1,11 @@
+package store
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Storage is a struct that holds all the ABIs and indexed contracts.
+type Storage = core.Storage
+
+// Store is a global variable of type Storage, holding all the ABIs and indexed contracts.
+var Store = &core.Store
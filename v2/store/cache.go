@@ -0,0 +1,43 @@
+package store
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Cache is a key-value store for decoded metadata, i.e. contract lookups, token infos and resolved
+// signatures, which can be shared between processes, e.g. with RedisCache. Values are JSON
+// encoded. The package uses a MemoryCache unless SetCache is called.
+type Cache = core.Cache
+
+// CacheTTL is the time entries are kept in the Cache, 0 keeps them until they are evicted.
+var CacheTTL = &core.CacheTTL
+
+// SetCache replaces the cache shared by the contract lookups, the token stores and the signature
+// resolvers, nil restores an empty MemoryCache.
+func SetCache(cache Cache) {
+	core.SetCache(cache)
+}
+
+// GetCache returns the cache shared by the contract lookups, the token stores and the signature
+// resolvers.
+func GetCache() Cache {
+	return core.GetCache()
+}
+
+// MemoryCache is the in-process Cache, holding at most Size entries and evicting the least
+// recently used one.
+type MemoryCache = core.MemoryCache
+
+// NewMemoryCache creates a cache of at most size entries.
+func NewMemoryCache(size int) *MemoryCache {
+	return core.NewMemoryCache(size)
+}
+
+// RedisCache is a Cache backed by a Redis server, speaking the RESP protocol over a single
+// connection, which is redialed after errors.
+type RedisCache = core.RedisCache
+
+// NewRedisCache creates a cache for the Redis server at addr, e.g. "localhost:6379".
+func NewRedisCache(addr string) *RedisCache {
+	return core.NewRedisCache(addr)
+}
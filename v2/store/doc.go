@@ -0,0 +1,6 @@
+// Package store holds the ABIs and indexed contracts used to decode the calls and logs of many
+// contracts, see Storage.
+//
+// Store points to the global storage used by the package-level functions. CacheTTL points to a
+// setting shared by all packages of the module, changed through the pointer.
+package store
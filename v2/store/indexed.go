@@ -0,0 +1,14 @@
+package store
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// IndexedABI is a struct for holding Ethereum ABIs.
+type IndexedABI = core.IndexedABI
+
+// BlockRange is an inclusive range of blocks. A To of 0 leaves the range open ended.
+type BlockRange = core.BlockRange
+
+// AbiVersion is a struct for holding the ABI of a contract valid in a block range.
+type AbiVersion = core.AbiVersion
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"time"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Kinds of the lookups cached by LookupCache.
+const (
+	LookupBytecode = core.LookupBytecode
+	LookupSymbol   = core.LookupSymbol
+	LookupName     = core.LookupName
+	LookupDecimals = core.LookupDecimals
+	LookupENSName  = core.LookupENSName
+)
+
+// LookupCache caches the bytecode, symbol, name and decimals of contracts by chain id and address,
+// so getBytecode, getSymbol, getName and getDecimals, and with them queryTokenInfo and
+// IndexedABI.GetBytecode, only hit the RPC once per contract. It holds at most Size entries,
+// evicting the least recently used one, and expires entries older than TTL, if set. Failed
+// lookups, and lookups on backends not reporting their chain id, are not cached.
+type LookupCache = core.LookupCache
+
+// NewLookupCache creates a cache of at most size entries, expiring them after ttl if it is not 0.
+func NewLookupCache(size int, ttl time.Duration) *LookupCache {
+	return core.NewLookupCache(size, ttl)
+}
+
+// SetLookupCache replaces the in-process cache of the contract lookups, nil disables it, leaving
+// only the shared Cache, see SetCache.
+func SetLookupCache(cache *LookupCache) {
+	core.SetLookupCache(cache)
+}
+
+// GetLookupCache returns the in-process cache of the contract lookups, nil if it is disabled.
+func GetLookupCache() *LookupCache {
+	return core.GetLookupCache()
+}
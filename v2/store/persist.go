@@ -0,0 +1,12 @@
+package store
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// MarshalABI encodes the ABI as JSON ABI, which abi.JSON and ParseABI parse back into an equal
+// ABI. Entries are sorted by kind and name, so the encoding is deterministic.
+func MarshalABI(contractAbi abi.ABI) ([]byte, error) {
+	return core.MarshalABI(contractAbi)
+}
@@ -0,0 +1,18 @@
+package store
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// DefaultABIs is the registry of the built-in ABIs. It holds the ABIs of ALL_DEFAULT_ABIS and the
+// ABIs of widely deployed protocol contracts, and can be extended with Register.
+var DefaultABIs = core.DefaultABIs
+
+// ABIRegistry holds JSON ABIs by name, so that they can be selected individually instead of by
+// their index in ALL_DEFAULT_ABIS. Names are case insensitive. It is safe for concurrent use.
+type ABIRegistry = core.ABIRegistry
+
+// NewABIRegistry returns an empty ABIRegistry.
+func NewABIRegistry() *ABIRegistry {
+	return core.NewABIRegistry()
+}
@@ -0,0 +1,25 @@
+package store
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// WatchdogConfig configures the limits of a Watchdog. Zero limits are not checked.
+type WatchdogConfig = core.WatchdogConfig
+
+// WatchdogStats is a struct for holding a single sample of the watchdog.
+type WatchdogStats = core.WatchdogStats
+
+// WatchdogAlert is passed to OnAlert when a sample exceeds one of the configured limits.
+type WatchdogAlert = core.WatchdogAlert
+
+// Watchdog monitors goroutine counts, heap usage and cache sizes of long-running decoder
+// processes, alerts when limits are exceeded and optionally trims the caches to protect the
+// process. Without OnAlert, alerts are logged.
+type Watchdog = core.Watchdog
+
+// NewWatchdog creates a watchdog with the given configuration. An invalid configuration fails
+// with ValidationErrors.
+func NewWatchdog(config WatchdogConfig) (*Watchdog, error) {
+	return core.NewWatchdog(config)
+}
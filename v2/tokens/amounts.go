@@ -0,0 +1,28 @@
+package tokens
+
+import (
+	"math/big"
+
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// RoundingMode defines how amounts are rounded when they are cut to a fixed precision.
+type RoundingMode = core.RoundingMode
+
+const (
+	RoundHalfEven = core.RoundHalfEven // Round to nearest, ties to even (banker's rounding).
+	RoundHalfUp   = core.RoundHalfUp   // Round to nearest, ties away from zero.
+	RoundTruncate = core.RoundTruncate // Cut off all digits beyond the precision (toward zero).
+)
+
+// AmountFormat configures how raw token amounts are rendered as human-readable decimals.
+// All arithmetic is done on big.Int, so formatted amounts are exact and reproducible.
+type AmountFormat = core.AmountFormat
+
+// DefaultAmountFormat is used wherever human-readable amounts are produced, e.g. ledger exports.
+var DefaultAmountFormat = &core.DefaultAmountFormat
+
+// FormatAmount renders the raw amount with the given token decimals using DefaultAmountFormat.
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	return core.FormatAmount(amount, decimals)
+}
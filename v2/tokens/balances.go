@@ -0,0 +1,38 @@
+package tokens
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Kinds of asset movements summarized by SummarizeBalanceChanges.
+const (
+	MovementNative = core.MovementNative
+	MovementFee    = core.MovementFee
+	MovementERC20  = core.MovementERC20
+	MovementERC721 = core.MovementERC721
+)
+
+// FeeAccount is the counterparty used for paid transaction fees.
+var FeeAccount = &core.FeeAccount
+
+// AssetMovement is a single transfer of value between two accounts within a transaction.
+type AssetMovement = core.AssetMovement
+
+// BalanceSummary holds all asset movements of a transaction and the resulting net balance change
+// per account and asset.
+type BalanceSummary = core.BalanceSummary
+
+// SummarizeBalanceChanges collects the native value transfer, the paid fee (if a receipt is given)
+// and all decoded Transfer logs of a transaction into asset movements and net balance changes.
+// Failed transactions only contain the fee movement.
+func SummarizeBalanceChanges(tx *types.Transaction, receipt *types.Receipt, logs []*decode.DecodedLog) (*BalanceSummary, error) {
+	return core.SummarizeBalanceChanges(tx, receipt, logs)
+}
+
+// TransferFromLog converts a decoded ERC20 or ERC721 Transfer log into an asset movement. It
+// returns nil for other logs.
+func TransferFromLog(decoded *decode.DecodedLog) *AssetMovement {
+	return core.TransferFromLog(decoded)
+}
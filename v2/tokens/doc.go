@@ -0,0 +1,7 @@
+// Package tokens reads the metadata of token contracts and derives token movements and balance
+// changes from decoded transactions.
+//
+// TknStore points to the global store of token metadata. DefaultAmountFormat, FeeAccount and
+// RosettaNativeCurrency point to settings shared by all packages of the module, changed through the
+// pointers.
+package tokens
@@ -0,0 +1,20 @@
+package tokens
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// FeeBreakdown attributes the fee paid by a transaction. On EIP-1559 chains the base fee part is
+// burned and only the priority tip goes to the block builder, before London the builder receives
+// the full gas price. OP-stack chains additionally charge an L1 data fee that is not part of
+// gasUsed * effectiveGasPrice.
+type FeeBreakdown = core.FeeBreakdown
+
+// L1Fees holds the OP-stack specific fee fields of a receipt.
+type L1Fees = core.L1Fees
+
+// ComputeFees computes the fee breakdown of a receipt using the header of its block.
+func ComputeFees(receipt *types.Receipt, header *types.Header) (*FeeBreakdown, error) {
+	return core.ComputeFees(receipt, header)
+}
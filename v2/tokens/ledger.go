@@ -0,0 +1,24 @@
+package tokens
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Ledger entry sides.
+const (
+	LedgerDebit  = core.LedgerDebit
+	LedgerCredit = core.LedgerCredit
+)
+
+// LedgerLine is one side of a double-entry booking derived from an asset movement. Every movement
+// produces a credit line for the account the value leaves and a debit line for the account
+// receiving it, so the lines of a transaction always balance per asset.
+type LedgerLine = core.LedgerLine
+
+// Ledger is a list of double-entry ledger lines.
+type Ledger = core.Ledger
+
+// LedgerFromSummary converts all movements of a balance summary into ledger lines.
+func LedgerFromSummary(summary *BalanceSummary) Ledger {
+	return core.LedgerFromSummary(summary)
+}
@@ -0,0 +1,32 @@
+package tokens
+
+import (
+	"math/big"
+
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Standards of the permits decoded by DecodePermits.
+const (
+	PermitEIP2612 = core.PermitEIP2612
+	PermitPermit2 = core.PermitPermit2
+)
+
+// PermitInfo is a signed token approval passed in calldata, see DecodePermits. Batch permits of
+// Permit2 are listed per token, sharing the signature.
+type PermitInfo = core.PermitInfo
+
+// DecodePermits decodes the permits in calldata of the EIP-2612 permit method of a token, or of
+// the permit and permitTransferFrom methods of Permit2. It returns nil for other calldata. The
+// permits are not verified, see Verify and VerifyPermits.
+func DecodePermits(contract string, data []byte) []PermitInfo {
+	return core.DecodePermits(contract, data)
+}
+
+// VerifyPermits verifies the Permit2 permits of the decoded method and its nested calls with the
+// domain of Permit2 on the chain. EIP-2612 permits depend on the domain of the token and are left
+// to Verify.
+func VerifyPermits(decoded *decode.DecodedMethod, chainId *big.Int) {
+	core.VerifyPermits(decoded, chainId)
+}
@@ -0,0 +1,50 @@
+package tokens
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Rosetta operation types and statuses emitted by ToRosetta.
+const (
+	RosettaOpCall   = core.RosettaOpCall
+	RosettaOpFee    = core.RosettaOpFee
+	RosettaOpERC20  = core.RosettaOpERC20
+	RosettaOpERC721 = core.RosettaOpERC721
+	RosettaSuccess  = core.RosettaSuccess
+	RosettaFailure  = core.RosettaFailure
+)
+
+// RosettaNativeCurrency is the currency used for value transfers and fees of the connected chain.
+var RosettaNativeCurrency = &core.RosettaNativeCurrency
+
+// RosettaTransaction is a transaction in the Rosetta Data API format.
+type RosettaTransaction = core.RosettaTransaction
+
+// RosettaTransactionIdentifier uniquely identifies a transaction.
+type RosettaTransactionIdentifier = core.RosettaTransactionIdentifier
+
+// RosettaOperationIdentifier identifies an operation within a transaction.
+type RosettaOperationIdentifier = core.RosettaOperationIdentifier
+
+// RosettaOperation is a single balance changing operation of a transaction.
+type RosettaOperation = core.RosettaOperation
+
+// RosettaAccount is the account affected by an operation.
+type RosettaAccount = core.RosettaAccount
+
+// RosettaAmount is a signed amount in the smallest unit of its currency.
+type RosettaAmount = core.RosettaAmount
+
+// RosettaCurrency describes the asset of an amount. Token contracts are kept in the metadata.
+type RosettaCurrency = core.RosettaCurrency
+
+// ToRosetta converts a transaction, its optional receipt and its decoded logs into Rosetta
+// operations, based on the movements of SummarizeBalanceChanges. Native value transfers become a
+// CALL debit/credit pair, the paid fee becomes a FEE debit of the sender and every decoded Transfer
+// log becomes an ERC20 or ERC721 debit/credit pair. Failed transactions only carry the fee and
+// are marked with a FAILURE status in the transaction metadata.
+func ToRosetta(tx *types.Transaction, receipt *types.Receipt, logs []*decode.DecodedLog) (*RosettaTransaction, error) {
+	return core.ToRosetta(tx, receipt, logs)
+}
@@ -0,0 +1,12 @@
+package tokens
+
+import (
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// ITknInfo represents the structure of the 'token_info' table.
+type ITknInfo = core.ITknInfo
+
+type ITknStore = core.ITknStore
+
+var TknStore = &core.TknStore
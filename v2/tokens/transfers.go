@@ -0,0 +1,38 @@
+package tokens
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Standards of the token transfers extracted by ExtractTransfers.
+const (
+	StandardERC20   = core.StandardERC20
+	StandardERC721  = core.StandardERC721
+	StandardERC1155 = core.StandardERC1155
+	StandardWrapped = core.StandardWrapped // Deposit and Withdrawal of wrapped native tokens, e.g. WETH.
+)
+
+// WrappedNativeTokens are the contracts whose Deposit and Withdrawal events are extracted as
+// transfers from and to the zero address, as other contracts emit events of the same signature.
+// It holds the wrapped native tokens of Ethereum, OP-stack chains, Arbitrum, Polygon and BNB Chain.
+var WrappedNativeTokens = core.WrappedNativeTokens
+
+// TokenTransfer is a single token transfer of any standard. Mints and wrapping deposits are
+// transfers from the zero address, burns and withdrawals transfers to it.
+type TokenTransfer = core.TokenTransfer
+
+// ExtractTransfers converts the ERC20 and ERC721 Transfer, ERC1155 TransferSingle and
+// TransferBatch, and wrapped native token Deposit and Withdrawal logs into token transfers, in log
+// order. The raw topics and data of the logs are used, so the transfers do not depend on the
+// param names of the ABIs the logs were decoded with. Logs removed by a reorganization are skipped.
+func ExtractTransfers(logs []*decode.DecodedLog) []TokenTransfer {
+	return core.ExtractTransfers(logs)
+}
+
+// ExtractReceiptTransfers is like ExtractTransfers for the raw logs of a receipt, which need not
+// be decoded first. Failed transactions do not transfer any tokens.
+func ExtractReceiptTransfers(receipt *types.Receipt) []TokenTransfer {
+	return core.ExtractReceiptTransfers(receipt)
+}
@@ -0,0 +1,39 @@
+package tokens
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w2496/go-abi-decoder/v2/decode"
+	"github.com/w2496/go-abi-decoder/v2/internal/core"
+)
+
+// Labels of the transactions classified by ClassifyTransaction.
+const (
+	LabelSwap     = core.LabelSwap
+	LabelTransfer = core.LabelTransfer
+	LabelApproval = core.LabelApproval
+	LabelMint     = core.LabelMint
+	LabelBurn     = core.LabelBurn
+	LabelBridge   = core.LabelBridge
+	LabelDeploy   = core.LabelDeploy
+	LabelUnknown  = core.LabelUnknown
+)
+
+// BridgeMethods are the names of methods sending assets to another chain, e.g. of the canonical
+// bridges of rollups. Calls of these methods are classified as LabelBridge.
+var BridgeMethods = core.BridgeMethods
+
+// BridgeEvents are the names of events emitted when assets are sent to or received from another
+// chain. Transactions emitting them are classified as LabelBridge.
+var BridgeEvents = core.BridgeEvents
+
+// Classification labels a transaction, see ClassifyTransaction.
+type Classification = core.Classification
+
+// ClassifyTransaction labels a transaction by its decoded method, its decoded logs and the token
+// transfers of the logs. Contract creations are labeled first, then approvals, bridge transfers,
+// swaps, mints, burns and plain transfers. Rules matching the method are certain, rules matching
+// logs or the transfer set have a lower confidence. The method, and the transaction of calls
+// decoded without one, may be nil.
+func ClassifyTransaction(tx *types.Transaction, method *decode.DecodedMethod, logs []*decode.DecodedLog) *Classification {
+	return core.ClassifyTransaction(tx, method, logs)
+}